@@ -29,6 +29,10 @@ type ServerConfig struct {
 	Port    int    `mapstructure:"port"`
 	Host    string `mapstructure:"host"`
 	BaseURL string `mapstructure:"base_url"`
+	// TrustedProxies is a comma-separated list of CIDRs of the reverse proxies in front of the server.
+	// When set, X-Forwarded-For is only believed from these proxies; when empty, Echo's default takes the
+	// forwarding headers as sent, so production deployments must set it.
+	TrustedProxies string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -67,6 +71,14 @@ type R2Config struct {
 	Region          string `mapstructure:"region"`
 }
 
+// TokenEncryptionConfig holds the key-encryption keys for OAuth tokens stored in the database.
+// Keys is "id:base64key,..." of 32 byte keys; ActiveKey names the one new tokens are encrypted with.
+// Keep retired keys listed until a rotation has re-encrypted every token.
+type TokenEncryptionConfig struct {
+	Keys      string `mapstructure:"keys"`
+	ActiveKey string `mapstructure:"active_key"`
+}
+
 type Config struct {
 	Environment Environment
 	Server      ServerConfig
@@ -76,12 +88,19 @@ type Config struct {
 	Redis       RedisConfig `mapstructure:"redis"`
 	R2          R2Config    `mapstructure:"r2"`
 	GoogleAPI   GoogleAPIConfig `mapstructure:"google_api"`
+	TokenEncryption TokenEncryptionConfig `mapstructure:"token_encryption"`
 }
 
+// GoogleAPIConfig holds the Google OAuth client and the Calendar API client settings.
+// Calendar settings left at zero take the client defaults; CalendarBaseURL can point at a fake server.
 type GoogleAPIConfig struct {
-	ClientID     string `mapstructure:"client_id"`
-	ClientSecret string `mapstructure:"client_secret"`
-	RedirectURI  string `mapstructure:"redirect_uri"`
+	ClientID              string  `mapstructure:"client_id"`
+	ClientSecret          string  `mapstructure:"client_secret"`
+	RedirectURI           string  `mapstructure:"redirect_uri"`
+	CalendarBaseURL       string  `mapstructure:"calendar_base_url"`
+	CalendarRateLimit     float64 `mapstructure:"calendar_rate_limit"`
+	CalendarUserRateLimit float64 `mapstructure:"calendar_user_rate_limit"`
+	CalendarMaxRetries    int     `mapstructure:"calendar_max_retries"`
 }
 
 // ----------------------------------------------------------------------------
@@ -147,6 +166,17 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.TokenEncryption.Keys != "" || c.TokenEncryption.ActiveKey != "" {
+		if c.TokenEncryption.Keys == "" {
+			errors = append(errors, "token encryption keys are required when an active key is set")
+		}
+		if c.TokenEncryption.ActiveKey == "" {
+			errors = append(errors, "token encryption active key is required when keys are configured")
+		}
+	} else if c.Environment == ProdEnvironment {
+		errors = append(errors, "token encryption keys are required in production")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed:\n- %s", strings.Join(errors, "\n- "))
 	}
@@ -201,6 +231,7 @@ func Init(env Environment) error {
 		// Server configuration
 		v.BindEnv("server.host", "APP_SERVER_HOST")
 		v.BindEnv("server.base_url", "APP_SERVER_BASE_URL")
+		v.BindEnv("server.trusted_proxies", "APP_SERVER_TRUSTED_PROXIES")
 
 		// Database configuration
 		v.BindEnv("database.host", "APP_DATABASE_HOST")
@@ -236,6 +267,14 @@ func Init(env Environment) error {
 		v.BindEnv("google_api.client_id", "GOOGLE_CLIENT_ID")
 		v.BindEnv("google_api.client_secret", "GOOGLE_CLIENT_SECRET")
 		v.BindEnv("google_api.redirect_uri", "GOOGLE_REDIRECT_URI")
+		v.BindEnv("google_api.calendar_base_url", "GOOGLE_CALENDAR_BASE_URL")
+		v.BindEnv("google_api.calendar_rate_limit", "GOOGLE_CALENDAR_RATE_LIMIT")
+		v.BindEnv("google_api.calendar_user_rate_limit", "GOOGLE_CALENDAR_USER_RATE_LIMIT")
+		v.BindEnv("google_api.calendar_max_retries", "GOOGLE_CALENDAR_MAX_RETRIES")
+
+		// OAuth token encryption
+		v.BindEnv("token_encryption.keys", "APP_TOKEN_ENCRYPTION_KEYS")
+		v.BindEnv("token_encryption.active_key", "APP_TOKEN_ENCRYPTION_ACTIVE_KEY")
 
 		// 3. Unmarshal
 		instance = &Config{}
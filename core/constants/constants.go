@@ -8,9 +8,15 @@ const (
 
 	// OTP related keys
 	RedisKeyOTPChangePassword = RedisKeyPrefix + "otp_change_password:"
-	
+
 	// OAuth related keys
 	RedisKeyOAuthState = RedisKeyPrefix + "oauth_state:"
+
+	// Public booking abuse protection keys
+	RedisKeyBookingRateIP            = RedisKeyPrefix + "booking_rate_ip:"
+	RedisKeyBookingRateEmail         = RedisKeyPrefix + "booking_rate_email:"
+	RedisKeyBookingChallenge         = RedisKeyPrefix + "booking_challenge:"
+	RedisKeyBookingGuestVerification = RedisKeyPrefix + "booking_guest_verification:"
 )
 
 const (
@@ -21,6 +27,8 @@ const (
 	DefaultAccessTokenExpiry        = 24 * time.Hour
 	DefaultRefreshTokenExpiry       = 7 * 24 * time.Hour
 	DefaultResetPasswordTokenExpiry = 5 * time.Minute
+	DefaultInvitationRSVPExpiry     = 30 * 24 * time.Hour
+	DefaultInvitationSignupExpiry   = 30 * time.Minute
 	DefaultOTPExpiration            = 5 * time.Minute
 	DefaultZeroExpiration           = 0
 )
@@ -30,6 +38,11 @@ const (
 	ScopeTokenRefresh           = "refresh"
 	ScopeTokenResetPassword     = "reset_password"
 	ScopeTokenEmailVerification = "email_verification"
+	ScopeTokenInvitationRSVP    = "invitation_rsvp"
+	ScopeTokenInvitationSignup  = "invitation_signup"
+	ScopeTokenBookingAccept     = "booking_accept"
+	ScopeTokenBookingDecline    = "booking_decline"
+	ScopeTokenWaitlistClaim     = "waitlist_claim"
 )
 
 // Giới hạn login
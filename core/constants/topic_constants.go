@@ -1,5 +1,14 @@
 package constants
 
 const (
-	TopicQueueEmailDelivery = "email_delivery"
+	TopicQueueEmailDelivery       = "email_delivery"
+	TopicQueueNotificationDigest  = "notification_digest"
+	TopicQueueWebhookDelivery     = "webhook_delivery"
+	TopicQueueCalendarSync        = "calendar_sync"
+	TopicQueueFocusTime           = "focus_time_planning"
+	TopicQueueBookingExpiry       = "booking_request_expiry"
+	TopicQueueBookingPayments     = "booking_payment_reconcile"
+	TopicQueueAttendancePrompts   = "attendance_prompts"
+	TopicQueueActionItemReminders = "action_item_reminders"
+	TopicQueueConnectionHealth    = "calendar_connection_health"
 )
@@ -0,0 +1,37 @@
+package constants
+
+// Webhook event types emitted to outbound webhook subscriptions
+const (
+	WebhookEventBookingRequested    = "booking.requested"
+	WebhookEventBookingAccepted     = "booking.accepted"
+	WebhookEventBookingDeclined     = "booking.declined"
+	WebhookEventBookingExpired      = "booking.expired"
+	WebhookEventBookingCancelled    = "booking.cancelled"
+	WebhookEventBookingPaid         = "booking.paid"
+	WebhookEventBookingRefunded     = "booking.refunded"
+	WebhookEventInvitationResponded = "invitation.responded"
+	WebhookEventMeetingCreated      = "meeting.created"
+	WebhookEventMeetingUpdated      = "meeting.updated"
+	WebhookEventMeetingScheduled    = "meeting.scheduled"
+	WebhookEventMeetingDeleted      = "meeting.deleted"
+	WebhookEventMeetingAttendance   = "meeting.attendance_marked"
+	WebhookEventOrderPlaced         = "order.placed"
+)
+
+// WebhookEventTypes lists every event type a subscription may register for
+var WebhookEventTypes = []string{
+	WebhookEventBookingRequested,
+	WebhookEventBookingAccepted,
+	WebhookEventBookingDeclined,
+	WebhookEventBookingExpired,
+	WebhookEventBookingCancelled,
+	WebhookEventBookingPaid,
+	WebhookEventBookingRefunded,
+	WebhookEventInvitationResponded,
+	WebhookEventMeetingCreated,
+	WebhookEventMeetingUpdated,
+	WebhookEventMeetingScheduled,
+	WebhookEventMeetingDeleted,
+	WebhookEventMeetingAttendance,
+	WebhookEventOrderPlaced,
+}
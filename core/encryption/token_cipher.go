@@ -0,0 +1,164 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Encrypted values look like enc:v1:<key id>:<wrapped data key>:<ciphertext>.
+// Every value gets its own random data key, sealed with AES-GCM under the key-encryption key (KEK)
+// named by the key ID. Values under a retired KEK stay readable while it is configured;
+// rotating re-encrypts them under the active KEK with a fresh data key.
+const (
+	tokenPrefix = "enc:v1:"
+	dataKeySize = 32
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,32}$`)
+
+var encoding = base64.RawURLEncoding
+
+// KeyRing holds the key-encryption keys; new values are always sealed with the active one
+type KeyRing struct {
+	keys     map[string][]byte
+	activeID string
+}
+
+// ParseKeys reads keys in the form "id1:base64key,id2:base64key". Keys must be 32 bytes (AES-256).
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key entry %q, expected <id>:<base64 key> with an id of letters, digits and dashes", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("key %s is not valid base64: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %s must be 32 bytes, got %d", id, len(key))
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("key %s is defined twice", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// NewKeyRing creates a key ring that encrypts with activeID and decrypts with any of keys
+func NewKeyRing(keys map[string][]byte, activeID string) (*KeyRing, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key %q is not one of the configured keys", activeID)
+	}
+	return &KeyRing{keys: keys, activeID: activeID}, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with
+func (k *KeyRing) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt seals plaintext under a fresh data key wrapped with the active key
+func (k *KeyRing) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.activeID], dataKey, []byte(k.activeID))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return tokenPrefix + k.activeID + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key it names
+func (k *KeyRing) Decrypt(value string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(value, tokenPrefix), ":")
+	if !IsEncrypted(value) || len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	kek, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("encrypted with unknown key %q", parts[0])
+	}
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	ciphertext, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	dataKey, err := open(kek, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	plaintext, err := open(dataKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, tokenPrefix)
+}
+
+// KeyID returns the ID of the key value is encrypted with, or "" for plaintext
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, tokenPrefix), ":")
+	return id
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
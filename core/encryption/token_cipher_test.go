@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestKeyRingRoundTrip(t *testing.T) {
+	ring, err := NewKeyRing(map[string][]byte{"k1": testKey(1)}, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		plaintext string
+	}{
+		{name: "access token", plaintext: "ya29.a0AfH6SMBx-example"},
+		{name: "empty", plaintext: ""},
+		{name: "unicode", plaintext: "mã thông báo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := ring.Encrypt(tt.plaintext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsEncrypted(encrypted) || KeyID(encrypted) != "k1" {
+				t.Fatalf("Encrypt() = %q, want an enc:v1 value under k1", encrypted)
+			}
+			if tt.plaintext != "" && strings.Contains(encrypted, tt.plaintext) {
+				t.Fatalf("Encrypt() leaks the plaintext: %q", encrypted)
+			}
+			decrypted, err := ring.Decrypt(encrypted)
+			if err != nil || decrypted != tt.plaintext {
+				t.Fatalf("Decrypt() = %q, %v; want %q", decrypted, err, tt.plaintext)
+			}
+		})
+	}
+
+	first, _ := ring.Encrypt("same")
+	second, _ := ring.Encrypt("same")
+	if first == second {
+		t.Error("encrypting the same value twice gave the same ciphertext")
+	}
+}
+
+func TestKeyRingRotate(t *testing.T) {
+	oldRing, err := NewKeyRing(map[string][]byte{"k1": testKey(1)}, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := oldRing.Encrypt("refresh-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After rotation both keys are configured and k2 is active
+	newRing, err := NewKeyRing(map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := newRing.Decrypt(encrypted); err != nil || got != "refresh-token" {
+		t.Fatalf("new ring Decrypt(old value) = %q, %v", got, err)
+	}
+	rotated, err := newRing.Encrypt("refresh-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if KeyID(rotated) != "k2" {
+		t.Fatalf("KeyID(rotated) = %q, want k2", KeyID(rotated))
+	}
+
+	// Once k1 is retired, only re-encrypted values still open
+	retired, err := NewKeyRing(map[string][]byte{"k2": testKey(2)}, "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retired.Decrypt(encrypted); err == nil {
+		t.Error("value under a retired key decrypted")
+	}
+	if got, err := retired.Decrypt(rotated); err != nil || got != "refresh-token" {
+		t.Errorf("Decrypt(rotated) = %q, %v", got, err)
+	}
+}
+
+func TestKeyRingRejectsTampering(t *testing.T) {
+	ring, _ := NewKeyRing(map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, "k1")
+	encrypted, _ := ring.Encrypt("secret")
+	parts := strings.Split(encrypted, ":")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "plaintext", value: "secret"},
+		{name: "missing part", value: strings.Join(parts[:4], ":")},
+		{name: "unknown key", value: strings.Join([]string{parts[0], parts[1], "k9", parts[3], parts[4]}, ":")},
+		// the key ID is authenticated with the wrapped data key, so relabelling it fails
+		{name: "relabelled key", value: strings.Join([]string{parts[0], parts[1], "k2", parts[3], parts[4]}, ":")},
+		{name: "ciphertext of another value", value: strings.Join(append(parts[:4:4], mustEncryptPart(t, ring)), ":")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ring.Decrypt(tt.value); err == nil {
+				t.Errorf("Decrypt(%q) = %q, want an error", tt.value, got)
+			}
+		})
+	}
+}
+
+func TestNewKeyRingRequiresActiveKey(t *testing.T) {
+	if _, err := NewKeyRing(map[string][]byte{"k1": testKey(1)}, "k2"); err == nil {
+		t.Error("NewKeyRing() with an unknown active key succeeded")
+	}
+}
+
+// mustEncryptPart returns the ciphertext part of a fresh value, sealed under a different data key
+func mustEncryptPart(t *testing.T, ring *KeyRing) string {
+	t.Helper()
+	other, err := ring.Encrypt("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(other, ":")
+	return parts[4]
+}
@@ -0,0 +1,91 @@
+package encryption
+
+import "errors"
+
+var tokenRing *KeyRing
+
+// Init sets the key ring OAuth tokens are encrypted with. Without one, tokens are stored as they are.
+func Init(ring *KeyRing) {
+	tokenRing = ring
+}
+
+// Enabled reports whether OAuth tokens are encrypted at rest
+func Enabled() bool {
+	return tokenRing != nil
+}
+
+// ActiveKeyID returns the ID of the key new tokens are encrypted with, or "" when encryption is off
+func ActiveKeyID() string {
+	if tokenRing == nil {
+		return ""
+	}
+	return tokenRing.activeID
+}
+
+// EncryptToken prepares an OAuth token for storage; empty tokens stay empty
+func EncryptToken(token string) (string, error) {
+	if token == "" || tokenRing == nil || IsEncrypted(token) {
+		return token, nil
+	}
+	return tokenRing.Encrypt(token)
+}
+
+// DecryptToken reads a stored OAuth token. Plaintext stored before encryption was enabled is returned as is.
+func DecryptToken(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if tokenRing == nil {
+		return "", errors.New("token is encrypted but no encryption keys are configured")
+	}
+	return tokenRing.Decrypt(value)
+}
+
+// EncryptTokenPtr is EncryptToken for nullable columns
+func EncryptTokenPtr(token *string) (*string, error) {
+	if token == nil {
+		return nil, nil
+	}
+	value, err := EncryptToken(*token)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// DecryptTokenPtr is DecryptToken for nullable columns
+func DecryptTokenPtr(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	token, err := DecryptToken(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// UpToDatePattern is a SQL LIKE pattern matching stored tokens that need no work:
+// any encrypted value when only migrating plaintext, values under the active key when rotating.
+func UpToDatePattern(rotate bool) string {
+	if rotate && tokenRing != nil {
+		return tokenPrefix + tokenRing.activeID + ":%"
+	}
+	return tokenPrefix + "%"
+}
+
+// ReencryptToken returns value encrypted under the active key. Plaintext is encrypted;
+// values under another key are re-encrypted only when rotating.
+func ReencryptToken(value string, rotate bool) (string, error) {
+	if tokenRing == nil {
+		return "", errors.New("no encryption keys are configured")
+	}
+	if value == "" || (IsEncrypted(value) && (!rotate || KeyID(value) == tokenRing.activeID)) {
+		return value, nil
+	}
+	token, err := DecryptToken(value)
+	if err != nil {
+		return "", err
+	}
+	return tokenRing.Encrypt(token)
+}
@@ -0,0 +1,43 @@
+package googlecalendar
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backoff is the wait before retry number attempt+1: exponential from initialDelay, capped at maxDelay,
+// with jitter so clients throttled together do not retry together
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.maxDelay
+	if attempt < 16 {
+		if d := c.initialDelay << attempt; d < c.maxDelay {
+			delay = d
+		}
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := at.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
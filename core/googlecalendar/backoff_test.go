@@ -0,0 +1,61 @@
+package googlecalendar
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		value     string
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{name: "empty", value: "", wantOK: false},
+		{name: "seconds", value: "30", wantDelay: 30 * time.Second, wantOK: true},
+		{name: "seconds with spaces", value: " 2 ", wantDelay: 2 * time.Second, wantOK: true},
+		{name: "zero seconds", value: "0", wantDelay: 0, wantOK: true},
+		{name: "negative seconds", value: "-5", wantOK: false},
+		{name: "http date in the future", value: now.Add(90 * time.Second).Format(http.TimeFormat), wantDelay: 90 * time.Second, wantOK: true},
+		{name: "http date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), wantDelay: 0, wantOK: true},
+		{name: "garbage", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || delay != tt.wantDelay {
+				t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, delay, ok, tt.wantDelay, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	client := &Client{initialDelay: 500 * time.Millisecond, maxDelay: 30 * time.Second}
+
+	tests := []struct {
+		attempt int
+		// the delay before jitter; backoff returns between half of it and all of it
+		base time.Duration
+	}{
+		{attempt: 0, base: 500 * time.Millisecond},
+		{attempt: 1, base: time.Second},
+		{attempt: 3, base: 4 * time.Second},
+		{attempt: 6, base: 30 * time.Second},
+		{attempt: 40, base: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			delay := client.backoff(tt.attempt)
+			if delay < tt.base/2 || delay > tt.base {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, delay, tt.base/2, tt.base)
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package googlecalendar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-starter/core/config"
+	"go-api-starter/core/logger"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBaseURL is the Google Calendar API v3 endpoint
+const DefaultBaseURL = "https://www.googleapis.com/calendar/v3"
+
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultMaxRetries    = 4
+	defaultRateLimit     = 50 // requests per second for the whole process
+	defaultUserRateLimit = 5  // requests per second per user
+	defaultInitialDelay  = 500 * time.Millisecond
+	defaultMaxDelay      = 30 * time.Second
+	// maxRetryAfter is the longest Retry-After the client waits for; a longer one fails the request
+	maxRetryAfter = time.Minute
+)
+
+// Config configures a Client. Zero values take the defaults.
+type Config struct {
+	BaseURL string
+	// RateLimit caps requests per second across all users, UserRateLimit per user
+	RateLimit     float64
+	UserRateLimit float64
+	// MaxRetries is how often a throttled or failed request is retried; negative disables retries
+	MaxRetries int
+	Timeout    time.Duration
+	// HTTPClient replaces the default client, e.g. to talk to the fake server
+	HTTPClient *http.Client
+}
+
+// Client calls the Google Calendar API. It waits for the per-user and global rate limits, retries
+// throttled (429, rate limit 403) and failed (5xx) requests with exponential backoff honouring
+// Retry-After, and records per-operation metrics.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	maxRetries   int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	limiter      *limiter
+	metrics      *Metrics
+}
+
+// Request is one Google Calendar API call
+type Request struct {
+	// UserID is whose quota the call counts against; uuid.Nil only counts against the global limit
+	UserID uuid.UUID
+	Method string
+	// Path is relative to the base URL, e.g. "/calendars/primary/events"; it may carry a query string
+	Path        string
+	AccessToken string
+	Body        []byte
+	// ETag is sent as If-Match
+	ETag string
+	// Idempotent allows retrying a POST after a server error (e.g. a freeBusy query).
+	// Other methods are always retried; a POST otherwise only when it was throttled.
+	Idempotent bool
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = defaultRateLimit
+	}
+	if cfg.UserRateLimit <= 0 {
+		cfg.UserRateLimit = defaultUserRateLimit
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &Client{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient:   httpClient,
+		maxRetries:   cfg.MaxRetries,
+		initialDelay: defaultInitialDelay,
+		maxDelay:     defaultMaxDelay,
+		limiter:      newLimiter(cfg.RateLimit, cfg.UserRateLimit),
+		metrics:      newMetrics(),
+	}
+}
+
+var (
+	defaultClient     *Client
+	defaultClientOnce sync.Once
+)
+
+// Init sets the client shared by every module
+func Init(cfg Config) {
+	defaultClient = NewClient(cfg)
+}
+
+// Default returns the shared client, built from the google_api configuration when Init was not called
+func Default() *Client {
+	if defaultClient != nil {
+		return defaultClient
+	}
+	defaultClientOnce.Do(func() {
+		if defaultClient == nil {
+			defaultClient = NewClient(ConfigFrom(config.Get().GoogleAPI))
+		}
+	})
+	return defaultClient
+}
+
+// ConfigFrom reads the client settings of the google_api configuration
+func ConfigFrom(cfg config.GoogleAPIConfig) Config {
+	return Config{
+		BaseURL:       cfg.CalendarBaseURL,
+		RateLimit:     cfg.CalendarRateLimit,
+		UserRateLimit: cfg.CalendarUserRateLimit,
+		MaxRetries:    cfg.CalendarMaxRetries,
+	}
+}
+
+// BaseURL is the API endpoint requests are sent to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Metrics returns the client's request metrics
+func (c *Client) Metrics() *Metrics {
+	return c.metrics
+}
+
+// Do sends the request, retrying it while Google throttles it or fails. The caller closes the
+// response body; when retries run out the last response is returned as is.
+func (c *Client) Do(ctx context.Context, req *Request) (*http.Response, error) {
+	operation := operationName(req.Method, req.Path)
+	started := time.Now()
+
+	for attempt := 0; ; attempt++ {
+		waited, err := c.limiter.wait(ctx, req.UserID)
+		if err != nil {
+			c.metrics.record(operation, 0, time.Since(started), attempt, waited, err)
+			return nil, err
+		}
+
+		resp, err := c.send(ctx, req)
+		retry, delay := false, time.Duration(0)
+		if ctx.Err() == nil {
+			retry, delay = c.shouldRetry(req, resp, err, attempt)
+		}
+		if !retry {
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			c.metrics.record(operation, status, time.Since(started), attempt, waited, err)
+			return resp, err
+		}
+
+		if resp != nil {
+			logger.Warn("GoogleCalendar:Retry", "operation", operation, "status", resp.StatusCode, "attempt", attempt+1, "delay", delay)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			logger.Warn("GoogleCalendar:Retry", "operation", operation, "error", err, "attempt", attempt+1, "delay", delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			c.metrics.record(operation, 0, time.Since(started), attempt+1, waited, ctx.Err())
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req *Request) (*http.Response, error) {
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, c.baseURL+req.Path, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+req.AccessToken)
+	if req.Body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if req.ETag != "" {
+		httpReq.Header.Set("If-Match", req.ETag)
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// shouldRetry decides whether a request is sent again and after how long
+func (c *Client) shouldRetry(req *Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= c.maxRetries {
+		return false, 0
+	}
+	idempotent := req.Method != http.MethodPost || req.Idempotent
+
+	if err != nil {
+		// A request cut off mid-way may have been applied; only resend what is safe to repeat
+		if !idempotent {
+			return false, 0
+		}
+		return true, c.backoff(attempt)
+	}
+
+	throttled := resp.StatusCode == http.StatusTooManyRequests || isRateLimitForbidden(resp)
+	failed := resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented
+	if !throttled && !(failed && idempotent) {
+		return false, 0
+	}
+
+	delay := c.backoff(attempt)
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		if retryAfter > maxRetryAfter {
+			return false, 0
+		}
+		delay = retryAfter
+	}
+	return true, delay
+}
+
+// isRateLimitForbidden reports whether a 403 is Google's usage limit error rather than a permission error.
+// It reads the body and puts it back for the caller.
+func isRateLimitForbidden(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	text := string(body)
+	return strings.Contains(text, "rateLimitExceeded") || strings.Contains(text, "userRateLimitExceeded")
+}
+
+// operationName is the metrics label of a request: its method and path with IDs replaced,
+// e.g. "PATCH /calendars/{calendarId}/events/{eventId}"
+func operationName(method, path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		switch segments[i-1] {
+		case "calendars":
+			segments[i] = "{calendarId}"
+		case "events":
+			segments[i] = "{eventId}"
+		}
+	}
+	return fmt.Sprintf("%s /%s", method, strings.Join(segments, "/"))
+}
+
+// EventPath is the path of an event of the user's primary calendar
+func EventPath(eventID string) string {
+	return "/calendars/primary/events/" + url.PathEscape(eventID)
+}
@@ -0,0 +1,304 @@
+package googlecalendar_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api-starter/core/googlecalendar"
+	"go-api-starter/core/googlecalendar/fake"
+
+	"github.com/google/uuid"
+)
+
+const (
+	aliceToken = "token-alice"
+	aliceEmail = "alice@example.com"
+	bobToken   = "token-bob"
+	bobEmail   = "bob@example.com"
+)
+
+func newTestClient(t *testing.T, maxRetries int) (*googlecalendar.Client, *fake.Server) {
+	t.Helper()
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+	server.AddUser(aliceToken, aliceEmail)
+	server.AddUser(bobToken, bobEmail)
+
+	cfg := server.Config()
+	cfg.MaxRetries = maxRetries
+	return googlecalendar.NewClient(cfg), server
+}
+
+func do(t *testing.T, client *googlecalendar.Client, req *googlecalendar.Request) (int, map[string]interface{}) {
+	t.Helper()
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do(%s %s) error: %v", req.Method, req.Path, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var decoded map[string]interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Fatalf("Do(%s %s) returned invalid JSON: %s", req.Method, req.Path, body)
+		}
+	}
+	return resp.StatusCode, decoded
+}
+
+func mustJSON(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func eventBody(t *testing.T, summary string, start, end time.Time, attendees ...string) []byte {
+	t.Helper()
+	data := map[string]interface{}{
+		"summary": summary,
+		"start":   map[string]string{"dateTime": start.Format(time.RFC3339)},
+		"end":     map[string]string{"dateTime": end.Format(time.RFC3339)},
+	}
+	var list []map[string]string
+	for _, email := range attendees {
+		list = append(list, map[string]string{"email": email})
+	}
+	if len(list) > 0 {
+		data["attendees"] = list
+	}
+	return mustJSON(t, data)
+}
+
+func TestDoRetriesTooManyRequestsAfterRetryAfter(t *testing.T) {
+	client, server := newTestClient(t, 2)
+	server.FailNext(1, http.StatusTooManyRequests, "1")
+
+	started := time.Now()
+	status, _ := do(t, client, &googlecalendar.Request{
+		UserID:      uuid.New(),
+		Method:      http.MethodGet,
+		Path:        "/calendars/primary/events",
+		AccessToken: aliceToken,
+	})
+
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if got := server.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+
+	stats := client.Metrics().Snapshot()
+	if len(stats) != 1 || stats[0].Operation != "GET /calendars/{calendarId}/events" || stats[0].Retries != 1 || stats[0].Statuses[http.StatusOK] != 1 {
+		t.Errorf("metrics = %+v, want one GET events request with 1 retry and a 200", stats)
+	}
+}
+
+func TestDoGivesUpWhenRetryAfterIsTooLong(t *testing.T) {
+	client, server := newTestClient(t, 2)
+	server.FailNext(1, http.StatusTooManyRequests, "120")
+
+	status, _ := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodGet,
+		Path:        "/calendars/primary/events",
+		AccessToken: aliceToken,
+	})
+
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", status)
+	}
+	if got := server.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestDoRetriesRateLimitForbidden(t *testing.T) {
+	client, server := newTestClient(t, 2)
+	server.FailNext(1, http.StatusForbidden, "")
+
+	status, _ := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodGet,
+		Path:        "/calendars/primary/events",
+		AccessToken: aliceToken,
+	})
+
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if got := server.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestDoRetriesServerErrorsOnlyWhenIdempotent(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+
+	tests := []struct {
+		name         string
+		req          *googlecalendar.Request
+		wantStatus   int
+		wantRequests int
+	}{
+		{
+			name: "insert is not retried",
+			req: &googlecalendar.Request{
+				Method:      http.MethodPost,
+				Path:        "/calendars/primary/events",
+				AccessToken: aliceToken,
+				Body:        eventBody(t, "Planning", now, now.Add(time.Hour)),
+			},
+			wantStatus:   http.StatusInternalServerError,
+			wantRequests: 1,
+		},
+		{
+			name: "idempotent freeBusy query is retried",
+			req: &googlecalendar.Request{
+				Method:      http.MethodPost,
+				Path:        "/freeBusy",
+				AccessToken: aliceToken,
+				Body: mustJSON(t, map[string]interface{}{
+					"timeMin": now.Format(time.RFC3339),
+					"timeMax": now.Add(time.Hour).Format(time.RFC3339),
+					"items":   []map[string]string{{"id": aliceEmail}},
+				}),
+				Idempotent: true,
+			},
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name: "get is retried",
+			req: &googlecalendar.Request{
+				Method:      http.MethodGet,
+				Path:        "/calendars/primary/events",
+				AccessToken: aliceToken,
+			},
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t, 2)
+			server.FailNext(1, http.StatusInternalServerError, "")
+
+			status, _ := do(t, client, tt.req)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got := server.Requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDoStopsAfterMaxRetries(t *testing.T) {
+	client, server := newTestClient(t, 1)
+	server.FailNext(3, http.StatusServiceUnavailable, "0")
+
+	status, _ := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodGet,
+		Path:        "/calendars/primary/events",
+		AccessToken: aliceToken,
+	})
+
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", status)
+	}
+	if got := server.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2 (one retry)", got)
+	}
+}
+
+func TestSchedulingFlow(t *testing.T) {
+	client, server := newTestClient(t, 2)
+	day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+	busyStart, busyEnd := day.Add(10*time.Hour), day.Add(11*time.Hour)
+	server.AddEvent(bobEmail, map[string]interface{}{
+		"summary": "Bob's 1:1",
+		"start":   map[string]string{"dateTime": busyStart.Format(time.RFC3339)},
+		"end":     map[string]string{"dateTime": busyEnd.Format(time.RFC3339)},
+	})
+
+	// 1. Alice checks when Bob is busy
+	status, freeBusy := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodPost,
+		Path:        "/freeBusy",
+		AccessToken: aliceToken,
+		Body: mustJSON(t, map[string]interface{}{
+			"timeMin": day.Add(9 * time.Hour).Format(time.RFC3339),
+			"timeMax": day.Add(17 * time.Hour).Format(time.RFC3339),
+			"items":   []map[string]string{{"id": bobEmail}},
+		}),
+		Idempotent: true,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("freeBusy status = %d, want 200", status)
+	}
+	busy := freeBusy["calendars"].(map[string]interface{})[bobEmail].(map[string]interface{})["busy"].([]interface{})
+	if len(busy) != 1 || busy[0].(map[string]interface{})["start"] != busyStart.Format(time.RFC3339) {
+		t.Fatalf("busy = %v, want Bob's 10:00-11:00 event", busy)
+	}
+
+	// 2. She books the free hour after it with Bob as attendee
+	status, created := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodPost,
+		Path:        "/calendars/primary/events",
+		AccessToken: aliceToken,
+		Body:        eventBody(t, "Design review", busyEnd, busyEnd.Add(time.Hour), bobEmail),
+	})
+	if status != http.StatusOK {
+		t.Fatalf("insert status = %d, want 200", status)
+	}
+	eventID, _ := created["id"].(string)
+	etag, _ := created["etag"].(string)
+	if eventID == "" || etag == "" {
+		t.Fatalf("created event = %v, want an id and etag", created)
+	}
+
+	// 3. She renames it, guarded by the etag she got
+	status, patched := do(t, client, &googlecalendar.Request{
+		Method:      http.MethodPatch,
+		Path:        googlecalendar.EventPath(eventID),
+		AccessToken: aliceToken,
+		Body:        mustJSON(t, map[string]string{"summary": "Design review (v2)"}),
+		ETag:        etag,
+	})
+	if status != http.StatusOK || patched["summary"] != "Design review (v2)" {
+		t.Fatalf("patch = %d %v, want 200 with the new summary", status, patched)
+	}
+
+	// 4. A second patch with the old etag loses the race
+	status, _ = do(t, client, &googlecalendar.Request{
+		Method:      http.MethodPatch,
+		Path:        googlecalendar.EventPath(eventID),
+		AccessToken: aliceToken,
+		Body:        mustJSON(t, map[string]string{"summary": "Stale"}),
+		ETag:        etag,
+	})
+	if status != http.StatusPreconditionFailed {
+		t.Errorf("stale patch status = %d, want 412", status)
+	}
+
+	// Bob now sees both events, the review with its new name
+	var summaries []string
+	for _, ev := range server.Events(bobEmail) {
+		summaries = append(summaries, ev["summary"].(string))
+	}
+	if len(summaries) != 2 || summaries[1] != "Design review (v2)" {
+		t.Errorf("Bob's events = %v, want his 1:1 and the renamed review", summaries)
+	}
+}
@@ -0,0 +1,519 @@
+// Package fake is an in-process Google Calendar API server for running the scheduling flows offline.
+//
+//	server := fake.NewServer()
+//	defer server.Close()
+//	server.AddUser("token-alice", "alice@example.com")
+//	googlecalendar.Init(server.Config())
+//
+// It keeps events in memory and implements the calls the app makes: calendarList, freeBusy and
+// events list (with sync tokens), insert, get, patch, update and delete (with If-Match). An event
+// shows up on the calendars of its organizer and of attendees that are known users. FailNext makes
+// the next requests fail, to exercise the client's retries.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-starter/core/googlecalendar"
+)
+
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	users    map[string]string // access token -> calendar email
+	events   map[string]*event
+	order    []string // event IDs in insertion order
+	sequence int64
+	nextID   int64
+	failures []failure
+	requests int
+}
+
+type event struct {
+	data      map[string]interface{}
+	organizer string
+	sequence  int64
+	cancelled bool
+	// removed holds attendees who deleted the event from their calendar
+	removed map[string]bool
+}
+
+type failure struct {
+	status     int
+	retryAfter string
+}
+
+// NewServer starts a fake server on a local port
+func NewServer() *Server {
+	s := &Server{
+		users:  make(map[string]string),
+		events: make(map[string]*event),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL is the base URL of the fake API
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Config is a client configuration that talks to the fake server, with rate limits high enough not to slow it down
+func (s *Server) Config() googlecalendar.Config {
+	return googlecalendar.Config{
+		BaseURL:       s.server.URL,
+		RateLimit:     1000,
+		UserRateLimit: 1000,
+		HTTPClient:    s.server.Client(),
+	}
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddUser lets requests with accessToken act as the owner of the calendar email
+func (s *Server) AddUser(accessToken, email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[accessToken] = strings.ToLower(email)
+}
+
+// AddEvent puts an event on the calendar of organizer, e.g. to make the user busy, and returns its ID.
+// data follows the Google event resource: summary, start, end, attendees, transparency...
+func (s *Server) AddEvent(organizer string, data map[string]interface{}) string {
+	// Round-trip through JSON so the event has the shape a request body decodes to
+	encoded, _ := json.Marshal(data)
+	var decoded map[string]interface{}
+	json.Unmarshal(encoded, &decoded)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(strings.ToLower(organizer), decoded)
+}
+
+// Events returns the events on the calendar of email, cancelled ones included
+func (s *Server) Events(email string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email = strings.ToLower(email)
+	var events []map[string]interface{}
+	for _, id := range s.order {
+		if ev := s.events[id]; ev.visibleTo(email) {
+			events = append(events, ev.resource(email))
+		}
+	}
+	return events
+}
+
+// FailNext answers the next count requests with status (e.g. 429 or 503) and, when not empty,
+// a Retry-After header
+func (s *Server) FailNext(count int, status int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, failure{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests is how many requests the server received
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		reason := "backendError"
+		if f.status == http.StatusTooManyRequests || f.status == http.StatusForbidden {
+			reason = "rateLimitExceeded"
+		}
+		writeError(w, f.status, reason)
+		return
+	}
+
+	email, ok := s.users[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authError")
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && len(path) == 3 && path[0] == "users" && path[2] == "calendarList":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"items": []map[string]interface{}{{"id": email, "summary": email, "primary": true, "accessRole": "owner"}},
+		})
+	case r.Method == http.MethodPost && len(path) == 1 && path[0] == "freeBusy":
+		s.freeBusy(w, r)
+	case len(path) >= 3 && path[0] == "calendars" && path[2] == "events":
+		calendar := path[1]
+		if calendar == "primary" {
+			calendar = email
+		}
+		if strings.ToLower(calendar) != email {
+			writeError(w, http.StatusNotFound, "notFound")
+			return
+		}
+		if len(path) == 3 {
+			switch r.Method {
+			case http.MethodGet:
+				s.listEvents(w, r, email)
+			case http.MethodPost:
+				s.insertEvent(w, r, email)
+			default:
+				writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed")
+			}
+			return
+		}
+		s.eventByID(w, r, email, path[3])
+	default:
+		writeError(w, http.StatusNotFound, "notFound")
+	}
+}
+
+func (s *Server) freeBusy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TimeMin string `json:"timeMin"`
+		TimeMax string `json:"timeMax"`
+		Items   []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError")
+		return
+	}
+	from, err1 := time.Parse(time.RFC3339, req.TimeMin)
+	to, err2 := time.Parse(time.RFC3339, req.TimeMax)
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "invalid")
+		return
+	}
+
+	calendars := make(map[string]interface{})
+	for _, item := range req.Items {
+		email := strings.ToLower(item.ID)
+		busy := []map[string]string{}
+		for _, id := range s.order {
+			ev := s.events[id]
+			if ev.cancelled || !ev.visibleTo(email) || ev.data["transparency"] == "transparent" || ev.declinedBy(email) {
+				continue
+			}
+			start, end, ok := ev.times()
+			if !ok || !start.Before(to) || !end.After(from) {
+				continue
+			}
+			busy = append(busy, map[string]string{"start": start.UTC().Format(time.RFC3339), "end": end.UTC().Format(time.RFC3339)})
+		}
+		calendars[item.ID] = map[string]interface{}{"busy": busy}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":      "calendar#freeBusy",
+		"timeMin":   req.TimeMin,
+		"timeMax":   req.TimeMax,
+		"calendars": calendars,
+	})
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request, email string) {
+	query := r.URL.Query()
+	showDeleted := query.Get("showDeleted") == "true"
+
+	var since int64 = -1
+	if token := query.Get("syncToken"); token != "" {
+		seq, err := strconv.ParseInt(strings.TrimPrefix(token, "sync-"), 10, 64)
+		if err != nil || seq > s.sequence {
+			writeError(w, http.StatusGone, "fullSyncRequired")
+			return
+		}
+		since = seq
+		showDeleted = true
+	}
+	from, _ := time.Parse(time.RFC3339, query.Get("timeMin"))
+	to, _ := time.Parse(time.RFC3339, query.Get("timeMax"))
+
+	var items []map[string]interface{}
+	for _, id := range s.order {
+		ev := s.events[id]
+		if !ev.visibleTo(email) && !(since >= 0 && ev.removed[email]) {
+			continue
+		}
+		if since >= 0 && ev.sequence <= since {
+			continue
+		}
+		removed := ev.cancelled || ev.removed[email]
+		if removed && !showDeleted {
+			continue
+		}
+		if start, end, ok := ev.times(); ok && since < 0 {
+			if (!from.IsZero() && !end.After(from)) || (!to.IsZero() && !start.Before(to)) {
+				continue
+			}
+		}
+		resource := ev.resource(email)
+		if removed {
+			resource["status"] = "cancelled"
+		}
+		items = append(items, resource)
+	}
+	if query.Get("orderBy") == "startTime" {
+		sort.SliceStable(items, func(i, j int) bool {
+			return eventStart(items[i]).Before(eventStart(items[j]))
+		})
+	}
+
+	offset, _ := strconv.Atoi(query.Get("pageToken"))
+	if offset > len(items) {
+		offset = len(items)
+	}
+	limit := 250
+	if max, err := strconv.Atoi(query.Get("maxResults")); err == nil && max > 0 {
+		limit = max
+	}
+	response := map[string]interface{}{"kind": "calendar#events"}
+	if end := offset + limit; end < len(items) {
+		response["items"] = items[offset:end]
+		response["nextPageToken"] = strconv.Itoa(end)
+	} else {
+		response["items"] = items[offset:]
+		response["nextSyncToken"] = fmt.Sprintf("sync-%d", s.sequence)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) insertEvent(w http.ResponseWriter, r *http.Request, email string) {
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "parseError")
+		return
+	}
+	if _, _, ok := (&event{data: data}).times(); !ok {
+		writeError(w, http.StatusBadRequest, "invalid")
+		return
+	}
+	id := s.insert(email, data)
+	writeJSON(w, http.StatusOK, s.events[id].resource(email))
+}
+
+func (s *Server) eventByID(w http.ResponseWriter, r *http.Request, email, id string) {
+	ev, ok := s.events[id]
+	if !ok || !ev.visibleTo(email) {
+		writeError(w, http.StatusNotFound, "notFound")
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != ev.data["etag"] {
+		writeError(w, http.StatusPreconditionFailed, "conditionNotMet")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if ev.cancelled {
+			writeError(w, http.StatusGone, "deleted")
+			return
+		}
+		writeJSON(w, http.StatusOK, ev.resource(email))
+	case http.MethodPatch, http.MethodPut:
+		if ev.cancelled {
+			writeError(w, http.StatusGone, "deleted")
+			return
+		}
+		var changes map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			writeError(w, http.StatusBadRequest, "parseError")
+			return
+		}
+		if r.Method == http.MethodPut {
+			ev.data = map[string]interface{}{"id": ev.data["id"], "created": ev.data["created"], "organizer": ev.data["organizer"]}
+		}
+		for key, value := range changes {
+			switch key {
+			case "id", "etag", "created", "updated", "organizer", "htmlLink":
+			default:
+				ev.data[key] = value
+			}
+		}
+		s.touch(ev)
+		writeJSON(w, http.StatusOK, ev.resource(email))
+	case http.MethodDelete:
+		if ev.cancelled {
+			writeError(w, http.StatusGone, "deleted")
+			return
+		}
+		if email == ev.organizer {
+			ev.cancelled = true
+			ev.data["status"] = "cancelled"
+		} else {
+			ev.removed[email] = true
+		}
+		s.touch(ev)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed")
+	}
+}
+
+func (s *Server) insert(organizer string, data map[string]interface{}) string {
+	s.nextID++
+	id := fmt.Sprintf("fake%06d", s.nextID)
+	now := time.Now().UTC().Format(time.RFC3339)
+	data["id"] = id
+	data["created"] = now
+	data["organizer"] = map[string]interface{}{"email": organizer}
+	data["htmlLink"] = "https://calendar.google.com/calendar/event?eid=" + id
+	if _, ok := data["status"]; !ok {
+		data["status"] = "confirmed"
+	}
+	if _, ok := data["conferenceData"]; ok {
+		data["hangoutLink"] = "https://meet.google.com/" + id
+	}
+
+	ev := &event{data: data, organizer: organizer, removed: make(map[string]bool)}
+	s.events[id] = ev
+	s.order = append(s.order, id)
+	s.touch(ev)
+	return id
+}
+
+// touch records a change: a new etag, updated time and sync sequence
+func (s *Server) touch(ev *event) {
+	s.sequence++
+	ev.sequence = s.sequence
+	ev.data["etag"] = fmt.Sprintf("\"%d\"", s.sequence)
+	ev.data["updated"] = time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func (ev *event) visibleTo(email string) bool {
+	if email == ev.organizer {
+		return true
+	}
+	if ev.removed[email] {
+		return false
+	}
+	for _, attendee := range ev.attendees() {
+		if strings.EqualFold(fmt.Sprint(attendee["email"]), email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ev *event) declinedBy(email string) bool {
+	for _, attendee := range ev.attendees() {
+		if strings.EqualFold(fmt.Sprint(attendee["email"]), email) {
+			return attendee["responseStatus"] == "declined"
+		}
+	}
+	return false
+}
+
+func (ev *event) attendees() []map[string]interface{} {
+	list, _ := ev.data["attendees"].([]interface{})
+	attendees := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if attendee, ok := item.(map[string]interface{}); ok {
+			attendees = append(attendees, attendee)
+		}
+	}
+	return attendees
+}
+
+func (ev *event) times() (time.Time, time.Time, bool) {
+	start, ok1 := parseEventTime(ev.data["start"])
+	end, ok2 := parseEventTime(ev.data["end"])
+	return start, end, ok1 && ok2
+}
+
+// resource is the event as the calendar of email sees it, with the self flags set
+func (ev *event) resource(email string) map[string]interface{} {
+	resource := copyMap(ev.data)
+	resource["kind"] = "calendar#event"
+	resource["organizer"] = map[string]interface{}{"email": ev.organizer, "self": ev.organizer == email}
+	if list, ok := resource["attendees"].([]interface{}); ok {
+		attendees := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			attendee, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			attendee = copyMap(attendee)
+			if strings.EqualFold(fmt.Sprint(attendee["email"]), email) {
+				attendee["self"] = true
+			}
+			if _, ok := attendee["responseStatus"]; !ok {
+				attendee["responseStatus"] = "needsAction"
+			}
+			attendees = append(attendees, attendee)
+		}
+		resource["attendees"] = attendees
+	}
+	return resource
+}
+
+func parseEventTime(value interface{}) (time.Time, bool) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return time.Time{}, false
+	}
+	if dateTime, ok := fields["dateTime"].(string); ok && dateTime != "" {
+		t, err := time.Parse(time.RFC3339, dateTime)
+		return t, err == nil
+	}
+	if date, ok := fields["date"].(string); ok && date != "" {
+		t, err := time.Parse("2006-01-02", date)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+func eventStart(resource map[string]interface{}) time.Time {
+	start, _ := parseEventTime(resource["start"])
+	return start
+}
+
+// copyMap copies the top level of an event resource; nested values are replaced, never changed in place
+func copyMap(data map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		copied[key] = value
+	}
+	return copied
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError answers in Google's error format
+func writeError(w http.ResponseWriter, status int, reason string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": http.StatusText(status),
+			"errors":  []map[string]interface{}{{"domain": "global", "reason": reason, "message": http.StatusText(status)}},
+		},
+	})
+}
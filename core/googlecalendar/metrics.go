@@ -0,0 +1,79 @@
+package googlecalendar
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// throttledAfter is the rate limiter wait from which a request counts as throttled
+const throttledAfter = 10 * time.Millisecond
+
+// Metrics counts the client's requests per operation
+type Metrics struct {
+	mu         sync.Mutex
+	operations map[string]*OperationStats
+}
+
+// OperationStats are the counters of one operation, e.g. "GET /calendars/{calendarId}/events"
+type OperationStats struct {
+	Operation string `json:"operation"`
+	Requests  int64  `json:"requests"`
+	// Retries counts resent attempts; Throttled counts requests the rate limiter held back
+	Retries   int64 `json:"retries"`
+	Throttled int64 `json:"throttled"`
+	// Errors counts requests that got no response (network error, timeout, cancelled)
+	Errors int64 `json:"errors"`
+	// Statuses counts final responses by status code
+	Statuses       map[int]int64 `json:"statuses"`
+	TotalLatencyMs int64         `json:"total_latency_ms"`
+	MaxLatencyMs   int64         `json:"max_latency_ms"`
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{operations: make(map[string]*OperationStats)}
+}
+
+func (m *Metrics) record(operation string, status int, latency time.Duration, retries int, waited time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.operations[operation]
+	if !ok {
+		stats = &OperationStats{Operation: operation, Statuses: make(map[int]int64)}
+		m.operations[operation] = stats
+	}
+	stats.Requests++
+	stats.Retries += int64(retries)
+	if waited >= throttledAfter {
+		stats.Throttled++
+	}
+	if err != nil {
+		stats.Errors++
+	} else {
+		stats.Statuses[status]++
+	}
+	ms := latency.Milliseconds()
+	stats.TotalLatencyMs += ms
+	if ms > stats.MaxLatencyMs {
+		stats.MaxLatencyMs = ms
+	}
+}
+
+// Snapshot returns a copy of the counters, sorted by operation
+func (m *Metrics) Snapshot() []OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]OperationStats, 0, len(m.operations))
+	for _, stats := range m.operations {
+		copied := *stats
+		copied.Statuses = make(map[int]int64, len(stats.Statuses))
+		for status, count := range stats.Statuses {
+			copied.Statuses[status] = count
+		}
+		snapshot = append(snapshot, copied)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Operation < snapshot[j].Operation })
+	return snapshot
+}
@@ -0,0 +1,83 @@
+package googlecalendar
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// userLimiterIdle is how long an unused per-user limiter is kept
+const userLimiterIdle = 10 * time.Minute
+
+// limiter spaces requests out under a global and a per-user rate, each with a burst of twice the rate per second
+type limiter struct {
+	global    *rate.Limiter
+	userRate  rate.Limit
+	userBurst int
+
+	mu        sync.Mutex
+	users     map[uuid.UUID]*userLimiter
+	lastPrune time.Time
+}
+
+type userLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func newLimiter(globalRate, userRate float64) *limiter {
+	return &limiter{
+		global:    rate.NewLimiter(rate.Limit(globalRate), burstFor(globalRate)),
+		userRate:  rate.Limit(userRate),
+		userBurst: burstFor(userRate),
+		users:     make(map[uuid.UUID]*userLimiter),
+		lastPrune: time.Now(),
+	}
+}
+
+// wait blocks until the request may be sent and returns how long it waited
+func (l *limiter) wait(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	started := time.Now()
+	if userID != uuid.Nil {
+		if err := l.forUser(userID).Wait(ctx); err != nil {
+			return time.Since(started), err
+		}
+	}
+	if err := l.global.Wait(ctx); err != nil {
+		return time.Since(started), err
+	}
+	return time.Since(started), nil
+}
+
+func (l *limiter) forUser(userID uuid.UUID) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > userLimiterIdle {
+		for id, user := range l.users {
+			if now.Sub(user.lastUsed) > userLimiterIdle {
+				delete(l.users, id)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	user, ok := l.users[userID]
+	if !ok {
+		user = &userLimiter{limiter: rate.NewLimiter(l.userRate, l.userBurst)}
+		l.users[userID] = user
+	}
+	user.lastUsed = now
+	return user.limiter
+}
+
+func burstFor(perSecond float64) int {
+	if burst := int(perSecond * 2); burst > 1 {
+		return burst
+	}
+	return 1
+}
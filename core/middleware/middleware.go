@@ -47,6 +47,11 @@ func (m *Middleware) AuthMiddleware() echo.MiddlewareFunc {
 				return m.Unauthorized(errors.ErrInvalidTokenFormat, "invalid token: "+err.Error())
 			}
 
+			// Only access tokens sign a user in; RSVP, approval, claim and reset links are signed the same way
+			if !utils.ValidateTokenScope(claims, constants.ScopeTokenAccess) {
+				return m.Unauthorized(errors.ErrUnauthorized, "token is not an access token")
+			}
+
 			// Set user claims in context
 			c.Set(constants.ContextTokenData, claims)
 			return next(c)
@@ -7,6 +7,7 @@ import (
 	"go-api-starter/core/cache"
 	"go-api-starter/core/config"
 	"go-api-starter/core/database"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/logger"
 	"go-api-starter/core/middleware"
 
@@ -19,11 +20,14 @@ import (
 	"go-api-starter/modules/meeting"
 	"go-api-starter/modules/notification"
 	"go-api-starter/modules/product"
+	"go-api-starter/modules/webhook"
 
 	// "go-api-starter/modules/storage"
 	"go-api-starter/workers"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -37,8 +41,12 @@ type Server struct {
 	addr  string
 	cache *cache.Cache
 	db    database.Database
+	task  string
 }
 
+// tokenKeysTask runs OAuth token key maintenance instead of the HTTP server (see runTokenKeysTask)
+var tokenKeysTask = flag.String("token-keys", "", "Re-encrypt stored OAuth tokens and exit: migrate (plaintext only) or rotate (also retired keys)")
+
 func initEnvironment() (config.Environment, error) {
 	env := flag.String("env", "dev", "Environment (dev/prod)")
 	flag.Parse()
@@ -98,6 +106,17 @@ func initServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// Encrypt OAuth tokens at rest
+	if err = initTokenEncryption(cfg.TokenEncryption); err != nil {
+		logger.Error("Failed to initialize token encryption", "error", err)
+		return nil, fmt.Errorf("failed to initialize token encryption: %w", err)
+	}
+
+	// One-off token maintenance does not need the rest of the server
+	if *tokenKeysTask != "" {
+		return &Server{db: db, task: *tokenKeysTask}, nil
+	}
+
 	// Initialize Redis cache
 	redisCache := cache.NewCache(
 		cfg.Redis.Address,
@@ -116,6 +135,9 @@ func initServer() (*Server, error) {
 	}
 	utils.InitEmailConfig(emailConfig)
 
+	// Initialize the Google Calendar API client shared by every module
+	googlecalendar.Init(googlecalendar.ConfigFrom(cfg.GoogleAPI))
+
 	// Initialize R2 client
 	// r2Client, err := storageClient.NewS3Client(cfg)
 	// if err != nil {
@@ -137,6 +159,13 @@ func initServer() (*Server, error) {
 	// )
 
 	e := echo.New()
+	// c.RealIP() keys the per-IP limits of the public booking endpoints, so it must not come from a header the client controls
+	if extractor := ipExtractor(cfg.Server.TrustedProxies); extractor != nil {
+		e.IPExtractor = extractor
+	} else {
+		logger.Warn("Server:TrustedProxies:NotConfigured",
+			"message", "client IPs are read from X-Forwarded-For/X-Real-IP as sent; set APP_SERVER_TRUSTED_PROXIES to the reverse proxy CIDRs")
+	}
 
 	// Middleware
 	e.Use(echo_middleware.Recover())
@@ -146,10 +175,12 @@ func initServer() (*Server, error) {
 	// Swagger API documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// Initialize Webhook module first so other modules can emit events
+	webhookService := webhook.Init(e, db, *redisCache)
+
 	// Initialize modules
-	product.Init(e, db, *redisCache)
+	product.Init(e, db, *redisCache, webhookService)
 	// storage.Init(e, db, r2Client, *redisCache)
-	auth.Init(e, db, *redisCache)
 
 	// Initialize common middleware for Notification
 	mw := middleware.NewMiddleware(nil)
@@ -158,14 +189,18 @@ func initServer() (*Server, error) {
 	notifService := notification.Init(e.Group("/api/v1/private"), db, mw)
 
 	// Initialize Invitation module
-	invitationService := invitation.Init(e.Group("/api/v1/private"), db, mw, notifService)
+	invitationService := invitation.Init(e, db, mw, notifService, webhookService)
+
+	// Auth attaches email invitations to users once they register or sign in with that address
+	auth.Init(e, db, *redisCache, invitationService)
 
 	calendar.Init(e, db, *redisCache, notifService, invitationService)
-	booking.Init(e, db, *redisCache, notifService, invitationService)
-	meeting.Init(e, db, mw)
+	booking.Init(e, db, *redisCache, notifService, invitationService, webhookService)
+	meeting.Init(e, db, mw, notifService, webhookService)
 
 	// Initialize Asynq worker server
 	workers.NewServer()
+	workers.NewScheduler()
 
 	return &Server{
 		echo:  e,
@@ -182,6 +217,9 @@ func Run() error {
 		fmt.Printf("Failed to initialize server: %v\n", err)
 		return err
 	}
+	if srv.task != "" {
+		return srv.runTokenKeysTask()
+	}
 	return srv.start()
 }
 
@@ -213,3 +251,27 @@ func (s *Server) start() error {
 	logger.Info("Server shutdown complete")
 	return nil
 }
+
+// ipExtractor returns the X-Forwarded-For entry added by the first trusted proxy as the client IP, with Echo's
+// default trust of private ranges turned off. It returns nil when trustedProxies lists none, leaving Echo's
+// default, which believes the forwarding headers of any client.
+func ipExtractor(trustedProxies string) echo.IPExtractor {
+	var options []echo.TrustOption
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Warn("Server:TrustedProxies:InvalidCIDR", "cidr", cidr, "error", err)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	options = append(options, echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false))
+	return echo.ExtractIPFromXFFHeader(options...)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"go-api-starter/core/config"
+	"go-api-starter/core/encryption"
+	"go-api-starter/core/logger"
+	authRepository "go-api-starter/modules/auth/repository"
+	calRepository "go-api-starter/modules/calendar/repository"
+)
+
+// initTokenEncryption loads the key ring OAuth tokens are encrypted with; without keys tokens stay in plaintext
+func initTokenEncryption(cfg config.TokenEncryptionConfig) error {
+	if cfg.Keys == "" {
+		logger.Warn("Token encryption is not configured, OAuth tokens are stored in plaintext")
+		return nil
+	}
+	keys, err := encryption.ParseKeys(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	ring, err := encryption.NewKeyRing(keys, cfg.ActiveKey)
+	if err != nil {
+		return err
+	}
+	encryption.Init(ring)
+	return nil
+}
+
+// runTokenKeysTask re-encrypts the stored OAuth tokens and exits.
+//
+//	-token-keys migrate  encrypts tokens stored before encryption was enabled (run once after enabling it)
+//	-token-keys rotate   also re-encrypts tokens under retired keys with the active key
+//
+// A retired key can be removed from the configuration once a rotate run logs no skipped rows.
+func (s *Server) runTokenKeysTask() error {
+	var rotate bool
+	switch s.task {
+	case "migrate":
+	case "rotate":
+		rotate = true
+	default:
+		return fmt.Errorf("invalid -token-keys task %q, use migrate or rotate", s.task)
+	}
+	if !encryption.Enabled() {
+		return fmt.Errorf("token encryption keys are not configured")
+	}
+
+	ctx := context.Background()
+	socialLogins, err := authRepository.NewAuthRepository(s.db).ReencryptSocialLoginTokens(ctx, rotate)
+	if err != nil {
+		return fmt.Errorf("re-encrypt social login tokens: %w", err)
+	}
+	connections, err := calRepository.NewCalendarRepository(s.db).ReencryptConnectionTokens(ctx, rotate)
+	if err != nil {
+		return fmt.Errorf("re-encrypt calendar connection tokens: %w", err)
+	}
+
+	logger.Info("Token keys task complete",
+		"task", s.task,
+		"active_key", encryption.ActiveKeyID(),
+		"social_logins", socialLogins,
+		"calendar_connections", connections,
+	)
+	return nil
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CalendarMethod is the iTIP method of a calendar invite (RFC 5546)
+type CalendarMethod string
+
+const (
+	CalendarMethodRequest CalendarMethod = "REQUEST"
+	CalendarMethodCancel  CalendarMethod = "CANCEL"
+)
+
+const (
+	icsDateFormat  = "20060102T150405Z"
+	icsLineOctets  = 75
+	googleAddURL   = "https://calendar.google.com/calendar/render"
+	outlookAddURL  = "https://outlook.live.com/calendar/0/deeplink/compose"
+	outlookTimeFmt = "2006-01-02T15:04:05Z"
+)
+
+// CalendarInvite describes one meeting for an iCalendar invite or add-to-calendar links
+type CalendarInvite struct {
+	// UID identifies the meeting across updates; a cancellation must reuse the UID of the request
+	UID string
+	// Sequence must grow with every update of the same UID
+	Sequence       int
+	Method         CalendarMethod
+	Summary        string
+	Description    string
+	Location       string
+	Start          time.Time
+	End            time.Time
+	OrganizerName  string
+	OrganizerEmail string
+	Attendees      []string
+}
+
+// ICS renders the invite as an iCalendar (RFC 5545) document
+func (i CalendarInvite) ICS() []byte {
+	method := i.Method
+	if method == "" {
+		method = CalendarMethodRequest
+	}
+	status := "CONFIRMED"
+	if method == CalendarMethodCancel {
+		status = "CANCELLED"
+	}
+
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("PRODID:-//SmartMeet//Booking//EN")
+	line("VERSION:2.0")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:" + string(method))
+	line("BEGIN:VEVENT")
+	line("UID:" + escapeICSText(i.UID))
+	line(fmt.Sprintf("SEQUENCE:%d", i.Sequence))
+	line("DTSTAMP:" + time.Now().UTC().Format(icsDateFormat))
+	line("DTSTART:" + i.Start.UTC().Format(icsDateFormat))
+	line("DTEND:" + i.End.UTC().Format(icsDateFormat))
+	line("SUMMARY:" + escapeICSText(i.Summary))
+	if i.Description != "" {
+		line("DESCRIPTION:" + escapeICSText(i.Description))
+	}
+	if i.Location != "" {
+		line("LOCATION:" + escapeICSText(i.Location))
+	}
+	line("STATUS:" + status)
+	if i.OrganizerEmail != "" {
+		line("ORGANIZER" + icsCommonName(i.OrganizerName) + ":mailto:" + i.OrganizerEmail)
+	}
+	for _, attendee := range i.Attendees {
+		line("ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + attendee)
+	}
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// Attachment returns the invite as an email attachment that mail clients offer to add to the calendar
+func (i CalendarInvite) Attachment() EmailAttachment {
+	method := i.Method
+	if method == "" {
+		method = CalendarMethodRequest
+	}
+	filename := "invite.ics"
+	if method == CalendarMethodCancel {
+		filename = "cancel.ics"
+	}
+	return EmailAttachment{
+		Filename:    filename,
+		ContentType: "text/calendar; charset=UTF-8; method=" + string(method),
+		Content:     i.ICS(),
+	}
+}
+
+// GoogleCalendarURL returns a link that opens the meeting prefilled in Google Calendar
+func (i CalendarInvite) GoogleCalendarURL() string {
+	q := url.Values{}
+	q.Set("action", "TEMPLATE")
+	q.Set("text", i.Summary)
+	q.Set("dates", i.Start.UTC().Format(icsDateFormat)+"/"+i.End.UTC().Format(icsDateFormat))
+	if i.Description != "" {
+		q.Set("details", i.Description)
+	}
+	if i.Location != "" {
+		q.Set("location", i.Location)
+	}
+	return googleAddURL + "?" + q.Encode()
+}
+
+// OutlookCalendarURL returns a link that opens the meeting prefilled in Outlook on the web
+func (i CalendarInvite) OutlookCalendarURL() string {
+	q := url.Values{}
+	q.Set("path", "/calendar/action/compose")
+	q.Set("rru", "addevent")
+	q.Set("subject", i.Summary)
+	q.Set("startdt", i.Start.UTC().Format(outlookTimeFmt))
+	q.Set("enddt", i.End.UTC().Format(outlookTimeFmt))
+	if i.Description != "" {
+		q.Set("body", i.Description)
+	}
+	if i.Location != "" {
+		q.Set("location", i.Location)
+	}
+	return outlookAddURL + "?" + q.Encode()
+}
+
+// escapeICSText escapes a TEXT value: backslash, semicolon, comma and line breaks
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsCommonName renders a CN parameter; DQUOTE cannot be escaped, so it is dropped
+func icsCommonName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer(`"`, "", "\r", " ", "\n", " ").Replace(name))
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
+
+// foldICSLine splits a content line into lines of at most 75 octets, never inside a UTF-8 sequence
+func foldICSLine(s string) string {
+	if len(s) <= icsLineOctets {
+		return s
+	}
+	var b strings.Builder
+	limit := icsLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space, which counts toward the limit
+		limit = icsLineOctets - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "Weekly sync", want: "Weekly sync"},
+		{name: "comma and semicolon", in: "Room 1, floor 2; east", want: `Room 1\, floor 2\; east`},
+		{name: "backslash", in: `C:\notes`, want: `C:\\notes`},
+		{name: "newline", in: "line 1\nline 2", want: `line 1\nline 2`},
+		{name: "crlf and cr", in: "a\r\nb\rc", want: `a\nb\nc`},
+		{name: "unicode untouched", in: "Họp nhóm", want: "Họp nhóm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeICSText(tt.in); got != tt.want {
+				t.Errorf("escapeICSText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "short line", in: "SUMMARY:Weekly sync"},
+		{name: "exactly 75 octets", in: "DESCRIPTION:" + strings.Repeat("a", 63)},
+		{name: "long ascii", in: "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{name: "long multi-byte", in: "SUMMARY:" + strings.Repeat("Họp nhóm dự án ", 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := foldICSLine(tt.in)
+			lines := strings.Split(got, "\r\n")
+			if len(tt.in) <= icsLineOctets && len(lines) != 1 {
+				t.Fatalf("foldICSLine() folded a %d octet line", len(tt.in))
+			}
+
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > icsLineOctets {
+					t.Errorf("line %d is %d octets, want at most %d", i, len(line), icsLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Fatalf("continuation line %d does not start with a space: %q", i, line)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != tt.in {
+				t.Errorf("unfolding gives %q, want %q", unfolded.String(), tt.in)
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@ package utils
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"html/template"
+	"mime/multipart"
 	"net"
 	"net/smtp"
+	"net/textproto"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -40,6 +43,15 @@ type EmailMessage struct {
 	Subject string
 	Body    string
 	IsHTML  bool
+	// Attachments turn the message into multipart/mixed with the body as its first part
+	Attachments []EmailAttachment
+}
+
+// EmailAttachment is a file attached to an email message
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
 }
 
 // IsValidEmail checks if email format is valid and within length limits
@@ -261,18 +273,72 @@ func buildEmailContent(config EmailConfig, message EmailMessage) ([]byte, error)
 	buffer.WriteString(fmt.Sprintf("Subject: %s\r\n", message.Subject))
 	buffer.WriteString("MIME-Version: 1.0\r\n")
 
+	bodyType := "text/plain; charset=UTF-8"
 	if message.IsHTML {
-		buffer.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
-	} else {
-		buffer.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		bodyType = "text/html; charset=UTF-8"
 	}
 
+	if len(message.Attachments) == 0 {
+		buffer.WriteString(fmt.Sprintf("Content-Type: %s\r\n", bodyType))
+		buffer.WriteString("\r\n")
+		buffer.WriteString(message.Body)
+		return buffer.Bytes(), nil
+	}
+
+	// Body and attachments as multipart/mixed
+	parts := multipart.NewWriter(&buffer)
+	buffer.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n", parts.Boundary()))
 	buffer.WriteString("\r\n")
-	buffer.WriteString(message.Body)
+
+	bodyPart, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {bodyType},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = bodyPart.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		filename := strings.NewReplacer(`"`, "", "\r", "", "\n", "").Replace(attachment.Filename)
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", contentType, filename)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", filename)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = part.Write(base64Lines(attachment.Content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
 
 	return buffer.Bytes(), nil
 }
 
+// base64Lines encodes content as base64 in lines of 76 characters, as MIME requires
+func base64Lines(content []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var buffer bytes.Buffer
+	for len(encoded) > 76 {
+		buffer.WriteString(encoded[:76])
+		buffer.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buffer.WriteString(encoded)
+	buffer.WriteString("\r\n")
+	return buffer.Bytes()
+}
+
 // InitEmailConfig khởi tạo global email config một lần
 func InitEmailConfig(config EmailConfig) {
 	globalEmailOnce.Do(func() {
@@ -0,0 +1,21 @@
+-- Opt-in daily/weekly digest of unread notifications
+-- digested_at marks notifications already included in a digest email
+
+CREATE TABLE IF NOT EXISTS notification_digest_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE,
+    frequency VARCHAR(20) NOT NULL DEFAULT 'off', -- off, daily, weekly
+    locale VARCHAR(10) NOT NULL DEFAULT 'vi',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh',
+    send_hour INT NOT NULL DEFAULT 8,
+    send_weekday INT NOT NULL DEFAULT 1, -- 0 = Sunday, used for weekly digests
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digest_settings_frequency ON notification_digest_settings(frequency);
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digested_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_digested_at ON notifications(digested_at);
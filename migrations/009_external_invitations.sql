@@ -0,0 +1,8 @@
+-- Email-only invitees: attendees without an account are stored by email
+-- and attached to a user (invitee_id) once they register or sign in with that address.
+-- Status may now also be 'tentative' (RSVP from the invitation email).
+
+ALTER TABLE event_invitations ALTER COLUMN invitee_id DROP NOT NULL;
+ALTER TABLE event_invitations ADD COLUMN IF NOT EXISTS invitee_email VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_event_invitations_invitee_email ON event_invitations(LOWER(invitee_email)) WHERE invitee_id IS NULL;
@@ -0,0 +1,7 @@
+-- Event revision tracking for invitations
+-- event_data is re-snapshotted and event_revision bumped whenever the creator updates the event;
+-- status becomes 'cancelled' when the creator deletes it.
+
+ALTER TABLE event_invitations ADD COLUMN IF NOT EXISTS event_revision INT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_event_invitations_event_google_id ON event_invitations(event_google_id);
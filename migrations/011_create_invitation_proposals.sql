@@ -0,0 +1,34 @@
+-- "Propose a new time" counter-proposals on invitations
+-- slots holds the alternative times suggested by the invitee: [{"start_time": RFC3339, "end_time": RFC3339}]
+-- status: pending, accepted, rejected, superseded (another proposal for the same event was accepted)
+
+CREATE TABLE IF NOT EXISTS invitation_proposals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invitation_id UUID NOT NULL REFERENCES event_invitations(id) ON DELETE CASCADE,
+    event_google_id VARCHAR(255) NOT NULL,
+    creator_id UUID NOT NULL,
+    proposer_id UUID NOT NULL,
+    slots JSONB NOT NULL DEFAULT '[]',
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    accepted_slot_index INT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitation_proposals_invitation_id ON invitation_proposals(invitation_id);
+CREATE INDEX IF NOT EXISTS idx_invitation_proposals_creator_id ON invitation_proposals(creator_id);
+CREATE INDEX IF NOT EXISTS idx_invitation_proposals_event_google_id ON invitation_proposals(event_google_id);
+
+-- Every status transition of a proposal, including its creation
+CREATE TABLE IF NOT EXISTS invitation_proposal_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    proposal_id UUID NOT NULL REFERENCES invitation_proposals(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitation_proposal_history_proposal_id ON invitation_proposal_history(proposal_id);
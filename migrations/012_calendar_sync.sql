@@ -0,0 +1,60 @@
+-- Two-way sync between local events and Google Calendar
+-- Links local events to their provider copy, mirrors provider events and tracks incremental sync per connection
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS provider VARCHAR(50);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS provider_event_id VARCHAR(255);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS provider_etag VARCHAR(255);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS provider_updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS last_synced_at TIMESTAMP WITH TIME ZONE;
+-- Provider start minus local start, kept constant by sync (booking accepts schedule the provider copy a day later)
+ALTER TABLE events ADD COLUMN IF NOT EXISTS provider_offset_minutes INTEGER NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_provider_event_id ON events(host_id, provider, provider_event_id)
+    WHERE provider_event_id IS NOT NULL;
+
+-- Incremental sync state per user/provider connection
+CREATE TABLE IF NOT EXISTS calendar_sync_states (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    sync_token TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'never', -- never, ok, error
+    last_error TEXT,
+    last_synced_at TIMESTAMP WITH TIME ZONE,
+    last_full_sync_at TIMESTAMP WITH TIME ZONE,
+    events_pulled INTEGER NOT NULL DEFAULT 0,
+    events_pushed INTEGER NOT NULL DEFAULT 0,
+    conflicts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_calendar_sync_user_provider UNIQUE(user_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_sync_states_user_id ON calendar_sync_states(user_id);
+
+-- Local mirror of provider events, kept current by the sync job
+CREATE TABLE IF NOT EXISTS calendar_synced_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_event_id VARCHAR(255) NOT NULL,
+    etag VARCHAR(255),
+    summary TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    event_type VARCHAR(50) NOT NULL DEFAULT 'default', -- default, focusTime, outOfOffice, ...
+    transparency VARCHAR(20) NOT NULL DEFAULT 'opaque',
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    all_day BOOLEAN NOT NULL DEFAULT false,
+    organizer_email VARCHAR(255),
+    is_organizer BOOLEAN NOT NULL DEFAULT false,
+    attendees JSONB NOT NULL DEFAULT '[]',
+    local_event_id UUID REFERENCES events(id) ON DELETE SET NULL,
+    provider_updated_at TIMESTAMP WITH TIME ZONE,
+    synced_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_calendar_synced_event UNIQUE(user_id, provider, provider_event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_synced_events_user_start ON calendar_synced_events(user_id, start_time);
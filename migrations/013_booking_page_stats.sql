@@ -0,0 +1,17 @@
+-- Booking page funnel counters used by calendar analytics (views -> requests -> accepted/declined)
+
+CREATE TABLE IF NOT EXISTS booking_page_daily_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    stat_date DATE NOT NULL,
+    page_views INTEGER NOT NULL DEFAULT 0,
+    requests INTEGER NOT NULL DEFAULT 0,
+    accepted INTEGER NOT NULL DEFAULT 0,
+    declined INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_booking_page_daily_stats UNIQUE(user_id, stat_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_page_daily_stats_user_date ON booking_page_daily_stats(user_id, stat_date);
@@ -0,0 +1,35 @@
+-- Automatic focus-time blocking
+-- preferred_windows: [{"weekdays": [1,2,3,4,5], "start": "09:00", "end": "12:00"}] (ISO weekdays, 1 = Monday)
+-- focus_time_blocks are the "Focus time" busy events the planner created on the user's Google Calendar
+-- status: active, removed (deleted by a rebalance or on the provider)
+
+CREATE TABLE IF NOT EXISTS focus_time_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    target_minutes_per_week INTEGER NOT NULL DEFAULT 600,
+    min_block_minutes INTEGER NOT NULL DEFAULT 60,
+    max_block_minutes INTEGER NOT NULL DEFAULT 120,
+    preferred_windows JSONB NOT NULL DEFAULT '[]',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh',
+    last_planned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_focus_time_policy_user UNIQUE(user_id)
+);
+
+CREATE TABLE IF NOT EXISTS focus_time_blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL DEFAULT 'google',
+    provider_event_id VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_focus_time_blocks_user_time ON focus_time_blocks(user_id, start_time) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_focus_time_blocks_provider_event ON focus_time_blocks(user_id, provider, provider_event_id);
@@ -0,0 +1,22 @@
+-- Out-of-office periods: days removed from availability while the user is on leave
+-- start_time/end_time cover whole days in the period's timezone ([first day 00:00, day after last day 00:00))
+-- auto_action decides what happens to booking requests and invitations inside the period: decline, delegate
+-- provider_event_id is the optional "Out of office" event created on the user's Google Calendar
+
+CREATE TABLE IF NOT EXISTS out_of_office_periods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh',
+    message TEXT,
+    auto_action VARCHAR(20) NOT NULL DEFAULT 'decline',
+    delegate_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    provider_event_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_out_of_office_range CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_out_of_office_periods_user_time ON out_of_office_periods(user_id, start_time, end_time);
@@ -0,0 +1,27 @@
+-- Booking page profiles: a human-readable slug per host plus page branding.
+-- Slugs a host gave up stay in booking_slug_history so old links keep redirecting.
+
+CREATE TABLE IF NOT EXISTS booking_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(40),
+    title VARCHAR(120),
+    welcome_text TEXT,
+    avatar_url TEXT,
+    brand_color VARCHAR(7),
+    locale VARCHAR(5) NOT NULL DEFAULT 'vi',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_profiles_slug ON booking_profiles(LOWER(slug)) WHERE slug IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS booking_slug_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(40) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_slug_history_slug ON booking_slug_history(LOWER(slug));
+CREATE INDEX IF NOT EXISTS idx_booking_slug_history_user ON booking_slug_history(user_id);
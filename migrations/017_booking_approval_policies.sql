@@ -0,0 +1,19 @@
+-- Per event type approval policy of booking page requests.
+-- mode: manual (host approves every request), auto (always accept),
+-- conditional (accept guests from allowed_domains and/or returning guests, otherwise manual)
+
+CREATE TABLE IF NOT EXISTS booking_approval_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL DEFAULT 'default',
+    mode VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (mode IN ('manual', 'auto', 'conditional')),
+    allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+    accept_returning_guests BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_booking_approval_policy UNIQUE(user_id, event_type)
+);
+
+-- Returning guest lookups match accepted bookings by the guest email stored in events.preferences
+CREATE INDEX IF NOT EXISTS idx_events_host_guest_email ON events(host_id, LOWER(preferences->>'guest_email'));
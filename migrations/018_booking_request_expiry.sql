@@ -0,0 +1,7 @@
+-- How long a host has to answer a booking request before it expires (default 24 hours)
+
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS approval_window_minutes INTEGER NOT NULL DEFAULT 1440
+    CHECK (approval_window_minutes BETWEEN 60 AND 20160);
+
+-- Expiry job scans pending booking requests by age
+CREATE INDEX IF NOT EXISTS idx_events_pending_created ON events(created_at) WHERE status = 'pending';
@@ -0,0 +1,18 @@
+-- One-time accept/decline links of booking requests.
+-- Only the SHA-256 of the signed token is stored; a token is bound to one event and one action,
+-- and every other open link of the event is revoked when one of them is used.
+
+CREATE TABLE IF NOT EXISTS booking_action_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('accept', 'decline')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_action_tokens_open ON booking_action_tokens(event_id)
+    WHERE used_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,17 @@
+-- Abuse protection of public booking requests:
+-- require_email_verification holds a request until the guest confirms their email,
+-- require_challenge makes the booking form solve a proof-of-work challenge.
+
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS require_email_verification BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS require_challenge BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Guests a host does not accept booking requests from, by exact email or by email domain
+CREATE TABLE IF NOT EXISTS booking_blocked_guests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('email', 'domain')),
+    value VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_booking_blocked_guest UNIQUE(user_id, kind, value)
+);
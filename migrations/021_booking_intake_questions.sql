@@ -0,0 +1,17 @@
+-- Questions a host asks guests on the booking form, per event type.
+-- Answers are stored with the booking request in events.preferences->'answers'.
+
+CREATE TABLE IF NOT EXISTS booking_intake_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL DEFAULT 'default',
+    position INTEGER NOT NULL DEFAULT 0,
+    label VARCHAR(200) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'long_text', 'phone', 'single_choice', 'multi_choice')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_intake_questions_user_type ON booking_intake_questions(user_id, event_type, position);
@@ -0,0 +1,43 @@
+-- Waitlist of a host's booking page. Guests wait for a day (or any day) of an event type;
+-- a slot freed by a cancelled, declined or expired booking is offered to them in join order.
+
+CREATE TABLE IF NOT EXISTS booking_waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    host_key VARCHAR(255) NOT NULL, -- slug or social login ID of the booking page the guest joined from
+    guest_name VARCHAR(255) NOT NULL,
+    guest_email VARCHAR(255) NOT NULL,
+    event_type VARCHAR(64) NOT NULL DEFAULT 'default',
+    wait_date DATE, -- NULL waits for any day
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh',
+    answers JSONB,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'booked', 'removed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_entries_waiting ON booking_waitlist_entries(host_id, event_type, created_at)
+    WHERE status = 'waiting';
+
+-- A guest waits at most once for the same day and event type
+CREATE UNIQUE INDEX IF NOT EXISTS unique_booking_waitlist_entry ON booking_waitlist_entries
+    (host_id, lower(guest_email), event_type, COALESCE(wait_date, DATE '0001-01-01'))
+    WHERE status = 'waiting';
+
+-- Time-limited offers of a freed slot; only the SHA-256 of the claim token is stored
+CREATE TABLE IF NOT EXISTS booking_waitlist_offers (
+    id UUID PRIMARY KEY,
+    entry_id UUID NOT NULL REFERENCES booking_waitlist_entries(id) ON DELETE CASCADE,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'claimed', 'expired', 'taken')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_offers_open ON booking_waitlist_offers(expires_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_offers_slot ON booking_waitlist_offers(host_id, start_time);
@@ -0,0 +1,35 @@
+-- Paid bookings. A priced event type turns each booking request into an order of the shop's order system:
+-- the request holds its slot while the order's payment is pending and is confirmed once the order is paid.
+
+CREATE TABLE IF NOT EXISTS booking_prices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL DEFAULT 'default',
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount >= 0), -- 0 keeps an event type free when the default is priced
+    payment_window_minutes INT NOT NULL DEFAULT 30 CHECK (payment_window_minutes BETWEEN 5 AND 1440),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_booking_price UNIQUE(user_id, event_type)
+);
+
+-- The order of a paid booking request. Booking orders have no shipping; the shipping columns of orders keep their defaults.
+CREATE TABLE IF NOT EXISTS booking_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL UNIQUE REFERENCES events(id) ON DELETE CASCADE,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_number VARCHAR(64) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    payment_method_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'cancelled', 'refunded')),
+    hold_expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- the slot is released when the order is not paid by then
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_payments_pending ON booking_payments(order_id) WHERE status = 'pending';
+
+-- Waitlisted guests of a priced event type pay with the method they picked when joining
+ALTER TABLE booking_waitlist_entries ADD COLUMN IF NOT EXISTS payment_method_id INT;
@@ -0,0 +1,34 @@
+-- Attendance of scheduled events, marked by the host per participant after the meeting.
+-- Meeting participants are users; the guest of a booking is identified by the email of the request.
+
+CREATE TABLE IF NOT EXISTS event_attendance (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    participant VARCHAR(255) GENERATED ALWAYS AS (COALESCE(user_id::text, LOWER(email))) STORED,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('attended', 'no_show', 'late')),
+    marked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT event_attendance_participant CHECK (user_id IS NOT NULL OR email IS NOT NULL),
+    CONSTRAINT unique_event_attendance UNIQUE(event_id, participant)
+);
+
+-- No-show counts per guest email of a host
+CREATE INDEX IF NOT EXISTS idx_event_attendance_no_show_email ON event_attendance(LOWER(email)) WHERE status = 'no_show';
+
+-- Events whose host was already asked to mark attendance
+CREATE TABLE IF NOT EXISTS event_attendance_prompts (
+    event_id UUID PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    prompted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- attendance_prompts asks the host to mark attendance after their meetings end.
+-- Guests with at least no_show_threshold no-shows (0 = off) need approval or are blocked, per no_show_action.
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS attendance_prompts BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS no_show_threshold INT NOT NULL DEFAULT 0 CHECK (no_show_threshold >= 0);
+ALTER TABLE booking_profiles ADD COLUMN IF NOT EXISTS no_show_action VARCHAR(20) NOT NULL DEFAULT 'require_approval'
+    CHECK (no_show_action IN ('require_approval', 'block'));
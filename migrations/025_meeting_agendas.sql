@@ -0,0 +1,54 @@
+-- Structured agendas, shared notes and action items of events, plus reusable agenda templates.
+-- The host and the participants (user_events) of an event can see all of it; the host edits the agenda.
+
+CREATE TABLE IF NOT EXISTS event_agenda_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    title VARCHAR(200) NOT NULL,
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    duration_minutes INTEGER CHECK (duration_minutes IS NULL OR duration_minutes > 0), -- time box
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_agenda_items_event ON event_agenda_items(event_id, position);
+
+-- One shared notes document per event; version guards against overwriting someone else's edit
+CREATE TABLE IF NOT EXISTS event_notes (
+    event_id UUID PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    content TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS event_action_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    title VARCHAR(300) NOT NULL,
+    assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    due_date TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'done')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    reminded_at TIMESTAMP WITH TIME ZONE, -- the assignee was reminded of the due date
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_action_items_event ON event_action_items(event_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_event_action_items_assignee_open ON event_action_items(assignee_id, due_date) WHERE status = 'open';
+
+-- Agenda templates of a user, applied to any of their events (e.g. every occurrence of a weekly meeting)
+CREATE TABLE IF NOT EXISTS agenda_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(120) NOT NULL,
+    items JSONB NOT NULL DEFAULT '[]', -- [{"title", "owner_id", "duration_minutes"}] in agenda order
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_agenda_template_name UNIQUE(owner_id, name)
+);
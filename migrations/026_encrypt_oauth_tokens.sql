@@ -0,0 +1,14 @@
+-- OAuth tokens are encrypted at rest by the application (AES-GCM envelope encryption, see core/encryption).
+-- Stored values look like enc:v1:<key id>:<wrapped data key>:<ciphertext>; the key ID names the
+-- key-encryption key from APP_TOKEN_ENCRYPTION_KEYS. The keys never reach the database, so existing rows
+-- are encrypted by the application once the keys are configured:
+--
+--     go run main.go -env prod -token-keys migrate
+--
+-- After changing APP_TOKEN_ENCRYPTION_ACTIVE_KEY, re-encrypt rows under the retired key with -token-keys rotate.
+-- Plaintext rows keep working until then.
+
+COMMENT ON COLUMN calendar_connections.access_token IS 'OAuth access token, encrypted (enc:v1:<key id>:...)';
+COMMENT ON COLUMN calendar_connections.refresh_token IS 'OAuth refresh token, encrypted (enc:v1:<key id>:...)';
+COMMENT ON COLUMN social_logins.access_token IS 'OAuth access token, encrypted (enc:v1:<key id>:...)';
+COMMENT ON COLUMN social_logins.refresh_token IS 'OAuth refresh token, encrypted (enc:v1:<key id>:...)';
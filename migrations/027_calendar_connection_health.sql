@@ -0,0 +1,20 @@
+-- Health of a user's calendar connection, checked by the connection health job and on every token refresh.
+-- A connection is unhealthy when the provider rejected its refresh token (e.g. invalid_grant after the user
+-- revoked access); the user is notified once per incident with a link to reconnect. Signing in with the
+-- provider again stores new tokens, which makes an earlier failure stale.
+CREATE TABLE IF NOT EXISTS calendar_connection_health (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'healthy' CHECK (status IN ('healthy', 'unhealthy')),
+    last_error TEXT,
+    failed_at TIMESTAMP WITH TIME ZONE, -- start of the current incident
+    notified_at TIMESTAMP WITH TIME ZONE, -- the user was asked to reconnect for the current incident
+    last_refreshed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, provider)
+);
+
+-- last_synced_at is the last attempt; keep the last run that succeeded as well
+ALTER TABLE calendar_sync_states ADD COLUMN IF NOT EXISTS last_success_at TIMESTAMP WITH TIME ZONE;
@@ -8,8 +8,10 @@ import (
 )
 
 type RegisterRequest struct {
-	Phone    string `json:"phone"`
-	Password string `json:"password"`
+	Phone           string `json:"phone"`
+	Password        string `json:"password"`
+	Email           string `json:"email,omitempty"`
+	InvitationToken string `json:"invitation_token,omitempty"` // sign up token emailed to an invitee; verifies Email
 }
 
 type RegisterResponse struct {
@@ -15,9 +15,10 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func Init(e *echo.Echo, db database.Database, cache cache.Cache) {
+func Init(e *echo.Echo, db database.Database, cache cache.Cache, invitationLinker service.InvitationLinker) {
 	repo := repository.NewAuthRepository(db)
 	authService := service.NewAuthService(repo, cache)
+	authService.SetInvitationLinker(invitationLinker)
 	controller := controller.NewAuthController(authService)
 	middleware := middleware.NewMiddleware(authService)
 
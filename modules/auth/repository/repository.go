@@ -77,10 +77,12 @@ type AuthRepositoryInterface interface {
 	// ========================================
 	GetSocialLoginByUserIDAndProvider(ctx context.Context, userID uuid.UUID, providerID uuid.UUID) (*entity.SocialLogin, error)
 	SaveOrUpdateSocialLogin(ctx context.Context, socialLogin *entity.SocialLogin) error
+	UpdateSocialLoginTokens(ctx context.Context, userID uuid.UUID, providerID uuid.UUID, accessToken string, refreshToken *string, expiresAt time.Time) error
 	GetOAuthProviderByName(ctx context.Context, name string) (*entity.OAuthProvider, error)
 	SeedGoogleProvider(ctx context.Context, clientID string, clientSecret string, redirectURI string) error
 	GetSocialLoginByID(ctx context.Context, id uuid.UUID) (*entity.SocialLogin, error)
 	GetSocialLoginByUsername(ctx context.Context, username string) (*entity.SocialLogin, error)
+	ReencryptSocialLoginTokens(ctx context.Context, rotate bool) (int, error)
 
 	// ========================================
 	// Social Users Search Operations
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"go-api-starter/core/encryption"
 	"go-api-starter/core/logger"
 	"go-api-starter/modules/auth/entity"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -24,6 +26,10 @@ func (r *AuthRepository) GetSocialLoginByUserIDAndProvider(ctx context.Context,
 		logger.Error("AuthRepository:GetSocialLoginByUserIDAndProvider:Error", "error", err, "user_id", userID, "provider_id", providerID)
 		return nil, err
 	}
+	if err := decryptSocialLoginTokens(&socialLogin); err != nil {
+		logger.Error("AuthRepository:GetSocialLoginByUserIDAndProvider:Decrypt", "error", err, "user_id", userID)
+		return nil, err
+	}
 	return &socialLogin, nil
 }
 
@@ -51,7 +57,17 @@ func (r *AuthRepository) SaveOrUpdateSocialLogin(ctx context.Context, socialLogi
 			is_active = EXCLUDED.is_active,
 			updated_at = NOW()
 	`
-	_, err := r.DB.NamedExecContext(ctx, query, socialLogin)
+	stored := *socialLogin
+	var err error
+	if stored.AccessToken, err = encryption.EncryptTokenPtr(socialLogin.AccessToken); err != nil {
+		logger.Error("AuthRepository:SaveOrUpdateSocialLogin:Encrypt", "error", err)
+		return err
+	}
+	if stored.RefreshToken, err = encryption.EncryptTokenPtr(socialLogin.RefreshToken); err != nil {
+		logger.Error("AuthRepository:SaveOrUpdateSocialLogin:Encrypt", "error", err)
+		return err
+	}
+	_, err = r.DB.NamedExecContext(ctx, query, &stored)
 	if err != nil {
 		logger.Error("AuthRepository:SaveOrUpdateSocialLogin:Error", "error", err)
 		return err
@@ -59,6 +75,31 @@ func (r *AuthRepository) SaveOrUpdateSocialLogin(ctx context.Context, socialLogi
 	return nil
 }
 
+// UpdateSocialLoginTokens stores refreshed OAuth tokens of a social login.
+// A nil refreshToken keeps the stored one; providers only return a new refresh token when they rotate it.
+func (r *AuthRepository) UpdateSocialLoginTokens(ctx context.Context, userID uuid.UUID, providerID uuid.UUID, accessToken string, refreshToken *string, expiresAt time.Time) error {
+	query := `
+		UPDATE social_logins
+		SET access_token = $3, refresh_token = COALESCE($4, refresh_token), token_expires_at = $5, updated_at = NOW()
+		WHERE user_id = $1 AND provider_id = $2 AND is_active = true
+	`
+	storedAccessToken, err := encryption.EncryptToken(accessToken)
+	if err != nil {
+		logger.Error("AuthRepository:UpdateSocialLoginTokens:Encrypt", "error", err)
+		return err
+	}
+	storedRefreshToken, err := encryption.EncryptTokenPtr(refreshToken)
+	if err != nil {
+		logger.Error("AuthRepository:UpdateSocialLoginTokens:Encrypt", "error", err)
+		return err
+	}
+	if err := r.DB.ExecContext(ctx, query, userID, providerID, storedAccessToken, storedRefreshToken, expiresAt); err != nil {
+		logger.Error("AuthRepository:UpdateSocialLoginTokens:Error", "error", err, "user_id", userID, "provider_id", providerID)
+		return err
+	}
+	return nil
+}
+
 func (r *AuthRepository) GetOAuthProviderByName(ctx context.Context, name string) (*entity.OAuthProvider, error) {
 	var provider entity.OAuthProvider
 	query := `SELECT * FROM oauth_providers WHERE name = $1 AND is_active = true`
@@ -122,6 +163,10 @@ func (r *AuthRepository) GetSocialLoginByID(ctx context.Context, id uuid.UUID) (
 		logger.Error("AuthRepository:GetSocialLoginByID:Error", "error", err, "id", id)
 		return nil, err
 	}
+	if err := decryptSocialLoginTokens(&sl); err != nil {
+		logger.Error("AuthRepository:GetSocialLoginByID:Decrypt", "error", err, "id", id)
+		return nil, err
+	}
 	return &sl, nil
 }
 
@@ -136,5 +181,99 @@ func (r *AuthRepository) GetSocialLoginByUsername(ctx context.Context, username
 		logger.Error("AuthRepository:GetSocialLoginByUsername:Error", "error", err, "username", username)
 		return nil, err
 	}
+	if err := decryptSocialLoginTokens(&sl); err != nil {
+		logger.Error("AuthRepository:GetSocialLoginByUsername:Decrypt", "error", err, "username", username)
+		return nil, err
+	}
 	return &sl, nil
 }
+
+// reencryptBatchSize is how many rows ReencryptSocialLoginTokens reads per query
+const reencryptBatchSize = 200
+
+// ReencryptSocialLoginTokens encrypts the tokens of social logins that are still stored in plaintext,
+// or with rotate also those encrypted under a retired key. It returns how many rows were rewritten.
+// A token refreshed while the rows are processed is left for the next run instead of being overwritten.
+func (r *AuthRepository) ReencryptSocialLoginTokens(ctx context.Context, rotate bool) (int, error) {
+	query := `
+		SELECT id, access_token, refresh_token FROM social_logins
+		WHERE id > $1
+		AND ((access_token <> '' AND access_token NOT LIKE $2) OR (refresh_token <> '' AND refresh_token NOT LIKE $2))
+		ORDER BY id
+		LIMIT $3
+	`
+	update := `
+		UPDATE social_logins SET access_token = $2, refresh_token = $3
+		WHERE id = $1 AND access_token IS NOT DISTINCT FROM $4 AND refresh_token IS NOT DISTINCT FROM $5
+	`
+
+	type tokenRow struct {
+		ID           uuid.UUID `db:"id"`
+		AccessToken  *string   `db:"access_token"`
+		RefreshToken *string   `db:"refresh_token"`
+	}
+	// changed counts rows whose tokens were refreshed between reading and rewriting them
+	rewritten, changed := 0, 0
+	lastID := uuid.Nil
+	for {
+		var rows []tokenRow
+		if err := r.DB.SelectContext(ctx, &rows, query, lastID, encryption.UpToDatePattern(rotate), reencryptBatchSize); err != nil {
+			logger.Error("AuthRepository:ReencryptSocialLoginTokens:Error", "error", err)
+			return rewritten, err
+		}
+		for _, row := range rows {
+			lastID = row.ID
+			accessToken, err := reencryptTokenPtr(row.AccessToken, rotate)
+			if err != nil {
+				logger.Error("AuthRepository:ReencryptSocialLoginTokens:Skip", "error", err, "id", row.ID)
+				continue
+			}
+			refreshToken, err := reencryptTokenPtr(row.RefreshToken, rotate)
+			if err != nil {
+				logger.Error("AuthRepository:ReencryptSocialLoginTokens:Skip", "error", err, "id", row.ID)
+				continue
+			}
+			result, err := r.DB.SQLx().ExecContext(ctx, update, row.ID, accessToken, refreshToken, row.AccessToken, row.RefreshToken)
+			if err != nil {
+				logger.Error("AuthRepository:ReencryptSocialLoginTokens:Error", "error", err, "id", row.ID)
+				return rewritten, err
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				logger.Error("AuthRepository:ReencryptSocialLoginTokens:RowsAffected:Error", "error", err, "id", row.ID)
+				return rewritten, err
+			}
+			if rowsAffected == 0 {
+				changed++
+				continue
+			}
+			rewritten++
+		}
+		if len(rows) < reencryptBatchSize {
+			if changed > 0 {
+				logger.Info("AuthRepository:ReencryptSocialLoginTokens:ChangedMeanwhile", "skipped", changed)
+			}
+			return rewritten, nil
+		}
+	}
+}
+
+func decryptSocialLoginTokens(sl *entity.SocialLogin) error {
+	var err error
+	if sl.AccessToken, err = encryption.DecryptTokenPtr(sl.AccessToken); err != nil {
+		return err
+	}
+	sl.RefreshToken, err = encryption.DecryptTokenPtr(sl.RefreshToken)
+	return err
+}
+
+func reencryptTokenPtr(value *string, rotate bool) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	token, err := encryption.ReencryptToken(*value, rotate)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
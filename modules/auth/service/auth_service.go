@@ -14,6 +14,7 @@ import (
 	"go-api-starter/modules/auth/mapper"
 	"io"
 	"net/http"
+	"strings"
 
 	"time"
 
@@ -428,6 +429,30 @@ func (service *AuthService) Register(ctx context.Context, requestData *dto.Regis
 		return nil, errors.NewAppError(errors.ErrAlreadyExists, "user with phone already exists", nil)
 	}
 
+	// Optional email; it counts as verified only with the short-lived sign up token emailed to an invitee.
+	// The RSVP token of the invitation itself is long-lived and forwarded with the invitation, so it proves nothing.
+	email := strings.TrimSpace(requestData.Email)
+	emailVerified := false
+	if requestData.InvitationToken != "" {
+		claims, err := utils.ValidateAndParseToken(requestData.InvitationToken)
+		if err != nil || claims.Scope != constants.ScopeTokenInvitationSignup || claims.Email == "" {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid invitation token", err)
+		}
+		if email != "" && !strings.EqualFold(email, claims.Email) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "email does not match invitation", nil)
+		}
+		email = claims.Email
+		emailVerified = true
+	}
+	if email != "" {
+		if !utils.IsValidEmail(email) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid email", nil)
+		}
+		if existingUser, _ := service.GetUserByIdentifier(ctx, email); existingUser != nil {
+			return nil, errors.NewAppError(errors.ErrAlreadyExists, "user with email already exists", nil)
+		}
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(requestData.Password)
 	if err != nil {
@@ -440,6 +465,13 @@ func (service *AuthService) Register(ctx context.Context, requestData *dto.Regis
 		Password: hashedPassword,
 		IsActive: true, // Set default to true for new users
 	}
+	if email != "" {
+		userEntity.Email = &email
+		if emailVerified {
+			now := time.Now()
+			userEntity.EmailVerifiedAt = &now
+		}
+	}
 
 	// Save user to database
 	createdUser, err := service.repo.CreateUser(ctx, userEntity)
@@ -447,6 +479,10 @@ func (service *AuthService) Register(ctx context.Context, requestData *dto.Regis
 		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to create user", err)
 	}
 
+	if emailVerified {
+		service.attachEmailInvitations(ctx, createdUser.ID, email)
+	}
+
 	// Generate JWT tokens
 	accessToken, err := utils.GenerateToken(createdUser.ID, createdUser.Email, createdUser.Username, constants.ScopeTokenAccess)
 	if err != nil {
@@ -638,6 +674,10 @@ func (service *AuthService) HandleGoogleCallback(ctx context.Context, code strin
 		user = createdUser
 	}
 
+	if userInfo.VerifiedEmail {
+		service.attachEmailInvitations(ctx, user.ID, userInfo.Email)
+	}
+
 	provider, err := service.repo.GetOAuthProviderByName(ctx, "google")
 	if err != nil {
 		logger.Error("AuthService:HandleGoogleCallback:GetOAuthProviderByName:Error:", err)
@@ -848,6 +888,10 @@ func (service *AuthService) VerifyGoogleIdToken(ctx context.Context, idToken str
 		user = createdUser
 	}
 
+	if userInfo.VerifiedEmail {
+		service.attachEmailInvitations(ctx, user.ID, userInfo.Email)
+	}
+
 	// Get or create Google provider
 	provider, err := service.repo.GetOAuthProviderByName(ctx, "google")
 	if err != nil {
@@ -998,3 +1042,17 @@ func (service *AuthService) exchangeAuthCodeForTokens(ctx context.Context, serve
 
 	return result, nil
 }
+
+// SetInvitationLinker wires the invitation module so email-only invitations follow the user on sign up / Google sign in
+func (service *AuthService) SetInvitationLinker(linker InvitationLinker) {
+	service.invitationLinker = linker
+}
+
+func (service *AuthService) attachEmailInvitations(ctx context.Context, userID uuid.UUID, email string) {
+	if service.invitationLinker == nil || email == "" {
+		return
+	}
+	if err := service.invitationLinker.AttachEmailInvitations(ctx, userID, email); err != nil {
+		logger.Error("AuthService:attachEmailInvitations:Error", "user_id", userID, "error", err)
+	}
+}
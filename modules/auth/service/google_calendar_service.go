@@ -3,10 +3,11 @@ package service
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
-	"go-api-starter/core/config"
 	"go-api-starter/core/constants"
 	"go-api-starter/core/errors"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/logger"
 	"go-api-starter/core/params"
 	"go-api-starter/modules/auth/dto"
@@ -18,8 +19,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
 func (service *AuthService) GetGoogleCalendarEvents(ctx context.Context, userID uuid.UUID, params params.QueryParams, timeMin string, timeMax string) (*dto.PaginatedGoogleCalendarEventDTO, *errors.AppError) {
@@ -32,8 +31,8 @@ func (service *AuthService) GetGoogleCalendarEvents(ctx context.Context, userID
 		return nil, errors.NewAppError(errors.ErrUnauthorized, "Google OAuth token not found. Please login with Google again", nil)
 	}
 
-	apiURL := service.buildCalendarEventsURL(timeMin, timeMax)
-	allEvents, appErr := service.fetchGoogleCalendarEvents(ctx, apiURL, googleToken)
+	apiPath := service.buildCalendarEventsPath(timeMin, timeMax)
+	allEvents, appErr := service.fetchGoogleCalendarEvents(ctx, userID, apiPath, googleToken)
 	if appErr != nil {
 		return nil, appErr
 	}
@@ -86,7 +85,7 @@ func (service *AuthService) GetGoogleCalendarList(ctx context.Context, userID uu
 		return nil, errors.NewAppError(errors.ErrUnauthorized, "Google OAuth token not found. Please login with Google again", nil)
 	}
 
-	allCalendars, appErr := service.fetchGoogleCalendarList(ctx, googleToken)
+	allCalendars, appErr := service.fetchGoogleCalendarList(ctx, userID, googleToken)
 	if appErr != nil {
 		return nil, appErr
 	}
@@ -108,18 +107,13 @@ func (service *AuthService) GetGoogleCalendarList(ctx context.Context, userID uu
 	return mapper.ToPaginatedGoogleCalendarDTO(paginatedItems, totalItems, params.PageNumber, params.PageSize), nil
 }
 
-func (service *AuthService) fetchGoogleCalendarList(ctx context.Context, accessToken string) ([]dto.GoogleCalendar, *errors.AppError) {
-	apiURL := "https://www.googleapis.com/calendar/v3/users/me/calendarList"
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		logger.Error("AuthService:fetchGoogleCalendarList:NewRequest:Error", "error", err)
-		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to create request", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+func (service *AuthService) fetchGoogleCalendarList(ctx context.Context, userID uuid.UUID, accessToken string) ([]dto.GoogleCalendar, *errors.AppError) {
+	resp, err := service.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      http.MethodGet,
+		Path:        "/users/me/calendarList",
+		AccessToken: accessToken,
+	})
 	if err != nil {
 		logger.Error("AuthService:fetchGoogleCalendarList:DoRequest:Error", "error", err)
 		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to fetch calendar list", err)
@@ -149,8 +143,8 @@ func (service *AuthService) fetchGoogleCalendarList(ctx context.Context, accessT
 	return calendarListResponse.Items, nil
 }
 
-func (service *AuthService) buildCalendarEventsURL(timeMin string, timeMax string) string {
-	apiURL := "https://www.googleapis.com/calendar/v3/calendars/primary/events"
+func (service *AuthService) buildCalendarEventsPath(timeMin string, timeMax string) string {
+	apiPath := "/calendars/primary/events"
 	params := url.Values{}
 	params.Add("singleEvents", "true")
 	params.Add("orderBy", "startTime")
@@ -165,20 +159,16 @@ func (service *AuthService) buildCalendarEventsURL(timeMin string, timeMax strin
 		params.Add("timeMax", timeMax)
 	}
 
-	return apiURL + "?" + params.Encode()
+	return apiPath + "?" + params.Encode()
 }
 
-func (service *AuthService) fetchGoogleCalendarEvents(ctx context.Context, apiURL string, accessToken string) ([]dto.GoogleCalendarEvent, *errors.AppError) {
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		logger.Error("AuthService:fetchGoogleCalendarEvents:NewRequest:Error", "error", err)
-		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to create request", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+func (service *AuthService) fetchGoogleCalendarEvents(ctx context.Context, userID uuid.UUID, apiPath string, accessToken string) ([]dto.GoogleCalendarEvent, *errors.AppError) {
+	resp, err := service.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      http.MethodGet,
+		Path:        apiPath,
+		AccessToken: accessToken,
+	})
 	if err != nil {
 		logger.Error("AuthService:fetchGoogleCalendarEvents:DoRequest:Error", "error", err)
 		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to fetch calendar events", err)
@@ -209,88 +199,14 @@ func (service *AuthService) fetchGoogleCalendarEvents(ctx context.Context, apiUR
 }
 
 func (service *AuthService) getGoogleTokenForUser(ctx context.Context, userID uuid.UUID) (string, error) {
-	provider, err := service.repo.GetOAuthProviderByName(ctx, "google")
-	if err != nil {
-		return "", fmt.Errorf("failed to get Google provider: %w", err)
-	}
-	if provider == nil {
-		return "", fmt.Errorf("Google provider not found in database")
-	}
-
-	socialLogin, err := service.repo.GetSocialLoginByUserIDAndProvider(ctx, userID, provider.ID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get social login: %w", err)
-	}
-	if socialLogin == nil || socialLogin.AccessToken == nil {
+	token, err := service.tokens.AccessToken(ctx, userID, "google")
+	if goerrors.Is(err, ErrTokenNotFound) {
 		return "", fmt.Errorf("Google token not found for user %s. Please login with Google again", userID)
 	}
-
-	accessToken := *socialLogin.AccessToken
-	refreshToken := ""
-	if socialLogin.RefreshToken != nil {
-		refreshToken = *socialLogin.RefreshToken
-	}
-
-	var expiresAt time.Time
-	if socialLogin.TokenExpiresAt != nil {
-		expiresAt = *socialLogin.TokenExpiresAt
+	if goerrors.Is(err, ErrTokenRevoked) {
+		return "", fmt.Errorf("Google access was revoked for user %s. Please login with Google again", userID)
 	}
-
-	if !expiresAt.IsZero() && time.Now().After(expiresAt) {
-		if refreshToken == "" {
-			return "", fmt.Errorf("Google token expired and no refresh token available")
-		}
-
-		newToken, err := service.refreshGoogleToken(ctx, refreshToken)
-		if err != nil {
-			return "", fmt.Errorf("failed to refresh Google token: %w", err)
-		}
-
-		expiresAtTime := newToken.ExpiresAt
-		socialLogin.AccessToken = &newToken.AccessToken
-		if newToken.RefreshToken != "" {
-			socialLogin.RefreshToken = &newToken.RefreshToken
-		}
-		socialLogin.TokenExpiresAt = &expiresAtTime
-		socialLogin.LastLoginAt = &expiresAtTime
-
-		if err := service.repo.SaveOrUpdateSocialLogin(ctx, socialLogin); err != nil {
-			logger.Error("AuthService:getGoogleTokenForUser:SaveOrUpdateSocialLogin:Error", "error", err)
-			return "", fmt.Errorf("failed to update refreshed token: %w", err)
-		}
-
-		return newToken.AccessToken, nil
-	}
-
-	return accessToken, nil
-}
-
-func (service *AuthService) refreshGoogleToken(ctx context.Context, refreshToken string) (*GoogleToken, error) {
-	cfg, ok := config.GetSafe()
-	if !ok {
-		return nil, fmt.Errorf("config not initialized")
-	}
-
-	oauthConfig := &oauth2.Config{
-		ClientID:     cfg.GoogleAPI.ClientID,
-		ClientSecret: cfg.GoogleAPI.ClientSecret,
-		Endpoint:     google.Endpoint,
-	}
-
-	tokenSource := oauthConfig.TokenSource(ctx, &oauth2.Token{
-		RefreshToken: refreshToken,
-	})
-
-	newToken, err := tokenSource.Token()
-	if err != nil {
-		return nil, err
-	}
-
-	return &GoogleToken{
-		AccessToken:  newToken.AccessToken,
-		RefreshToken: newToken.RefreshToken,
-		ExpiresAt:    newToken.Expiry,
-	}, nil
+	return token, err
 }
 
 func (service *AuthService) GetGoogleAccessToken(ctx context.Context, userID uuid.UUID) (string, *errors.AppError) {
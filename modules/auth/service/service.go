@@ -4,6 +4,7 @@ import (
 	"context"
 	"go-api-starter/core/cache"
 	"go-api-starter/core/errors"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/params"
 	"go-api-starter/modules/auth/dto"
 	"go-api-starter/modules/auth/entity"
@@ -14,8 +15,16 @@ import (
 )
 
 type AuthService struct {
-	repo  repository.AuthRepositoryInterface
-	cache cache.Cache
+	repo             repository.AuthRepositoryInterface
+	cache            cache.Cache
+	invitationLinker InvitationLinker
+	tokens           *TokenStore
+	google           *googlecalendar.Client
+}
+
+// InvitationLinker attaches invitations sent to an email address to the account that owns it
+type InvitationLinker interface {
+	AttachEmailInvitations(ctx context.Context, userID uuid.UUID, email string) error
 }
 
 type GoogleToken struct {
@@ -26,8 +35,10 @@ type GoogleToken struct {
 
 func NewAuthService(repo repository.AuthRepositoryInterface, cache cache.Cache) AuthServiceInterface {
 	return &AuthService{
-		repo:  repo,
-		cache: cache,
+		repo:   repo,
+		cache:  cache,
+		tokens: NewTokenStore(repo),
+		google: googlecalendar.Default(),
 	}
 }
 
@@ -57,6 +68,7 @@ type AuthServiceInterface interface {
 
 	RefreshToken(ctx context.Context, token string) (*dto.RefreshTokenResponse, *errors.AppError)
 	GetUserByIdentifier(ctx context.Context, identifier string) (*dto.UserResponse, *errors.AppError)
+	SetInvitationLinker(linker InvitationLinker)
 
 	PrivateAssignRoleToUser(ctx context.Context, req *dto.UserRoleRequest) *errors.AppError
 	PrivateAssignPermissionToRole(ctx context.Context, req *dto.RolePermissionRequest) error
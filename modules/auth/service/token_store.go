@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"go-api-starter/core/config"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/auth/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/singleflight"
+)
+
+// TokenExpiryLeeway is how long an access token must stay valid to be handed out without a refresh
+const TokenExpiryLeeway = 5 * time.Minute
+
+var (
+	// ErrTokenNotFound means the user has no active social login with tokens for the provider
+	ErrTokenNotFound = goerrors.New("oauth token not found")
+	// ErrTokenRevoked means the provider rejected the refresh token (or there is none): the user has to sign in again
+	ErrTokenRevoked = goerrors.New("oauth token revoked")
+)
+
+// tokenRefreshes lets one refresh per user and provider run at a time across all token stores in the process;
+// concurrent callers wait for it and share its result
+var tokenRefreshes singleflight.Group
+
+// OAuthToken is a user's OAuth credentials for a provider
+type OAuthToken struct {
+	UserID       uuid.UUID
+	Provider     string
+	Email        string
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	// UpdatedAt is when the tokens were last stored
+	UpdatedAt time.Time
+	// Refreshed is set when the access token was refreshed to serve this call
+	Refreshed bool
+
+	providerID uuid.UUID
+}
+
+// expiresWithin reports whether the access token expires within d; a token without an expiry counts as expired
+func (t *OAuthToken) expiresWithin(d time.Duration) bool {
+	return t.ExpiresAt.IsZero() || time.Now().Add(d).After(t.ExpiresAt)
+}
+
+// TokenStore reads and refreshes users' OAuth tokens. The tokens are kept on the user's social login
+// (social_logins), the single place every caller of a provider API gets its credentials from.
+type TokenStore struct {
+	repo repository.AuthRepositoryInterface
+}
+
+func NewTokenStore(repo repository.AuthRepositoryInterface) *TokenStore {
+	return &TokenStore{repo: repo}
+}
+
+// Token returns the stored tokens as they are, without refreshing them
+func (s *TokenStore) Token(ctx context.Context, userID uuid.UUID, provider string) (*OAuthToken, error) {
+	oauthProvider, err := s.repo.GetOAuthProviderByName(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s provider: %w", provider, err)
+	}
+	if oauthProvider == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	socialLogin, err := s.repo.GetSocialLoginByUserIDAndProvider(ctx, userID, oauthProvider.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get social login: %w", err)
+	}
+	if socialLogin == nil || socialLogin.AccessToken == nil || *socialLogin.AccessToken == "" {
+		return nil, ErrTokenNotFound
+	}
+
+	token := &OAuthToken{
+		UserID:      userID,
+		Provider:    provider,
+		AccessToken: *socialLogin.AccessToken,
+		UpdatedAt:   socialLogin.UpdatedAt,
+		providerID:  oauthProvider.ID,
+	}
+	if socialLogin.ProviderEmail != nil {
+		token.Email = *socialLogin.ProviderEmail
+	}
+	if socialLogin.RefreshToken != nil {
+		token.RefreshToken = *socialLogin.RefreshToken
+	}
+	if socialLogin.TokenExpiresAt != nil {
+		token.ExpiresAt = *socialLogin.TokenExpiresAt
+	}
+	return token, nil
+}
+
+// AccessToken returns an access token that is valid for at least TokenExpiryLeeway
+func (s *TokenStore) AccessToken(ctx context.Context, userID uuid.UUID, provider string) (string, error) {
+	token, err := s.ValidToken(ctx, userID, provider, TokenExpiryLeeway)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// ValidToken returns the user's tokens with an access token valid for at least minValidity,
+// refreshing and storing it when it expires sooner.
+func (s *TokenStore) ValidToken(ctx context.Context, userID uuid.UUID, provider string, minValidity time.Duration) (*OAuthToken, error) {
+	token, err := s.Token(ctx, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+	if !token.expiresWithin(minValidity) {
+		return token, nil
+	}
+
+	// The refresh outlives a caller that gives up, so the callers sharing it still get the new token
+	flightCtx := context.WithoutCancel(ctx)
+	result, err, _ := tokenRefreshes.Do(provider+":"+userID.String(), func() (interface{}, error) {
+		return s.refresh(flightCtx, userID, provider, minValidity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshed := *result.(*OAuthToken)
+	return &refreshed, nil
+}
+
+// Save stores new tokens on the user's social login for the provider
+func (s *TokenStore) Save(ctx context.Context, userID uuid.UUID, provider string, accessToken, refreshToken string, expiresAt time.Time) error {
+	token, err := s.Token(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+	var storedRefreshToken *string
+	if refreshToken != "" {
+		storedRefreshToken = &refreshToken
+	}
+	return s.repo.UpdateSocialLoginTokens(ctx, userID, token.providerID, accessToken, storedRefreshToken, expiresAt)
+}
+
+// refresh runs inside the user's single flight
+func (s *TokenStore) refresh(ctx context.Context, userID uuid.UUID, provider string, minValidity time.Duration) (*OAuthToken, error) {
+	// Read again: a refresh that finished while this one was waiting to start already stored a new token
+	token, err := s.Token(ctx, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+	if !token.expiresWithin(minValidity) {
+		return token, nil
+	}
+	if token.RefreshToken == "" {
+		// Without an expiry there is no telling; let the provider API answer
+		if token.ExpiresAt.IsZero() || !token.expiresWithin(0) {
+			return token, nil
+		}
+		return nil, fmt.Errorf("%w: no refresh token", ErrTokenRevoked)
+	}
+
+	logger.Info("TokenStore:Refresh", "user_id", userID, "provider", provider)
+	newToken, err := refreshProviderToken(ctx, provider, token.RefreshToken)
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if goerrors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+			logger.Error("TokenStore:Refresh:InvalidGrant", "user_id", userID, "provider", provider, "description", retrieveErr.ErrorDescription)
+			return nil, fmt.Errorf("%w: invalid_grant: %s", ErrTokenRevoked, retrieveErr.ErrorDescription)
+		}
+		logger.Error("TokenStore:Refresh:Error", "user_id", userID, "provider", provider, "error", err)
+		return nil, fmt.Errorf("failed to refresh %s token: %w", provider, err)
+	}
+
+	token.AccessToken = newToken.AccessToken
+	token.ExpiresAt = newToken.ExpiresAt
+	token.Refreshed = true
+	// Providers that rotate refresh tokens return a new one; the old one stops working
+	var rotatedRefreshToken *string
+	if newToken.RefreshToken != "" && newToken.RefreshToken != token.RefreshToken {
+		token.RefreshToken = newToken.RefreshToken
+		rotatedRefreshToken = &newToken.RefreshToken
+	}
+	if err := s.repo.UpdateSocialLoginTokens(ctx, userID, token.providerID, token.AccessToken, rotatedRefreshToken, token.ExpiresAt); err != nil {
+		// The new access token still works for this call; the next one refreshes again
+		logger.Error("TokenStore:Refresh:Save:Error", "user_id", userID, "provider", provider, "error", err)
+	} else {
+		token.UpdatedAt = time.Now()
+	}
+	return token, nil
+}
+
+// refreshProviderToken exchanges a refresh token for a new access token
+func refreshProviderToken(ctx context.Context, provider string, refreshToken string) (*GoogleToken, error) {
+	if provider != "google" {
+		return nil, fmt.Errorf("token refresh is not supported for provider %s", provider)
+	}
+	cfg, ok := config.GetSafe()
+	if !ok {
+		return nil, fmt.Errorf("config not initialized")
+	}
+
+	oauthConfig := &oauth2.Config{
+		ClientID:     cfg.GoogleAPI.ClientID,
+		ClientSecret: cfg.GoogleAPI.ClientSecret,
+		Endpoint:     google.Endpoint,
+	}
+	newToken, err := oauthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoogleToken{
+		AccessToken:  newToken.AccessToken,
+		RefreshToken: newToken.RefreshToken,
+		ExpiresAt:    newToken.Expiry,
+	}, nil
+}
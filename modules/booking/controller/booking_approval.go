@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	bookingdto "go-api-starter/modules/booking/dto"
+	bookingentity "go-api-starter/modules/booking/entity"
+	caldto "go-api-starter/modules/calendar/dto"
+	meetentity "go-api-starter/modules/meeting/entity"
+	notifdto "go-api-starter/modules/notification/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bookingRequestPreferences is what a booking page request stores in events.preferences
+type bookingRequestPreferences struct {
+	GuestName  string                    `json:"guest_name"`
+	GuestEmail string                    `json:"guest_email"`
+	EventType  string                    `json:"event_type,omitempty"`
+	Answers    []bookingdto.IntakeAnswer `json:"answers,omitempty"`
+}
+
+// bookingRequestGuest reads the guest stored on a booking request event
+func bookingRequestGuest(ev *meetentity.Event) bookingRequestPreferences {
+	var p bookingRequestPreferences
+	if ev.Preferences != nil && *ev.Preferences != "" {
+		_ = json.Unmarshal([]byte(*ev.Preferences), &p)
+	}
+	p.GuestName = strings.TrimSpace(p.GuestName)
+	p.GuestEmail = strings.TrimSpace(p.GuestEmail)
+	return p
+}
+
+// acceptBookingRequest schedules a pending booking request on the host's Google Calendar,
+// marks it scheduled and confirms it to the guest
+func (b *BookingController) acceptBookingRequest(ctx context.Context, ev *meetentity.Event) (*caldto.CreateEventResponse, *errors.AppError) {
+	if ev.HostID == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event has no host", nil)
+	}
+	if ev.StartDate == nil || ev.EndDate == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "missing start/end", nil)
+	}
+	if appErr := b.checkRequestOpen(ctx, ev); appErr != nil {
+		return nil, appErr
+	}
+	// A paid event type is confirmed only once its order is paid
+	if appErr := b.checkBookingPaid(ctx, ev); appErr != nil {
+		return nil, appErr
+	}
+	guestEmail := bookingRequestGuest(ev).GuestEmail
+
+	// Get timezone, default to Asia/Ho_Chi_Minh if empty
+	timezone := ev.Timezone
+	if timezone == "" {
+		timezone = "Asia/Ho_Chi_Minh"
+	}
+
+	// IMPORTANT: Add 1 day to the booking time when accepting
+	// User selects time, but when accepting, automatically add 1 day
+	adjustedStartDate := ev.StartDate.AddDate(0, 0, 1)
+	adjustedEndDate := ev.EndDate.AddDate(0, 0, 1)
+
+	logger.Info("acceptBookingRequest:TimeAdjusted",
+		"event_id", ev.ID.String(),
+		"original_start", ev.StartDate.Format(time.RFC3339),
+		"adjusted_start", adjustedStartDate.Format(time.RFC3339),
+		"original_end", ev.EndDate.Format(time.RFC3339),
+		"adjusted_end", adjustedEndDate.Format(time.RFC3339))
+
+	req := &caldto.CreateEventRequest{
+		Title:       ev.Title,
+		Description: bookingEventDescription(ev),
+		StartTime:   formatTimeInTimezone(adjustedStartDate, timezone),
+		EndTime:     formatTimeInTimezone(adjustedEndDate, timezone),
+		Timezone:    timezone,
+	}
+	if guestEmail != "" {
+		req.Attendees = []string{guestEmail}
+	}
+	created, er := b.CalendarService.CreateEvent(ctx, *ev.HostID, req)
+	if er != nil {
+		return nil, errors.NewAppError(errors.ErrForbidden, er.Error(), er)
+	}
+	// Update event status and meeting_link
+	ev.Status = meetentity.EventStatusScheduled
+	if created.MeetingLink != "" {
+		link := created.MeetingLink
+		ev.MeetingLink = &link
+	}
+	if err := b.MeetingRepo.UpdateEvent(ctx, ev); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err)
+	}
+	b.linkProviderEvent(ctx, ev, created, adjustedStartDate)
+	// Emailed links of an answered request stop working
+	_ = b.BookingService.RevokeApprovalLinks(ctx, ev.ID)
+	b.emitBookingEvent(ctx, constants.WebhookEventBookingAccepted, ev, guestEmail)
+	b.recordBookingOutcome(ctx, ev, bookingentity.BookingPageStatAccepted)
+	b.sendBookingConfirmation(ctx, ev, adjustedStartDate, adjustedEndDate, guestEmail)
+	return created, nil
+}
+
+// autoAcceptBookingRequest accepts a new booking request when the host's approval policy allows it.
+// It returns false when the request has to wait for the host, including when scheduling it failed.
+func (b *BookingController) autoAcceptBookingRequest(ctx context.Context, ev *meetentity.Event) bool {
+	if ev.HostID == nil {
+		return false
+	}
+	guest := bookingRequestGuest(ev)
+	decision := b.BookingService.EvaluateApproval(ctx, *ev.HostID, guest.EventType, guest.GuestEmail)
+	if !decision.AutoAccept {
+		return false
+	}
+	if _, appErr := b.acceptBookingRequest(ctx, ev); appErr != nil {
+		logger.Warn("BookingController:autoAcceptBookingRequest:Accept:Error", "event_id", ev.ID.String(), "error", appErr)
+		return false
+	}
+
+	if b.NotificationSvc != nil {
+		_ = b.NotificationSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+			UserID:  *ev.HostID,
+			Title:   "Yêu cầu đặt lịch đã được tự động chấp nhận",
+			Message: ev.Title,
+			Type:    "booking_auto_accepted",
+			Data: map[string]interface{}{
+				"event_id":    ev.ID.String(),
+				"guest_name":  guest.GuestName,
+				"guest_email": guest.GuestEmail,
+				"event_type":  guest.EventType,
+				"reason":      decision.Reason,
+			},
+		})
+	}
+	return true
+}
+
+// ListApprovalPolicies returns the booking approval policies of the current user
+// @Summary Danh sách chính sách duyệt đặt lịch
+// @Description Chính sách duyệt yêu cầu đặt lịch theo loại sự kiện (event type "default" áp dụng khi không có chính sách riêng)
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} bookingdto.ApprovalPolicyResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/approval-policies [get]
+func (b *BookingController) ListApprovalPolicies(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.ListApprovalPolicies(c.Request().Context(), userID)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy chính sách duyệt thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// SaveApprovalPolicy creates or replaces the approval policy of an event type
+// @Summary Cập nhật chính sách duyệt đặt lịch
+// @Description mode: manual (duyệt thủ công), auto (luôn tự động chấp nhận), conditional (tự động chấp nhận khách thuộc tên miền cho phép hoặc khách quay lại)
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param event_type path string true "Loại sự kiện, ví dụ default"
+// @Param request body bookingdto.SaveApprovalPolicyRequest true "Chính sách duyệt"
+// @Success 200 {object} bookingdto.ApprovalPolicyResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/approval-policies/{event_type} [put]
+func (b *BookingController) SaveApprovalPolicy(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.SaveApprovalPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.SaveApprovalPolicy(c.Request().Context(), userID, c.Param("event_type"), &req)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Cập nhật chính sách duyệt thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// DeleteApprovalPolicy removes the approval policy of an event type
+// @Summary Xoá chính sách duyệt đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param event_type path string true "Loại sự kiện"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} errors.AppError
+// @Router /private/booking/approval-policies/{event_type} [delete]
+func (b *BookingController) DeleteApprovalPolicy(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	if appErr := b.BookingService.DeleteApprovalPolicy(c.Request().Context(), userID, c.Param("event_type")); appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Xoá chính sách duyệt thành công",
+		"timestamp": time.Now(),
+	})
+}
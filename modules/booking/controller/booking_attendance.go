@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"go-api-starter/core/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListGuestAttendance returns the no-show counts of the current user's booking guests
+// @Summary Số lần vắng mặt của khách đặt lịch
+// @Description Khách có ít nhất một lần no-show, kèm số lần đến muộn và đã tham gia. restriction cho biết chính sách no-show (no_show_threshold, no_show_action trong hồ sơ đặt lịch) đang áp dụng cho khách: require_approval hoặc block
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param email query string false "Chỉ lấy một khách"
+// @Success 200 {array} bookingdto.GuestAttendanceResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/guest-attendance [get]
+func (b *BookingController) ListGuestAttendance(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.ListGuestAttendance(c.Request().Context(), userID, c.QueryParam("email"))
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy số lần vắng mặt của khách thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
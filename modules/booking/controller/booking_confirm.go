@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"go-api-starter/core/errors"
+	bookingentity "go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Emailed links only open a confirmation page: mail scanners and link previews fetch every URL in a
+// message, so the action itself runs on the POST the page's button sends.
+
+// PublicTokenAcceptPage shows the confirmation page of an emailed accept link
+// @Summary Trang xác nhận chấp nhận yêu cầu đặt lịch
+// @Description Kiểm tra link chấp nhận trong email mà không dùng nó; yêu cầu chỉ được chấp nhận khi chủ lịch bấm nút (POST)
+// @Tags Booking
+// @Produce html
+// @Param id path string true "Event ID"
+// @Param token query string true "Token trong email"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /public/booking/requests/{id}/accept [get]
+func (b *BookingController) PublicTokenAcceptPage(c echo.Context) error {
+	return b.actionConfirmPage(c, bookingentity.BookingActionAccept)
+}
+
+// PublicTokenDeclinePage shows the confirmation page of an emailed decline link
+// @Summary Trang xác nhận từ chối yêu cầu đặt lịch
+// @Description Kiểm tra link từ chối trong email mà không dùng nó; yêu cầu chỉ bị từ chối khi chủ lịch bấm nút (POST)
+// @Tags Booking
+// @Produce html
+// @Param id path string true "Event ID"
+// @Param token query string true "Token trong email"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /public/booking/requests/{id}/decline [get]
+func (b *BookingController) PublicTokenDeclinePage(c echo.Context) error {
+	return b.actionConfirmPage(c, bookingentity.BookingActionDecline)
+}
+
+func (b *BookingController) actionConfirmPage(c echo.Context, action bookingentity.BookingAction) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing token", nil))
+	}
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
+	}
+	ev, err := b.MeetingRepo.GetEventByID(c.Request().Context(), eventID)
+	if err != nil || ev == nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
+	}
+	if appErr := b.checkRequestOpen(c.Request().Context(), ev); appErr != nil {
+		return c.JSON(http.StatusConflict, appErr)
+	}
+	if _, appErr := b.BookingService.CheckActionToken(c.Request().Context(), ev.ID, action, token); appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+
+	title, button, color := "Chấp nhận yêu cầu đặt lịch?", "Chấp nhận", "#10b981"
+	if action == bookingentity.BookingActionDecline {
+		title, button, color = "Từ chối yêu cầu đặt lịch?", "Từ chối", "#ef4444"
+	}
+	actionURL := fmt.Sprintf("/api/v1/public/booking/requests/%s/%s", ev.ID, action)
+	return c.HTML(http.StatusOK, confirmActionPage(title, "Sự kiện: "+ev.Title, actionURL, token, button, color))
+}
+
+// confirmActionPage renders a page whose single button posts token to actionURL
+func confirmActionPage(title, message, actionURL, token, button, color string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="vi">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>%s | SmartMeet</title>
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, "Helvetica Neue", Arial, sans-serif; background: #f4f5fb; margin: 0; padding: 40px 20px; }
+		.container { max-width: 480px; margin: 0 auto; background: white; border-radius: 20px; box-shadow: 0 20px 60px rgba(0, 0, 0, 0.1); padding: 40px; text-align: center; }
+		h1 { font-size: 24px; color: #1e293b; }
+		p { color: #64748b; }
+		button { border: 0; border-radius: 8px; color: white; cursor: pointer; font-size: 16px; font-weight: 600; padding: 12px 28px; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<h1>%s</h1>
+		<p>%s</p>
+		<form method="POST" action="%s">
+			<input type="hidden" name="token" value="%s">
+			<button type="submit" style="background: %s">%s</button>
+		</form>
+	</div>
+</body>
+</html>`,
+		html.EscapeString(title),
+		html.EscapeString(title),
+		html.EscapeString(message),
+		html.EscapeString(actionURL),
+		html.EscapeString(token),
+		color,
+		html.EscapeString(button),
+	)
+}
@@ -1,19 +1,22 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"html"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
-	"go-api-starter/core/config"
 	"go-api-starter/core/constants"
 	"go-api-starter/core/errors"
 	"go-api-starter/core/logger"
 	"go-api-starter/core/utils"
 	authservice "go-api-starter/modules/auth/service"
+	bookingdto "go-api-starter/modules/booking/dto"
+	bookingentity "go-api-starter/modules/booking/entity"
 	bookingsvc "go-api-starter/modules/booking/service"
 	caldto "go-api-starter/modules/calendar/dto"
 	calsvc "go-api-starter/modules/calendar/service"
@@ -21,6 +24,7 @@ import (
 	meetrepo "go-api-starter/modules/meeting/repository"
 	notifdto "go-api-starter/modules/notification/dto"
 	notifsvc "go-api-starter/modules/notification/service"
+	webhooksvc "go-api-starter/modules/webhook/service"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -32,134 +36,32 @@ type BookingController struct {
 	MeetingRepo     meetrepo.MeetingRepositoryInterface
 	NotificationSvc *notifsvc.NotificationService
 	BookingService  bookingsvc.BookingService
+	WebhookSvc      *webhooksvc.WebhookService
 }
 
-func NewBookingController(cal calsvc.CalendarService, auth authservice.AuthServiceInterface, meetingRepo meetrepo.MeetingRepositoryInterface, notif *notifsvc.NotificationService, bookingSvc bookingsvc.BookingService) *BookingController {
+func NewBookingController(cal calsvc.CalendarService, auth authservice.AuthServiceInterface, meetingRepo meetrepo.MeetingRepositoryInterface, notif *notifsvc.NotificationService, bookingSvc bookingsvc.BookingService, webhookSvc *webhooksvc.WebhookService) *BookingController {
 	return &BookingController{
 		CalendarService: cal,
 		AuthService:     auth,
 		MeetingRepo:     meetingRepo,
 		NotificationSvc: notif,
 		BookingService:  bookingSvc,
+		WebhookSvc:      webhookSvc,
 	}
 }
 
 func (b *BookingController) PublicPage(c echo.Context) error {
 	slug := c.Param("slug")
-	html := `
-<!doctype html>
-<html>
-<head>
-<meta charset="utf-8">
-<meta name="viewport" content="width=device-width,initial-scale=1">
-<title>Personal Booking</title>
-<style>
-:root{--bg:#f7f7f9;--fg:#111;--muted:#666;--primary:#2563eb;--border:#ddd}
-body{font-family:Inter,Arial,Helvetica,sans-serif;margin:0;background:var(--bg);color:var(--fg)}
-.container{max-width:980px;margin:40px auto;padding:0 20px}
-.header{display:flex;gap:12px;align-items:center;margin-bottom:24px}
-.avatar{width:48px;height:48px;border-radius:999px;background:#e5e7eb;display:flex;align-items:center;justify-content:center;font-weight:700}
-.title{font-size:20px;font-weight:700}
-.subtitle{color:var(--muted)}
-.grid{display:grid;grid-template-columns:1fr 340px;gap:20px}
-.card{background:#fff;border:1px solid var(--border);border-radius:12px;padding:16px}
-.calendar{display:grid;grid-template-columns:repeat(7,1fr);gap:10px;margin-top:12px}
-.day{background:#fff;border:1px solid var(--border);border-radius:10px;height:64px;display:flex;align-items:center;justify-content:center;cursor:pointer}
-.day.active{border-color:var(--primary);box-shadow:0 0 0 2px rgba(37,99,235,.2)}
-.nav{display:flex;justify-content:space-between;align-items:center;margin-top:8px}
-.slots{display:flex;flex-wrap:wrap;gap:8px;margin-top:12px}
-.slot{border:1px solid var(--border);border-radius:8px;padding:8px 12px;background:#fff;cursor:pointer}
-.slot.active{border-color:var(--primary);background:#eef2ff}
-.btn{background:var(--primary);color:#fff;border:none;border-radius:8px;padding:10px 14px;cursor:pointer}
-.btn:disabled{opacity:.6;cursor:not-allowed}
-.muted{color:var(--muted)}
-.row{display:flex;gap:10px;align-items:center;margin-top:10px}
-input{padding:8px;border:1px solid var(--border);border-radius:8px;width:100%}
-</style>
-</head>
-<body>
-<div class="container">
-  <div class="header">
-    <div class="avatar">G</div>
-    <div>
-      <div class="title">` + slug + `</div>
-      <div class="subtitle">Schedule a meeting with me</div>
-    </div>
-  </div>
-  <div class="grid">
-    <div class="card">
-      <div class="nav">
-        <button id="prev" class="btn" style="background:#eee;color:#111">‹</button>
-        <div id="monthTitle" class="title" style="font-size:18px">Month</div>
-        <button id="next" class="btn" style="background:#eee;color:#111">›</button>
-      </div>
-      <div class="calendar" id="calendar"></div>
-      <div class="slots" id="slots"></div>
-    </div>
-    <div class="card">
-      <div class="title" style="font-size:18px">30 min meeting</div>
-      <div class="muted">Date TBD<br>Google Meet<br>You'll receive a calendar invitation and meeting link via email</div>
-      <div class="row"><input id="name" placeholder="Your name"></div>
-      <div class="row"><input id="email" placeholder="Your email"></div>
-      <div class="row"><button id="book" class="btn" disabled>Book selected</button></div>
-    </div>
-  </div>
-</div>
-<script>
-const slug = ` + `"` + slug + `"` + `;
-const $ = id => document.getElementById(id)
-let current = new Date()
-let selectedDay = null
-let selectedSlot = null
-function monthName(y,m){return new Date(y,m,1).toLocaleString('en-US',{month:'long'})+' '+y}
-function startOfMonth(d){return new Date(d.getFullYear(), d.getMonth(), 1)}
-function endOfMonth(d){return new Date(d.getFullYear(), d.getMonth()+1, 0)}
-function dayStartVN(date){const y=date.getFullYear(),m=('0'+(date.getMonth()+1)).slice(-2),d=('0'+date.getDate()).slice(-2);return y+'-'+m+'-'+d+'T00:00:00+07:00'}
-function dayEndVN(date){const y=date.getFullYear(),m=('0'+(date.getMonth()+1)).slice(-2),d=('0'+date.getDate()).slice(-2);return y+'-'+m+'-'+d+'T23:59:59+07:00'}
-async function loadSlotsForDay(date){
-  selectedSlot=null
-  const root=$('slots'); root.innerHTML=''
-  const interval=30
-  const url='/api/v1/public/booking/'+encodeURIComponent(slug)+'/free?start_time='+encodeURIComponent(dayStartVN(date))+'&end_time='+encodeURIComponent(dayEndVN(date))+'&interval='+encodeURIComponent(interval)
-  const res=await fetch(url)
-  const data=await res.json()
-  const slots=(data&&data.slots)||[]
-  slots.forEach(s=>{
-    const t=s.start.split('T')[1].slice(0,5)
-    const el=document.createElement('div'); el.className='slot'; el.textContent=t
-    el.onclick=()=>{selectedSlot={start:s.start, end:s.end}; setActiveSlot(el)}
-    root.appendChild(el)
-  })
-  $('book').disabled=!selectedSlot
-}
-function setActiveSlot(el){document.querySelectorAll('.slot').forEach(s=>s.classList.remove('active')); el.classList.add('active'); $('book').disabled=false}
-function buildCalendar(d){
-  const y=d.getFullYear(), m=d.getMonth()
-  $('monthTitle').textContent=monthName(y,m)
-  const grid=$('calendar'); grid.innerHTML=''
-  const first=startOfMonth(d), last=endOfMonth(d)
-  const firstDayOfWeek=first.getDay()
-  for(let i=0;i<firstDayOfWeek;i++){const ph=document.createElement('div'); ph.className='day'; grid.appendChild(ph)}
-  for(let day=1; day<=last.getDate(); day++){
-    const date=new Date(y,m,day)
-    const el=document.createElement('div'); el.className='day'; el.textContent=day
-    el.onclick=()=>{document.querySelectorAll('.day').forEach(d=>d.classList.remove('active')); el.classList.add('active'); selectedDay=date; loadSlotsForDay(date)}
-    grid.appendChild(el)
-  }
-}
-$('prev').onclick=()=>{current=new Date(current.getFullYear(), current.getMonth()-1, 1); buildCalendar(current)}
-$('next').onclick=()=>{current=new Date(current.getFullYear(), current.getMonth()+1, 1); buildCalendar(current)}
-buildCalendar(current)
-$('book').onclick=async ()=>{
-  if(!selectedSlot) return
-  const payload={start_time:selectedSlot.start,end_time:selectedSlot.end,name:$('name').value,email:$('email').value}
-  const res=await fetch('/api/v1/public/booking/'+encodeURIComponent(slug)+'/schedule',{method:'POST',headers:{'Content-Type':'application/json'},body:JSON.stringify(payload)})
-  const j=await res.json(); alert((j&&j.message)||'Booked')
-}
-</script>
-</body>
-</html>`
-	return c.HTML(http.StatusOK, html)
+	// Links to a slug the host gave up keep working
+	if resolved, appErr := b.BookingService.ResolveBookingSlug(c.Request().Context(), slug); appErr == nil && resolved != nil && resolved.Moved {
+		target := "/p/" + url.PathEscape(resolved.Slug)
+		if query := c.QueryString(); query != "" {
+			target += "?" + query
+		}
+		return c.Redirect(http.StatusMovedPermanently, target)
+	}
+	b.recordPageView(c.Request().Context(), slug)
+	return b.renderBookingPage(c, slug, "free", slug)
 }
 
 func (b *BookingController) PublicPersonalPage(c echo.Context) error {
@@ -167,446 +69,13 @@ func (b *BookingController) PublicPersonalPage(c echo.Context) error {
 	token := c.QueryParam("token")
 	accept := c.QueryParam("accept")
 	
-	// If token and accept are provided, handle accept action
+	// accept=true links only lead to the confirmation page; the request is accepted by its POST
 	if token != "" && accept == "true" {
-		return b.handleAcceptFromPersonalPage(c, id, token)
+		target := fmt.Sprintf("/api/v1/public/booking/requests/%s/accept?token=%s", url.PathEscape(id), url.QueryEscape(token))
+		return c.Redirect(http.StatusFound, target)
 	}
-	
-	html := `
-<!doctype html>
-<html>
-<head>
-<meta charset="utf-8">
-<meta name="viewport" content="width=device-width,initial-scale=1">
-<title>Personal Booking</title>
-<style>
-:root{--bg:#f7f7f9;--fg:#111;--muted:#666;--primary:#2563eb;--border:#ddd}
-body{font-family:Inter,Arial,Helvetica,sans-serif;margin:0;background:var(--bg);color:var(--fg)}
-.container{max-width:980px;margin:40px auto;padding:0 20px}
-.header{display:flex;gap:12px;align-items:center;margin-bottom:24px}
-.avatar{width:48px;height:48px;border-radius:999px;background:#e5e7eb;display:flex;align-items:center;justify-content:center;font-weight:700}
-.title{font-size:20px;font-weight:700}
-.subtitle{color:var(--muted)}
-.grid{display:grid;grid-template-columns:1fr 340px;gap:20px}
-.card{background:#fff;border:1px solid var(--border);border-radius:12px;padding:16px}
-.calendar{display:grid;grid-template-columns:repeat(7,1fr);gap:10px;margin-top:12px}
-.day{background:#fff;border:1px solid var(--border);border-radius:10px;height:64px;display:flex;align-items:center;justify-content:center;cursor:pointer}
-.day.active{border-color:var(--primary);box-shadow:0 0 0 2px rgba(37,99,235,.2)}
-.nav{display:flex;justify-content:space-between;align-items:center;margin-top:8px}
-.slots{display:flex;flex-wrap:wrap;gap:8px;margin-top:12px}
-.slot{border:1px solid var(--border);border-radius:8px;padding:8px 12px;background:#fff;cursor:pointer}
-.slot.active{border-color:var(--primary);background:#eef2ff}
-.btn{background:var(--primary);color:#fff;border:none;border-radius:8px;padding:10px 14px;cursor:pointer}
-.btn:disabled{opacity:.6;cursor:not-allowed}
-.muted{color:var(--muted)}
-.row{display:flex;gap:10px;align-items:center;margin-top:10px}
-input{padding:8px;border:1px solid var(--border);border-radius:8px;width:100%}
-</style>
-</head>
-<body>
-<div class="container">
-  <div class="header">
-    <div class="avatar">G</div>
-    <div>
-      <div class="title">Personal Booking</div>
-      <div class="subtitle">Schedule a meeting with me</div>
-    </div>
-  </div>
-  <div class="grid">
-    <div class="card">
-      <div class="nav">
-        <button id="prev" class="btn" style="background:#eee;color:#111">‹</button>
-        <div id="monthTitle" class="title" style="font-size:18px">Month</div>
-        <button id="next" class="btn" style="background:#eee;color:#111">›</button>
-      </div>
-      <div class="calendar" id="calendar"></div>
-      <div class="slots" id="slots"></div>
-    </div>
-    <div class="card">
-      <div class="title" style="font-size:18px">30 min meeting</div>
-      <div class="muted">Date TBD<br>Google Meet<br>You'll receive a calendar invitation and meeting link via email</div>
-      <div class="row"><input id="name" placeholder="Your name"></div>
-      <div class="row"><input id="email" placeholder="Your email"></div>
-      <div class="row"><button id="book" class="btn" disabled>Book selected</button></div>
-    </div>
-  </div>
-</div>
-<script>
-const id = ` + `"` + id + `"` + `;
-const $ = id => document.getElementById(id)
-let current = new Date()
-let selectedDay = null
-let selectedSlot = null
-function monthName(y,m){return new Date(y,m,1).toLocaleString('en-US',{month:'long'})+' '+y}
-function startOfMonth(d){return new Date(d.getFullYear(), d.getMonth(), 1)}
-function endOfMonth(d){return new Date(d.getFullYear(), d.getMonth()+1, 0)}
-function dayStartVN(date){const y=date.getFullYear(),m=('0'+(date.getMonth()+1)).slice(-2),d=('0'+date.getDate()).slice(-2);return y+'-'+m+'-'+d+'T00:00:00+07:00'}
-function dayEndVN(date){const y=date.getFullYear(),m=('0'+(date.getMonth()+1)).slice(-2),d=('0'+date.getDate()).slice(-2);return y+'-'+m+'-'+d+'T23:59:59+07:00'}
-async function loadSlotsForDay(date){
-  selectedSlot=null
-  const root=$('slots'); root.innerHTML=''
-  try {
-  const body={duration_minutes:30, days_ahead:1, start_date: date.toISOString().split('T')[0], time_preference:'', working_hours_only:false}
-  const res=await fetch('/api/v1/public/booking/'+encodeURIComponent(id)+'/suggested-slots',{method:'POST',headers:{'Content-Type':'application/json'},body:JSON.stringify(body)})
-  const data=await res.json()
-  const slots=(data&&data.slots)||[]
-  // Helper function to convert UTC time to VN timezone and format
-  const formatTimeVN = (timeStr) => {
-    const d = new Date(timeStr)
-    // Use Intl.DateTimeFormat to get VN time components accurately
-    const formatter = new Intl.DateTimeFormat('en-CA', {
-      timeZone: 'Asia/Ho_Chi_Minh',
-      year: 'numeric',
-      month: '2-digit',
-      day: '2-digit',
-      hour: '2-digit',
-      minute: '2-digit',
-      second: '2-digit',
-      hour12: false
-    })
-    const parts = formatter.formatToParts(d)
-    const y = parts.find(p => p.type === 'year').value
-    const m = parts.find(p => p.type === 'month').value
-    const day = parts.find(p => p.type === 'day').value
-    const h = parts.find(p => p.type === 'hour').value
-    const min = parts.find(p => p.type === 'minute').value
-    const sec = parts.find(p => p.type === 'second').value
-    return {date: y + '-' + m + '-' + day, time: h + ':' + min, full: y + '-' + m + '-' + day + 'T' + h + ':' + min + ':' + sec + '+07:00'}
-  }
-  
-  slots.forEach(s=>{
-    const startTime = s.start_time || ''
-    const endTime = s.end_time || ''
-    // Convert to VN timezone for display
-    const startVN = formatTimeVN(startTime)
-    // Display time (HH:mm)
-    const el=document.createElement('div'); el.className='slot'; el.textContent=startVN.time
-    el.onclick=()=>{
-      // When user selects slot, use the same VN timezone format
-      const endVN = formatTimeVN(endTime)
-      selectedSlot={start:startVN.full, end:endVN.full}
-      setActiveSlot(el)
-    }
-    root.appendChild(el)
-  })
-  } catch (e) {
-    const el=document.createElement('div'); el.className='muted'; el.textContent='Failed to load slots'
-    root.appendChild(el)
-  }
-  $('book').disabled=!selectedSlot
-}
-function setActiveSlot(el){document.querySelectorAll('.slot').forEach(s=>s.classList.remove('active')); el.classList.add('active'); $('book').disabled=false}
-function buildCalendar(d){
-  const y=d.getFullYear(), m=d.getMonth()
-  $('monthTitle').textContent=monthName(y,m)
-  const grid=$('calendar'); grid.innerHTML=''
-  const first=startOfMonth(d), last=endOfMonth(d)
-  const firstDayOfWeek=first.getDay()
-  for(let i=0;i<firstDayOfWeek;i++){const ph=document.createElement('div'); ph.className='day'; grid.appendChild(ph)}
-  for(let day=1; day<=last.getDate(); day++){
-    const date=new Date(y,m,day)
-    const el=document.createElement('div'); el.className='day'; el.textContent=day
-    el.onclick=()=>{document.querySelectorAll('.day').forEach(d=>d.classList.remove('active')); el.classList.add('active'); selectedDay=date; loadSlotsForDay(date)}
-    grid.appendChild(el)
-  }
-}
-$('prev').onclick=()=>{current=new Date(current.getFullYear(), current.getMonth()-1, 1); buildCalendar(current)}
-$('next').onclick=()=>{current=new Date(current.getFullYear(), current.getMonth()+1, 1); buildCalendar(current)}
-buildCalendar(current)
-$('book').onclick=async ()=>{
-  if(!selectedSlot) return
-  const payload={start_time:selectedSlot.start,end_time:selectedSlot.end,name:$('name').value,email:$('email').value}
-  const res=await fetch('/api/v1/public/booking/'+encodeURIComponent(id)+'/schedule',{method:'POST',headers:{'Content-Type':'application/json'},body:JSON.stringify(payload)})
-  const j=await res.json(); alert((j&&j.message)||'Booked')
-}
-</script>
-</body>
-</html>`
-	return c.HTML(http.StatusOK, html)
-}
-
-// handleAcceptFromPersonalPage handles accept action from personal booking page
-func (b *BookingController) handleAcceptFromPersonalPage(c echo.Context, idStr, token string) error {
-	ctx := c.Request().Context()
-	eventID, err := uuid.Parse(idStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
-	}
-	
-	ev, err := b.MeetingRepo.GetEventByID(ctx, eventID)
-	if err != nil || ev == nil {
-		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
-	}
-	
-	claims, err := utils.ValidateAndParseToken(token)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "invalid token", err))
-	}
-	
-	if ev.HostID == nil || *ev.HostID != claims.UserID {
-		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
-	}
-	
-	if ev.StartDate == nil || ev.EndDate == nil {
-		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing start/end", nil))
-	}
-	
-	guestEmail := ""
-	if ev.Preferences != nil && *ev.Preferences != "" {
-		type Pref struct {
-			GuestName  string `json:"guest_name"`
-			GuestEmail string `json:"guest_email"`
-		}
-		var p Pref
-		_ = json.Unmarshal([]byte(*ev.Preferences), &p)
-		guestEmail = strings.TrimSpace(p.GuestEmail)
-	}
-	
-	// Get timezone, default to Asia/Ho_Chi_Minh if empty
-	timezone := ev.Timezone
-	if timezone == "" {
-		timezone = "Asia/Ho_Chi_Minh"
-	}
-	
-	// IMPORTANT: Add 1 day to the booking time when accepting
-	// User selects time, but when accepting, automatically add 1 day
-	adjustedStartDate := ev.StartDate.AddDate(0, 0, 1)
-	adjustedEndDate := ev.EndDate.AddDate(0, 0, 1)
-	
-	logger.Info("handleAcceptFromPersonalPage:TimeAdjusted",
-		"event_id", eventID.String(),
-		"original_start", ev.StartDate.Format(time.RFC3339),
-		"adjusted_start", adjustedStartDate.Format(time.RFC3339),
-		"original_end", ev.EndDate.Format(time.RFC3339),
-		"adjusted_end", adjustedEndDate.Format(time.RFC3339))
-	
-	req := &caldto.CreateEventRequest{
-		Title:       ev.Title,
-		Description: "Personal booking",
-		StartTime:   formatTimeInTimezone(adjustedStartDate, timezone),
-		EndTime:     formatTimeInTimezone(adjustedEndDate, timezone),
-		Timezone:    timezone,
-	}
-	if guestEmail != "" {
-		req.Attendees = []string{guestEmail}
-	}
-	
-	created, er := b.CalendarService.CreateEvent(ctx, claims.UserID, req)
-	if er != nil {
-		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, er.Error(), er))
-	}
-	
-	ev.Status = meetentity.EventStatusScheduled
-	if created.MeetingLink != "" {
-		link := created.MeetingLink
-		ev.MeetingLink = &link
-	}
-	if err := b.MeetingRepo.UpdateEvent(ctx, ev); err != nil {
-		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
-	}
-
-	// Format event time for display (with +1 day adjustment)
-	eventTimeStr := "Chưa xác định"
-	if ev.StartDate != nil && ev.EndDate != nil {
-		// Add 1 day for display (same as when creating calendar event)
-		adjustedStartDate := ev.StartDate.AddDate(0, 0, 1)
-		adjustedEndDate := ev.EndDate.AddDate(0, 0, 1)
-		// Convert to VN timezone for display
-		vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-		startVN := adjustedStartDate.In(vnLoc)
-		endVN := adjustedEndDate.In(vnLoc)
-		startTime := startVN.Format("15:04")
-		endTime := endVN.Format("15:04")
-		dateStr := startVN.Format("02/01/2006")
-		eventTimeStr = fmt.Sprintf("%s, %s - %s", dateStr, startTime, endTime)
-	}
-
-	// Return HTML success page
-	html := fmt.Sprintf(`<!DOCTYPE html>
-<html lang="vi">
-<head>
-	<meta charset="UTF-8">
-	<meta name="viewport" content="width=device-width, initial-scale=1.0">
-	<title>Lịch đã được chấp nhận | SmartMeet</title>
-	<style>
-		* {
-			margin: 0;
-			padding: 0;
-			box-sizing: border-box;
-		}
-		
-		body {
-			font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, "Helvetica Neue", Arial, sans-serif;
-			background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%);
-			min-height: 100vh;
-			display: flex;
-			align-items: center;
-			justify-content: center;
-			padding: 20px;
-		}
-		
-		.container {
-			background: white;
-			border-radius: 20px;
-			box-shadow: 0 20px 60px rgba(0, 0, 0, 0.3);
-			padding: 48px;
-			max-width: 500px;
-			width: 100%%;
-			text-align: center;
-		}
-		
-		.success-icon {
-			width: 80px;
-			height: 80px;
-			border-radius: 50%%;
-			background: #10b981;
-			display: flex;
-			align-items: center;
-			justify-content: center;
-			margin: 0 auto 24px;
-			animation: scaleIn 0.5s ease-out;
-		}
-		
-		.success-icon::before {
-			content: "✓";
-			color: white;
-			font-size: 48px;
-			font-weight: bold;
-		}
-		
-		@keyframes scaleIn {
-			from {
-				transform: scale(0);
-			}
-			to {
-				transform: scale(1);
-			}
-		}
-		
-		h1 {
-			font-size: 28px;
-			color: #1e293b;
-			margin-bottom: 12px;
-			font-weight: 700;
-		}
-		
-		.message {
-			font-size: 16px;
-			color: #64748b;
-			margin-bottom: 32px;
-			line-height: 1.6;
-		}
-		
-		.event-details {
-			background: #f8fafc;
-			border-radius: 12px;
-			padding: 24px;
-			margin-bottom: 32px;
-			text-align: left;
-		}
-		
-		.event-details h2 {
-			font-size: 18px;
-			color: #1e293b;
-			margin-bottom: 16px;
-			font-weight: 600;
-		}
-		
-		.detail-row {
-			display: flex;
-			align-items: center;
-			margin-bottom: 12px;
-			font-size: 14px;
-			color: #475569;
-		}
-		
-		.detail-row:last-child {
-			margin-bottom: 0;
-		}
-		
-		.detail-label {
-			font-weight: 600;
-			color: #64748b;
-			min-width: 100px;
-		}
-		
-		.detail-value {
-			color: #1e293b;
-			flex: 1;
-		}
-		
-		.meeting-link {
-			display: inline-block;
-			background: #2563eb;
-			color: white;
-			text-decoration: none;
-			padding: 12px 24px;
-			border-radius: 8px;
-			font-weight: 600;
-			margin-top: 8px;
-			transition: background 0.2s;
-		}
-		
-		.meeting-link:hover {
-			background: #1d4ed8;
-		}
-		
-		.close-btn {
-			background: #e2e8f0;
-			color: #475569;
-			border: none;
-			padding: 12px 24px;
-			border-radius: 8px;
-			font-weight: 600;
-			cursor: pointer;
-			transition: background 0.2s;
-		}
-		
-		.close-btn:hover {
-			background: #cbd5e1;
-		}
-	</style>
-</head>
-<body>
-	<div class="container">
-		<div class="success-icon"></div>
-		<h1>Lịch đã được chấp nhận!</h1>
-		<p class="message">Yêu cầu đặt lịch của bạn đã được chấp nhận. Sự kiện đã được thêm vào lịch.</p>
-		
-		<div class="event-details">
-			<h2>Chi tiết sự kiện</h2>
-			<div class="detail-row">
-				<span class="detail-label">Tiêu đề:</span>
-				<span class="detail-value">%s</span>
-			</div>
-			<div class="detail-row">
-				<span class="detail-label">Thời gian:</span>
-				<span class="detail-value">%s</span>
-			</div>
-			%s
-		</div>
-		
-		<button class="close-btn" onclick="window.close()">Đóng</button>
-	</div>
-</body>
-</html>`,
-		html.EscapeString(ev.Title),
-		eventTimeStr,
-		func() string {
-			if ev.MeetingLink != nil && *ev.MeetingLink != "" {
-				return fmt.Sprintf(`
-			<div class="detail-row">
-				<span class="detail-label">Meeting Link:</span>
-				<span class="detail-value">
-					<a href="%s" target="_blank" class="meeting-link">Tham gia Google Meet</a>
-				</span>
-			</div>`, html.EscapeString(*ev.MeetingLink))
-			}
-			return ""
-		}(),
-	)
-
-	return c.HTML(http.StatusOK, html)
+	b.recordPageView(c.Request().Context(), id)
+	return b.renderBookingPage(c, id, "suggested", "")
 }
 
 func (b *BookingController) PublicSuggestedSlots(c echo.Context) error {
@@ -654,25 +123,16 @@ func (b *BookingController) PublicFreeSlots(c echo.Context) error {
 	if intervalStr != "" {
 		interval = utils.ToNumberWithDefault(intervalStr, 30)
 	}
-	slID, ok := tryParseUUID(slug)
-	var userID uuid.UUID
-	if ok {
-		uid, appErr := b.AuthService.GetUserIDBySocialLoginID(ctx, slID)
-		if appErr != nil {
-			return c.JSON(http.StatusNotFound, appErr)
-		}
-		userID = uid
-	} else {
-		sl, appErr := b.AuthService.GetSocialLoginBySlug(ctx, slug)
-		if appErr != nil || sl == nil {
-			return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "not found", nil))
-		}
-		userID = sl.UserID
+	userID, _, appErr := b.resolveBookingHost(ctx, slug)
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "not found", nil))
 	}
 	busy, ferr := b.CalendarService.GetFreeBusy(ctx, userID, start, end)
 	if ferr != nil {
 		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, ferr.Error(), ferr))
 	}
+	// Requests still waiting for the host hold their slot until they expire
+	busy = append(busy, b.BookingService.HeldBookingSlots(ctx, userID, start, end)...)
 	slots := computeFreeSlots(start, end, busy, interval, window)
 	return c.JSON(http.StatusOK, map[string]any{"slots": slots})
 }
@@ -680,15 +140,67 @@ func (b *BookingController) PublicFreeSlots(c echo.Context) error {
 func (b *BookingController) PublicSchedule(c echo.Context) error {
 	ctx := c.Request().Context()
 	slug := c.Param("slug")
-	var req struct {
-		Name      string `json:"name"`
-		Email     string `json:"email"`
-		StartTime string `json:"start_time"`
-		EndTime   string `json:"end_time"`
-	}
+	var req bookingdto.PublicBookingRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid body", nil))
 	}
+	// Bots fill the hidden honeypot field; they get the usual answer and nothing is created
+	if strings.TrimSpace(req.Website) != "" {
+		logger.Info("PublicSchedule:Honeypot", "slug", slug, "ip", c.RealIP())
+		return c.JSON(http.StatusOK, ignoredBookingResponse())
+	}
+	if !utils.IsValidEmail(strings.TrimSpace(req.Email)) {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidEmail, "a valid email is required", nil))
+	}
+	if _, err := time.Parse(time.RFC3339, req.StartTime); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid start_time", nil))
+	}
+	if _, err := time.Parse(time.RFC3339, req.EndTime); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid end_time", nil))
+	}
+	if appErr := b.BookingService.CheckBookingRateLimit(ctx, c.RealIP(), req.Email); appErr != nil {
+		return c.JSON(http.StatusTooManyRequests, appErr)
+	}
+
+	hostID, _, appErr := b.resolveBookingHost(ctx, slug)
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	profile, appErr := b.BookingService.GetBookingProfile(ctx, hostID)
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	if _, appErr := b.BookingService.ValidateIntakeAnswers(ctx, hostID, req.EventType, req.Answers); appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	if profile.RequireChallenge || req.Challenge != "" {
+		if appErr := b.BookingService.VerifyBookingChallenge(ctx, req.Challenge, req.Nonce); appErr != nil {
+			return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+		}
+	}
+	if b.BookingService.IsGuestBlocked(ctx, hostID, req.Email) {
+		logger.Info("PublicSchedule:BlockedGuest", "host_id", hostID.String(), "guest_email", strings.TrimSpace(req.Email))
+		return c.JSON(http.StatusOK, ignoredBookingResponse())
+	}
+	// The request reaches the host once the guest confirms their email
+	if profile.RequireEmailVerification {
+		verification, appErr := b.BookingService.StartGuestVerification(ctx, slug, &req)
+		if appErr != nil {
+			return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+		}
+		return c.JSON(http.StatusAccepted, map[string]any{
+			"message":         "Check your email to confirm the booking request",
+			"status":          "verification_required",
+			"verification_id": verification.VerificationID,
+			"expires_at":      verification.ExpiresAt,
+		})
+	}
+	return b.submitBookingRequest(c, slug, &req)
+}
+
+// submitBookingRequest creates the pending booking request of a guest that passed the checks of PublicSchedule
+func (b *BookingController) submitBookingRequest(c echo.Context, slug string, req *bookingdto.PublicBookingRequest) error {
+	ctx := c.Request().Context()
 	// Parse time from RFC3339 format (e.g., "2026-01-28T12:00:00+07:00")
 	// This preserves the timezone information
 	logger.Info("PublicSchedule:ParseTime",
@@ -722,97 +234,294 @@ func (b *BookingController) PublicSchedule(c echo.Context) error {
 		"start_utc", start.Format(time.RFC3339),
 		"end_utc", end.Format(time.RFC3339))
 	
-	slID, ok := tryParseUUID(slug)
-	var userID uuid.UUID
-	var hostEmail string
-	if ok {
+	userID, hostEmail, appErr := b.resolveBookingHost(ctx, slug)
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	// Double-booking check: the slot must still be free on the host's calendar and not held by another request
+	available, appErr := b.BookingService.SlotAvailable(ctx, userID, start, end)
+	if appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	if !available {
+		return c.JSON(http.StatusConflict, errors.NewAppError(errors.ErrInvalidState, "this time is no longer available", nil))
+	}
+	// Answers follow the page owner's form, also when the request goes to a delegate below
+	answers, appErr := b.BookingService.ValidateIntakeAnswers(ctx, userID, req.EventType, req.Answers)
+	if appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	// So does the price; a priced event type needs a payment method
+	charge, appErr := b.BookingService.PriceBookingRequest(ctx, userID, req.EventType, req.PaymentMethodID)
+	if appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	// Requests for days the host is out of office go to their delegate or are declined below.
+	// Checked at the time that will be scheduled (same +1 day adjustment as when accepting).
+	pageOwnerID := userID
+	ooo, oooErr := b.CalendarService.GetOutOfOfficeDuring(ctx, userID, start.AddDate(0, 0, 1), end.AddDate(0, 0, 1))
+	if oooErr != nil {
+		logger.Error("PublicSchedule:GetOutOfOffice:Error", "host_id", userID.String(), "error", oooErr)
+	}
+	if ooo != nil && ooo.AutoAction == "delegate" {
+		if delegateID, err := uuid.Parse(ooo.DelegateUserID); err == nil {
+			userID = delegateID
+			hostEmail = b.googleEmail(ctx, delegateID)
+			ooo = nil
+		}
+	}
+	// Create pending event record; the guest is kept in preferences for the accept/decline paths
+	title := "Booking with " + strings.TrimSpace(req.Name)
+	prefs, _ := json.Marshal(bookingRequestPreferences{
+		GuestName:  strings.TrimSpace(req.Name),
+		GuestEmail: strings.TrimSpace(req.Email),
+		EventType:  bookingsvc.NormalizeEventType(req.EventType),
+		Answers:    answers,
+	})
+	prefsJSON := string(prefs)
+	ev := &meetentity.Event{
+		HostID:          &userID,
+		Title:           title,
+		DurationMinutes: 30,
+		Status:          meetentity.EventStatusPending,
+		Timezone:        "Asia/Ho_Chi_Minh",
+		Preferences:     &prefsJSON,
+	}
+	created, errCreate := b.MeetingRepo.CreateEvent(ctx, ev)
+	if errCreate != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to create booking request", errCreate))
+	}
+	// Update time window on event, keep status pending
+	created.StartDate = &start
+	created.EndDate = &end
+	if errUpd := b.MeetingRepo.UpdateEvent(ctx, created); errUpd != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to set booking time", errUpd))
+	}
+	if ooo != nil {
+		b.BookingService.RecordPageStat(ctx, pageOwnerID, bookingentity.BookingPageStatRequest)
+		return b.declineOutOfOfficeRequest(c, created, ooo, strings.TrimSpace(req.Email))
+	}
+	b.WebhookSvc.Emit(ctx, &userID, constants.WebhookEventBookingRequested, map[string]any{
+		"event_id":    created.ID.String(),
+		"host_id":     userID.String(),
+		"title":       title,
+		"start_time":  start.Format(time.RFC3339),
+		"end_time":    end.Format(time.RFC3339),
+		"guest_name":  strings.TrimSpace(req.Name),
+		"guest_email": strings.TrimSpace(req.Email),
+		"status":      created.Status,
+	})
+	b.BookingService.RecordPageStat(ctx, pageOwnerID, bookingentity.BookingPageStatRequest)
+	// Paid event types hold the slot until the order is paid and are confirmed then
+	if charge != nil {
+		return b.awaitBookingPayment(c, created, charge)
+	}
+	// Requests the host's approval policy accepts are scheduled right away
+	if b.autoAcceptBookingRequest(ctx, created) {
+		return c.JSON(http.StatusOK, map[string]any{
+			"message":  "Booking confirmed",
+			"event_id": created.ID.String(),
+			"status":   "scheduled",
+		})
+	}
+	// Notify host
+	if b.NotificationSvc != nil {
+		data := map[string]interface{}{
+			"event_id":    created.ID.String(),
+			"start_time":  req.StartTime,
+			"end_time":    req.EndTime,
+			"guest_name":  req.Name,
+			"guest_email": strings.TrimSpace(req.Email),
+			"answers":     answers,
+		}
+		if userID != pageOwnerID {
+			// Delegated while the page owner is out of office
+			data["on_behalf_of"] = pageOwnerID.String()
+		}
+		_ = b.NotificationSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+			UserID:  userID,
+			Title:   "Yêu cầu đặt lịch mới",
+			Message: title,
+			Type:    "booking_request",
+			Data:    data,
+		})
+	}
+	// One-time accept/decline links, valid for as long as the request waits for the host
+	var links *bookingdto.ApprovalLinks
+	if utils.IsValidEmail(hostEmail) {
+		var linkErr *errors.AppError
+		links, linkErr = b.BookingService.IssueApprovalLinks(ctx, userID, created.ID, b.BookingService.ApprovalWindow(ctx, userID))
+		if linkErr != nil {
+			logger.Error("PublicSchedule:IssueApprovalLinks:Error", "event_id", created.ID.String(), "error", linkErr)
+		}
+	}
+	// Send email to host if available
+	if links != nil {
+		conf := utils.GetEmailConfig()
+		acceptURL, declineURL := links.AcceptURL, links.DeclineURL
+		// Format time in VN timezone for email (with +1 day adjustment - same as when accepting)
+		// Add 1 day to show the actual time that will be scheduled when accepted
+		adjustedStart := start.AddDate(0, 0, 1)
+		adjustedEnd := end.AddDate(0, 0, 1)
+		vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
+		startVN := adjustedStart.In(vnLoc)
+		endVN := adjustedEnd.In(vnLoc)
+		timeStr := fmt.Sprintf("%s %s - %s", startVN.Format("02/01/2006"), startVN.Format("15:04"), endVN.Format("15:04"))
+		body := "<h3>New booking request</h3><p>Guest: " + templateEscape(req.Name) + " (" + templateEscape(strings.TrimSpace(req.Email)) + ")</p><p>Time: " + templateEscape(timeStr) + "</p>" + intakeAnswersHTML(answers) + "<p><a href=\"" + templateEscape(acceptURL) + "\">Accept</a> &nbsp;|&nbsp; <a href=\"" + templateEscape(declineURL) + "\">Decline</a></p>"
+		_ = utils.SendEmailTLS(*conf, utils.EmailMessage{
+			To:      []string{hostEmail},
+			Subject: "New booking request",
+			Body:    body,
+			IsHTML:  true,
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Booking request sent",
+		"event_id": created.ID.String(),
+		"status":   "pending",
+	})
+}
+
+// emitBookingEvent sends a booking lifecycle event to the host's webhook subscriptions
+func (b *BookingController) emitBookingEvent(ctx context.Context, eventType string, ev *meetentity.Event, guestEmail string) {
+	data := map[string]any{
+		"event_id":    ev.ID.String(),
+		"title":       ev.Title,
+		"status":      ev.Status,
+		"start_time":  ev.StartDate,
+		"end_time":    ev.EndDate,
+		"timezone":    ev.Timezone,
+		"guest_email": guestEmail,
+	}
+	if ev.HostID != nil {
+		data["host_id"] = ev.HostID.String()
+	}
+	if ev.MeetingLink != nil {
+		data["meeting_link"] = *ev.MeetingLink
+	}
+	b.WebhookSvc.Emit(ctx, ev.HostID, eventType, data)
+}
+
+// linkProviderEvent stores the Google event created for an accepted booking so calendar sync can reconcile it.
+// scheduledStart is the start sent to Google; its distance from the local start is kept by the sync.
+func (b *BookingController) linkProviderEvent(ctx context.Context, ev *meetentity.Event, created *caldto.CreateEventResponse, scheduledStart time.Time) {
+	if created == nil || created.EventID == "" || ev.StartDate == nil {
+		return
+	}
+	offset := int(scheduledStart.Sub(*ev.StartDate).Minutes())
+	if err := b.MeetingRepo.SetProviderLink(ctx, ev.ID, caldto.ProviderGoogle, created.EventID, created.ETag, offset); err != nil {
+		logger.Error("BookingController:linkProviderEvent:Error", "event_id", ev.ID.String(), "error", err)
+	}
+}
+
+// resolveBookingHost finds the host behind a social login ID, a booking profile slug (current or old)
+// or a Google username
+func (b *BookingController) resolveBookingHost(ctx context.Context, slug string) (uuid.UUID, string, *errors.AppError) {
+	if slID, ok := tryParseUUID(slug); ok {
 		uid, appErr := b.AuthService.GetUserIDBySocialLoginID(ctx, slID)
 		if appErr != nil {
-			return c.JSON(http.StatusNotFound, appErr)
+			return uuid.Nil, "", appErr
 		}
-		userID = uid
+		hostEmail := ""
 		if sl, appErr := b.AuthService.GetSocialLoginByID(ctx, slID); appErr == nil && sl != nil && sl.ProviderEmail != nil {
 			hostEmail = strings.TrimSpace(*sl.ProviderEmail)
 		}
-	} else {
-		sl, appErr := b.AuthService.GetSocialLoginBySlug(ctx, slug)
-		if appErr != nil || sl == nil {
-			return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "not found", nil))
-		}
-		userID = sl.UserID
-		if sl.ProviderEmail != nil {
-			hostEmail = strings.TrimSpace(*sl.ProviderEmail)
-		}
+		return uid, hostEmail, nil
+	}
+
+	resolved, appErr := b.BookingService.ResolveBookingSlug(ctx, slug)
+	if appErr != nil {
+		return uuid.Nil, "", appErr
+	}
+	if resolved != nil {
+		return resolved.UserID, b.googleEmail(ctx, resolved.UserID), nil
+	}
+
+	sl, appErr := b.AuthService.GetSocialLoginBySlug(ctx, slug)
+	if appErr != nil || sl == nil {
+		return uuid.Nil, "", errors.NewAppError(errors.ErrNotFound, "not found", nil)
+	}
+	hostEmail := ""
+	if sl.ProviderEmail != nil {
+		hostEmail = strings.TrimSpace(*sl.ProviderEmail)
+	}
+	return sl.UserID, hostEmail, nil
+}
+
+// recordBookingOutcome counts an accepted or declined booking request for its host
+func (b *BookingController) recordBookingOutcome(ctx context.Context, ev *meetentity.Event, stat bookingentity.BookingPageStat) {
+	if ev.HostID != nil {
+		b.BookingService.RecordPageStat(ctx, *ev.HostID, stat)
 	}
-	// Create pending event record
-	title := "Booking with " + strings.TrimSpace(req.Name)
-	ev := &meetentity.Event{
-		HostID:          &userID,
-		Title:           title,
-		DurationMinutes: 30,
-		Status:          meetentity.EventStatusPending,
-		Timezone:        "Asia/Ho_Chi_Minh",
+}
+
+// recordPageView counts a booking page view for the host behind slug
+func (b *BookingController) recordPageView(ctx context.Context, slug string) {
+	if hostID, _, appErr := b.resolveBookingHost(ctx, slug); appErr == nil {
+		b.BookingService.RecordPageStat(ctx, hostID, bookingentity.BookingPageStatView)
 	}
-	created, errCreate := b.MeetingRepo.CreateEvent(ctx, ev)
-	if errCreate != nil {
-		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to create booking request", errCreate))
+}
+
+// declineOutOfOfficeRequest declines a booking request for a time the host is out of office and tells the guest why
+func (b *BookingController) declineOutOfOfficeRequest(c echo.Context, ev *meetentity.Event, ooo *caldto.OutOfOfficeResponse, guestEmail string) error {
+	ctx := c.Request().Context()
+	ev.Status = meetentity.EventStatusCancelled
+	if err := b.MeetingRepo.UpdateEvent(ctx, ev); err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
 	}
-	// Update time window on event, keep status pending
-	created.StartDate = &start
-	created.EndDate = &end
-	if errUpd := b.MeetingRepo.UpdateEvent(ctx, created); errUpd != nil {
-		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to set booking time", errUpd))
+	b.emitBookingEvent(ctx, constants.WebhookEventBookingDeclined, ev, guestEmail)
+	b.recordBookingOutcome(ctx, ev, bookingentity.BookingPageStatDeclined)
+
+	message := "The host is out of office until " + formatOutOfOfficeDate(ooo.EndDate) + "."
+	if ooo.Message != "" {
+		message += " " + ooo.Message
 	}
-	// Notify host
-	if b.NotificationSvc != nil {
+	if b.NotificationSvc != nil && ev.HostID != nil {
 		_ = b.NotificationSvc.Create(ctx, &notifdto.CreateNotificationRequest{
-			UserID:  userID,
-			Title:   "Yêu cầu đặt lịch mới",
-			Message: title,
-			Type:    "booking_request",
+			UserID:  *ev.HostID,
+			Title:   "Yêu cầu đặt lịch bị tự động từ chối",
+			Message: ev.Title,
+			Type:    "booking_out_of_office",
 			Data: map[string]interface{}{
-				"event_id":    created.ID.String(),
-				"start_time":  req.StartTime,
-				"end_time":    req.EndTime,
-				"guest_name":  req.Name,
-				"guest_email": strings.TrimSpace(req.Email),
+				"event_id":    ev.ID.String(),
+				"guest_email": guestEmail,
 			},
 		})
 	}
-	// Send email to host if available
-	if utils.IsValidEmail(hostEmail) {
+	if utils.IsValidEmail(guestEmail) {
 		conf := utils.GetEmailConfig()
-		approveToken, _ := utils.GenerateToken(userID, &hostEmail, nil, "booking_approval", 15*time.Minute)
-		declineToken, _ := utils.GenerateToken(userID, &hostEmail, nil, "booking_approval", 15*time.Minute)
-		base := config.Get().Server.BaseURL
-		if base == "" {
-			base = "http://" + config.Get().Server.Host + ":" + fmt.Sprint(config.Get().Server.Port)
-		}
-		acceptURL := base + "/api/v1/public/booking/requests/" + created.ID.String() + "/accept?token=" + approveToken
-		declineURL := base + "/api/v1/public/booking/requests/" + created.ID.String() + "/decline?token=" + declineToken
-		// Format time in VN timezone for email (with +1 day adjustment - same as when accepting)
-		// Add 1 day to show the actual time that will be scheduled when accepted
-		adjustedStart := start.AddDate(0, 0, 1)
-		adjustedEnd := end.AddDate(0, 0, 1)
-		vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-		startVN := adjustedStart.In(vnLoc)
-		endVN := adjustedEnd.In(vnLoc)
-		timeStr := fmt.Sprintf("%s %s - %s", startVN.Format("02/01/2006"), startVN.Format("15:04"), endVN.Format("15:04"))
-		body := "<h3>New booking request</h3><p>Guest: " + templateEscape(req.Name) + " (" + templateEscape(strings.TrimSpace(req.Email)) + ")</p><p>Time: " + templateEscape(timeStr) + "</p><p><a href=\"" + templateEscape(acceptURL) + "\">Accept</a> &nbsp;|&nbsp; <a href=\"" + templateEscape(declineURL) + "\">Decline</a></p>"
+		body := "<h3>Booking declined</h3><p>Title: " + templateEscape(ev.Title) + "</p><p>" + templateEscape(message) + "</p>"
 		_ = utils.SendEmailTLS(*conf, utils.EmailMessage{
-			To:      []string{hostEmail},
-			Subject: "New booking request",
+			To:      []string{guestEmail},
+			Subject: "Your meeting request was declined",
 			Body:    body,
 			IsHTML:  true,
 		})
 	}
 	return c.JSON(http.StatusOK, map[string]any{
-		"message":  "Booking request sent",
-		"event_id": created.ID.String(),
-		"status":   "pending",
+		"message":  message,
+		"event_id": ev.ID.String(),
+		"status":   "declined",
 	})
 }
 
+// googleEmail returns the Google address of a user, or "" when they have no Google login
+func (b *BookingController) googleEmail(ctx context.Context, userID uuid.UUID) string {
+	sl, appErr := b.AuthService.GetSocialLoginByUserAndProviderName(ctx, userID, "google")
+	if appErr != nil || sl == nil || sl.ProviderEmail == nil {
+		return ""
+	}
+	return strings.TrimSpace(*sl.ProviderEmail)
+}
+
+func formatOutOfOfficeDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("Jan 2, 2006")
+}
+
 func tryParseUUID(s string) (uuid.UUID, bool) {
 	id, err := uuid.Parse(s)
 	if err != nil {
@@ -918,6 +627,239 @@ func (b *BookingController) GetWeekStatistics(c echo.Context) error {
 	})
 }
 
+// GetAnalytics returns calendar analytics of the current user
+// @Summary Thống kê lịch theo khoảng thời gian
+// @Description Thống kê theo ngày/tuần: số cuộc họp, thời gian họp và tập trung, họp liên tiếp, ngoài giờ, người cộng tác nhiều nhất và tỉ lệ chuyển đổi trang đặt lịch. Dữ liệu lấy từ lịch đã đồng bộ.
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param start_date query string false "Ngày bắt đầu (YYYY-MM-DD), mặc định thứ Hai tuần này"
+// @Param end_date query string false "Ngày kết thúc (YYYY-MM-DD)"
+// @Param timezone query string false "Múi giờ, mặc định Asia/Ho_Chi_Minh"
+// @Success 200 {object} bookingdto.AnalyticsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/analytics [get]
+func (b *BookingController) GetAnalytics(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	return b.analyticsResponse(c, func(ctx context.Context, query bookingdto.AnalyticsQuery) (*bookingdto.AnalyticsResponse, *errors.AppError) {
+		return b.BookingService.GetAnalytics(ctx, []uuid.UUID{userID}, query)
+	})
+}
+
+// GetTeamAnalytics returns calendar analytics across a group of users
+// @Summary Thống kê lịch của nhóm
+// @Description Thống kê tải cuộc họp của nhiều người dùng (dành cho trưởng nhóm, cần quyền analytics:team). Chỉ xem được người cùng nhóm với mình.
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param user_ids query string true "Danh sách user ID, phân tách bằng dấu phẩy (tối đa 50)"
+// @Param start_date query string false "Ngày bắt đầu (YYYY-MM-DD)"
+// @Param end_date query string false "Ngày kết thúc (YYYY-MM-DD)"
+// @Param timezone query string false "Múi giờ"
+// @Success 200 {object} bookingdto.AnalyticsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/booking/analytics/team [get]
+func (b *BookingController) GetTeamAnalytics(c echo.Context) error {
+	callerID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var userIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, raw := range strings.Split(c.QueryParam("user_ids"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid user id: "+raw, nil))
+		}
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	if len(userIDs) == 0 || len(userIDs) > 50 {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "user_ids must contain between 1 and 50 ids", nil))
+	}
+
+	return b.analyticsResponse(c, func(ctx context.Context, query bookingdto.AnalyticsQuery) (*bookingdto.AnalyticsResponse, *errors.AppError) {
+		return b.BookingService.GetTeamAnalytics(ctx, callerID, userIDs, query)
+	})
+}
+
+func (b *BookingController) analyticsResponse(c echo.Context, load func(context.Context, bookingdto.AnalyticsQuery) (*bookingdto.AnalyticsResponse, *errors.AppError)) error {
+	query := bookingdto.AnalyticsQuery{
+		StartDate: c.QueryParam("start_date"),
+		EndDate:   c.QueryParam("end_date"),
+		Timezone:  c.QueryParam("timezone"),
+	}
+
+	result, appErr := load(c.Request().Context(), query)
+	if appErr != nil {
+		httpStatus := http.StatusInternalServerError
+		switch appErr.Code {
+		case errors.ErrInvalidInput:
+			httpStatus = http.StatusBadRequest
+		case errors.ErrForbidden:
+			httpStatus = http.StatusForbidden
+		}
+		return c.JSON(httpStatus, appErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy thống kê lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// GetBookingProfile returns the booking page profile of the current user
+// @Summary Lấy hồ sơ trang đặt lịch
+// @Description Slug, tiêu đề, lời chào, ảnh đại diện, màu thương hiệu, ngôn ngữ và các slug cũ vẫn được chuyển hướng
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} bookingdto.BookingProfileResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/profile [get]
+func (b *BookingController) GetBookingProfile(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.GetBookingProfile(c.Request().Context(), userID)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy hồ sơ đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// UpdateBookingProfile updates the booking page title, welcome text, avatar, brand colour and locale
+// @Summary Cập nhật hồ sơ trang đặt lịch
+// @Description Trường bỏ trống được giữ nguyên, chuỗi rỗng sẽ xoá giá trị. brand_color dạng #RRGGBB, locale là vi hoặc en
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body bookingdto.UpdateBookingProfileRequest true "Thông tin hồ sơ"
+// @Success 200 {object} bookingdto.BookingProfileResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/profile [put]
+func (b *BookingController) UpdateBookingProfile(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.UpdateBookingProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.UpdateBookingProfile(c.Request().Context(), userID, &req)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Cập nhật hồ sơ đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// ChangeBookingSlug claims a new slug for the booking page of the current user
+// @Summary Đổi slug trang đặt lịch
+// @Description Slug dài 3-40 ký tự, chỉ gồm chữ thường, số và dấu gạch ngang. Slug cũ vẫn chuyển hướng về slug mới
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body bookingdto.ChangeBookingSlugRequest true "Slug mới"
+// @Success 200 {object} bookingdto.BookingProfileResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /private/booking/profile/slug [put]
+func (b *BookingController) ChangeBookingSlug(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.ChangeBookingSlugRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.ChangeBookingSlug(c.Request().Context(), userID, &req)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Đổi slug đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// CheckSlugAvailability tells whether the current user can claim a slug
+// @Summary Kiểm tra slug còn trống
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param slug query string true "Slug cần kiểm tra"
+// @Success 200 {object} bookingdto.SlugAvailabilityResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/profile/slug-availability [get]
+func (b *BookingController) CheckSlugAvailability(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.CheckSlugAvailability(c.Request().Context(), userID, c.QueryParam("slug"))
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Kiểm tra slug thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+func profileErrorStatus(appErr *errors.AppError) int {
+	switch appErr.Code {
+	case errors.ErrInvalidInput, errors.ErrInvalidSlug:
+		return http.StatusBadRequest
+	case errors.ErrDuplicateSlug:
+		return http.StatusConflict
+	case errors.ErrNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func templateEscape(s string) string {
 	return html.EscapeString(s)
 }
@@ -953,17 +895,26 @@ func (b *BookingController) PrivateListPending(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to get events", err))
 	}
+	window := b.BookingService.ApprovalWindow(c.Request().Context(), claims.UserID)
 	res := make([]map[string]any, 0, len(events))
 	for _, e := range events {
 		if e.Status != meetentity.EventStatusPending {
 			continue
 		}
+		guest := bookingRequestGuest(&e)
 		res = append(res, map[string]any{
 			"id":         e.ID.String(),
 			"title":      e.Title,
 			"start_time": e.StartDate,
 			"end_time":   e.EndDate,
 			"status":     e.Status,
+			"expires_at": e.CreatedAt.Add(window),
+			"guest_name":  guest.GuestName,
+			"guest_email": guest.GuestEmail,
+			"event_type":  bookingsvc.NormalizeEventType(guest.EventType),
+			"answers":     guest.Answers,
+			// POST /private/booking/requests/:id/approval-links issues new email links
+			"approval_links_url": "/api/v1/private/booking/requests/" + e.ID.String() + "/approval-links",
 		})
 	}
 	return c.JSON(http.StatusOK, map[string]any{"items": res})
@@ -1001,84 +952,17 @@ func (b *BookingController) PrivateAcceptRequest(c echo.Context) error {
 	if ev.HostID == nil || *ev.HostID != claims.UserID {
 		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
 	}
-	if ev.StartDate == nil || ev.EndDate == nil {
-		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing start/end", nil))
-	}
-	// Extract guest email from preferences if available
-	guestEmail := ""
-	if ev.Preferences != nil && *ev.Preferences != "" {
-		type Pref struct {
-			GuestName  string `json:"guest_name"`
-			GuestEmail string `json:"guest_email"`
-		}
-		var p Pref
-		_ = json.Unmarshal([]byte(*ev.Preferences), &p)
-		guestEmail = strings.TrimSpace(p.GuestEmail)
-	}
-	
-	// Get timezone, default to Asia/Ho_Chi_Minh if empty
-	timezone := ev.Timezone
-	if timezone == "" {
-		timezone = "Asia/Ho_Chi_Minh"
-	}
-	
-	// IMPORTANT: Add 1 day to the booking time when accepting
-	// User selects time, but when accepting, automatically add 1 day
-	adjustedStartDate := ev.StartDate.AddDate(0, 0, 1)
-	adjustedEndDate := ev.EndDate.AddDate(0, 0, 1)
-	
-	logger.Info("PrivateAcceptRequest:TimeAdjusted",
-		"event_id", eventID.String(),
-		"original_start", ev.StartDate.Format(time.RFC3339),
-		"adjusted_start", adjustedStartDate.Format(time.RFC3339),
-		"original_end", ev.EndDate.Format(time.RFC3339),
-		"adjusted_end", adjustedEndDate.Format(time.RFC3339))
-	
-	req := &caldto.CreateEventRequest{
-		Title:       ev.Title,
-		Description: "Personal booking",
-		StartTime:   formatTimeInTimezone(adjustedStartDate, timezone),
-		EndTime:     formatTimeInTimezone(adjustedEndDate, timezone),
-		Timezone:    timezone,
-	}
-	if guestEmail != "" {
-		req.Attendees = []string{guestEmail}
-	}
-	created, er := b.CalendarService.CreateEvent(c.Request().Context(), claims.UserID, req)
-	if er != nil {
-		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, er.Error(), er))
-	}
-	// Update event status and meeting_link
-	ev.Status = meetentity.EventStatusScheduled
-	if created.MeetingLink != "" {
-		link := created.MeetingLink
-		ev.MeetingLink = &link
-	}
-	if err := b.MeetingRepo.UpdateEvent(c.Request().Context(), ev); err != nil {
-		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
-	}
-	// Email guest if available
-	if utils.IsValidEmail(guestEmail) {
-		conf := utils.GetEmailConfig()
-		link := ""
-		if created.MeetingLink != "" {
-			link = created.MeetingLink
+	if _, appErr := b.acceptBookingRequest(c.Request().Context(), ev); appErr != nil {
+		httpStatus := http.StatusInternalServerError
+		switch appErr.Code {
+		case errors.ErrInvalidInput:
+			httpStatus = http.StatusBadRequest
+		case errors.ErrForbidden:
+			httpStatus = http.StatusForbidden
+		case errors.ErrInvalidState:
+			httpStatus = http.StatusConflict
 		}
-		// Format time in VN timezone for email (human-readable format, with +1 day adjustment)
-		// Add 1 day for email display (same as when creating calendar event)
-		adjustedStartDate := ev.StartDate.AddDate(0, 0, 1)
-		adjustedEndDate := ev.EndDate.AddDate(0, 0, 1)
-		vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-		startVN := adjustedStartDate.In(vnLoc)
-		endVN := adjustedEndDate.In(vnLoc)
-		timeStr := fmt.Sprintf("%s, %s - %s", startVN.Format("02/01/2006"), startVN.Format("15:04"), endVN.Format("15:04"))
-		body := "<h3>Booking confirmed</h3><p>Title: " + templateEscape(ev.Title) + "</p><p>Time: " + templateEscape(timeStr) + "</p><p>Meeting link: " + templateEscape(link) + "</p>"
-		_ = utils.SendEmailTLS(*conf, utils.EmailMessage{
-			To:      []string{guestEmail},
-			Subject: "Your meeting is confirmed",
-			Body:    body,
-			IsHTML:  true,
-		})
+		return c.JSON(httpStatus, appErr)
 	}
 	return c.JSON(http.StatusOK, map[string]any{"message": "accepted", "event_id": ev.ID.String()})
 }
@@ -1130,6 +1014,11 @@ func (b *BookingController) PrivateDeclineRequest(c echo.Context) error {
 		_ = json.Unmarshal([]byte(*ev.Preferences), &p)
 		guestEmail = strings.TrimSpace(p.GuestEmail)
 	}
+	_ = b.BookingService.RevokeApprovalLinks(c.Request().Context(), ev.ID)
+	b.BookingService.ReleaseBookingPayment(c.Request().Context(), ev.ID)
+	b.emitBookingEvent(c.Request().Context(), constants.WebhookEventBookingDeclined, ev, guestEmail)
+	b.recordBookingOutcome(c.Request().Context(), ev, bookingentity.BookingPageStatDeclined)
+	b.offerFreedSlot(c.Request().Context(), ev)
 	if utils.IsValidEmail(guestEmail) {
 		conf := utils.GetEmailConfig()
 		body := "<h3>Booking declined</h3><p>Title: " + templateEscape(ev.Title) + "</p>"
@@ -1143,9 +1032,10 @@ func (b *BookingController) PrivateDeclineRequest(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{"message": "declined"})
 }
 
+// PublicTokenAccept accepts a booking request from the confirmation page of an emailed link
 func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	id := c.Param("id")
-	token := c.QueryParam("token")
+	token := c.FormValue("token")
 	if token == "" {
 		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing token", nil))
 	}
@@ -1157,11 +1047,17 @@ func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	if err != nil || ev == nil {
 		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
 	}
-	claims, err := utils.ValidateAndParseToken(token)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "invalid token", err))
+	if appErr := b.checkRequestOpen(c.Request().Context(), ev); appErr != nil {
+		return c.JSON(http.StatusConflict, appErr)
 	}
-	if ev.HostID == nil || *ev.HostID != claims.UserID {
+	if appErr := b.checkBookingPaid(c.Request().Context(), ev); appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+	actionToken, appErr := b.BookingService.ConsumeActionToken(c.Request().Context(), ev.ID, bookingentity.BookingActionAccept, token)
+	if appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+	if ev.HostID == nil || *ev.HostID != actionToken.HostID {
 		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
 	}
 	if ev.StartDate == nil || ev.EndDate == nil {
@@ -1208,7 +1104,7 @@ func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	
 	req := &caldto.CreateEventRequest{
 		Title:       ev.Title,
-		Description: "Personal booking",
+		Description: bookingEventDescription(ev),
 		StartTime:   startTimeFormatted,
 		EndTime:     endTimeFormatted,
 		Timezone:    timezone,
@@ -1216,8 +1112,10 @@ func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	if guestEmail != "" {
 		req.Attendees = []string{guestEmail}
 	}
-	created, er := b.CalendarService.CreateEvent(c.Request().Context(), claims.UserID, req)
+	created, er := b.CalendarService.CreateEvent(c.Request().Context(), *ev.HostID, req)
 	if er != nil {
+		// Nothing was booked, so the host can use the link again once the calendar works
+		b.restoreActionToken(c.Request().Context(), actionToken)
 		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, er.Error(), er))
 	}
 	ev.Status = meetentity.EventStatusScheduled
@@ -1228,6 +1126,10 @@ func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	if err := b.MeetingRepo.UpdateEvent(c.Request().Context(), ev); err != nil {
 		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
 	}
+	b.linkProviderEvent(c.Request().Context(), ev, created, adjustedStartDate)
+	b.emitBookingEvent(c.Request().Context(), constants.WebhookEventBookingAccepted, ev, guestEmail)
+	b.recordBookingOutcome(c.Request().Context(), ev, bookingentity.BookingPageStatAccepted)
+	b.sendBookingConfirmation(c.Request().Context(), ev, adjustedStartDate, adjustedEndDate, guestEmail)
 
 	// Format event time for display (with +1 day adjustment)
 	eventTimeStr := "Chưa xác định"
@@ -1433,9 +1335,10 @@ func (b *BookingController) PublicTokenAccept(c echo.Context) error {
 	return c.HTML(http.StatusOK, html)
 }
 
+// PublicTokenDecline declines a booking request from the confirmation page of an emailed link
 func (b *BookingController) PublicTokenDecline(c echo.Context) error {
 	id := c.Param("id")
-	token := c.QueryParam("token")
+	token := c.FormValue("token")
 	if token == "" {
 		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing token", nil))
 	}
@@ -1447,17 +1350,25 @@ func (b *BookingController) PublicTokenDecline(c echo.Context) error {
 	if err != nil || ev == nil {
 		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
 	}
-	claims, err := utils.ValidateAndParseToken(token)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "invalid token", err))
+	if appErr := b.checkRequestOpen(c.Request().Context(), ev); appErr != nil {
+		return c.JSON(http.StatusConflict, appErr)
 	}
-	if ev.HostID == nil || *ev.HostID != claims.UserID {
+	actionToken, appErr := b.BookingService.ConsumeActionToken(c.Request().Context(), ev.ID, bookingentity.BookingActionDecline, token)
+	if appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+	if ev.HostID == nil || *ev.HostID != actionToken.HostID {
 		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
 	}
 	ev.Status = meetentity.EventStatusCancelled
 	if err := b.MeetingRepo.UpdateEvent(c.Request().Context(), ev); err != nil {
+		b.restoreActionToken(c.Request().Context(), actionToken)
 		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
 	}
+	b.BookingService.ReleaseBookingPayment(c.Request().Context(), ev.ID)
+	b.emitBookingEvent(c.Request().Context(), constants.WebhookEventBookingDeclined, ev, "")
+	b.recordBookingOutcome(c.Request().Context(), ev, bookingentity.BookingPageStatDeclined)
+	b.offerFreedSlot(c.Request().Context(), ev)
 	return c.JSON(http.StatusOK, map[string]any{"message": "declined"})
 }
 func computeFreeSlots(start, end time.Time, busy []caldto.TimeSlot, interval int, window string) []map[string]string {
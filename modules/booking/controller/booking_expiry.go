@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	bookingentity "go-api-starter/modules/booking/entity"
+	meetentity "go-api-starter/modules/meeting/entity"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// requestExpiresAt returns when a pending booking request expires if the host does not answer it
+func (b *BookingController) requestExpiresAt(ctx context.Context, ev *meetentity.Event) time.Time {
+	if ev.HostID == nil {
+		return ev.CreatedAt
+	}
+	return ev.CreatedAt.Add(b.BookingService.ApprovalWindow(ctx, *ev.HostID))
+}
+
+// checkRequestOpen rejects answering a booking request that was already answered or expired
+func (b *BookingController) checkRequestOpen(ctx context.Context, ev *meetentity.Event) *errors.AppError {
+	if ev.Status != meetentity.EventStatusPending {
+		return errors.NewAppError(errors.ErrInvalidState, "booking request is no longer pending", nil)
+	}
+	if !time.Now().Before(b.requestExpiresAt(ctx, ev)) {
+		return errors.NewAppError(errors.ErrInvalidState, "booking request has expired", nil)
+	}
+	return nil
+}
+
+// restoreActionToken reopens a consumed booking request link after the answer could not be recorded
+func (b *BookingController) restoreActionToken(ctx context.Context, used *bookingentity.BookingActionToken) {
+	if appErr := b.BookingService.RestoreActionToken(ctx, used); appErr != nil {
+		logger.Error("BookingController:RestoreActionToken:Error", "event_id", used.EventID, "error", appErr)
+	}
+}
+
+// actionTokenErrorStatus maps booking request link errors to HTTP statuses
+func actionTokenErrorStatus(appErr *errors.AppError) int {
+	switch appErr.Code {
+	case errors.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errors.ErrInvalidInput:
+		return http.StatusBadRequest
+	case errors.ErrInvalidState:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// PrivateRegenerateApprovalLinks issues new accept/decline links for a pending booking request
+// @Summary Tạo lại link duyệt yêu cầu đặt lịch
+// @Description Tạo link chấp nhận/từ chối dùng một lần mới cho yêu cầu đặt lịch đang chờ, có hiệu lực đến khi yêu cầu hết hạn. Các link cũ bị thu hồi. send_email=true gửi lại email cho chủ lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param send_email query bool false "Gửi lại email chứa link duyệt"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /private/booking/requests/{id}/approval-links [post]
+func (b *BookingController) PrivateRegenerateApprovalLinks(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
+	}
+	ev, err := b.MeetingRepo.GetEventByID(ctx, eventID)
+	if err != nil || ev == nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
+	}
+	if ev.HostID == nil || *ev.HostID != userID {
+		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
+	}
+	if appErr := b.checkRequestOpen(ctx, ev); appErr != nil {
+		return c.JSON(http.StatusConflict, appErr)
+	}
+
+	expiresAt := b.requestExpiresAt(ctx, ev)
+	hostEmail := b.googleEmail(ctx, userID)
+	links, appErr := b.BookingService.IssueApprovalLinks(ctx, userID, ev.ID, time.Until(expiresAt))
+	if appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+	acceptURL, declineURL := links.AcceptURL, links.DeclineURL
+
+	emailed := false
+	if c.QueryParam("send_email") == "true" && utils.IsValidEmail(hostEmail) {
+		guest := bookingRequestGuest(ev)
+		body := "<h3>Booking request awaiting your answer</h3><p>Guest: " + templateEscape(guest.GuestName) + " (" + templateEscape(guest.GuestEmail) + ")</p>" +
+			"<p>Title: " + templateEscape(ev.Title) + "</p><p>Expires: " + templateEscape(expiresAt.Format(time.RFC3339)) + "</p>" +
+			"<p><a href=\"" + templateEscape(acceptURL) + "\">Accept</a> &nbsp;|&nbsp; <a href=\"" + templateEscape(declineURL) + "\">Decline</a></p>"
+		emailed = utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+			To:      []string{hostEmail},
+			Subject: "Booking request awaiting your answer",
+			Body:    body,
+			IsHTML:  true,
+		}) == nil
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  http.StatusOK,
+		"message": "Tạo lại link duyệt thành công",
+		"data": map[string]interface{}{
+			"event_id":    ev.ID.String(),
+			"accept_url":  acceptURL,
+			"decline_url": declineURL,
+			"expires_at":  expiresAt,
+			"emailed":     emailed,
+		},
+		"timestamp": time.Now(),
+	})
+}
+
+// PrivateRevokeApprovalLinks revokes the emailed accept/decline links of a booking request
+// @Summary Thu hồi link duyệt yêu cầu đặt lịch
+// @Description Vô hiệu hoá mọi link chấp nhận/từ chối chưa dùng của yêu cầu đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/booking/requests/{id}/approval-links [delete]
+func (b *BookingController) PrivateRevokeApprovalLinks(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
+	}
+	ev, err := b.MeetingRepo.GetEventByID(ctx, eventID)
+	if err != nil || ev == nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
+	}
+	if ev.HostID == nil || *ev.HostID != userID {
+		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
+	}
+
+	if appErr := b.BookingService.RevokeApprovalLinks(ctx, ev.ID); appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Thu hồi link duyệt thành công",
+		"timestamp": time.Now(),
+	})
+}
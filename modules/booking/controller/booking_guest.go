@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"go-api-starter/core/errors"
+	bookingdto "go-api-starter/modules/booking/dto"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ignoredBookingResponse answers requests from bots and blocked guests like an accepted request, so they learn nothing
+func ignoredBookingResponse() map[string]any {
+	return map[string]any{
+		"message":  "Booking request sent",
+		"event_id": uuid.New().String(),
+		"status":   "pending",
+	}
+}
+
+// guestProtectionErrorStatus maps the errors of the public booking checks to HTTP statuses
+func guestProtectionErrorStatus(appErr *errors.AppError) int {
+	switch appErr.Code {
+	case errors.ErrInvalidInput, errors.ErrInvalidEmail:
+		return http.StatusBadRequest
+	case errors.ErrNotFound:
+		return http.StatusNotFound
+	case errors.ErrLimitExceeded:
+		return http.StatusTooManyRequests
+	case errors.ErrThirdParty:
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// PublicBookingChallenge hands the booking form a proof-of-work challenge
+// @Summary Lấy thử thách proof-of-work cho form đặt lịch
+// @Description Tìm nonce sao cho SHA-256(challenge + ":" + nonce) bắt đầu bằng difficulty bit 0, rồi gửi challenge và nonce cùng yêu cầu đặt lịch. Mỗi thử thách chỉ dùng được một lần
+// @Tags Booking
+// @Produce json
+// @Param slug path string true "Slug trang đặt lịch"
+// @Success 200 {object} bookingdto.BookingChallengeResponse
+// @Failure 404 {object} errors.AppError
+// @Router /public/booking/{slug}/challenge [get]
+func (b *BookingController) PublicBookingChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	if _, _, appErr := b.resolveBookingHost(ctx, c.Param("slug")); appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	challenge, appErr := b.BookingService.IssueBookingChallenge(ctx)
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, challenge)
+}
+
+// PublicConfirmGuestVerification confirms a guest's email and sends the held booking request to the host
+// @Summary Xác thực email khách đặt lịch
+// @Description Xác nhận mã đã gửi qua email; yêu cầu đặt lịch chỉ được gửi tới chủ lịch sau bước này
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param id path string true "Verification ID"
+// @Param request body bookingdto.ConfirmGuestVerificationRequest true "Mã xác thực"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 429 {object} errors.AppError
+// @Router /public/booking/verifications/{id} [post]
+func (b *BookingController) PublicConfirmGuestVerification(c echo.Context) error {
+	var req bookingdto.ConfirmGuestVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid body", nil))
+	}
+	hostKey, bookingReq, appErr := b.BookingService.ConfirmGuestVerification(c.Request().Context(), c.Param("id"), req.Code)
+	if appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	return b.submitBookingRequest(c, hostKey, bookingReq)
+}
+
+// ListBlockedGuests returns the emails and domains the current user does not accept booking requests from
+// @Summary Danh sách khách bị chặn đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} bookingdto.BlockedGuestResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/blocked-guests [get]
+func (b *BookingController) ListBlockedGuests(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.ListBlockedGuests(c.Request().Context(), userID)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy danh sách chặn thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// BlockGuest blocks booking requests from an email or a domain
+// @Summary Chặn khách đặt lịch
+// @Description kind: email (chặn một địa chỉ) hoặc domain (chặn mọi địa chỉ thuộc tên miền). Yêu cầu từ khách bị chặn bị bỏ qua mà không báo cho khách
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body bookingdto.BlockGuestRequest true "Email hoặc tên miền"
+// @Success 200 {object} bookingdto.BlockedGuestResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/blocked-guests [post]
+func (b *BookingController) BlockGuest(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.BlockGuestRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.BlockGuest(c.Request().Context(), userID, &req)
+	if appErr != nil {
+		if appErr.Code == errors.ErrLimitExceeded {
+			return c.JSON(http.StatusBadRequest, appErr)
+		}
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Chặn khách thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// UnblockGuest removes an entry from the block list
+// @Summary Bỏ chặn khách đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Blocked guest ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/booking/blocked-guests/{id} [delete]
+func (b *BookingController) UnblockGuest(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid id", nil))
+	}
+
+	if appErr := b.BookingService.UnblockGuest(c.Request().Context(), userID, id); appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Bỏ chặn khách thành công",
+		"timestamp": time.Now(),
+	})
+}
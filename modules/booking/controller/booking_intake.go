@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	bookingdto "go-api-starter/modules/booking/dto"
+	meetentity "go-api-starter/modules/meeting/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// intakeAnswerLines renders answers as "Label: value" lines
+func intakeAnswerLines(answers []bookingdto.IntakeAnswer) []string {
+	lines := make([]string, 0, len(answers))
+	for _, a := range answers {
+		lines = append(lines, a.Label+": "+strings.Join(a.Values, ", "))
+	}
+	return lines
+}
+
+// intakeAnswersHTML renders answers for the host's booking request email
+func intakeAnswersHTML(answers []bookingdto.IntakeAnswer) string {
+	if len(answers) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, a := range answers {
+		b.WriteString("<li><b>" + templateEscape(a.Label) + ":</b> " + templateEscape(strings.Join(a.Values, ", ")) + "</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// bookingEventDescription is the Google event description of an accepted booking request, with the guest's answers
+func bookingEventDescription(ev *meetentity.Event) string {
+	description := "Personal booking"
+	if lines := intakeAnswerLines(bookingRequestGuest(ev).Answers); len(lines) > 0 {
+		description += "\n\n" + strings.Join(lines, "\n")
+	}
+	return description
+}
+
+// PublicIntakeQuestions returns the questions of the host's booking form
+// @Summary Câu hỏi trên form đặt lịch
+// @Tags Booking
+// @Produce json
+// @Param slug path string true "Slug trang đặt lịch"
+// @Param event_type query string false "Loại sự kiện, mặc định default"
+// @Success 200 {array} bookingdto.IntakeQuestionResponse
+// @Failure 404 {object} errors.AppError
+// @Router /public/booking/{slug}/questions [get]
+func (b *BookingController) PublicIntakeQuestions(c echo.Context) error {
+	ctx := c.Request().Context()
+	hostID, _, appErr := b.resolveBookingHost(ctx, c.Param("slug"))
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	questions, appErr := b.BookingService.ListIntakeQuestions(ctx, hostID, c.QueryParam("event_type"))
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"questions": questions})
+}
+
+// ListIntakeQuestions returns the booking form questions of an event type
+// @Summary Danh sách câu hỏi form đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param event_type path string true "Loại sự kiện, ví dụ default"
+// @Success 200 {array} bookingdto.IntakeQuestionResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/intake-questions/{event_type} [get]
+func (b *BookingController) ListIntakeQuestions(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.ListIntakeQuestions(c.Request().Context(), userID, c.Param("event_type"))
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy câu hỏi đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// SaveIntakeQuestions replaces the booking form questions of an event type
+// @Summary Cập nhật câu hỏi form đặt lịch
+// @Description Thay toàn bộ danh sách câu hỏi theo thứ tự hiển thị. type: text, long_text, phone, single_choice, multi_choice (hai loại lựa chọn cần options). Câu hỏi có id được giữ nguyên, câu hỏi không còn trong danh sách bị xoá
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param event_type path string true "Loại sự kiện, ví dụ default"
+// @Param request body bookingdto.SaveIntakeQuestionsRequest true "Danh sách câu hỏi"
+// @Success 200 {array} bookingdto.IntakeQuestionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/intake-questions/{event_type} [put]
+func (b *BookingController) SaveIntakeQuestions(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.SaveIntakeQuestionsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.SaveIntakeQuestions(c.Request().Context(), userID, c.Param("event_type"), &req)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Cập nhật câu hỏi đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// ExportIntakeAnswers exports the guests' answers of the current user's booking requests
+// @Summary Xuất câu trả lời của khách đặt lịch
+// @Description format=csv (mặc định, mỗi câu hỏi một cột) hoặc json. Bỏ trống event_type để xuất mọi loại sự kiện
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Param event_type query string false "Loại sự kiện"
+// @Param format query string false "csv hoặc json"
+// @Success 200 {array} bookingdto.IntakeAnswersRecord
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/intake-answers/export [get]
+func (b *BookingController) ExportIntakeAnswers(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	records, appErr := b.BookingService.ExportIntakeAnswers(c.Request().Context(), userID, c.QueryParam("event_type"))
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	switch c.QueryParam("format") {
+	case "json":
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status":    http.StatusOK,
+			"message":   "Xuất câu trả lời thành công",
+			"data":      records,
+			"timestamp": time.Now(),
+		})
+	case "", "csv":
+	default:
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "format must be csv or json", nil))
+	}
+
+	// One column per question label, in the order the labels first appear
+	var labels []string
+	seen := map[string]bool{}
+	for _, r := range records {
+		for _, a := range r.Answers {
+			if !seen[a.Label] {
+				seen[a.Label] = true
+				labels = append(labels, a.Label)
+			}
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"booking-answers-%s.csv\"", time.Now().Format("20060102")))
+	c.Response().WriteHeader(http.StatusOK)
+	w := csv.NewWriter(c.Response())
+	header := []string{"event_id", "title", "status", "start_time", "created_at", "guest_name", "guest_email", "event_type"}
+	for _, label := range labels {
+		header = append(header, csvCell(label))
+	}
+	_ = w.Write(header)
+	for _, r := range records {
+		start := ""
+		if r.StartTime != nil {
+			start = r.StartTime.Format(time.RFC3339)
+		}
+		row := []string{r.EventID, csvCell(r.Title), r.Status, start, r.CreatedAt.Format(time.RFC3339), csvCell(r.GuestName), csvCell(r.GuestEmail), r.EventType}
+		values := make(map[string]string, len(r.Answers))
+		for _, a := range r.Answers {
+			values[a.Label] = csvCell(strings.Join(a.Values, "; "))
+		}
+		for _, label := range labels {
+			row = append(row, values[label])
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvCell keeps guest input from being read as a formula by spreadsheet apps
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
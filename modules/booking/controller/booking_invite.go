@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	bookingsvc "go-api-starter/modules/booking/service"
+	meetentity "go-api-starter/modules/meeting/entity"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// bookingInvite describes a booking for the guest's calendar; start and end are the times scheduled on the host's calendar.
+// The UID is derived from the event, so a later cancellation replaces the invite the guest accepted.
+func (b *BookingController) bookingInvite(ctx context.Context, ev *meetentity.Event, start, end time.Time, guestEmail string) utils.CalendarInvite {
+	domain := "smartmeet"
+	if u, err := url.Parse(bookingsvc.PublicBaseURL()); err == nil && u.Hostname() != "" {
+		domain = u.Hostname()
+	}
+	invite := utils.CalendarInvite{
+		UID:     ev.ID.String() + "@" + domain,
+		Method:  utils.CalendarMethodRequest,
+		Summary: ev.Title,
+		Start:   start,
+		End:     end,
+	}
+	if ev.MeetingLink != nil && *ev.MeetingLink != "" {
+		invite.Location = *ev.MeetingLink
+		invite.Description = "Join: " + *ev.MeetingLink
+	}
+	if ev.Description != nil && *ev.Description != "" {
+		if invite.Description != "" {
+			invite.Description += "\n\n"
+		}
+		invite.Description += *ev.Description
+	}
+	if ev.HostID != nil {
+		invite.OrganizerEmail = b.googleEmail(ctx, *ev.HostID)
+	}
+	if invite.OrganizerEmail == "" {
+		// The invite is sent from the system mailbox, so replies go there when the host has no Google address
+		invite.OrganizerEmail = utils.GetEmailConfig().From
+		invite.OrganizerName = utils.GetEmailConfig().FromName
+	}
+	if guestEmail != "" {
+		invite.Attendees = []string{guestEmail}
+	}
+	return invite
+}
+
+// scheduledBookingTimes returns the times of a scheduled booking on the host's calendar
+func scheduledBookingTimes(ev *meetentity.Event) (time.Time, time.Time) {
+	if ev.ProviderEventID == nil {
+		// Accepting adds 1 day to the requested time; without a provider link the offset was not stored
+		return ev.StartDate.AddDate(0, 0, 1), ev.EndDate.AddDate(0, 0, 1)
+	}
+	offset := time.Duration(ev.ProviderOffset) * time.Minute
+	return ev.StartDate.Add(offset), ev.EndDate.Add(offset)
+}
+
+// formatBookingTime formats a booking's time range for guest emails, in Vietnam time
+func formatBookingTime(start, end time.Time) string {
+	vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
+	startVN := start.In(vnLoc)
+	endVN := end.In(vnLoc)
+	return fmt.Sprintf("%s, %s - %s", startVN.Format("02/01/2006"), startVN.Format("15:04"), endVN.Format("15:04"))
+}
+
+// sendBookingConfirmation emails the guest that the booking is confirmed, with a calendar invite
+// and add-to-calendar links for guests whose mail client does not pick the invite up
+func (b *BookingController) sendBookingConfirmation(ctx context.Context, ev *meetentity.Event, start, end time.Time, guestEmail string) {
+	if !utils.IsValidEmail(guestEmail) {
+		return
+	}
+	invite := b.bookingInvite(ctx, ev, start, end, guestEmail)
+	body := "<h3>Booking confirmed</h3><p>Title: " + templateEscape(ev.Title) + "</p><p>Time: " + templateEscape(formatBookingTime(start, end)) + "</p>"
+	if invite.Location != "" {
+		body += "<p>Meeting link: <a href=\"" + templateEscape(invite.Location) + "\">" + templateEscape(invite.Location) + "</a></p>"
+	}
+	body += "<p>Add to your calendar: <a href=\"" + templateEscape(invite.GoogleCalendarURL()) + "\">Google Calendar</a> &nbsp;|&nbsp; " +
+		"<a href=\"" + templateEscape(invite.OutlookCalendarURL()) + "\">Outlook</a> &nbsp;|&nbsp; or open the attached invite.ics</p>"
+
+	if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+		To:          []string{guestEmail},
+		Subject:     "Your meeting is confirmed",
+		Body:        body,
+		IsHTML:      true,
+		Attachments: []utils.EmailAttachment{invite.Attachment()},
+	}); err != nil {
+		logger.Warn("BookingController:sendBookingConfirmation:SendEmail:Error", "event_id", ev.ID.String(), "error", err)
+	}
+}
+
+// sendBookingCancellation emails the guest that a confirmed booking was cancelled,
+// with a cancellation that removes the invite from their calendar
+func (b *BookingController) sendBookingCancellation(ctx context.Context, ev *meetentity.Event, start, end time.Time, guestEmail string) {
+	if !utils.IsValidEmail(guestEmail) {
+		return
+	}
+	invite := b.bookingInvite(ctx, ev, start, end, guestEmail)
+	invite.Method = utils.CalendarMethodCancel
+	invite.Sequence = 1
+	body := "<h3>Booking cancelled</h3><p>Title: " + templateEscape(ev.Title) + "</p><p>Time: " + templateEscape(formatBookingTime(start, end)) + "</p>" +
+		"<p>The host cancelled this meeting.</p>"
+
+	if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+		To:          []string{guestEmail},
+		Subject:     "Your meeting was cancelled",
+		Body:        body,
+		IsHTML:      true,
+		Attachments: []utils.EmailAttachment{invite.Attachment()},
+	}); err != nil {
+		logger.Warn("BookingController:sendBookingCancellation:SendEmail:Error", "event_id", ev.ID.String(), "error", err)
+	}
+}
+
+// PrivateCancelBooking cancels a confirmed booking and withdraws the guest's calendar invite
+// @Summary Huỷ lịch hẹn đã xác nhận
+// @Description Huỷ lịch hẹn đã được chấp nhận. Bản sao trên Google Calendar được gỡ ở lần đồng bộ kế tiếp, khách nhận email huỷ kèm file .ics (METHOD:CANCEL)
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /private/booking/requests/{id}/cancel [post]
+func (b *BookingController) PrivateCancelBooking(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
+	}
+	ev, err := b.MeetingRepo.GetEventByID(ctx, eventID)
+	if err != nil || ev == nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
+	}
+	if ev.HostID == nil || *ev.HostID != userID {
+		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
+	}
+	if ev.Status != meetentity.EventStatusScheduled || ev.StartDate == nil || ev.EndDate == nil {
+		return c.JSON(http.StatusConflict, errors.NewAppError(errors.ErrInvalidState, "only a confirmed booking can be cancelled", nil))
+	}
+
+	// A linked event stays cancelled so the calendar sync job removes the Google copy
+	ev.Status = meetentity.EventStatusCancelled
+	if err := b.MeetingRepo.UpdateEvent(ctx, ev); err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to update event", err))
+	}
+	guestEmail := bookingRequestGuest(ev).GuestEmail
+	b.BookingService.ReleaseBookingPayment(ctx, ev.ID)
+	b.emitBookingEvent(ctx, constants.WebhookEventBookingCancelled, ev, guestEmail)
+	start, end := scheduledBookingTimes(ev)
+	b.sendBookingCancellation(ctx, ev, start, end, guestEmail)
+	b.offerFreedSlot(ctx, ev)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Huỷ lịch hẹn thành công",
+		"data":      map[string]interface{}{"event_id": ev.ID.String()},
+		"timestamp": time.Now(),
+	})
+}
@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	bookingdto "go-api-starter/modules/booking/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageLocale = "vi"
+	defaultBrandColor = "#2563eb"
+)
+
+// bookingPageTemplates are parsed from the templates directory on every render, like the email templates
+var bookingPageTemplates = []string{
+	filepath.Join("templates", "booking", "layout.html"),
+	filepath.Join("templates", "booking", "booking_page.html"),
+}
+
+// bookingPageMessages holds the booking page texts per supported locale
+var bookingPageMessages = map[string]map[string]string{
+	"vi": {
+		"personal_title":    "Đặt lịch cá nhân",
+		"subtitle":          "Đặt lịch hẹn với tôi",
+		"meeting_title":     "Cuộc họp 30 phút",
+		"date_tbd":          "Chưa chọn thời gian",
+		"invitation_hint":   "Bạn sẽ nhận được lời mời và link cuộc họp qua email",
+		"name_placeholder":  "Họ và tên",
+		"email_placeholder": "Email của bạn",
+		"book":              "Đặt lịch",
+		"booked":            "Đã gửi yêu cầu đặt lịch",
+		"slots_failed":      "Không tải được khung giờ trống",
+		"no_slots":          "Không còn khung giờ trống trong ngày này",
+		"previous_month":    "Tháng trước",
+		"next_month":        "Tháng sau",
+		"ooo_upcoming":      "Vắng mặt từ %s đến %s. Không thể đặt lịch trong những ngày này.",
+		"ooo_active":        "Đang vắng mặt đến hết %s. Không thể đặt lịch trong những ngày này.",
+		"verify_prompt":     "Chúng tôi đã gửi mã xác thực tới email của bạn. Nhập mã để gửi yêu cầu đặt lịch.",
+		"code_placeholder":  "Mã xác thực",
+		"verify":            "Xác nhận",
+		"verify_failed":     "Không xác thực được email",
+		"book_failed":       "Không gửi được yêu cầu đặt lịch",
+		"waitlist_prompt":   "Ngày này đã kín lịch. Đăng ký danh sách chờ để nhận email khi có khung giờ trống.",
+		"waitlist_join":     "Vào danh sách chờ",
+		"waitlist_joined":   "Bạn đã có tên trong danh sách chờ",
+		"waitlist_failed":   "Không đăng ký được danh sách chờ",
+		"waitlist_contact":  "Nhập họ tên và email để vào danh sách chờ",
+		"price":             "Phí đặt lịch",
+		"payment_method":    "Phương thức thanh toán",
+		"payment_required":  "Chọn phương thức thanh toán",
+	},
+	"en": {
+		"personal_title":    "Personal Booking",
+		"subtitle":          "Schedule a meeting with me",
+		"meeting_title":     "30 min meeting",
+		"date_tbd":          "Date TBD",
+		"invitation_hint":   "You'll receive a calendar invitation and meeting link via email",
+		"name_placeholder":  "Your name",
+		"email_placeholder": "Your email",
+		"book":              "Book selected",
+		"booked":            "Booking request sent",
+		"slots_failed":      "Failed to load slots",
+		"no_slots":          "No free slots on this day",
+		"previous_month":    "Previous month",
+		"next_month":        "Next month",
+		"ooo_upcoming":      "Out of office %s – %s. Requests for these days can't be booked.",
+		"ooo_active":        "Currently out of office until %s. Requests for these days can't be booked.",
+		"verify_prompt":     "We sent a code to your email. Enter it to send your booking request.",
+		"code_placeholder":  "Verification code",
+		"verify":            "Confirm",
+		"verify_failed":     "Could not verify your email",
+		"book_failed":       "Could not send the booking request",
+		"waitlist_prompt":   "This day is fully booked. Join the waitlist and we'll email you when a time opens up.",
+		"waitlist_join":     "Join the waitlist",
+		"waitlist_joined":   "You are on the waitlist",
+		"waitlist_failed":   "Could not join the waitlist",
+		"waitlist_contact":  "Enter your name and email to join the waitlist",
+		"price":             "Price",
+		"payment_method":    "Payment method",
+		"payment_required":  "Choose a payment method",
+	},
+}
+
+// bookingPageIntlLocales maps page locales to the tags the browser's Intl API formats dates with
+var bookingPageIntlLocales = map[string]string{"vi": "vi-VN", "en": "en-US"}
+
+// bookingPageData is rendered by templates/booking/layout.html
+type bookingPageData struct {
+	Lang      string
+	Title     string
+	Subtitle  string
+	AvatarURL string
+	Initial   string
+	Notice    string
+	Theme     bookingPageTheme
+	T         map[string]string
+	Config    bookingPageConfig
+}
+
+// bookingPageTheme is the host's brand colour as CSS values
+type bookingPageTheme struct {
+	Primary template.CSS
+	Ring    template.CSS
+	Tint    template.CSS
+}
+
+// bookingPageConfig is handed to static/booking/booking.js
+type bookingPageConfig struct {
+	Host      string            `json:"host"` // slug or social login ID used in the public booking API
+	Mode      string            `json:"mode"` // "free" lists free/busy gaps, "suggested" asks for suggested slots
+	Locale    string            `json:"locale"`
+	Challenge bool              `json:"challenge"` // the form solves a proof-of-work challenge before booking
+	Messages  map[string]string `json:"messages"`
+}
+
+// renderBookingPage renders the public booking page of the host behind hostKey.
+// defaultTitle is shown when the host has no page title; "" uses the localized default.
+func (b *BookingController) renderBookingPage(c echo.Context, hostKey, mode, defaultTitle string) error {
+	ctx := c.Request().Context()
+
+	profile := &bookingdto.BookingProfileResponse{}
+	if hostID, _, appErr := b.resolveBookingHost(ctx, hostKey); appErr == nil {
+		if p, appErr := b.BookingService.GetBookingProfile(ctx, hostID); appErr == nil {
+			profile = p
+		}
+	}
+
+	lang := pageLocale(c, profile.Locale)
+	messages := bookingPageMessages[lang]
+
+	title := profile.Title
+	if title == "" {
+		title = defaultTitle
+	}
+	if title == "" {
+		title = messages["personal_title"]
+	}
+	subtitle := profile.WelcomeText
+	if subtitle == "" {
+		subtitle = messages["subtitle"]
+	}
+
+	data := bookingPageData{
+		Lang:      lang,
+		Title:     title,
+		Subtitle:  subtitle,
+		AvatarURL: profile.AvatarURL,
+		Initial:   "G",
+		Notice:    b.outOfOfficeNotice(ctx, hostKey, lang),
+		Theme:     pageTheme(profile.BrandColor),
+		T:         messages,
+		Config: bookingPageConfig{
+			Host:      hostKey,
+			Mode:      mode,
+			Locale:    bookingPageIntlLocales[lang],
+			Challenge: profile.RequireChallenge,
+			Messages:  messages,
+		},
+	}
+	if initial := []rune(strings.TrimSpace(title)); len(initial) > 0 {
+		data.Initial = strings.ToUpper(string(initial[0]))
+	}
+
+	tmpl, err := template.ParseFiles(bookingPageTemplates...)
+	if err != nil {
+		logger.Error("BookingController:renderBookingPage:ParseFiles:Error", "error", err)
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to load booking page", err))
+	}
+	var body strings.Builder
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		logger.Error("BookingController:renderBookingPage:Execute:Error", "error", err)
+		return c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "failed to render booking page", err))
+	}
+	return c.HTML(http.StatusOK, body.String())
+}
+
+// outOfOfficeNotice describes the host's current or upcoming out-of-office period in the page locale
+func (b *BookingController) outOfOfficeNotice(ctx context.Context, slug, lang string) string {
+	hostID, _, appErr := b.resolveBookingHost(ctx, slug)
+	if appErr != nil {
+		return ""
+	}
+	ooo, err := b.CalendarService.GetOutOfOfficeNotice(ctx, hostID)
+	if err != nil || ooo == nil {
+		return ""
+	}
+	messages := bookingPageMessages[lang]
+	text := fmt.Sprintf(messages["ooo_upcoming"], formatPageDate(ooo.StartDate, lang), formatPageDate(ooo.EndDate, lang))
+	if ooo.Active {
+		text = fmt.Sprintf(messages["ooo_active"], formatPageDate(ooo.EndDate, lang))
+	}
+	if ooo.Message != "" {
+		text += " " + ooo.Message
+	}
+	return text
+}
+
+// pageLocale picks the page language: ?lang=, then the visitor's Accept-Language, then the host's setting
+func pageLocale(c echo.Context, hostLocale string) string {
+	if lang := supportedPageLocale(c.QueryParam("lang")); lang != "" {
+		return lang
+	}
+	for _, tag := range acceptedLanguages(c.Request().Header.Get("Accept-Language")) {
+		if lang := supportedPageLocale(tag); lang != "" {
+			return lang
+		}
+	}
+	if lang := supportedPageLocale(hostLocale); lang != "" {
+		return lang
+	}
+	return defaultPageLocale
+}
+
+// supportedPageLocale reduces a language tag such as "en-US" to a supported page locale, or ""
+func supportedPageLocale(tag string) string {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := bookingPageMessages[lang]; ok {
+		return lang
+	}
+	return ""
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header, most preferred first
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if fields[0] == "" || fields[0] == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: fields[0], q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// pageTheme derives the page colours from the host's #RRGGBB brand colour
+func pageTheme(brandColor string) bookingPageTheme {
+	color := defaultBrandColor
+	if len(brandColor) == 7 && brandColor[0] == '#' {
+		if _, err := strconv.ParseUint(brandColor[1:], 16, 32); err == nil {
+			color = brandColor
+		}
+	}
+	rgb, _ := strconv.ParseUint(color[1:], 16, 32)
+	r, g, bl := rgb>>16, rgb>>8&0xff, rgb&0xff
+	return bookingPageTheme{
+		Primary: template.CSS(color),
+		Ring:    template.CSS(fmt.Sprintf("rgba(%d,%d,%d,.2)", r, g, bl)),
+		Tint:    template.CSS(fmt.Sprintf("rgba(%d,%d,%d,.08)", r, g, bl)),
+	}
+}
+
+// formatPageDate renders a YYYY-MM-DD date for the page locale
+func formatPageDate(date, lang string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	if lang == "vi" {
+		return t.Format("02/01/2006")
+	}
+	return t.Format("Jan 2, 2006")
+}
@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	bookingdto "go-api-starter/modules/booking/dto"
+	bookingentity "go-api-starter/modules/booking/entity"
+	meetentity "go-api-starter/modules/meeting/entity"
+	notifdto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// awaitBookingPayment places the order of a new request for a priced event type. The request holds its slot
+// while the order is waiting for payment and reaches the host once it is paid.
+func (b *BookingController) awaitBookingPayment(c echo.Context, ev *meetentity.Event, charge *bookingdto.BookingCharge) error {
+	ctx := c.Request().Context()
+	guest := bookingRequestGuest(ev)
+	payment, appErr := b.BookingService.CreateBookingOrder(ctx, *ev.HostID, ev.ID, ev.Title, guest.GuestName, guest.GuestEmail, charge)
+	if appErr != nil {
+		// Without an order nobody can pay, so the request must not hold the slot
+		ev.Status = meetentity.EventStatusCancelled
+		if err := b.MeetingRepo.UpdateEvent(ctx, ev); err != nil {
+			logger.Error("BookingController:awaitBookingPayment:CancelRequest:Error", "event_id", ev.ID.String(), "error", err)
+		}
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Booking reserved. Pay the order to confirm it",
+		"event_id": ev.ID.String(),
+		"status":   "awaiting_payment",
+		"payment":  payment,
+	})
+}
+
+// checkBookingPaid rejects confirming a booking whose order is still waiting for payment
+func (b *BookingController) checkBookingPaid(ctx context.Context, ev *meetentity.Event) *errors.AppError {
+	payment, appErr := b.BookingService.GetBookingPayment(ctx, ev.ID)
+	if appErr != nil {
+		return appErr
+	}
+	if payment != nil && payment.Status == bookingentity.BookingPaymentPending {
+		return errors.NewAppError(errors.ErrInvalidState, "booking is waiting for payment", nil)
+	}
+	return nil
+}
+
+// confirmPaidBooking records the payment of a booking request and confirms the booking. When it cannot be
+// scheduled right away the host is asked to accept it; a paid booking is never left to expire silently.
+func (b *BookingController) confirmPaidBooking(ctx context.Context, ev *meetentity.Event) (*bookingdto.BookingPaymentResponse, *errors.AppError) {
+	if ev.HostID == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event has no host", nil)
+	}
+	if ev.Status != meetentity.EventStatusPending {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "booking request is no longer pending", nil)
+	}
+	payment, appErr := b.BookingService.MarkBookingPaid(ctx, ev.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	guest := bookingRequestGuest(ev)
+	title, notifType := "Đặt lịch đã được thanh toán", "booking_paid"
+	if _, acceptErr := b.acceptBookingRequest(ctx, ev); acceptErr != nil {
+		logger.Warn("BookingController:confirmPaidBooking:Accept:Error", "event_id", ev.ID.String(), "error", acceptErr)
+		title, notifType = "Đặt lịch đã thanh toán cần được chấp nhận", "booking_paid_pending"
+	}
+	if b.NotificationSvc != nil {
+		_ = b.NotificationSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+			UserID:  *ev.HostID,
+			Title:   title,
+			Message: ev.Title,
+			Type:    notifType,
+			Data: map[string]interface{}{
+				"event_id":     ev.ID.String(),
+				"guest_name":   guest.GuestName,
+				"guest_email":  guest.GuestEmail,
+				"order_number": payment.OrderNumber,
+				"amount":       payment.Amount,
+			},
+		})
+	}
+	return payment, nil
+}
+
+// HandlePaymentTask is the worker handler of constants.TopicQueueBookingPayments. It confirms the bookings whose
+// order was paid in the order system, e.g. by the payment provider.
+func (b *BookingController) HandlePaymentTask(ctx context.Context, _ []byte) error {
+	payments, err := b.BookingService.SettledBookingPayments(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		ev, err := b.MeetingRepo.GetEventByID(ctx, payments[i].EventID)
+		if err != nil || ev == nil {
+			logger.Warn("BookingController:HandlePaymentTask:GetEvent:Error", "event_id", payments[i].EventID.String(), "error", err)
+			continue
+		}
+		if _, appErr := b.confirmPaidBooking(ctx, ev); appErr != nil {
+			logger.Warn("BookingController:HandlePaymentTask:Confirm:Error", "event_id", ev.ID.String(), "error", appErr)
+		}
+	}
+	return nil
+}
+
+// PublicBookingPrice returns what a guest pays for booking an event type
+// @Summary Giá đặt lịch
+// @Description Giá của loại sự kiện (amount = 0 khi miễn phí) và các phương thức thanh toán khách có thể chọn
+// @Tags Booking
+// @Produce json
+// @Param slug path string true "Slug trang đặt lịch"
+// @Param event_type query string false "Loại sự kiện, mặc định default"
+// @Success 200 {object} bookingdto.PublicBookingPriceResponse
+// @Failure 404 {object} errors.AppError
+// @Router /public/booking/{slug}/price [get]
+func (b *BookingController) PublicBookingPrice(c echo.Context) error {
+	ctx := c.Request().Context()
+	hostID, _, appErr := b.resolveBookingHost(ctx, c.Param("slug"))
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	price, appErr := b.BookingService.GetPublicBookingPrice(ctx, hostID, c.QueryParam("event_type"))
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, price)
+}
+
+// ListBookingPrices returns the event type prices of the current user
+// @Summary Danh sách giá đặt lịch
+// @Description Giá theo loại sự kiện (event type "default" áp dụng khi loại sự kiện không có giá riêng)
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} bookingdto.BookingPriceResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/prices [get]
+func (b *BookingController) ListBookingPrices(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	result, appErr := b.BookingService.ListBookingPrices(c.Request().Context(), userID)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy giá đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// SaveBookingPrice creates or replaces the price of an event type
+// @Summary Cập nhật giá đặt lịch
+// @Description Khách đặt loại sự kiện có giá phải chọn phương thức thanh toán; hệ thống tạo đơn hàng và giữ khung giờ trong payment_window_minutes. Lịch chỉ được xác nhận khi đơn hàng đã thanh toán
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param event_type path string true "Loại sự kiện, ví dụ default"
+// @Param request body bookingdto.SaveBookingPriceRequest true "Giá đặt lịch"
+// @Success 200 {object} bookingdto.BookingPriceResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/prices/{event_type} [put]
+func (b *BookingController) SaveBookingPrice(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	var req bookingdto.SaveBookingPriceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", err))
+	}
+
+	result, appErr := b.BookingService.SaveBookingPrice(c.Request().Context(), userID, c.Param("event_type"), &req)
+	if appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Cập nhật giá đặt lịch thành công",
+		"data":      result,
+		"timestamp": time.Now(),
+	})
+}
+
+// DeleteBookingPrice removes the price of an event type
+// @Summary Xoá giá đặt lịch
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param event_type path string true "Loại sự kiện"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} errors.AppError
+// @Router /private/booking/prices/{event_type} [delete]
+func (b *BookingController) DeleteBookingPrice(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+
+	if appErr := b.BookingService.DeleteBookingPrice(c.Request().Context(), userID, c.Param("event_type")); appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Xoá giá đặt lịch thành công",
+		"timestamp": time.Now(),
+	})
+}
+
+// PrivateConfirmBookingPayment records the payment of a booking request and confirms the booking
+// @Summary Xác nhận thanh toán đặt lịch
+// @Description Chủ lịch xác nhận đã nhận tiền (ví dụ chuyển khoản). Đơn hàng chuyển sang paid và lịch hẹn được xác nhận. Đơn thanh toán qua cổng thanh toán được xác nhận tự động
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Router /private/booking/requests/{id}/payment [post]
+func (b *BookingController) PrivateConfirmBookingPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid event id", nil))
+	}
+	ev, err := b.MeetingRepo.GetEventByID(ctx, eventID)
+	if err != nil || ev == nil {
+		return c.JSON(http.StatusNotFound, errors.NewAppError(errors.ErrNotFound, "event not found", err))
+	}
+	if ev.HostID == nil || *ev.HostID != userID {
+		return c.JSON(http.StatusForbidden, errors.NewAppError(errors.ErrForbidden, "not authorized", nil))
+	}
+
+	payment, appErr := b.confirmPaidBooking(ctx, ev)
+	if appErr != nil {
+		return c.JSON(actionTokenErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Xác nhận thanh toán thành công",
+		"data":      payment,
+		"timestamp": time.Now(),
+	})
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	bookingdto "go-api-starter/modules/booking/dto"
+	meetentity "go-api-starter/modules/meeting/entity"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// offerFreedSlot offers the slot of a cancelled, declined or expired booking to the host's waitlist
+func (b *BookingController) offerFreedSlot(ctx context.Context, ev *meetentity.Event) {
+	if ev.HostID == nil || ev.StartDate == nil || ev.EndDate == nil {
+		return
+	}
+	b.BookingService.OfferFreedSlot(ctx, *ev.HostID, bookingRequestGuest(ev).EventType, *ev.StartDate, *ev.EndDate)
+}
+
+// waitlistErrorStatus maps waitlist errors to HTTP statuses
+func waitlistErrorStatus(appErr *errors.AppError) int {
+	switch appErr.Code {
+	case errors.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errors.ErrInvalidState:
+		return http.StatusConflict
+	case errors.ErrResourceExpired:
+		return http.StatusGone
+	}
+	return guestProtectionErrorStatus(appErr)
+}
+
+// PublicJoinWaitlist puts a guest on the host's waitlist for a fully booked day or event type
+// @Summary Đăng ký danh sách chờ
+// @Description Khách đăng ký chờ một ngày (để trống date để chờ bất kỳ ngày nào) của một loại sự kiện. Khi có lịch bị huỷ, từ chối hoặc hết hạn, khung giờ được gửi lần lượt cho khách trong danh sách chờ qua link nhận lịch có thời hạn
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param slug path string true "Slug trang đặt lịch"
+// @Param body body bookingdto.JoinWaitlistRequest true "Thông tin khách"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 429 {object} errors.AppError
+// @Router /public/booking/{slug}/waitlist [post]
+func (b *BookingController) PublicJoinWaitlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.Param("slug")
+	var req bookingdto.JoinWaitlistRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid body", nil))
+	}
+	joined := map[string]any{
+		"message": "You are on the waitlist. We will email you when a time opens up",
+		"status":  "waiting",
+	}
+	// Bots fill the hidden honeypot field; they get the usual answer and nothing is stored
+	if strings.TrimSpace(req.Website) != "" {
+		logger.Info("PublicJoinWaitlist:Honeypot", "slug", slug, "ip", c.RealIP())
+		return c.JSON(http.StatusCreated, joined)
+	}
+	if !utils.IsValidEmail(strings.TrimSpace(req.Email)) {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidEmail, "a valid email is required", nil))
+	}
+	if appErr := b.BookingService.CheckBookingRateLimit(ctx, c.RealIP(), req.Email); appErr != nil {
+		return c.JSON(http.StatusTooManyRequests, appErr)
+	}
+
+	hostID, _, appErr := b.resolveBookingHost(ctx, slug)
+	if appErr != nil {
+		return c.JSON(http.StatusNotFound, appErr)
+	}
+	profile, appErr := b.BookingService.GetBookingProfile(ctx, hostID)
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	if profile.RequireChallenge || req.Challenge != "" {
+		if appErr := b.BookingService.VerifyBookingChallenge(ctx, req.Challenge, req.Nonce); appErr != nil {
+			return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+		}
+	}
+	if b.BookingService.IsGuestBlocked(ctx, hostID, req.Email) {
+		logger.Info("PublicJoinWaitlist:BlockedGuest", "host_id", hostID.String(), "guest_email", strings.TrimSpace(req.Email))
+		return c.JSON(http.StatusCreated, joined)
+	}
+
+	entry, appErr := b.BookingService.JoinWaitlist(ctx, hostID, slug, &req)
+	if appErr != nil {
+		return c.JSON(guestProtectionErrorStatus(appErr), appErr)
+	}
+	joined["date"] = entry.Date
+	joined["event_type"] = entry.EventType
+	return c.JSON(http.StatusCreated, joined)
+}
+
+// PublicWaitlistOfferPage shows the confirmation page of the link emailed to a waitlisted guest
+// @Summary Trang xác nhận nhận khung giờ từ danh sách chờ
+// @Description Link trong email gửi khách đang chờ. Chỉ kiểm tra link và hiển thị nút xác nhận; khung giờ chỉ được nhận khi khách bấm nút (POST)
+// @Tags Booking
+// @Produce html
+// @Param id path string true "Offer ID"
+// @Param token query string true "Token nhận lịch"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 410 {object} errors.AppError
+// @Router /public/booking/waitlist/offers/{id}/claim [get]
+func (b *BookingController) PublicWaitlistOfferPage(c echo.Context) error {
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid offer id", nil))
+	}
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing token", nil))
+	}
+
+	offer, appErr := b.BookingService.CheckWaitlistOffer(c.Request().Context(), offerID, token)
+	if appErr != nil {
+		return c.JSON(waitlistErrorStatus(appErr), appErr)
+	}
+	vnLoc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
+	start, end := offer.StartTime.In(vnLoc), offer.EndTime.In(vnLoc)
+	message := "Khung giờ: " + start.Format("02/01/2006, 15:04") + " - " + end.Format("15:04")
+	actionURL := "/api/v1/public/booking/waitlist/offers/" + offer.ID.String() + "/claim"
+	return c.HTML(http.StatusOK, confirmActionPage("Nhận khung giờ trống?", message, actionURL, token, "Nhận khung giờ", "#10b981"))
+}
+
+// PublicClaimWaitlistOffer books a freed slot offered to a waitlisted guest
+// @Summary Nhận khung giờ từ danh sách chờ
+// @Description Gửi từ trang xác nhận của link trong email. Khung giờ được kiểm tra trùng lịch như mọi yêu cầu đặt lịch rồi gửi yêu cầu đặt lịch cho chủ lịch. Mỗi link chỉ dùng được một lần
+// @Tags Booking
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "Offer ID"
+// @Param token formData string true "Token nhận lịch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 410 {object} errors.AppError
+// @Router /public/booking/waitlist/offers/{id}/claim [post]
+func (b *BookingController) PublicClaimWaitlistOffer(c echo.Context) error {
+	ctx := c.Request().Context()
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid offer id", nil))
+	}
+	token := c.FormValue("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "missing token", nil))
+	}
+
+	hostKey, req, appErr := b.BookingService.ClaimWaitlistOffer(ctx, offerID, token)
+	if appErr != nil {
+		return c.JSON(waitlistErrorStatus(appErr), appErr)
+	}
+	if hostID, _, appErr := b.resolveBookingHost(ctx, hostKey); appErr == nil && b.BookingService.IsGuestBlocked(ctx, hostID, req.Email) {
+		logger.Info("PublicClaimWaitlistOffer:BlockedGuest", "host_id", hostID.String(), "guest_email", req.Email)
+		return c.JSON(http.StatusOK, ignoredBookingResponse())
+	}
+	// The guest proved their email by following the emailed link, so the request goes to the host right away
+	return b.submitBookingRequest(c, hostKey, req)
+}
+
+// ListWaitlist returns the guests on the authenticated host's waitlist
+// @Summary Danh sách chờ đặt lịch
+// @Description Khách đang chờ khung giờ trống, theo thứ tự được mời trong từng loại sự kiện
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} errors.AppError
+// @Router /private/booking/waitlist [get]
+func (b *BookingController) ListWaitlist(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	entries, appErr := b.BookingService.ListWaitlist(c.Request().Context(), userID)
+	if appErr != nil {
+		return c.JSON(http.StatusInternalServerError, appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Lấy danh sách chờ thành công",
+		"data":      entries,
+		"timestamp": time.Now(),
+	})
+}
+
+// RemoveWaitlistEntry takes a guest off the authenticated host's waitlist
+// @Summary Xoá khách khỏi danh sách chờ
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Waitlist entry ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/booking/waitlist/{id} [delete]
+func (b *BookingController) RemoveWaitlistEntry(c echo.Context) error {
+	userID, err := b.getUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil))
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "invalid waitlist entry id", nil))
+	}
+	if appErr := b.BookingService.RemoveWaitlistEntry(c.Request().Context(), userID, id); appErr != nil {
+		return c.JSON(profileErrorStatus(appErr), appErr)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    http.StatusOK,
+		"message":   "Xoá khỏi danh sách chờ thành công",
+		"timestamp": time.Now(),
+	})
+}
@@ -0,0 +1,64 @@
+package dto
+
+// AnalyticsQuery selects the range and timezone of calendar analytics
+type AnalyticsQuery struct {
+	StartDate string `query:"start_date"` // YYYY-MM-DD, inclusive
+	EndDate   string `query:"end_date"`   // YYYY-MM-DD, inclusive
+	Timezone  string `query:"timezone"`   // IANA name, default Asia/Ho_Chi_Minh
+}
+
+// AnalyticsSummary aggregates meeting load over a period
+type AnalyticsSummary struct {
+	Meetings           int     `json:"meetings"`
+	MeetingMinutes     int     `json:"meeting_minutes"`
+	MeetingHours       float64 `json:"meeting_hours"`
+	FocusMinutes       int     `json:"focus_minutes"`
+	FocusHours         float64 `json:"focus_hours"`
+	BackToBack         int     `json:"back_to_back"` // meetings starting within 5 minutes of the previous one ending
+	AfterHoursMeetings int     `json:"after_hours_meetings"`
+	AfterHoursMinutes  int     `json:"after_hours_minutes"` // outside 08:00-18:00 on weekdays, or on weekends
+}
+
+// AnalyticsBucket is the summary of one day or one week (Date is the day, or the Monday of the week)
+type AnalyticsBucket struct {
+	Date string `json:"date"`
+	AnalyticsSummary
+}
+
+// CollaboratorStat counts meetings shared with an attendee
+type CollaboratorStat struct {
+	Email          string `json:"email"`
+	Meetings       int    `json:"meetings"`
+	MeetingMinutes int    `json:"meeting_minutes"`
+}
+
+// BookingConversion is the booking page funnel over the period
+type BookingConversion struct {
+	PageViews      int     `json:"page_views"`
+	Requests       int     `json:"requests"`
+	Accepted       int     `json:"accepted"`
+	Declined       int     `json:"declined"`
+	RequestRate    float64 `json:"request_rate"`    // requests / page views
+	AcceptanceRate float64 `json:"acceptance_rate"` // accepted / requests
+}
+
+// UserAnalytics is one member's summary in team analytics
+type UserAnalytics struct {
+	UserID       string `json:"user_id"`
+	LastSyncedAt string `json:"last_synced_at,omitempty"`
+	AnalyticsSummary
+	Booking BookingConversion `json:"booking"`
+}
+
+// AnalyticsResponse is calendar analytics over a range, computed from locally synced calendar data
+type AnalyticsResponse struct {
+	StartDate        string             `json:"start_date"`
+	EndDate          string             `json:"end_date"`
+	Timezone         string             `json:"timezone"`
+	Summary          AnalyticsSummary   `json:"summary"`
+	Daily            []AnalyticsBucket  `json:"daily"`
+	Weekly           []AnalyticsBucket  `json:"weekly"`
+	TopCollaborators []CollaboratorStat `json:"top_collaborators"`
+	Booking          BookingConversion  `json:"booking"`
+	Users            []UserAnalytics    `json:"users"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// SaveApprovalPolicyRequest sets how booking requests of an event type are approved
+type SaveApprovalPolicyRequest struct {
+	Mode                  string   `json:"mode"`            // manual, auto or conditional
+	AllowedDomains        []string `json:"allowed_domains"` // conditional: guest email domains accepted automatically
+	AcceptReturningGuests bool     `json:"accept_returning_guests"`
+}
+
+// ApprovalPolicyResponse is a host's approval policy for one event type
+type ApprovalPolicyResponse struct {
+	EventType             string   `json:"event_type"`
+	Mode                  string   `json:"mode"`
+	AllowedDomains        []string `json:"allowed_domains"`
+	AcceptReturningGuests bool     `json:"accept_returning_guests"`
+}
+
+// ApprovalDecision is the outcome of evaluating a booking request against the host's policy
+type ApprovalDecision struct {
+	AutoAccept bool
+	Reason     string // always, allowed_domain or returning_guest when AutoAccept; no_shows when the guest's no-shows need the host
+}
+
+// ApprovalLinks are the one-time accept and decline links of a pending booking request
+type ApprovalLinks struct {
+	AcceptURL  string    `json:"accept_url"`
+	DeclineURL string    `json:"decline_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
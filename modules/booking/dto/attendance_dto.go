@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// GuestAttendanceResponse is how often a guest attended, came late to or missed the host's bookings
+type GuestAttendanceResponse struct {
+	Email        string     `json:"email"`
+	NoShows      int        `json:"no_shows"`
+	Late         int        `json:"late"`
+	Attended     int        `json:"attended"`
+	LastNoShowAt *time.Time `json:"last_no_show_at,omitempty"`
+	Restriction  string     `json:"restriction,omitempty"` // require_approval or block once the host's no-show threshold is reached
+}
@@ -2,7 +2,8 @@ package dto
 
 // PersonalBookingURLResponse represents the response for personal booking URL
 type PersonalBookingURLResponse struct {
-	URL string `json:"url"`
+	URL  string `json:"url"`
+	Slug string `json:"slug,omitempty"`
 }
 
 // WeekStatisticsResponse represents weekly event statistics
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// PublicBookingRequest is what the public booking form submits
+type PublicBookingRequest struct {
+	Name      string                 `json:"name"`
+	Email     string                 `json:"email"`
+	StartTime string                 `json:"start_time"`
+	EndTime   string                 `json:"end_time"`
+	EventType string                 `json:"event_type"` // selects the host's approval policy and form questions, "default" when empty
+	Website   string                 `json:"website"`    // honeypot field hidden from people; bots fill it in
+	Challenge string                 `json:"challenge"`  // proof-of-work challenge from GET /challenge
+	Nonce     string                 `json:"nonce"`      // solution of the challenge
+	Answers   map[string]interface{} `json:"answers"`    // question ID to a string, or a list of strings for multi_choice
+
+	PaymentMethodID int `json:"payment_method_id"` // required when the event type has a price
+}
+
+// BookingChallengeResponse is a proof-of-work challenge: find a nonce so that
+// SHA-256(challenge + ":" + nonce) starts with Difficulty zero bits
+type BookingChallengeResponse struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// GuestVerificationResponse tells the guest to confirm their email with the emailed code or link
+type GuestVerificationResponse struct {
+	VerificationID string    `json:"verification_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// ConfirmGuestVerificationRequest confirms the guest's email with the emailed code
+type ConfirmGuestVerificationRequest struct {
+	Code string `json:"code"`
+}
+
+// BlockGuestRequest blocks booking requests from an email or a whole domain
+type BlockGuestRequest struct {
+	Kind  string `json:"kind"` // email or domain
+	Value string `json:"value"`
+}
+
+// BlockedGuestResponse is an entry of the host's block list
+type BlockedGuestResponse struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
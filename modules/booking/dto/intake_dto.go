@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// IntakeQuestionRequest is one question of the booking form; questions without an ID are created
+type IntakeQuestionRequest struct {
+	ID       string   `json:"id,omitempty"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`    // text, long_text, phone, single_choice or multi_choice
+	Options  []string `json:"options"` // choices of single_choice and multi_choice
+	Required bool     `json:"required"`
+}
+
+// SaveIntakeQuestionsRequest replaces the booking form questions of an event type, in form order
+type SaveIntakeQuestionsRequest struct {
+	Questions []IntakeQuestionRequest `json:"questions"`
+}
+
+// IntakeQuestionResponse is a booking form question
+type IntakeQuestionResponse struct {
+	ID       string   `json:"id"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+// IntakeAnswer is a guest's answer, stored with the question's label and type at booking time
+type IntakeAnswer struct {
+	QuestionID string   `json:"question_id"`
+	Label      string   `json:"label"`
+	Type       string   `json:"type"`
+	Values     []string `json:"values"`
+}
+
+// IntakeAnswersRecord is a booking request with its intake answers, as exported to the host
+type IntakeAnswersRecord struct {
+	EventID    string         `json:"event_id"`
+	Title      string         `json:"title"`
+	Status     string         `json:"status"`
+	StartTime  *time.Time     `json:"start_time"`
+	CreatedAt  time.Time      `json:"created_at"`
+	GuestName  string         `json:"guest_name"`
+	GuestEmail string         `json:"guest_email"`
+	EventType  string         `json:"event_type"`
+	Answers    []IntakeAnswer `json:"answers"`
+}
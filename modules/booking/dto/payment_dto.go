@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+// SaveBookingPriceRequest sets what guests pay for booking an event type
+type SaveBookingPriceRequest struct {
+	Amount               float64 `json:"amount"`                 // 0 makes the event type free, also when the default event type is priced
+	PaymentWindowMinutes int     `json:"payment_window_minutes"` // how long the slot is held for the payment, 30 when 0
+}
+
+// BookingPriceResponse is a host's price for one event type
+type BookingPriceResponse struct {
+	EventType            string  `json:"event_type"`
+	Amount               float64 `json:"amount"`
+	PaymentWindowMinutes int     `json:"payment_window_minutes"`
+}
+
+// PaymentMethodOption is a payment method a guest can pay a booking with
+type PaymentMethodOption struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+}
+
+// PublicBookingPriceResponse is what the booking page shows before a guest books an event type
+type PublicBookingPriceResponse struct {
+	EventType            string                `json:"event_type"`
+	Amount               float64               `json:"amount"` // 0 for a free event type
+	PaymentWindowMinutes int                   `json:"payment_window_minutes,omitempty"`
+	PaymentMethods       []PaymentMethodOption `json:"payment_methods"`
+}
+
+// BookingCharge is the price of a booking request and how the guest pays it
+type BookingCharge struct {
+	EventType         string
+	Amount            float64
+	PaymentWindow     time.Duration
+	PaymentMethodID   int
+	PaymentMethodName string
+}
+
+// BookingPaymentResponse is the order of a paid booking request
+type BookingPaymentResponse struct {
+	EventID       string     `json:"event_id"`
+	OrderID       string     `json:"order_id"`
+	OrderNumber   string     `json:"order_number"`
+	Amount        float64    `json:"amount"`
+	PaymentMethod string     `json:"payment_method"`
+	Status        string     `json:"status"`
+	PayBy         time.Time  `json:"pay_by"` // the slot is released when the order is not paid by then
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+}
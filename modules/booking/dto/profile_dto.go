@@ -0,0 +1,46 @@
+package dto
+
+// BookingProfileResponse is the host's booking page profile
+type BookingProfileResponse struct {
+	Slug                     string   `json:"slug,omitempty"`
+	URL                      string   `json:"url,omitempty"`
+	Title                    string   `json:"title,omitempty"`
+	WelcomeText              string   `json:"welcome_text,omitempty"`
+	AvatarURL                string   `json:"avatar_url,omitempty"`
+	BrandColor               string   `json:"brand_color,omitempty"`
+	Locale                   string   `json:"locale"`
+	ApprovalWindowHours      int      `json:"approval_window_hours"` // pending requests expire after this long
+	RequireEmailVerification bool     `json:"require_email_verification"`
+	RequireChallenge         bool     `json:"require_challenge"`
+	AttendancePrompts        bool     `json:"attendance_prompts"`
+	NoShowThreshold          int      `json:"no_show_threshold"` // 0 when off
+	NoShowAction             string   `json:"no_show_action"`
+	PreviousSlugs            []string `json:"previous_slugs"` // old slugs that redirect to the current one
+}
+
+// UpdateBookingProfileRequest changes the page settings; omitted fields are kept, empty strings clear them
+type UpdateBookingProfileRequest struct {
+	Title                    *string `json:"title"`
+	WelcomeText              *string `json:"welcome_text"`
+	AvatarURL                *string `json:"avatar_url"`                 // http(s) URL
+	BrandColor               *string `json:"brand_color"`                // #RRGGBB
+	Locale                   *string `json:"locale"`                     // vi or en
+	ApprovalWindowHours      *int    `json:"approval_window_hours"`      // 1 to 336
+	RequireEmailVerification *bool   `json:"require_email_verification"` // guests confirm their email before the request reaches the host
+	RequireChallenge         *bool   `json:"require_challenge"`          // the booking form must solve a proof-of-work challenge
+	AttendancePrompts        *bool   `json:"attendance_prompts"`         // ask to mark attendance after each meeting ends
+	NoShowThreshold          *int    `json:"no_show_threshold"`          // 0 (off) to 20 no-shows of a guest
+	NoShowAction             *string `json:"no_show_action"`             // require_approval or block
+}
+
+// ChangeBookingSlugRequest claims a new slug for the booking page
+type ChangeBookingSlugRequest struct {
+	Slug string `json:"slug"`
+}
+
+// SlugAvailabilityResponse tells whether a slug can be claimed by the caller
+type SlugAvailabilityResponse struct {
+	Slug      string `json:"slug"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
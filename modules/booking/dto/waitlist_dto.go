@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// JoinWaitlistRequest puts a guest on the host's waitlist for a day, or for any day when Date is empty
+type JoinWaitlistRequest struct {
+	Name      string                 `json:"name"`
+	Email     string                 `json:"email"`
+	Date      string                 `json:"date"`       // YYYY-MM-DD in Timezone; empty waits for any day
+	EventType string                 `json:"event_type"` // "default" when empty
+	Timezone  string                 `json:"timezone"`   // IANA name, Asia/Ho_Chi_Minh when empty
+	Website   string                 `json:"website"`    // honeypot field hidden from people; bots fill it in
+	Challenge string                 `json:"challenge"`
+	Nonce     string                 `json:"nonce"`
+	Answers   map[string]interface{} `json:"answers"` // answers of the booking form, sent with the booking once a slot is claimed
+
+	PaymentMethodID int `json:"payment_method_id"` // required when the event type has a price
+}
+
+// WaitlistEntryResponse is a guest on the host's waitlist
+type WaitlistEntryResponse struct {
+	ID         string    `json:"id"`
+	Position   int       `json:"position,omitempty"` // place in the host's queue, 1 is offered first
+	GuestName  string    `json:"guest_name"`
+	GuestEmail string    `json:"guest_email"`
+	EventType  string    `json:"event_type"`
+	Date       string    `json:"date,omitempty"` // empty for any day
+	Timezone   string    `json:"timezone"`
+	CreatedAt  time.Time `json:"created_at"`
+}
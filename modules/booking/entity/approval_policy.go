@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ApprovalMode decides how booking requests of an event type are approved
+type ApprovalMode string
+
+const (
+	ApprovalModeManual      ApprovalMode = "manual"
+	ApprovalModeAuto        ApprovalMode = "auto"
+	ApprovalModeConditional ApprovalMode = "conditional"
+)
+
+// DefaultEventType is the event type of booking page requests that do not name one
+const DefaultEventType = "default"
+
+// BookingApprovalPolicy is a host's approval rule for one event type
+type BookingApprovalPolicy struct {
+	ID                    uuid.UUID      `db:"id" json:"id"`
+	UserID                uuid.UUID      `db:"user_id" json:"user_id"`
+	EventType             string         `db:"event_type" json:"event_type"`
+	Mode                  ApprovalMode   `db:"mode" json:"mode"`
+	AllowedDomains        pq.StringArray `db:"allowed_domains" json:"allowed_domains"`
+	AcceptReturningGuests bool           `db:"accept_returning_guests" json:"accept_returning_guests"`
+	CreatedAt             time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time      `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuestAttendance sums up how a guest showed up to a host's bookings
+type GuestAttendance struct {
+	Email        string     `db:"email" json:"email"`
+	NoShows      int        `db:"no_shows" json:"no_shows"`
+	Late         int        `db:"late" json:"late"`
+	Attended     int        `db:"attended" json:"attended"`
+	LastNoShowAt *time.Time `db:"last_no_show_at" json:"last_no_show_at,omitempty"`
+}
+
+// AttendancePrompt is an ended event whose host is asked to mark attendance
+type AttendancePrompt struct {
+	EventID uuid.UUID `db:"event_id" json:"event_id"`
+	HostID  uuid.UUID `db:"host_id" json:"host_id"`
+	Title   string    `db:"title" json:"title"`
+	EndedAt time.Time `db:"ended_at" json:"ended_at"`
+}
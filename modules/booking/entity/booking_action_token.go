@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingAction is what an emailed booking request link does
+type BookingAction string
+
+const (
+	BookingActionAccept  BookingAction = "accept"
+	BookingActionDecline BookingAction = "decline"
+)
+
+// BookingActionToken is a one-time accept/decline link of a booking request; only the token's hash is stored
+type BookingActionToken struct {
+	ID        uuid.UUID     `db:"id" json:"id"`
+	EventID   uuid.UUID     `db:"event_id" json:"event_id"`
+	HostID    uuid.UUID     `db:"host_id" json:"host_id"`
+	Action    BookingAction `db:"action" json:"action"`
+	TokenHash string        `db:"token_hash" json:"-"`
+	ExpiresAt time.Time     `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time    `db:"used_at" json:"used_at,omitempty"`
+	RevokedAt *time.Time    `db:"revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
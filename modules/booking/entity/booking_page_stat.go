@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingPageStat is a booking page funnel counter
+type BookingPageStat string
+
+const (
+	BookingPageStatView     BookingPageStat = "page_views"
+	BookingPageStatRequest  BookingPageStat = "requests"
+	BookingPageStatAccepted BookingPageStat = "accepted"
+	BookingPageStatDeclined BookingPageStat = "declined"
+)
+
+// BookingPageDailyStats holds a host's booking page funnel counters for one day
+type BookingPageDailyStats struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	StatDate  time.Time `db:"stat_date" json:"stat_date"`
+	PageViews int       `db:"page_views" json:"page_views"`
+	Requests  int       `db:"requests" json:"requests"`
+	Accepted  int       `db:"accepted" json:"accepted"`
+	Declined  int       `db:"declined" json:"declined"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
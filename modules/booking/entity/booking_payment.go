@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingPrice is what a host charges for booking an event type
+type BookingPrice struct {
+	ID                   uuid.UUID `db:"id" json:"id"`
+	UserID               uuid.UUID `db:"user_id" json:"user_id"`
+	EventType            string    `db:"event_type" json:"event_type"`
+	Amount               float64   `db:"amount" json:"amount"`
+	PaymentWindowMinutes int       `db:"payment_window_minutes" json:"payment_window_minutes"`
+	CreatedAt            time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// BookingPaymentStatus is the state of the order of a paid booking request
+type BookingPaymentStatus string
+
+const (
+	BookingPaymentPending   BookingPaymentStatus = "pending"
+	BookingPaymentPaid      BookingPaymentStatus = "paid"
+	BookingPaymentCancelled BookingPaymentStatus = "cancelled" // released before it was paid
+	BookingPaymentRefunded  BookingPaymentStatus = "refunded"  // released after it was paid
+)
+
+// BookingPayment links a paid booking request to its order
+type BookingPayment struct {
+	ID                uuid.UUID            `db:"id" json:"id"`
+	EventID           uuid.UUID            `db:"event_id" json:"event_id"`
+	HostID            uuid.UUID            `db:"host_id" json:"host_id"`
+	OrderID           uuid.UUID            `db:"order_id" json:"order_id"`
+	OrderNumber       string               `db:"order_number" json:"order_number"`
+	Amount            float64              `db:"amount" json:"amount"`
+	PaymentMethodName string               `db:"payment_method_name" json:"payment_method_name"`
+	Status            BookingPaymentStatus `db:"status" json:"status"`
+	HoldExpiresAt     time.Time            `db:"hold_expires_at" json:"hold_expires_at"`
+	PaidAt            *time.Time           `db:"paid_at" json:"paid_at,omitempty"`
+	CreatedAt         time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time            `db:"updated_at" json:"updated_at"`
+}
+
+// BookingPaymentMethod is an active payment method of the shop a guest can pay a booking with
+type BookingPaymentMethod struct {
+	ID       int    `db:"id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	Provider string `db:"provider" json:"provider"`
+	Type     string `db:"type" json:"type"`
+}
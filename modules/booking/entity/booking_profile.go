@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingProfile is the public face of a host's booking page
+type BookingProfile struct {
+	ID                       uuid.UUID    `db:"id" json:"id"`
+	UserID                   uuid.UUID    `db:"user_id" json:"user_id"`
+	Slug                     *string      `db:"slug" json:"slug"`
+	Title                    *string      `db:"title" json:"title"`
+	WelcomeText              *string      `db:"welcome_text" json:"welcome_text"`
+	AvatarURL                *string      `db:"avatar_url" json:"avatar_url"`
+	BrandColor               *string      `db:"brand_color" json:"brand_color"` // #RRGGBB
+	Locale                   string       `db:"locale" json:"locale"`
+	ApprovalWindowMinutes    int          `db:"approval_window_minutes" json:"approval_window_minutes"`       // unanswered requests expire after this
+	RequireEmailVerification bool         `db:"require_email_verification" json:"require_email_verification"` // guests confirm their email before the host sees the request
+	RequireChallenge         bool         `db:"require_challenge" json:"require_challenge"`                   // the booking form solves a proof-of-work challenge
+	AttendancePrompts        bool         `db:"attendance_prompts" json:"attendance_prompts"`                 // ask the host to mark attendance after their meetings end
+	NoShowThreshold          int          `db:"no_show_threshold" json:"no_show_threshold"`                   // no-shows after which NoShowAction applies to a guest, 0 = off
+	NoShowAction             NoShowAction `db:"no_show_action" json:"no_show_action"`
+	CreatedAt                time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt                time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// NoShowAction is what happens to booking requests of guests with repeated no-shows
+type NoShowAction string
+
+const (
+	NoShowRequireApproval NoShowAction = "require_approval" // never accepted automatically
+	NoShowBlock           NoShowAction = "block"            // ignored like a blocked guest
+)
+
+// BookingSlugHistory is a slug a host used before; links to it redirect to the current slug
+type BookingSlugHistory struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// BlockedGuestKind tells whether a blocked guest entry matches an email or a whole domain
+type BlockedGuestKind string
+
+const (
+	BlockedGuestEmail  BlockedGuestKind = "email"
+	BlockedGuestDomain BlockedGuestKind = "domain"
+)
+
+// BookingBlockedGuest is a guest email or domain a host does not accept booking requests from
+type BookingBlockedGuest struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind      BlockedGuestKind `db:"kind" json:"kind"`
+	Value     string           `db:"value" json:"value"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PendingBookingRequest is a booking page request waiting for its host, with the time it expires
+type PendingBookingRequest struct {
+	ID              uuid.UUID  `db:"id"`
+	HostID          uuid.UUID  `db:"host_id"`
+	Title           string     `db:"title"`
+	DurationMinutes int        `db:"duration_minutes"`
+	Timezone        string     `db:"timezone"`
+	StartDate       *time.Time `db:"start_date"`
+	EndDate         *time.Time `db:"end_date"`
+	Preferences     *string    `db:"preferences"`
+	CreatedAt       time.Time  `db:"created_at"`
+	ExpiresAt       time.Time  `db:"expires_at"`
+}
+
+// Guest returns the guest name and email stored on the request
+func (r *PendingBookingRequest) Guest() (string, string) {
+	var guest struct {
+		GuestName  string `json:"guest_name"`
+		GuestEmail string `json:"guest_email"`
+	}
+	if r.Preferences != nil && *r.Preferences != "" {
+		_ = json.Unmarshal([]byte(*r.Preferences), &guest)
+	}
+	return strings.TrimSpace(guest.GuestName), strings.TrimSpace(guest.GuestEmail)
+}
+
+// EventType returns the event type the guest booked, "" for requests from before event types
+func (r *PendingBookingRequest) EventType() string {
+	var prefs struct {
+		EventType string `json:"event_type"`
+	}
+	if r.Preferences != nil && *r.Preferences != "" {
+		_ = json.Unmarshal([]byte(*r.Preferences), &prefs)
+	}
+	return prefs.EventType
+}
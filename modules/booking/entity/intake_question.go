@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// IntakeQuestionType is the kind of answer a booking form question takes
+type IntakeQuestionType string
+
+const (
+	IntakeQuestionText         IntakeQuestionType = "text"
+	IntakeQuestionLongText     IntakeQuestionType = "long_text"
+	IntakeQuestionPhone        IntakeQuestionType = "phone"
+	IntakeQuestionSingleChoice IntakeQuestionType = "single_choice"
+	IntakeQuestionMultiChoice  IntakeQuestionType = "multi_choice"
+)
+
+// BookingIntakeQuestion is a question guests answer on the host's booking form for one event type
+type BookingIntakeQuestion struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	EventType string             `db:"event_type" json:"event_type"`
+	Position  int                `db:"position" json:"position"`
+	Label     string             `db:"label" json:"label"`
+	Type      IntakeQuestionType `db:"type" json:"type"`
+	Options   pq.StringArray     `db:"options" json:"options"` // choices of single_choice and multi_choice
+	Required  bool               `db:"required" json:"required"`
+	CreatedAt time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// BookingRequestAnswers is a booking request whose preferences hold the guest's intake answers
+type BookingRequestAnswers struct {
+	ID          uuid.UUID  `db:"id"`
+	Title       string     `db:"title"`
+	Status      string     `db:"status"`
+	StartDate   *time.Time `db:"start_date"`
+	EndDate     *time.Time `db:"end_date"`
+	Preferences *string    `db:"preferences"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStatus is the state of a guest on a host's waitlist
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	WaitlistStatusBooked  WaitlistStatus = "booked"
+	WaitlistStatusRemoved WaitlistStatus = "removed"
+)
+
+// WaitlistOfferStatus is the state of a freed slot offered to a waitlisted guest
+type WaitlistOfferStatus string
+
+const (
+	WaitlistOfferOpen    WaitlistOfferStatus = "open"
+	WaitlistOfferClaimed WaitlistOfferStatus = "claimed"
+	WaitlistOfferExpired WaitlistOfferStatus = "expired"
+	WaitlistOfferTaken   WaitlistOfferStatus = "taken" // booked by someone else before it was claimed
+)
+
+// BookingWaitlistEntry is a guest waiting for a free slot of a host on a day (any day when WaitDate is nil)
+type BookingWaitlistEntry struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	HostID          uuid.UUID      `db:"host_id" json:"host_id"`
+	HostKey         string         `db:"host_key" json:"host_key"`
+	GuestName       string         `db:"guest_name" json:"guest_name"`
+	GuestEmail      string         `db:"guest_email" json:"guest_email"`
+	EventType       string         `db:"event_type" json:"event_type"`
+	WaitDate        *time.Time     `db:"wait_date" json:"wait_date,omitempty"`
+	Timezone        string         `db:"timezone" json:"timezone"`
+	Answers         *string        `db:"answers" json:"answers,omitempty"`                     // JSONB as string
+	PaymentMethodID *int           `db:"payment_method_id" json:"payment_method_id,omitempty"` // pays for a claimed slot of a priced event type
+	Status          WaitlistStatus `db:"status" json:"status"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// BookingWaitlistOffer is a freed slot offered to one waitlisted guest until it expires; only the claim token's hash is stored
+type BookingWaitlistOffer struct {
+	ID        uuid.UUID           `db:"id" json:"id"`
+	EntryID   uuid.UUID           `db:"entry_id" json:"entry_id"`
+	HostID    uuid.UUID           `db:"host_id" json:"host_id"`
+	EventType string              `db:"event_type" json:"event_type"`
+	StartTime time.Time           `db:"start_time" json:"start_time"`
+	EndTime   time.Time           `db:"end_time" json:"end_time"`
+	TokenHash string              `db:"token_hash" json:"-"`
+	Status    WaitlistOfferStatus `db:"status" json:"status"`
+	ExpiresAt time.Time           `db:"expires_at" json:"expires_at"`
+	ClaimedAt *time.Time          `db:"claimed_at" json:"claimed_at,omitempty"`
+	CreatedAt time.Time           `db:"created_at" json:"created_at"`
+}
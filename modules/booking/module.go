@@ -2,11 +2,13 @@ package booking
 
 import (
 	"go-api-starter/core/cache"
+	"go-api-starter/core/constants"
 	"go-api-starter/core/database"
 	"go-api-starter/core/middleware"
 	authRepository "go-api-starter/modules/auth/repository"
 	authService "go-api-starter/modules/auth/service"
 	"go-api-starter/modules/booking/controller"
+	bookingRepository "go-api-starter/modules/booking/repository"
 	bookingService "go-api-starter/modules/booking/service"
 	"go-api-starter/modules/booking/router"
 	calRepository "go-api-starter/modules/calendar/repository"
@@ -14,21 +16,36 @@ import (
 	invitService "go-api-starter/modules/invitation/service"
 	notifService "go-api-starter/modules/notification/service"
 	meetRepository "go-api-starter/modules/meeting/repository"
+	webhookService "go-api-starter/modules/webhook/service"
+	"go-api-starter/workers"
 
 	"github.com/labstack/echo/v4"
 )
 
-func Init(e *echo.Echo, db database.Database, cache cache.Cache, notifSvc *notifService.NotificationService, invitSvc *invitService.InvitationService) {
+func Init(e *echo.Echo, db database.Database, cache cache.Cache, notifSvc *notifService.NotificationService, invitSvc *invitService.InvitationService, webhookSvc *webhookService.WebhookService) {
 	calRepo := calRepository.NewCalendarRepository(db)
 	authRepo := authRepository.NewAuthRepository(db)
 	authSvc := authService.NewAuthService(authRepo, cache)
-	calSvc := calService.NewCalendarService(calRepo, authRepo, notifSvc, invitSvc)
 	meetRepo := meetRepository.NewMeetingRepository(db)
+	calSvc := calService.NewCalendarService(calRepo, authRepo, notifSvc, invitSvc, meetRepo)
 	
 	// Initialize booking service
-	bookingSvc := bookingService.NewBookingService(authSvc)
+	bookingRepo := bookingRepository.NewBookingRepository(db)
+	bookingSvc := bookingService.NewBookingService(authSvc, calRepo, bookingRepo, calSvc, notifSvc, webhookSvc, cache)
 	
-	ctrl := controller.NewBookingController(calSvc, authSvc, meetRepo, notifSvc, bookingSvc)
+	ctrl := controller.NewBookingController(calSvc, authSvc, meetRepo, notifSvc, bookingSvc, webhookSvc)
 	mw := middleware.NewMiddleware(authSvc)
 	router.NewBookingRouter(ctrl).Setup(e, mw)
+
+	// Expire booking requests the host did not answer within their approval window
+	workers.RegisterHandler(constants.TopicQueueBookingExpiry, bookingSvc.HandleExpiryTask)
+	workers.RegisterPeriodicTask("*/15 * * * *", constants.TopicQueueBookingExpiry)
+
+	// Confirm paid bookings whose order was paid through the order system
+	workers.RegisterHandler(constants.TopicQueueBookingPayments, ctrl.HandlePaymentTask)
+	workers.RegisterPeriodicTask("*/5 * * * *", constants.TopicQueueBookingPayments)
+
+	// Ask hosts to mark attendance once their meetings have ended
+	workers.RegisterHandler(constants.TopicQueueAttendancePrompts, bookingSvc.HandleAttendancePromptTask)
+	workers.RegisterPeriodicTask("*/15 * * * *", constants.TopicQueueAttendancePrompts)
 }
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetApprovalPolicies returns all approval policies of a host
+func (r *BookingRepository) GetApprovalPolicies(ctx context.Context, userID uuid.UUID) ([]entity.BookingApprovalPolicy, error) {
+	var policies []entity.BookingApprovalPolicy
+	query := `SELECT * FROM booking_approval_policies WHERE user_id = $1 ORDER BY event_type`
+	if err := r.db.SelectContext(ctx, &policies, query, userID); err != nil {
+		logger.Error("BookingRepository:GetApprovalPolicies:Error:", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetApprovalPolicy returns the host's policy for an event type, or nil when there is none
+func (r *BookingRepository) GetApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string) (*entity.BookingApprovalPolicy, error) {
+	var policy entity.BookingApprovalPolicy
+	query := `SELECT * FROM booking_approval_policies WHERE user_id = $1 AND event_type = $2`
+	if err := r.db.GetContext(ctx, &policy, query, userID, eventType); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetApprovalPolicy:Error:", err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SaveApprovalPolicy creates or replaces the host's policy for policy.EventType
+func (r *BookingRepository) SaveApprovalPolicy(ctx context.Context, policy *entity.BookingApprovalPolicy) error {
+	query := `
+		INSERT INTO booking_approval_policies (user_id, event_type, mode, allowed_domains, accept_returning_guests, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id, event_type) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			allowed_domains = EXCLUDED.allowed_domains,
+			accept_returning_guests = EXCLUDED.accept_returning_guests,
+			updated_at = NOW()
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, policy, query,
+		policy.UserID, policy.EventType, policy.Mode, policy.AllowedDomains, policy.AcceptReturningGuests,
+	); err != nil {
+		logger.Error("BookingRepository:SaveApprovalPolicy:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteApprovalPolicy removes the host's policy for an event type
+func (r *BookingRepository) DeleteApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string) error {
+	query := `DELETE FROM booking_approval_policies WHERE user_id = $1 AND event_type = $2`
+	if err := r.db.ExecContext(ctx, query, userID, eventType); err != nil {
+		logger.Error("BookingRepository:DeleteApprovalPolicy:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// HasAcceptedBookingFromGuest reports whether the host already accepted a booking request from guestEmail
+func (r *BookingRepository) HasAcceptedBookingFromGuest(ctx context.Context, hostID uuid.UUID, guestEmail string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM events
+			WHERE host_id = $1 AND status = 'scheduled'
+			  AND LOWER(preferences->>'guest_email') = LOWER($2)
+		)
+	`
+	if err := r.db.GetContext(ctx, &exists, query, hostID, guestEmail); err != nil {
+		logger.Error("BookingRepository:HasAcceptedBookingFromGuest:Error:", err)
+		return false, err
+	}
+	return exists, nil
+}
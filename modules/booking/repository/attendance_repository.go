@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// CountGuestNoShows returns how often a guest email did not show up to the host's events
+func (r *BookingRepository) CountGuestNoShows(ctx context.Context, hostID uuid.UUID, email string) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM event_attendance a
+		JOIN events e ON e.id = a.event_id
+		WHERE e.host_id = $1 AND a.status = 'no_show' AND LOWER(a.email) = LOWER($2)
+	`
+	if err := r.db.GetContext(ctx, &count, query, hostID, email); err != nil {
+		logger.Error("BookingRepository:CountGuestNoShows:Error:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetGuestAttendance returns the attendance of the host's guests with at least one no-show, most no-shows first.
+// A non-empty email limits it to that guest.
+func (r *BookingRepository) GetGuestAttendance(ctx context.Context, hostID uuid.UUID, email string) ([]entity.GuestAttendance, error) {
+	var guests []entity.GuestAttendance
+	query := `
+		SELECT LOWER(a.email) AS email,
+			COUNT(*) FILTER (WHERE a.status = 'no_show') AS no_shows,
+			COUNT(*) FILTER (WHERE a.status = 'late') AS late,
+			COUNT(*) FILTER (WHERE a.status = 'attended') AS attended,
+			MAX(e.start_date) FILTER (WHERE a.status = 'no_show') AS last_no_show_at
+		FROM event_attendance a
+		JOIN events e ON e.id = a.event_id
+		WHERE e.host_id = $1 AND a.email IS NOT NULL AND ($2 = '' OR LOWER(a.email) = LOWER($2))
+		GROUP BY LOWER(a.email)
+		HAVING COUNT(*) FILTER (WHERE a.status = 'no_show') > 0
+		ORDER BY no_shows DESC, email
+	`
+	if err := r.db.SelectContext(ctx, &guests, query, hostID, email); err != nil {
+		logger.Error("BookingRepository:GetGuestAttendance:Error:", err)
+		return nil, err
+	}
+	return guests, nil
+}
+
+// ClaimAttendancePrompts returns up to limit events that ended within the last week and have nobody marked yet,
+// of hosts who want to be asked, and records the prompt so each event is claimed once.
+// Events count from their scheduled time, i.e. start and end shifted by the provider offset.
+func (r *BookingRepository) ClaimAttendancePrompts(ctx context.Context, now time.Time, limit int) ([]entity.AttendancePrompt, error) {
+	var prompts []entity.AttendancePrompt
+	query := `
+		WITH due AS (
+			SELECT e.id, e.host_id, e.title, e.end_date + e.provider_offset_minutes * INTERVAL '1 minute' AS ended_at
+			FROM events e
+			JOIN booking_profiles p ON p.user_id = e.host_id AND p.attendance_prompts
+			WHERE e.status = 'scheduled' AND e.end_date IS NOT NULL
+				AND e.end_date + e.provider_offset_minutes * INTERVAL '1 minute' BETWEEN $1::timestamptz - INTERVAL '7 days' AND $1
+				AND (COALESCE(e.preferences->>'guest_email', '') <> '' OR EXISTS (SELECT 1 FROM user_events ue WHERE ue.event_id = e.id))
+				AND NOT EXISTS (SELECT 1 FROM event_attendance a WHERE a.event_id = e.id)
+				AND NOT EXISTS (SELECT 1 FROM event_attendance_prompts ap WHERE ap.event_id = e.id)
+			ORDER BY ended_at
+			LIMIT $2
+		), prompted AS (
+			INSERT INTO event_attendance_prompts (event_id, host_id, prompted_at)
+			SELECT id, host_id, NOW() FROM due
+			ON CONFLICT (event_id) DO NOTHING
+			RETURNING event_id
+		)
+		SELECT due.id AS event_id, due.host_id, due.title, due.ended_at
+		FROM due JOIN prompted ON prompted.event_id = due.id
+		ORDER BY due.ended_at
+	`
+	if err := r.db.SelectContext(ctx, &prompts, query, now, limit); err != nil {
+		logger.Error("BookingRepository:ClaimAttendancePrompts:Error:", err)
+		return nil, err
+	}
+	return prompts, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetBlockedGuests returns the emails and domains a host blocked
+func (r *BookingRepository) GetBlockedGuests(ctx context.Context, userID uuid.UUID) ([]entity.BookingBlockedGuest, error) {
+	var blocked []entity.BookingBlockedGuest
+	query := `SELECT * FROM booking_blocked_guests WHERE user_id = $1 ORDER BY kind, value`
+	if err := r.db.SelectContext(ctx, &blocked, query, userID); err != nil {
+		logger.Error("BookingRepository:GetBlockedGuests:Error:", err)
+		return nil, err
+	}
+	return blocked, nil
+}
+
+// BlockGuest adds an email or domain to the host's block list; blocking it again keeps the existing entry
+func (r *BookingRepository) BlockGuest(ctx context.Context, blocked *entity.BookingBlockedGuest) error {
+	query := `
+		INSERT INTO booking_blocked_guests (user_id, kind, value, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, kind, value) DO UPDATE SET value = EXCLUDED.value
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, blocked, query, blocked.UserID, blocked.Kind, blocked.Value); err != nil {
+		logger.Error("BookingRepository:BlockGuest:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// UnblockGuest removes an entry from the host's block list
+func (r *BookingRepository) UnblockGuest(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	var deleted uuid.UUID
+	query := `DELETE FROM booking_blocked_guests WHERE user_id = $1 AND id = $2 RETURNING id`
+	if err := r.db.GetContext(ctx, &deleted, query, userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		logger.Error("BookingRepository:UnblockGuest:Error:", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// IsGuestBlocked reports whether the host blocked the guest's email or its domain
+func (r *BookingRepository) IsGuestBlocked(ctx context.Context, userID uuid.UUID, email, domain string) (bool, error) {
+	var blocked bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM booking_blocked_guests
+			WHERE user_id = $1 AND ((kind = 'email' AND value = $2) OR (kind = 'domain' AND value = $3))
+		)
+	`
+	if err := r.db.GetContext(ctx, &blocked, query, userID, email, domain); err != nil {
+		logger.Error("BookingRepository:IsGuestBlocked:Error:", err)
+		return false, err
+	}
+	return blocked, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreateActionToken stores a newly issued booking request link
+func (r *BookingRepository) CreateActionToken(ctx context.Context, token *entity.BookingActionToken) error {
+	query := `
+		INSERT INTO booking_action_tokens (event_id, host_id, action, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, token, query,
+		token.EventID, token.HostID, token.Action, token.TokenHash, token.ExpiresAt,
+	); err != nil {
+		logger.Error("BookingRepository:CreateActionToken:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ConsumeActionToken marks an open, unexpired link of the event and action as used and revokes the event's other
+// open links. It returns nil when no such link exists, including when it was already used or revoked.
+func (r *BookingRepository) ConsumeActionToken(ctx context.Context, tokenHash string, eventID uuid.UUID, action entity.BookingAction) (*entity.BookingActionToken, error) {
+	var token entity.BookingActionToken
+	query := `
+		WITH used AS (
+			UPDATE booking_action_tokens SET used_at = NOW()
+			WHERE token_hash = $1 AND event_id = $2 AND action = $3
+			  AND used_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+			RETURNING *
+		), revoked AS (
+			UPDATE booking_action_tokens t SET revoked_at = NOW()
+			FROM used
+			WHERE t.event_id = used.event_id AND t.id <> used.id
+			  AND t.used_at IS NULL AND t.revoked_at IS NULL
+		)
+		SELECT * FROM used
+	`
+	if err := r.db.GetContext(ctx, &token, query, tokenHash, eventID, action); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:ConsumeActionToken:Error:", err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetOpenActionToken returns the open, unexpired link of the event and action with the given hash, or nil
+func (r *BookingRepository) GetOpenActionToken(ctx context.Context, tokenHash string, eventID uuid.UUID, action entity.BookingAction) (*entity.BookingActionToken, error) {
+	var token entity.BookingActionToken
+	query := `
+		SELECT * FROM booking_action_tokens
+		WHERE token_hash = $1 AND event_id = $2 AND action = $3
+		  AND used_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+	`
+	if err := r.db.GetContext(ctx, &token, query, tokenHash, eventID, action); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetOpenActionToken:Error:", err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RestoreActionToken undoes ConsumeActionToken: the used link and the links revoked along with it
+// (same timestamp, as both were set by one statement) are opened again
+func (r *BookingRepository) RestoreActionToken(ctx context.Context, token *entity.BookingActionToken) error {
+	if token.UsedAt == nil {
+		return nil
+	}
+	query := `
+		WITH restored AS (
+			UPDATE booking_action_tokens SET used_at = NULL
+			WHERE id = $1 AND used_at = $2
+			RETURNING event_id
+		)
+		UPDATE booking_action_tokens t SET revoked_at = NULL
+		FROM restored
+		WHERE t.event_id = restored.event_id AND t.revoked_at = $2
+	`
+	if err := r.db.ExecContext(ctx, query, token.ID, *token.UsedAt); err != nil {
+		logger.Error("BookingRepository:RestoreActionToken:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeActionTokens revokes every open link of a booking request
+func (r *BookingRepository) RevokeActionTokens(ctx context.Context, eventID uuid.UUID) error {
+	query := `
+		UPDATE booking_action_tokens SET revoked_at = NOW()
+		WHERE event_id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+	if err := r.db.ExecContext(ctx, query, eventID); err != nil {
+		logger.Error("BookingRepository:RevokeActionTokens:Error:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetBookingPrices returns all event type prices of a host
+func (r *BookingRepository) GetBookingPrices(ctx context.Context, userID uuid.UUID) ([]entity.BookingPrice, error) {
+	var prices []entity.BookingPrice
+	query := `SELECT * FROM booking_prices WHERE user_id = $1 ORDER BY event_type`
+	if err := r.db.SelectContext(ctx, &prices, query, userID); err != nil {
+		logger.Error("BookingRepository:GetBookingPrices:Error:", err)
+		return nil, err
+	}
+	return prices, nil
+}
+
+// GetBookingPrice returns the host's price for an event type, or nil when there is none
+func (r *BookingRepository) GetBookingPrice(ctx context.Context, userID uuid.UUID, eventType string) (*entity.BookingPrice, error) {
+	var price entity.BookingPrice
+	query := `SELECT * FROM booking_prices WHERE user_id = $1 AND event_type = $2`
+	if err := r.db.GetContext(ctx, &price, query, userID, eventType); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetBookingPrice:Error:", err)
+		return nil, err
+	}
+	return &price, nil
+}
+
+// SaveBookingPrice creates or replaces the host's price for price.EventType
+func (r *BookingRepository) SaveBookingPrice(ctx context.Context, price *entity.BookingPrice) error {
+	query := `
+		INSERT INTO booking_prices (user_id, event_type, amount, payment_window_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (user_id, event_type) DO UPDATE SET
+			amount = EXCLUDED.amount,
+			payment_window_minutes = EXCLUDED.payment_window_minutes,
+			updated_at = NOW()
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, price, query, price.UserID, price.EventType, price.Amount, price.PaymentWindowMinutes); err != nil {
+		logger.Error("BookingRepository:SaveBookingPrice:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteBookingPrice removes the host's price for an event type
+func (r *BookingRepository) DeleteBookingPrice(ctx context.Context, userID uuid.UUID, eventType string) error {
+	query := `DELETE FROM booking_prices WHERE user_id = $1 AND event_type = $2`
+	if err := r.db.ExecContext(ctx, query, userID, eventType); err != nil {
+		logger.Error("BookingRepository:DeleteBookingPrice:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetActivePaymentMethods returns the shop's active payment methods in display order
+func (r *BookingRepository) GetActivePaymentMethods(ctx context.Context) ([]entity.BookingPaymentMethod, error) {
+	var methods []entity.BookingPaymentMethod
+	query := `SELECT id, name, provider, type FROM payment_methods WHERE is_active ORDER BY sort_order, id`
+	if err := r.db.SelectContext(ctx, &methods, query); err != nil {
+		logger.Error("BookingRepository:GetActivePaymentMethods:Error:", err)
+		return nil, err
+	}
+	return methods, nil
+}
+
+// GetActivePaymentMethod returns an active payment method, or nil when it does not exist or is disabled
+func (r *BookingRepository) GetActivePaymentMethod(ctx context.Context, id int) (*entity.BookingPaymentMethod, error) {
+	var method entity.BookingPaymentMethod
+	query := `SELECT id, name, provider, type FROM payment_methods WHERE id = $1 AND is_active`
+	if err := r.db.GetContext(ctx, &method, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetActivePaymentMethod:Error:", err)
+		return nil, err
+	}
+	return &method, nil
+}
+
+// CreateBookingOrder places a pending order for a paid booking request and links it to the request.
+// payment carries the request, the amount and the hold; the order is filled in on return.
+func (r *BookingRepository) CreateBookingOrder(ctx context.Context, payment *entity.BookingPayment, paymentMethodID int, customerName, customerEmail, notes string) error {
+	query := `
+		WITH placed AS (
+			INSERT INTO orders (
+				order_number, customer_email, customer_phone, customer_name,
+				order_state, payment_status, subtotal, total_amount,
+				payment_method_id, payment_method_name, notes, admin_notes
+			) VALUES ($4, $5, '', $6, 'pending', 'pending', $7, $7, $8, $9, $10, '')
+			RETURNING id, order_number
+		)
+		INSERT INTO booking_payments (event_id, host_id, order_id, order_number, amount, payment_method_name, status, hold_expires_at, created_at, updated_at)
+		SELECT $1, $2, placed.id, placed.order_number, $7, $9, 'pending', $3, NOW(), NOW() FROM placed
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, payment, query,
+		payment.EventID, payment.HostID, payment.HoldExpiresAt, payment.OrderNumber, customerEmail, customerName,
+		payment.Amount, paymentMethodID, payment.PaymentMethodName, notes,
+	); err != nil {
+		logger.Error("BookingRepository:CreateBookingOrder:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetBookingPayment returns the payment of a booking request, or nil for a free booking
+func (r *BookingRepository) GetBookingPayment(ctx context.Context, eventID uuid.UUID) (*entity.BookingPayment, error) {
+	var payment entity.BookingPayment
+	query := `SELECT * FROM booking_payments WHERE event_id = $1`
+	if err := r.db.GetContext(ctx, &payment, query, eventID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetBookingPayment:Error:", err)
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// MarkBookingPaymentPaid marks a pending booking payment and its order as paid.
+// It returns nil when the payment is not pending anymore.
+func (r *BookingRepository) MarkBookingPaymentPaid(ctx context.Context, eventID uuid.UUID) (*entity.BookingPayment, error) {
+	var payment entity.BookingPayment
+	query := `
+		WITH paid AS (
+			UPDATE booking_payments SET status = 'paid', paid_at = NOW(), updated_at = NOW()
+			WHERE event_id = $1 AND status = 'pending'
+			RETURNING *
+		), confirmed AS (
+			UPDATE orders o SET payment_status = 'paid', order_state = 'confirmed', confirmed_at = COALESCE(o.confirmed_at, NOW()), updated_at = NOW()
+			FROM paid
+			WHERE o.id = paid.order_id
+		)
+		SELECT * FROM paid
+	`
+	if err := r.db.GetContext(ctx, &payment, query, eventID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:MarkBookingPaymentPaid:Error:", err)
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// ReleaseBookingPayment cancels the order of a booking that will not take place: an unpaid order is cancelled,
+// a paid one refunded, also when it was paid in the order system and the booking not confirmed yet.
+// It returns the released payment, or nil when there was nothing to release.
+func (r *BookingRepository) ReleaseBookingPayment(ctx context.Context, eventID uuid.UUID) (*entity.BookingPayment, error) {
+	var payment entity.BookingPayment
+	query := `
+		WITH released AS (
+			UPDATE booking_payments bp
+			SET status = CASE
+					WHEN bp.status = 'paid' OR EXISTS (SELECT 1 FROM orders o WHERE o.id = bp.order_id AND o.payment_status = 'paid')
+					THEN 'refunded' ELSE 'cancelled' END,
+				updated_at = NOW()
+			WHERE bp.event_id = $1 AND bp.status IN ('pending', 'paid')
+			RETURNING bp.*
+		), cancelled AS (
+			UPDATE orders o SET payment_status = released.status, order_state = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+			FROM released
+			WHERE o.id = released.order_id
+		)
+		SELECT * FROM released
+	`
+	if err := r.db.GetContext(ctx, &payment, query, eventID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:ReleaseBookingPayment:Error:", err)
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetSettledBookingPayments returns up to limit pending booking payments whose order was marked paid
+// in the order system, e.g. by the payment provider
+func (r *BookingRepository) GetSettledBookingPayments(ctx context.Context, limit int) ([]entity.BookingPayment, error) {
+	var payments []entity.BookingPayment
+	query := `
+		SELECT bp.* FROM booking_payments bp
+		JOIN orders o ON o.id = bp.order_id
+		WHERE bp.status = 'pending' AND o.payment_status = 'paid'
+		ORDER BY bp.created_at
+		LIMIT $1
+	`
+	if err := r.db.SelectContext(ctx, &payments, query, limit); err != nil {
+		logger.Error("BookingRepository:GetSettledBookingPayments:Error:", err)
+		return nil, err
+	}
+	return payments, nil
+}
+
+// GetBookingGuestEmail returns the guest email stored on a booking request, "" when there is none
+func (r *BookingRepository) GetBookingGuestEmail(ctx context.Context, eventID uuid.UUID) (string, error) {
+	var email string
+	query := `SELECT COALESCE(TRIM(preferences->>'guest_email'), '') FROM events WHERE id = $1`
+	if err := r.db.GetContext(ctx, &email, query, eventID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		logger.Error("BookingRepository:GetBookingGuestEmail:Error:", err)
+		return "", err
+	}
+	return email, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetProfileByUserID returns the booking profile of a host, or nil when they have not set one up
+func (r *BookingRepository) GetProfileByUserID(ctx context.Context, userID uuid.UUID) (*entity.BookingProfile, error) {
+	var profile entity.BookingProfile
+	query := `SELECT * FROM booking_profiles WHERE user_id = $1`
+	if err := r.db.GetContext(ctx, &profile, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetProfileByUserID:Error:", err)
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetProfileBySlug returns the profile currently owning slug (case-insensitive)
+func (r *BookingRepository) GetProfileBySlug(ctx context.Context, slug string) (*entity.BookingProfile, error) {
+	var profile entity.BookingProfile
+	query := `SELECT * FROM booking_profiles WHERE LOWER(slug) = LOWER($1)`
+	if err := r.db.GetContext(ctx, &profile, query, slug); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetProfileBySlug:Error:", err)
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetSlugHistory returns the history entry of a slug a host used before (case-insensitive)
+func (r *BookingRepository) GetSlugHistory(ctx context.Context, slug string) (*entity.BookingSlugHistory, error) {
+	var history entity.BookingSlugHistory
+	query := `SELECT * FROM booking_slug_history WHERE LOWER(slug) = LOWER($1)`
+	if err := r.db.GetContext(ctx, &history, query, slug); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetSlugHistory:Error:", err)
+		return nil, err
+	}
+	return &history, nil
+}
+
+// SaveProfile creates or updates the page settings of a host; the slug is changed through ChangeSlug
+func (r *BookingRepository) SaveProfile(ctx context.Context, profile *entity.BookingProfile) error {
+	query := `
+		INSERT INTO booking_profiles (user_id, title, welcome_text, avatar_url, brand_color, locale, approval_window_minutes,
+			require_email_verification, require_challenge, attendance_prompts, no_show_threshold, no_show_action, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			title = EXCLUDED.title,
+			welcome_text = EXCLUDED.welcome_text,
+			avatar_url = EXCLUDED.avatar_url,
+			brand_color = EXCLUDED.brand_color,
+			locale = EXCLUDED.locale,
+			approval_window_minutes = EXCLUDED.approval_window_minutes,
+			require_email_verification = EXCLUDED.require_email_verification,
+			require_challenge = EXCLUDED.require_challenge,
+			attendance_prompts = EXCLUDED.attendance_prompts,
+			no_show_threshold = EXCLUDED.no_show_threshold,
+			no_show_action = EXCLUDED.no_show_action,
+			updated_at = NOW()
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, profile, query,
+		profile.UserID, profile.Title, profile.WelcomeText, profile.AvatarURL, profile.BrandColor, profile.Locale,
+		profile.ApprovalWindowMinutes, profile.RequireEmailVerification, profile.RequireChallenge,
+		profile.AttendancePrompts, profile.NoShowThreshold, profile.NoShowAction,
+	); err != nil {
+		logger.Error("BookingRepository:SaveProfile:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ChangeSlug gives the host a new slug in one statement: the previous slug moves to the history
+// so old links keep working, and a slug the host is reclaiming from their own history is removed from it
+func (r *BookingRepository) ChangeSlug(ctx context.Context, userID uuid.UUID, slug string) (*entity.BookingProfile, error) {
+	var profile entity.BookingProfile
+	query := `
+		WITH previous AS (
+			SELECT slug FROM booking_profiles WHERE user_id = $1
+		), archived AS (
+			INSERT INTO booking_slug_history (user_id, slug, created_at)
+			SELECT $1, slug, NOW() FROM previous
+			WHERE slug IS NOT NULL AND LOWER(slug) <> LOWER($2)
+			ON CONFLICT DO NOTHING
+		), reclaimed AS (
+			DELETE FROM booking_slug_history WHERE user_id = $1 AND LOWER(slug) = LOWER($2)
+		)
+		INSERT INTO booking_profiles (user_id, slug, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			slug = EXCLUDED.slug,
+			updated_at = NOW()
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, &profile, query, userID, slug); err != nil {
+		logger.Error("BookingRepository:ChangeSlug:Error:", err)
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetSlugHistoryByUser returns the slugs a host used before, newest first
+func (r *BookingRepository) GetSlugHistoryByUser(ctx context.Context, userID uuid.UUID) ([]entity.BookingSlugHistory, error) {
+	var history []entity.BookingSlugHistory
+	query := `SELECT * FROM booking_slug_history WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &history, query, userID); err != nil {
+		logger.Error("BookingRepository:GetSlugHistoryByUser:Error:", err)
+		return nil, err
+	}
+	return history, nil
+}
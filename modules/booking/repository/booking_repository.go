@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-starter/core/database"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BookingRepository stores booking page data that does not live on events
+type BookingRepository struct {
+	db database.Database
+}
+
+func NewBookingRepository(db database.Database) *BookingRepository {
+	return &BookingRepository{db: db}
+}
+
+// IncrementPageStat bumps one of today's booking page funnel counters for a host
+func (r *BookingRepository) IncrementPageStat(ctx context.Context, userID uuid.UUID, stat entity.BookingPageStat) error {
+	switch stat {
+	case entity.BookingPageStatView, entity.BookingPageStatRequest, entity.BookingPageStatAccepted, entity.BookingPageStatDeclined:
+	default:
+		return fmt.Errorf("unknown booking page stat: %s", stat)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO booking_page_daily_stats (user_id, stat_date, %[1]s, created_at, updated_at)
+		VALUES ($1, CURRENT_DATE, 1, NOW(), NOW())
+		ON CONFLICT (user_id, stat_date) DO UPDATE SET
+			%[1]s = booking_page_daily_stats.%[1]s + 1,
+			updated_at = NOW()
+	`, stat)
+	if err := r.db.ExecContext(ctx, query, userID); err != nil {
+		logger.Error("BookingRepository:IncrementPageStat:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetPageStats returns the daily funnel counters of the given hosts between two dates (inclusive)
+func (r *BookingRepository) GetPageStats(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.BookingPageDailyStats, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	var stats []entity.BookingPageDailyStats
+	query := `
+		SELECT * FROM booking_page_daily_stats
+		WHERE user_id = ANY($1::uuid[]) AND stat_date BETWEEN $2::date AND $3::date
+		ORDER BY stat_date
+	`
+	if err := r.db.SelectContext(ctx, &stats, query, pq.Array(ids), from.Format("2006-01-02"), to.Format("2006-01-02")); err != nil {
+		logger.Error("BookingRepository:GetPageStats:Error:", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetGroupMatesAmong returns which of the given users share a group with userID.
+// Group membership is stored against social_logins ids, so both sides are resolved through social_logins.
+func (r *BookingRepository) GetGroupMatesAmong(ctx context.Context, userID uuid.UUID, candidates []uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]string, len(candidates))
+	for i, id := range candidates {
+		ids[i] = id.String()
+	}
+
+	var mates []uuid.UUID
+	query := `
+		SELECT DISTINCT member.user_id
+		FROM social_logins me
+		JOIN user_groups mine ON mine.user_id = me.id
+		JOIN user_groups theirs ON theirs.group_id = mine.group_id
+		JOIN social_logins member ON member.id = theirs.user_id
+		WHERE me.user_id = $1 AND member.user_id = ANY($2::uuid[])
+	`
+	if err := r.db.SelectContext(ctx, &mates, query, userID, pq.Array(ids)); err != nil {
+		logger.Error("BookingRepository:GetGroupMatesAmong:Error:", err)
+		return nil, err
+	}
+	return mates, nil
+}
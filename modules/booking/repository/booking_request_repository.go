@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// pendingBookingRequests selects pending booking page requests with their expiry.
+// Requests created before the guest was stored on the event are recognised by their title.
+// A request whose order is not paid yet expires with its payment hold instead of the approval window.
+const pendingBookingRequests = `
+	SELECT e.id, e.host_id, e.title, e.duration_minutes, e.timezone, e.start_date, e.end_date, e.preferences, e.created_at,
+	       CASE WHEN bp.status = 'pending' THEN bp.hold_expires_at
+	            ELSE e.created_at + make_interval(mins => COALESCE(p.approval_window_minutes, 1440)) END AS expires_at
+	FROM events e
+	LEFT JOIN booking_profiles p ON p.user_id = e.host_id
+	LEFT JOIN booking_payments bp ON bp.event_id = e.id
+	WHERE e.status = 'pending' AND e.host_id IS NOT NULL
+	  AND (e.preferences->>'guest_email' IS NOT NULL OR e.title LIKE 'Booking with %')
+`
+
+// GetExpiredBookingRequests returns up to limit pending booking requests whose approval window ended before now
+func (r *BookingRepository) GetExpiredBookingRequests(ctx context.Context, now time.Time, limit int) ([]entity.PendingBookingRequest, error) {
+	var requests []entity.PendingBookingRequest
+	query := `SELECT * FROM (` + pendingBookingRequests + `) pending WHERE expires_at <= $1 ORDER BY expires_at LIMIT $2`
+	if err := r.db.SelectContext(ctx, &requests, query, now, limit); err != nil {
+		logger.Error("BookingRepository:GetExpiredBookingRequests:Error:", err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// GetHeldBookingRequests returns the host's unexpired pending requests overlapping [from, to); they hold their slot
+func (r *BookingRepository) GetHeldBookingRequests(ctx context.Context, hostID uuid.UUID, from, to, now time.Time) ([]entity.PendingBookingRequest, error) {
+	var requests []entity.PendingBookingRequest
+	query := `
+		SELECT * FROM (` + pendingBookingRequests + `) pending
+		WHERE host_id = $1 AND start_date < $3 AND end_date > $2 AND expires_at > $4
+		ORDER BY start_date
+	`
+	if err := r.db.SelectContext(ctx, &requests, query, hostID, from, to, now); err != nil {
+		logger.Error("BookingRepository:GetHeldBookingRequests:Error:", err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ExpireBookingRequest cancels a request that is still pending; false when the host answered it meanwhile
+func (r *BookingRepository) ExpireBookingRequest(ctx context.Context, id uuid.UUID) (bool, error) {
+	var expiredID uuid.UUID
+	query := `UPDATE events SET status = 'cancelled', updated_at = NOW() WHERE id = $1 AND status = 'pending' RETURNING id`
+	if err := r.db.GetContext(ctx, &expiredID, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		logger.Error("BookingRepository:ExpireBookingRequest:Error:", err)
+		return false, err
+	}
+	return true, nil
+}
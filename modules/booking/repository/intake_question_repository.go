@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetIntakeQuestions returns the host's booking form questions of an event type in form order
+func (r *BookingRepository) GetIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string) ([]entity.BookingIntakeQuestion, error) {
+	var questions []entity.BookingIntakeQuestion
+	query := `SELECT * FROM booking_intake_questions WHERE user_id = $1 AND event_type = $2 ORDER BY position`
+	if err := r.db.SelectContext(ctx, &questions, query, userID, eventType); err != nil {
+		logger.Error("BookingRepository:GetIntakeQuestions:Error:", err)
+		return nil, err
+	}
+	return questions, nil
+}
+
+// ReplaceIntakeQuestions makes questions the host's form for an event type in one statement.
+// Questions with an ID are updated in place so stored answers keep pointing at them; the rest are created,
+// and questions missing from the list are removed.
+func (r *BookingRepository) ReplaceIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string, questions []entity.BookingIntakeQuestion) ([]entity.BookingIntakeQuestion, error) {
+	type incoming struct {
+		ID       *uuid.UUID `json:"id"`
+		Label    string     `json:"label"`
+		Type     string     `json:"type"`
+		Options  []string   `json:"options"`
+		Required bool       `json:"required"`
+	}
+	list := make([]incoming, 0, len(questions))
+	for i := range questions {
+		q := incoming{Label: questions[i].Label, Type: string(questions[i].Type), Options: questions[i].Options, Required: questions[i].Required}
+		if questions[i].ID != uuid.Nil {
+			id := questions[i].ID
+			q.ID = &id
+		}
+		if q.Options == nil {
+			q.Options = []string{}
+		}
+		list = append(list, q)
+	}
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []entity.BookingIntakeQuestion
+	query := `
+		WITH incoming AS (
+			SELECT COALESCE((q.value->>'id')::uuid, gen_random_uuid()) AS id,
+			       (q.ordinality - 1)::int AS position,
+			       q.value->>'label' AS label,
+			       q.value->>'type' AS type,
+			       ARRAY(SELECT jsonb_array_elements_text(q.value->'options')) AS options,
+			       (q.value->>'required')::boolean AS required
+			FROM jsonb_array_elements($3::jsonb) WITH ORDINALITY AS q(value, ordinality)
+		), removed AS (
+			DELETE FROM booking_intake_questions
+			WHERE user_id = $1 AND event_type = $2 AND id NOT IN (SELECT id FROM incoming)
+		)
+		INSERT INTO booking_intake_questions (id, user_id, event_type, position, label, type, options, required, created_at, updated_at)
+		SELECT id, $1, $2, position, label, type, options, required, NOW(), NOW() FROM incoming
+		ON CONFLICT (id) DO UPDATE SET
+			position = EXCLUDED.position,
+			label = EXCLUDED.label,
+			type = EXCLUDED.type,
+			options = EXCLUDED.options,
+			required = EXCLUDED.required,
+			updated_at = NOW()
+		WHERE booking_intake_questions.user_id = $1 AND booking_intake_questions.event_type = $2
+		RETURNING *
+	`
+	if err := r.db.SelectContext(ctx, &saved, query, userID, eventType, string(payload)); err != nil {
+		logger.Error("BookingRepository:ReplaceIntakeQuestions:Error:", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// GetBookingRequestsWithAnswers returns the host's booking requests that stored intake answers, newest first
+func (r *BookingRepository) GetBookingRequestsWithAnswers(ctx context.Context, hostID uuid.UUID, eventType string, limit int) ([]entity.BookingRequestAnswers, error) {
+	var requests []entity.BookingRequestAnswers
+	query := `
+		SELECT e.id, e.title, e.status, e.start_date, e.end_date, e.preferences, e.created_at
+		FROM events e
+		WHERE e.host_id = $1 AND e.preferences->'answers' IS NOT NULL
+		  AND ($2 = '' OR COALESCE(e.preferences->>'event_type', 'default') = $2)
+		ORDER BY e.created_at DESC
+		LIMIT $3
+	`
+	if err := r.db.SelectContext(ctx, &requests, query, hostID, eventType, limit); err != nil {
+		logger.Error("BookingRepository:GetBookingRequestsWithAnswers:Error:", err)
+		return nil, err
+	}
+	return requests, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// JoinWaitlist adds a guest to the host's waitlist. A guest already waiting for the same day and event type
+// keeps their place; their name and answers are updated.
+func (r *BookingRepository) JoinWaitlist(ctx context.Context, entry *entity.BookingWaitlistEntry) error {
+	query := `
+		INSERT INTO booking_waitlist_entries (host_id, host_key, guest_name, guest_email, event_type, wait_date, timezone, answers, payment_method_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'waiting', NOW(), NOW())
+		ON CONFLICT (host_id, lower(guest_email), event_type, COALESCE(wait_date, DATE '0001-01-01')) WHERE status = 'waiting'
+		DO UPDATE SET host_key = EXCLUDED.host_key, guest_name = EXCLUDED.guest_name, timezone = EXCLUDED.timezone,
+			answers = EXCLUDED.answers, payment_method_id = EXCLUDED.payment_method_id, updated_at = NOW()
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, entry, query,
+		entry.HostID, entry.HostKey, entry.GuestName, entry.GuestEmail, entry.EventType, entry.WaitDate, entry.Timezone, entry.Answers,
+		entry.PaymentMethodID,
+	); err != nil {
+		logger.Error("BookingRepository:JoinWaitlist:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetWaitlistEntries returns the guests waiting on the host's waitlist in join order
+func (r *BookingRepository) GetWaitlistEntries(ctx context.Context, hostID uuid.UUID) ([]entity.BookingWaitlistEntry, error) {
+	var entries []entity.BookingWaitlistEntry
+	query := `SELECT * FROM booking_waitlist_entries WHERE host_id = $1 AND status = 'waiting' ORDER BY created_at, id`
+	if err := r.db.SelectContext(ctx, &entries, query, hostID); err != nil {
+		logger.Error("BookingRepository:GetWaitlistEntries:Error:", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetWaitlistEntry returns a waitlist entry by ID
+func (r *BookingRepository) GetWaitlistEntry(ctx context.Context, id uuid.UUID) (*entity.BookingWaitlistEntry, error) {
+	var entry entity.BookingWaitlistEntry
+	query := `SELECT * FROM booking_waitlist_entries WHERE id = $1`
+	if err := r.db.GetContext(ctx, &entry, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetWaitlistEntry:Error:", err)
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RemoveWaitlistEntry takes a waiting guest off the host's waitlist
+func (r *BookingRepository) RemoveWaitlistEntry(ctx context.Context, hostID, id uuid.UUID) (bool, error) {
+	var removed uuid.UUID
+	query := `
+		UPDATE booking_waitlist_entries SET status = 'removed', updated_at = NOW()
+		WHERE host_id = $1 AND id = $2 AND status = 'waiting'
+		RETURNING id
+	`
+	if err := r.db.GetContext(ctx, &removed, query, hostID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		logger.Error("BookingRepository:RemoveWaitlistEntry:Error:", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateWaitlistOffer offers a freed slot to the first waiting guest, in join order, who waits for its day and
+// event type, has no open offer and was not offered this slot before. It returns nil when nobody is left to offer
+// the slot to or the slot is already on offer.
+func (r *BookingRepository) CreateWaitlistOffer(ctx context.Context, offer *entity.BookingWaitlistOffer) (*entity.BookingWaitlistOffer, error) {
+	var created entity.BookingWaitlistOffer
+	query := `
+		WITH next AS (
+			SELECT w.id FROM booking_waitlist_entries w
+			WHERE w.host_id = $2 AND w.event_type = $3 AND w.status = 'waiting'
+			  AND (w.wait_date IS NULL OR w.wait_date = ($4::timestamptz AT TIME ZONE w.timezone)::date)
+			  AND NOT EXISTS (
+				SELECT 1 FROM booking_waitlist_offers o
+				WHERE o.entry_id = w.id AND (o.status = 'open' OR o.start_time = $4)
+			  )
+			  AND NOT EXISTS (
+				SELECT 1 FROM booking_waitlist_offers o
+				WHERE o.host_id = $2 AND o.start_time = $4 AND o.status = 'open'
+			  )
+			ORDER BY w.created_at, w.id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		INSERT INTO booking_waitlist_offers (id, entry_id, host_id, event_type, start_time, end_time, token_hash, status, expires_at, created_at)
+		SELECT $1, next.id, $2, $3, $4, $5, $6, 'open', $7, NOW() FROM next
+		RETURNING *
+	`
+	if err := r.db.GetContext(ctx, &created, query,
+		offer.ID, offer.HostID, offer.EventType, offer.StartTime, offer.EndTime, offer.TokenHash, offer.ExpiresAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:CreateWaitlistOffer:Error:", err)
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetWaitlistOffer returns a waitlist offer by ID
+func (r *BookingRepository) GetWaitlistOffer(ctx context.Context, id uuid.UUID) (*entity.BookingWaitlistOffer, error) {
+	var offer entity.BookingWaitlistOffer
+	query := `SELECT * FROM booking_waitlist_offers WHERE id = $1`
+	if err := r.db.GetContext(ctx, &offer, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:GetWaitlistOffer:Error:", err)
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// ClaimWaitlistOffer marks an open, unexpired offer as claimed and its guest as booked.
+// It returns nil when the offer was already claimed, expired or taken.
+func (r *BookingRepository) ClaimWaitlistOffer(ctx context.Context, id uuid.UUID, tokenHash string) (*entity.BookingWaitlistOffer, error) {
+	var offer entity.BookingWaitlistOffer
+	query := `
+		WITH claimed AS (
+			UPDATE booking_waitlist_offers SET status = 'claimed', claimed_at = NOW()
+			WHERE id = $1 AND token_hash = $2 AND status = 'open' AND expires_at > NOW()
+			RETURNING *
+		), booked AS (
+			UPDATE booking_waitlist_entries w SET status = 'booked', updated_at = NOW()
+			FROM claimed
+			WHERE w.id = claimed.entry_id AND w.status = 'waiting'
+		)
+		SELECT * FROM claimed
+	`
+	if err := r.db.GetContext(ctx, &offer, query, id, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("BookingRepository:ClaimWaitlistOffer:Error:", err)
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// CloseWaitlistOffer closes an open offer with status, e.g. when the slot was booked by someone else
+func (r *BookingRepository) CloseWaitlistOffer(ctx context.Context, id uuid.UUID, status entity.WaitlistOfferStatus) error {
+	query := `UPDATE booking_waitlist_offers SET status = $2 WHERE id = $1 AND status = 'open'`
+	if err := r.db.ExecContext(ctx, query, id, status); err != nil {
+		logger.Error("BookingRepository:CloseWaitlistOffer:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ExpireWaitlistOffers marks up to limit open offers that expired before now as expired and returns them
+func (r *BookingRepository) ExpireWaitlistOffers(ctx context.Context, now time.Time, limit int) ([]entity.BookingWaitlistOffer, error) {
+	var offers []entity.BookingWaitlistOffer
+	query := `
+		UPDATE booking_waitlist_offers SET status = 'expired'
+		WHERE id IN (
+			SELECT id FROM booking_waitlist_offers
+			WHERE status = 'open' AND expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	if err := r.db.SelectContext(ctx, &offers, query, now, limit); err != nil {
+		logger.Error("BookingRepository:ExpireWaitlistOffers:Error:", err)
+		return nil, err
+	}
+	return offers, nil
+}
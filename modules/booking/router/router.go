@@ -15,17 +15,28 @@ func NewBookingRouter(ctrl *controller.BookingController) *BookingRouter {
 }
 
 func (r *BookingRouter) Setup(e *echo.Echo, mw interface{}) {
+	e.Static("/static/booking", "static/booking")
 	e.GET("/p/:slug", r.Controller.PublicPage)
 	e.GET("/personal-booking/:id", r.Controller.PublicPersonalPage)
 	e.GET("/api/v1/public/booking/:slug/free", r.Controller.PublicFreeSlots)
 	e.POST("/api/v1/public/booking/:slug/schedule", r.Controller.PublicSchedule)
+	e.GET("/api/v1/public/booking/:slug/challenge", r.Controller.PublicBookingChallenge)
+	e.GET("/api/v1/public/booking/:slug/questions", r.Controller.PublicIntakeQuestions)
+	e.GET("/api/v1/public/booking/:slug/price", r.Controller.PublicBookingPrice)
+	e.POST("/api/v1/public/booking/verifications/:id", r.Controller.PublicConfirmGuestVerification)
+	e.POST("/api/v1/public/booking/:slug/waitlist", r.Controller.PublicJoinWaitlist)
+	e.GET("/api/v1/public/booking/waitlist/offers/:id/claim", r.Controller.PublicWaitlistOfferPage)
+	e.POST("/api/v1/public/booking/waitlist/offers/:id/claim", r.Controller.PublicClaimWaitlistOffer)
 	e.POST("/api/v1/public/booking/:id/suggested-slots", r.Controller.PublicSuggestedSlots)
-	e.GET("/api/v1/public/booking/requests/:id/accept", r.Controller.PublicTokenAccept)
-	e.GET("/api/v1/public/booking/requests/:id/decline", r.Controller.PublicTokenDecline)
+	e.GET("/api/v1/public/booking/requests/:id/accept", r.Controller.PublicTokenAcceptPage)
+	e.GET("/api/v1/public/booking/requests/:id/decline", r.Controller.PublicTokenDeclinePage)
+	e.POST("/api/v1/public/booking/requests/:id/accept", r.Controller.PublicTokenAccept)
+	e.POST("/api/v1/public/booking/requests/:id/decline", r.Controller.PublicTokenDecline)
 	// Private booking approval routes
 	if mw != nil {
 		if m, ok := mw.(interface {
 			AuthMiddleware() echo.MiddlewareFunc
+			PermissionMiddleware(requiredPermissions ...string) echo.MiddlewareFunc
 		}); ok {
 			v1 := e.Group("/api/v1")
 			priv := v1.Group("/private", m.AuthMiddleware())
@@ -33,11 +44,36 @@ func (r *BookingRouter) Setup(e *echo.Echo, mw interface{}) {
 			req.GET("", r.Controller.PrivateListPending)
 			req.POST("/:id/accept", r.Controller.PrivateAcceptRequest)
 			req.POST("/:id/decline", r.Controller.PrivateDeclineRequest)
+			req.POST("/:id/cancel", r.Controller.PrivateCancelBooking)
+			req.POST("/:id/payment", r.Controller.PrivateConfirmBookingPayment)
+			req.POST("/:id/approval-links", r.Controller.PrivateRegenerateApprovalLinks)
+			req.DELETE("/:id/approval-links", r.Controller.PrivateRevokeApprovalLinks)
 			
 			// Personal booking URL endpoint
 			booking := priv.Group("/booking")
 			booking.GET("/personal-url", r.Controller.GetPersonalBookingURL)
 			booking.GET("/week-statistics", r.Controller.GetWeekStatistics)
+			booking.GET("/analytics", r.Controller.GetAnalytics)
+			booking.GET("/analytics/team", r.Controller.GetTeamAnalytics, m.PermissionMiddleware("analytics:team"))
+			booking.GET("/profile", r.Controller.GetBookingProfile)
+			booking.PUT("/profile", r.Controller.UpdateBookingProfile)
+			booking.PUT("/profile/slug", r.Controller.ChangeBookingSlug)
+			booking.GET("/profile/slug-availability", r.Controller.CheckSlugAvailability)
+			booking.GET("/approval-policies", r.Controller.ListApprovalPolicies)
+			booking.PUT("/approval-policies/:event_type", r.Controller.SaveApprovalPolicy)
+			booking.DELETE("/approval-policies/:event_type", r.Controller.DeleteApprovalPolicy)
+			booking.GET("/blocked-guests", r.Controller.ListBlockedGuests)
+			booking.POST("/blocked-guests", r.Controller.BlockGuest)
+			booking.DELETE("/blocked-guests/:id", r.Controller.UnblockGuest)
+			booking.GET("/guest-attendance", r.Controller.ListGuestAttendance)
+			booking.GET("/intake-questions/:event_type", r.Controller.ListIntakeQuestions)
+			booking.PUT("/intake-questions/:event_type", r.Controller.SaveIntakeQuestions)
+			booking.GET("/intake-answers/export", r.Controller.ExportIntakeAnswers)
+			booking.GET("/waitlist", r.Controller.ListWaitlist)
+			booking.DELETE("/waitlist/:id", r.Controller.RemoveWaitlistEntry)
+			booking.GET("/prices", r.Controller.ListBookingPrices)
+			booking.PUT("/prices/:event_type", r.Controller.SaveBookingPrice)
+			booking.DELETE("/prices/:event_type", r.Controller.DeleteBookingPrice)
 		}
 	}
 }
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+// bookingActionScopes is the token scope of each booking request link, so a decline link cannot accept
+var bookingActionScopes = map[entity.BookingAction]string{
+	entity.BookingActionAccept:  constants.ScopeTokenBookingAccept,
+	entity.BookingActionDecline: constants.ScopeTokenBookingDecline,
+}
+
+// IssueApprovalLinks revokes the open links of a booking request and issues new one-time accept and decline links valid for ttl
+func (s *bookingService) IssueApprovalLinks(ctx context.Context, hostID, eventID uuid.UUID, ttl time.Duration) (*dto.ApprovalLinks, *errors.AppError) {
+	if ttl <= 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "booking request has expired", nil)
+	}
+	if err := s.bookingRepo.RevokeActionTokens(ctx, eventID); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to revoke approval links", err)
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	links := &dto.ApprovalLinks{ExpiresAt: expiresAt}
+	base := PublicBaseURL() + "/api/v1/public/booking/requests/" + eventID.String()
+	for _, action := range []entity.BookingAction{entity.BookingActionAccept, entity.BookingActionDecline} {
+		// The token subject is the event, like invitation RSVP tokens are bound to their invitation
+		token, err := utils.GenerateToken(eventID, nil, nil, bookingActionScopes[action], ttl)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to issue approval link", err)
+		}
+		if err := s.bookingRepo.CreateActionToken(ctx, &entity.BookingActionToken{
+			EventID:   eventID,
+			HostID:    hostID,
+			Action:    action,
+			TokenHash: hashActionToken(token),
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to issue approval link", err)
+		}
+
+		link := base + "/" + string(action) + "?token=" + url.QueryEscape(token)
+		if action == entity.BookingActionAccept {
+			links.AcceptURL = link
+		} else {
+			links.DeclineURL = link
+		}
+	}
+	return links, nil
+}
+
+// verifyActionToken checks the signature, scope and subject of a booking request link token
+func verifyActionToken(eventID uuid.UUID, action entity.BookingAction, token string) *errors.AppError {
+	scope, ok := bookingActionScopes[action]
+	if !ok {
+		return errors.NewAppError(errors.ErrInvalidInput, "unknown booking action", nil)
+	}
+	claims, err := utils.ValidateAndParseToken(token)
+	if err != nil {
+		return errors.NewAppError(errors.ErrUnauthorized, "invalid token", err)
+	}
+	if !utils.ValidateTokenScope(claims, scope) || claims.UserID != eventID {
+		return errors.NewAppError(errors.ErrUnauthorized, "token is not valid for this action", nil)
+	}
+	return nil
+}
+
+// CheckActionToken verifies that token is an open link for action on the event without using it,
+// for the confirmation page shown before the action is taken
+func (s *bookingService) CheckActionToken(ctx context.Context, eventID uuid.UUID, action entity.BookingAction, token string) (*entity.BookingActionToken, *errors.AppError) {
+	if appErr := verifyActionToken(eventID, action, token); appErr != nil {
+		return nil, appErr
+	}
+	open, err := s.bookingRepo.GetOpenActionToken(ctx, hashActionToken(token), eventID, action)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to check approval link", err)
+	}
+	if open == nil {
+		return nil, errors.NewAppError(errors.ErrUnauthorized, "this link was already used or revoked", nil)
+	}
+	return open, nil
+}
+
+// ConsumeActionToken verifies that token is an open link for action on the event and uses it up.
+// The event's other links are revoked, so a request is answered from email at most once.
+// When the action then fails, RestoreActionToken opens the links again.
+func (s *bookingService) ConsumeActionToken(ctx context.Context, eventID uuid.UUID, action entity.BookingAction, token string) (*entity.BookingActionToken, *errors.AppError) {
+	if appErr := verifyActionToken(eventID, action, token); appErr != nil {
+		return nil, appErr
+	}
+
+	used, err := s.bookingRepo.ConsumeActionToken(ctx, hashActionToken(token), eventID, action)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to use approval link", err)
+	}
+	if used == nil {
+		return nil, errors.NewAppError(errors.ErrUnauthorized, "this link was already used or revoked", nil)
+	}
+	return used, nil
+}
+
+// RestoreActionToken reopens a link consumed by ConsumeActionToken, and the links revoked with it,
+// after the action it authorised could not be completed
+func (s *bookingService) RestoreActionToken(ctx context.Context, used *entity.BookingActionToken) *errors.AppError {
+	if err := s.bookingRepo.RestoreActionToken(ctx, used); err != nil {
+		return errors.NewAppError(errors.ErrUpdateFailed, "Failed to restore approval link", err)
+	}
+	return nil
+}
+
+// RevokeApprovalLinks revokes the open accept/decline links of a booking request
+func (s *bookingService) RevokeApprovalLinks(ctx context.Context, eventID uuid.UUID) *errors.AppError {
+	if err := s.bookingRepo.RevokeActionTokens(ctx, eventID); err != nil {
+		return errors.NewAppError(errors.ErrUpdateFailed, "Failed to revoke approval links", err)
+	}
+	return nil
+}
+
+func hashActionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+	calDto "go-api-starter/modules/calendar/dto"
+	calEntity "go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	analyticsDefaultTimezone  = "Asia/Ho_Chi_Minh"
+	analyticsMaxRangeDays     = 366
+	analyticsTopCollaborators = 10
+
+	// Working hours used for after-hours load, same window as slot suggestions
+	workdayStartHour = 8
+	workdayEndHour   = 18
+
+	// backToBackGap is the largest break between two meetings still counted as back-to-back
+	backToBackGap = 5 * time.Minute
+)
+
+type syncedAttendee struct {
+	Email          string `json:"email"`
+	ResponseStatus string `json:"response_status"`
+	Self           bool   `json:"self"`
+}
+
+type analyticsMeeting struct {
+	userID    uuid.UUID
+	start     time.Time
+	end       time.Time
+	attendees []syncedAttendee
+}
+
+// GetTeamAnalytics computes analytics for several users on behalf of callerID. Every user other than
+// the caller must share a group with them; the analytics:team permission alone does not open up arbitrary users.
+func (s *bookingService) GetTeamAnalytics(ctx context.Context, callerID uuid.UUID, userIDs []uuid.UUID, query dto.AnalyticsQuery) (*dto.AnalyticsResponse, *errors.AppError) {
+	others := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != callerID {
+			others = append(others, id)
+		}
+	}
+	if len(others) > 0 {
+		mates, err := s.bookingRepo.GetGroupMatesAmong(ctx, callerID, others)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrGetFailed, "failed to check group membership", err)
+		}
+		allowed := make(map[uuid.UUID]bool, len(mates))
+		for _, id := range mates {
+			allowed[id] = true
+		}
+		for _, id := range others {
+			if !allowed[id] {
+				return nil, errors.NewAppError(errors.ErrForbidden, "user "+id.String()+" is not in any of your groups", nil)
+			}
+		}
+	}
+
+	return s.GetAnalytics(ctx, userIDs, query)
+}
+
+// GetAnalytics computes meeting load, focus time and booking page conversion for the given users
+// over a date range. It reads the calendar mirror kept by the sync job, never Google directly.
+func (s *bookingService) GetAnalytics(ctx context.Context, userIDs []uuid.UUID, query dto.AnalyticsQuery) (*dto.AnalyticsResponse, *errors.AppError) {
+	if len(userIDs) == 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "user_ids is required", nil)
+	}
+
+	timezone := strings.TrimSpace(query.Timezone)
+	if timezone == "" {
+		timezone = analyticsDefaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid timezone", err)
+	}
+
+	startDay, endDay, appErr := analyticsRange(query, loc)
+	if appErr != nil {
+		return nil, appErr
+	}
+	from := startDay
+	to := endDay.AddDate(0, 0, 1)
+
+	events, err := s.calRepo.GetSyncedEventsInRange(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get calendar events", err)
+	}
+	pageStats, err := s.bookingRepo.GetPageStats(ctx, userIDs, startDay, endDay)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get booking page statistics", err)
+	}
+
+	result := &dto.AnalyticsResponse{
+		StartDate: startDay.Format("2006-01-02"),
+		EndDate:   endDay.Format("2006-01-02"),
+		Timezone:  timezone,
+	}
+
+	// Buckets for every day and every week of the range, including empty ones
+	daily := map[string]*dto.AnalyticsBucket{}
+	weekly := map[string]*dto.AnalyticsBucket{}
+	for day := startDay; day.Before(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		result.Daily = append(result.Daily, dto.AnalyticsBucket{Date: key})
+		weekKey := weekStart(day).Format("2006-01-02")
+		if _, ok := weekly[weekKey]; !ok {
+			result.Weekly = append(result.Weekly, dto.AnalyticsBucket{Date: weekKey})
+			weekly[weekKey] = nil
+		}
+	}
+	for i := range result.Daily {
+		daily[result.Daily[i].Date] = &result.Daily[i]
+	}
+	for i := range result.Weekly {
+		weekly[result.Weekly[i].Date] = &result.Weekly[i]
+	}
+
+	perUser := map[uuid.UUID]*dto.UserAnalytics{}
+	for _, userID := range userIDs {
+		perUser[userID] = &dto.UserAnalytics{UserID: userID.String()}
+	}
+
+	var meetings []analyticsMeeting
+	for i := range events {
+		ev := &events[i]
+		user := perUser[ev.UserID]
+		if user == nil || ev.AllDay || ev.Status == "cancelled" || ev.EventType == "outOfOffice" || ev.EventType == "workingLocation" {
+			continue
+		}
+
+		start, end := clampRange(ev.StartTime, ev.EndTime, from, to)
+		if !end.After(start) {
+			continue
+		}
+		minutes := int(end.Sub(start).Minutes())
+		dayKey := start.In(loc).Format("2006-01-02")
+		weekKey := weekStart(start.In(loc)).Format("2006-01-02")
+
+		if isFocusEvent(ev) {
+			for _, summary := range []*dto.AnalyticsSummary{&result.Summary, &user.AnalyticsSummary, bucketSummary(daily, dayKey), bucketSummary(weekly, weekKey)} {
+				if summary != nil {
+					summary.FocusMinutes += minutes
+				}
+			}
+			continue
+		}
+
+		if ev.Transparency == "transparent" {
+			continue
+		}
+		var attendees []syncedAttendee
+		_ = json.Unmarshal([]byte(ev.Attendees), &attendees)
+		if declinedBySelf(attendees) {
+			continue
+		}
+
+		afterHours := afterHoursMinutes(start.In(loc), end.In(loc))
+		for _, summary := range []*dto.AnalyticsSummary{&result.Summary, &user.AnalyticsSummary, bucketSummary(daily, dayKey), bucketSummary(weekly, weekKey)} {
+			if summary == nil {
+				continue
+			}
+			summary.Meetings++
+			summary.MeetingMinutes += minutes
+			if afterHours > 0 {
+				summary.AfterHoursMeetings++
+				summary.AfterHoursMinutes += afterHours
+			}
+		}
+
+		meetings = append(meetings, analyticsMeeting{userID: ev.UserID, start: start, end: end, attendees: attendees})
+	}
+
+	// Back-to-back meetings, per user in start order
+	sort.Slice(meetings, func(i, j int) bool {
+		if meetings[i].userID != meetings[j].userID {
+			return meetings[i].userID.String() < meetings[j].userID.String()
+		}
+		return meetings[i].start.Before(meetings[j].start)
+	})
+	for i := 1; i < len(meetings); i++ {
+		prev, cur := meetings[i-1], meetings[i]
+		if prev.userID != cur.userID {
+			continue
+		}
+		gap := cur.start.Sub(prev.end)
+		if gap < 0 || gap > backToBackGap {
+			continue
+		}
+		dayKey := cur.start.In(loc).Format("2006-01-02")
+		weekKey := weekStart(cur.start.In(loc)).Format("2006-01-02")
+		for _, summary := range []*dto.AnalyticsSummary{&result.Summary, &perUser[cur.userID].AnalyticsSummary, bucketSummary(daily, dayKey), bucketSummary(weekly, weekKey)} {
+			if summary != nil {
+				summary.BackToBack++
+			}
+		}
+	}
+
+	result.TopCollaborators = topCollaborators(meetings, analyticsTopCollaborators)
+
+	for _, stat := range pageStats {
+		user := perUser[stat.UserID]
+		if user == nil {
+			continue
+		}
+		addConversion(&user.Booking, &stat)
+		addConversion(&result.Booking, &stat)
+	}
+
+	finalizeSummary(&result.Summary)
+	finalizeConversion(&result.Booking)
+	for i := range result.Daily {
+		finalizeSummary(&result.Daily[i].AnalyticsSummary)
+	}
+	for i := range result.Weekly {
+		finalizeSummary(&result.Weekly[i].AnalyticsSummary)
+	}
+	for _, userID := range userIDs {
+		user := perUser[userID]
+		finalizeSummary(&user.AnalyticsSummary)
+		finalizeConversion(&user.Booking)
+		if state, err := s.calRepo.GetSyncState(ctx, userID, calDto.ProviderGoogle); err == nil && state != nil && state.LastSyncedAt != nil {
+			user.LastSyncedAt = state.LastSyncedAt.Format(time.RFC3339)
+		}
+		result.Users = append(result.Users, *user)
+	}
+
+	logger.Info("BookingService:GetAnalytics:Success",
+		"users", len(userIDs),
+		"start_date", result.StartDate,
+		"end_date", result.EndDate,
+		"meetings", result.Summary.Meetings)
+
+	return result, nil
+}
+
+// RecordPageStat bumps a booking page funnel counter; failures only get logged
+func (s *bookingService) RecordPageStat(ctx context.Context, userID uuid.UUID, stat entity.BookingPageStat) {
+	if s.bookingRepo == nil {
+		return
+	}
+	if err := s.bookingRepo.IncrementPageStat(ctx, userID, stat); err != nil {
+		logger.Error("BookingService:RecordPageStat:Error", "user_id", userID, "stat", stat, "error", err)
+	}
+}
+
+// analyticsRange parses the requested dates, defaulting to the current Monday-Sunday week
+func analyticsRange(query dto.AnalyticsQuery, loc *time.Location) (time.Time, time.Time, *errors.AppError) {
+	now := time.Now().In(loc)
+	startDay := weekStart(now)
+	endDay := startDay.AddDate(0, 0, 6)
+
+	if query.StartDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", query.StartDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewAppError(errors.ErrInvalidInput, "invalid start_date, expected YYYY-MM-DD", err)
+		}
+		startDay = t
+		if query.EndDate == "" {
+			endDay = startDay.AddDate(0, 0, 6)
+		}
+	}
+	if query.EndDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", query.EndDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewAppError(errors.ErrInvalidInput, "invalid end_date, expected YYYY-MM-DD", err)
+		}
+		endDay = t
+	}
+
+	if endDay.Before(startDay) {
+		return time.Time{}, time.Time{}, errors.NewAppError(errors.ErrInvalidInput, "end_date must not be before start_date", nil)
+	}
+	if endDay.Sub(startDay) >= analyticsMaxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.NewAppError(errors.ErrInvalidInput, "date range must not exceed 366 days", nil)
+	}
+	return startDay, endDay, nil
+}
+
+// weekStart returns midnight of the Monday of t's week, in t's location
+func weekStart(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	monday := t.AddDate(0, 0, -(weekday - 1))
+	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func clampRange(start, end, from, to time.Time) (time.Time, time.Time) {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return start, end
+}
+
+func isFocusEvent(ev *calEntity.SyncedEvent) bool {
+	if ev.EventType == "focusTime" {
+		return true
+	}
+	return ev.Summary != nil && strings.EqualFold(strings.TrimSpace(*ev.Summary), "Focus time")
+}
+
+func declinedBySelf(attendees []syncedAttendee) bool {
+	for _, a := range attendees {
+		if a.Self {
+			return a.ResponseStatus == "declined"
+		}
+	}
+	return false
+}
+
+// afterHoursMinutes counts the minutes of [start, end) outside weekday working hours
+func afterHoursMinutes(start, end time.Time) int {
+	total := int(end.Sub(start).Minutes())
+	inHours := 0
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location()); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		workStart := time.Date(day.Year(), day.Month(), day.Day(), workdayStartHour, 0, 0, 0, day.Location())
+		workEnd := time.Date(day.Year(), day.Month(), day.Day(), workdayEndHour, 0, 0, 0, day.Location())
+		s, e := clampRange(start, end, workStart, workEnd)
+		if e.After(s) {
+			inHours += int(e.Sub(s).Minutes())
+		}
+	}
+	return total - inHours
+}
+
+func topCollaborators(meetings []analyticsMeeting, limit int) []dto.CollaboratorStat {
+	byEmail := map[string]*dto.CollaboratorStat{}
+	for _, m := range meetings {
+		minutes := int(m.end.Sub(m.start).Minutes())
+		seen := map[string]bool{}
+		for _, a := range m.attendees {
+			email := strings.ToLower(strings.TrimSpace(a.Email))
+			if a.Self || email == "" || seen[email] {
+				continue
+			}
+			seen[email] = true
+			stat, ok := byEmail[email]
+			if !ok {
+				stat = &dto.CollaboratorStat{Email: email}
+				byEmail[email] = stat
+			}
+			stat.Meetings++
+			stat.MeetingMinutes += minutes
+		}
+	}
+
+	result := make([]dto.CollaboratorStat, 0, len(byEmail))
+	for _, stat := range byEmail {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Meetings != result[j].Meetings {
+			return result[i].Meetings > result[j].Meetings
+		}
+		if result[i].MeetingMinutes != result[j].MeetingMinutes {
+			return result[i].MeetingMinutes > result[j].MeetingMinutes
+		}
+		return result[i].Email < result[j].Email
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func bucketSummary(buckets map[string]*dto.AnalyticsBucket, key string) *dto.AnalyticsSummary {
+	bucket := buckets[key]
+	if bucket == nil {
+		return nil
+	}
+	return &bucket.AnalyticsSummary
+}
+
+func addConversion(conversion *dto.BookingConversion, stat *entity.BookingPageDailyStats) {
+	conversion.PageViews += stat.PageViews
+	conversion.Requests += stat.Requests
+	conversion.Accepted += stat.Accepted
+	conversion.Declined += stat.Declined
+}
+
+func finalizeSummary(summary *dto.AnalyticsSummary) {
+	summary.MeetingHours = math.Round(float64(summary.MeetingMinutes)/60.0*100) / 100
+	summary.FocusHours = math.Round(float64(summary.FocusMinutes)/60.0*100) / 100
+}
+
+func finalizeConversion(conversion *dto.BookingConversion) {
+	if conversion.PageViews > 0 {
+		conversion.RequestRate = math.Round(float64(conversion.Requests)/float64(conversion.PageViews)*10000) / 10000
+	}
+	if conversion.Requests > 0 {
+		conversion.AcceptanceRate = math.Round(float64(conversion.Accepted)/float64(conversion.Requests)*10000) / 10000
+	}
+}
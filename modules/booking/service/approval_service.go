@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+const maxAllowedDomains = 50
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// NormalizeEventType returns the event type key of a booking request, defaulting to entity.DefaultEventType
+func NormalizeEventType(eventType string) string {
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	if eventType == "" {
+		return entity.DefaultEventType
+	}
+	return eventType
+}
+
+// ListApprovalPolicies returns the host's approval policies
+func (s *bookingService) ListApprovalPolicies(ctx context.Context, userID uuid.UUID) ([]dto.ApprovalPolicyResponse, *errors.AppError) {
+	policies, err := s.bookingRepo.GetApprovalPolicies(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get approval policies", err)
+	}
+
+	result := make([]dto.ApprovalPolicyResponse, 0, len(policies))
+	for i := range policies {
+		result = append(result, toApprovalPolicyResponse(&policies[i]))
+	}
+	return result, nil
+}
+
+// SaveApprovalPolicy creates or replaces the host's approval policy for an event type
+func (s *bookingService) SaveApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, *errors.AppError) {
+	eventType = NormalizeEventType(eventType)
+	if len(eventType) > 64 || !slugPattern.MatchString(eventType) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event_type may only contain lowercase letters, digits and hyphens (max 64)", nil)
+	}
+
+	policy := &entity.BookingApprovalPolicy{
+		UserID:                userID,
+		EventType:             eventType,
+		Mode:                  entity.ApprovalMode(strings.ToLower(strings.TrimSpace(req.Mode))),
+		AllowedDomains:        []string{},
+		AcceptReturningGuests: req.AcceptReturningGuests,
+	}
+	switch policy.Mode {
+	case entity.ApprovalModeManual, entity.ApprovalModeAuto:
+	case entity.ApprovalModeConditional:
+		if len(req.AllowedDomains) > maxAllowedDomains {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "allowed_domains can list at most 50 domains", nil)
+		}
+		seen := map[string]bool{}
+		for _, raw := range req.AllowedDomains {
+			domain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(raw)), "@")
+			if !domainPattern.MatchString(domain) {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid domain: "+raw, nil)
+			}
+			if !seen[domain] {
+				seen[domain] = true
+				policy.AllowedDomains = append(policy.AllowedDomains, domain)
+			}
+		}
+		if len(policy.AllowedDomains) == 0 && !policy.AcceptReturningGuests {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "A conditional policy needs allowed_domains or accept_returning_guests", nil)
+		}
+	default:
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "mode must be manual, auto or conditional", nil)
+	}
+	if policy.Mode != entity.ApprovalModeConditional {
+		policy.AcceptReturningGuests = false
+	}
+
+	if err := s.bookingRepo.SaveApprovalPolicy(ctx, policy); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save approval policy", err)
+	}
+	response := toApprovalPolicyResponse(policy)
+	return &response, nil
+}
+
+// DeleteApprovalPolicy removes the host's policy for an event type; its requests fall back to the default policy
+func (s *bookingService) DeleteApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string) *errors.AppError {
+	eventType = NormalizeEventType(eventType)
+	policy, err := s.bookingRepo.GetApprovalPolicy(ctx, userID, eventType)
+	if err != nil {
+		return errors.NewAppError(errors.ErrGetFailed, "Failed to get approval policy", err)
+	}
+	if policy == nil {
+		return errors.NewAppError(errors.ErrNotFound, "Approval policy not found", nil)
+	}
+	if err := s.bookingRepo.DeleteApprovalPolicy(ctx, userID, eventType); err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "Failed to delete approval policy", err)
+	}
+	return nil
+}
+
+// EvaluateApproval decides whether a booking request is accepted without the host.
+// The event type's policy applies, then the host's default policy; without any the host approves manually.
+// Guests the host's no-show policy restricts always wait for the host. Lookup errors fall back to manual approval.
+func (s *bookingService) EvaluateApproval(ctx context.Context, hostID uuid.UUID, eventType, guestEmail string) dto.ApprovalDecision {
+	if s.noShowRestriction(ctx, hostID, guestEmail) != "" {
+		return dto.ApprovalDecision{Reason: "no_shows"}
+	}
+	eventType = NormalizeEventType(eventType)
+	policy, err := s.bookingRepo.GetApprovalPolicy(ctx, hostID, eventType)
+	if err == nil && policy == nil && eventType != entity.DefaultEventType {
+		policy, err = s.bookingRepo.GetApprovalPolicy(ctx, hostID, entity.DefaultEventType)
+	}
+	if err != nil || policy == nil {
+		return dto.ApprovalDecision{}
+	}
+
+	switch policy.Mode {
+	case entity.ApprovalModeAuto:
+		return dto.ApprovalDecision{AutoAccept: true, Reason: "always"}
+	case entity.ApprovalModeConditional:
+		guestEmail = strings.ToLower(strings.TrimSpace(guestEmail))
+		at := strings.LastIndex(guestEmail, "@")
+		if at <= 0 {
+			return dto.ApprovalDecision{}
+		}
+		domain := guestEmail[at+1:]
+		for _, allowed := range policy.AllowedDomains {
+			if domain == allowed {
+				return dto.ApprovalDecision{AutoAccept: true, Reason: "allowed_domain"}
+			}
+		}
+		if policy.AcceptReturningGuests {
+			returning, err := s.bookingRepo.HasAcceptedBookingFromGuest(ctx, hostID, guestEmail)
+			if err != nil {
+				logger.Warn("BookingService:EvaluateApproval:ReturningGuest:Error", "host_id", hostID, "error", err)
+			}
+			if returning {
+				return dto.ApprovalDecision{AutoAccept: true, Reason: "returning_guest"}
+			}
+		}
+	}
+	return dto.ApprovalDecision{}
+}
+
+func toApprovalPolicyResponse(policy *entity.BookingApprovalPolicy) dto.ApprovalPolicyResponse {
+	domains := []string(policy.AllowedDomains)
+	if domains == nil {
+		domains = []string{}
+	}
+	return dto.ApprovalPolicyResponse{
+		EventType:             policy.EventType,
+		Mode:                  string(policy.Mode),
+		AllowedDomains:        domains,
+		AcceptReturningGuests: policy.AcceptReturningGuests,
+	}
+}
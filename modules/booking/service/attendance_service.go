@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+	notifdto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const attendancePromptBatchSize = 100
+
+// noShowRestriction returns what the host's no-show policy does to a guest's booking requests,
+// "" when the policy is off or the guest is below the threshold. Lookup errors do not restrict.
+func (s *bookingService) noShowRestriction(ctx context.Context, hostID uuid.UUID, email string) entity.NoShowAction {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ""
+	}
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, hostID)
+	if err != nil {
+		logger.Warn("BookingService:noShowRestriction:GetProfile:Error", "host_id", hostID, "error", err)
+		return ""
+	}
+	if profile == nil || profile.NoShowThreshold <= 0 {
+		return ""
+	}
+	noShows, err := s.bookingRepo.CountGuestNoShows(ctx, hostID, email)
+	if err != nil {
+		logger.Warn("BookingService:noShowRestriction:CountNoShows:Error", "host_id", hostID, "error", err)
+		return ""
+	}
+	if noShows < profile.NoShowThreshold {
+		return ""
+	}
+	return profile.NoShowAction
+}
+
+// ListGuestAttendance returns the host's guests with no-shows and what the no-show policy does to them.
+// A non-empty email looks up that guest only.
+func (s *bookingService) ListGuestAttendance(ctx context.Context, userID uuid.UUID, email string) ([]dto.GuestAttendanceResponse, *errors.AppError) {
+	guests, err := s.bookingRepo.GetGuestAttendance(ctx, userID, strings.TrimSpace(email))
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get guest attendance", err)
+	}
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking profile", err)
+	}
+
+	result := make([]dto.GuestAttendanceResponse, 0, len(guests))
+	for _, guest := range guests {
+		response := dto.GuestAttendanceResponse{
+			Email:        guest.Email,
+			NoShows:      guest.NoShows,
+			Late:         guest.Late,
+			Attended:     guest.Attended,
+			LastNoShowAt: guest.LastNoShowAt,
+		}
+		if profile != nil && profile.NoShowThreshold > 0 && guest.NoShows >= profile.NoShowThreshold {
+			response.Restriction = string(profile.NoShowAction)
+		}
+		result = append(result, response)
+	}
+	return result, nil
+}
+
+// HandleAttendancePromptTask is the worker handler of constants.TopicQueueAttendancePrompts.
+// It asks hosts who turned on attendance prompts to mark who showed up to their ended meetings.
+func (s *bookingService) HandleAttendancePromptTask(ctx context.Context, _ []byte) error {
+	for {
+		prompts, err := s.bookingRepo.ClaimAttendancePrompts(ctx, time.Now(), attendancePromptBatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range prompts {
+			s.promptAttendance(ctx, &prompts[i])
+		}
+		if len(prompts) < attendancePromptBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *bookingService) promptAttendance(ctx context.Context, prompt *entity.AttendancePrompt) {
+	if s.notifSvc == nil {
+		return
+	}
+	if err := s.notifSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+		UserID:  prompt.HostID,
+		Title:   "Cuộc hẹn đã kết thúc, hãy điểm danh người tham gia",
+		Message: prompt.Title,
+		Type:    "attendance_prompt",
+		Data: map[string]interface{}{
+			"event_id": prompt.EventID.String(),
+			"ended_at": prompt.EndedAt.Format(time.RFC3339),
+		},
+	}); err != nil {
+		logger.Warn("BookingService:promptAttendance:Notify:Error", "event_id", prompt.EventID, "error", err)
+	}
+}
@@ -6,13 +6,20 @@ import (
 	"math"
 	"time"
 
-	"go-api-starter/core/config"
+	"go-api-starter/core/cache"
 	"go-api-starter/core/constants"
 	"go-api-starter/core/errors"
 	"go-api-starter/core/logger"
 	"go-api-starter/core/params"
 	authservice "go-api-starter/modules/auth/service"
 	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+	"go-api-starter/modules/booking/repository"
+	caldto "go-api-starter/modules/calendar/dto"
+	calrepo "go-api-starter/modules/calendar/repository"
+	calsvc "go-api-starter/modules/calendar/service"
+	notifsvc "go-api-starter/modules/notification/service"
+	webhooksvc "go-api-starter/modules/webhook/service"
 
 	"github.com/google/uuid"
 )
@@ -20,15 +27,81 @@ import (
 type BookingService interface {
 	GetPersonalBookingURL(ctx context.Context, userID uuid.UUID) (*dto.PersonalBookingURLResponse, *errors.AppError)
 	GetWeekStatistics(ctx context.Context, userID uuid.UUID) (*dto.WeekStatisticsResponse, *errors.AppError)
+	GetAnalytics(ctx context.Context, userIDs []uuid.UUID, query dto.AnalyticsQuery) (*dto.AnalyticsResponse, *errors.AppError)
+	GetTeamAnalytics(ctx context.Context, callerID uuid.UUID, userIDs []uuid.UUID, query dto.AnalyticsQuery) (*dto.AnalyticsResponse, *errors.AppError)
+	RecordPageStat(ctx context.Context, userID uuid.UUID, stat entity.BookingPageStat)
+	GetBookingProfile(ctx context.Context, userID uuid.UUID) (*dto.BookingProfileResponse, *errors.AppError)
+	UpdateBookingProfile(ctx context.Context, userID uuid.UUID, req *dto.UpdateBookingProfileRequest) (*dto.BookingProfileResponse, *errors.AppError)
+	ChangeBookingSlug(ctx context.Context, userID uuid.UUID, req *dto.ChangeBookingSlugRequest) (*dto.BookingProfileResponse, *errors.AppError)
+	CheckSlugAvailability(ctx context.Context, userID uuid.UUID, slug string) (*dto.SlugAvailabilityResponse, *errors.AppError)
+	ResolveBookingSlug(ctx context.Context, slug string) (*ResolvedBookingSlug, *errors.AppError)
+	ListApprovalPolicies(ctx context.Context, userID uuid.UUID) ([]dto.ApprovalPolicyResponse, *errors.AppError)
+	SaveApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, *errors.AppError)
+	DeleteApprovalPolicy(ctx context.Context, userID uuid.UUID, eventType string) *errors.AppError
+	EvaluateApproval(ctx context.Context, hostID uuid.UUID, eventType, guestEmail string) dto.ApprovalDecision
+	ApprovalWindow(ctx context.Context, hostID uuid.UUID) time.Duration
+	HeldBookingSlots(ctx context.Context, hostID uuid.UUID, from, to time.Time) []caldto.TimeSlot
+	HandleExpiryTask(ctx context.Context, payload []byte) error
+	ExpirePendingRequests(ctx context.Context) (int, error)
+	IssueApprovalLinks(ctx context.Context, hostID, eventID uuid.UUID, ttl time.Duration) (*dto.ApprovalLinks, *errors.AppError)
+	CheckActionToken(ctx context.Context, eventID uuid.UUID, action entity.BookingAction, token string) (*entity.BookingActionToken, *errors.AppError)
+	ConsumeActionToken(ctx context.Context, eventID uuid.UUID, action entity.BookingAction, token string) (*entity.BookingActionToken, *errors.AppError)
+	RestoreActionToken(ctx context.Context, used *entity.BookingActionToken) *errors.AppError
+	RevokeApprovalLinks(ctx context.Context, eventID uuid.UUID) *errors.AppError
+	CheckBookingRateLimit(ctx context.Context, ip, email string) *errors.AppError
+	IssueBookingChallenge(ctx context.Context) (*dto.BookingChallengeResponse, *errors.AppError)
+	VerifyBookingChallenge(ctx context.Context, challenge, nonce string) *errors.AppError
+	StartGuestVerification(ctx context.Context, hostKey string, req *dto.PublicBookingRequest) (*dto.GuestVerificationResponse, *errors.AppError)
+	ConfirmGuestVerification(ctx context.Context, id, code string) (string, *dto.PublicBookingRequest, *errors.AppError)
+	IsGuestBlocked(ctx context.Context, hostID uuid.UUID, email string) bool
+	ListBlockedGuests(ctx context.Context, userID uuid.UUID) ([]dto.BlockedGuestResponse, *errors.AppError)
+	BlockGuest(ctx context.Context, userID uuid.UUID, req *dto.BlockGuestRequest) (*dto.BlockedGuestResponse, *errors.AppError)
+	UnblockGuest(ctx context.Context, userID, id uuid.UUID) *errors.AppError
+	ListIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string) ([]dto.IntakeQuestionResponse, *errors.AppError)
+	SaveIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveIntakeQuestionsRequest) ([]dto.IntakeQuestionResponse, *errors.AppError)
+	ValidateIntakeAnswers(ctx context.Context, hostID uuid.UUID, eventType string, answers map[string]interface{}) ([]dto.IntakeAnswer, *errors.AppError)
+	ExportIntakeAnswers(ctx context.Context, userID uuid.UUID, eventType string) ([]dto.IntakeAnswersRecord, *errors.AppError)
+	SlotAvailable(ctx context.Context, hostID uuid.UUID, start, end time.Time) (bool, *errors.AppError)
+	JoinWaitlist(ctx context.Context, hostID uuid.UUID, hostKey string, req *dto.JoinWaitlistRequest) (*dto.WaitlistEntryResponse, *errors.AppError)
+	ListWaitlist(ctx context.Context, userID uuid.UUID) ([]dto.WaitlistEntryResponse, *errors.AppError)
+	RemoveWaitlistEntry(ctx context.Context, userID, id uuid.UUID) *errors.AppError
+	OfferFreedSlot(ctx context.Context, hostID uuid.UUID, eventType string, start, end time.Time)
+	ExpireWaitlistOffers(ctx context.Context) (int, error)
+	CheckWaitlistOffer(ctx context.Context, offerID uuid.UUID, token string) (*entity.BookingWaitlistOffer, *errors.AppError)
+	ClaimWaitlistOffer(ctx context.Context, offerID uuid.UUID, token string) (string, *dto.PublicBookingRequest, *errors.AppError)
+	ListBookingPrices(ctx context.Context, userID uuid.UUID) ([]dto.BookingPriceResponse, *errors.AppError)
+	SaveBookingPrice(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveBookingPriceRequest) (*dto.BookingPriceResponse, *errors.AppError)
+	DeleteBookingPrice(ctx context.Context, userID uuid.UUID, eventType string) *errors.AppError
+	GetPublicBookingPrice(ctx context.Context, hostID uuid.UUID, eventType string) (*dto.PublicBookingPriceResponse, *errors.AppError)
+	PriceBookingRequest(ctx context.Context, hostID uuid.UUID, eventType string, paymentMethodID int) (*dto.BookingCharge, *errors.AppError)
+	CreateBookingOrder(ctx context.Context, hostID, eventID uuid.UUID, title, guestName, guestEmail string, charge *dto.BookingCharge) (*dto.BookingPaymentResponse, *errors.AppError)
+	GetBookingPayment(ctx context.Context, eventID uuid.UUID) (*entity.BookingPayment, *errors.AppError)
+	MarkBookingPaid(ctx context.Context, eventID uuid.UUID) (*dto.BookingPaymentResponse, *errors.AppError)
+	ReleaseBookingPayment(ctx context.Context, eventID uuid.UUID) *entity.BookingPayment
+	SettledBookingPayments(ctx context.Context) ([]entity.BookingPayment, error)
+	ListGuestAttendance(ctx context.Context, userID uuid.UUID, email string) ([]dto.GuestAttendanceResponse, *errors.AppError)
+	HandleAttendancePromptTask(ctx context.Context, payload []byte) error
 }
 
 type bookingService struct {
 	authService authservice.AuthServiceInterface
+	calRepo     calrepo.CalendarRepository
+	bookingRepo *repository.BookingRepository
+	calService  calsvc.CalendarService
+	notifSvc    *notifsvc.NotificationService
+	webhookSvc  *webhooksvc.WebhookService
+	cache       cache.Cache
 }
 
-func NewBookingService(authService authservice.AuthServiceInterface) BookingService {
+func NewBookingService(authService authservice.AuthServiceInterface, calRepo calrepo.CalendarRepository, bookingRepo *repository.BookingRepository, calService calsvc.CalendarService, notifSvc *notifsvc.NotificationService, webhookSvc *webhooksvc.WebhookService, cache cache.Cache) BookingService {
 	return &bookingService{
 		authService: authService,
+		calRepo:     calRepo,
+		bookingRepo: bookingRepo,
+		calService:  calService,
+		notifSvc:    notifSvc,
+		webhookSvc:  webhookSvc,
+		cache:       cache,
 	}
 }
 
@@ -48,21 +121,22 @@ func (s *bookingService) GetPersonalBookingURL(ctx context.Context, userID uuid.
 		return nil, errors.NewAppError(errors.ErrNotFound, "Chưa kết nối Google Calendar. Vui lòng kết nối Google Calendar trước.", nil)
 	}
 
-	// Get server host from config
-	cfg, isInitialized := config.GetSafe()
-	if !isInitialized {
-		logger.Error("BookingService:GetPersonalBookingURL:ConfigNotInitialized")
-		return nil, errors.NewAppError(errors.ErrInternalServer, "Server configuration error", nil)
+	// Prefer the claimed slug: {base_url}/p/{slug}, otherwise {base_url}/personal-booking/{social_login_id}
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("BookingService:GetPersonalBookingURL:GetProfileByUserID:Error", "error", err, "user_id", userID)
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking profile", err)
 	}
-
-	// Build URL: http://localhost:7070/personal-booking/{social_login_id}
-	// or use config server host if available
-	host := fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
-	if cfg.Server.Host != "" && cfg.Server.Host != "0.0.0.0" {
-		host = fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port)
+	if profile != nil && profile.Slug != nil {
+		bookingURL := BookingPageURL(*profile.Slug)
+		logger.Info("BookingService:GetPersonalBookingURL:Success", "user_id", userID, "slug", *profile.Slug, "url", bookingURL)
+		return &dto.PersonalBookingURLResponse{
+			URL:  bookingURL,
+			Slug: *profile.Slug,
+		}, nil
 	}
 
-	bookingURL := fmt.Sprintf("%s/personal-booking/%s", host, socialLogin.ID.String())
+	bookingURL := fmt.Sprintf("%s/personal-booking/%s", PublicBaseURL(), socialLogin.ID.String())
 
 	logger.Info("BookingService:GetPersonalBookingURL:Success", "user_id", userID, "social_login_id", socialLogin.ID, "url", bookingURL)
 
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/booking/entity"
+	caldto "go-api-starter/modules/calendar/dto"
+	notifdto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultApprovalWindowMinutes = 24 * 60
+	minApprovalWindowHours       = 1
+	maxApprovalWindowHours       = 14 * 24
+	bookingExpiryBatchSize       = 100
+	maxAlternativeSlots          = 5
+	alternativeSlotsDaysAhead    = 7
+)
+
+// ApprovalWindow returns how long the host's booking requests wait for an answer before they expire
+func (s *bookingService) ApprovalWindow(ctx context.Context, hostID uuid.UUID) time.Duration {
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, hostID)
+	if err != nil || profile == nil || profile.ApprovalWindowMinutes <= 0 {
+		return defaultApprovalWindowMinutes * time.Minute
+	}
+	return time.Duration(profile.ApprovalWindowMinutes) * time.Minute
+}
+
+// HeldBookingSlots returns the slots held by the host's unanswered booking requests within [from, to)
+func (s *bookingService) HeldBookingSlots(ctx context.Context, hostID uuid.UUID, from, to time.Time) []caldto.TimeSlot {
+	requests, err := s.bookingRepo.GetHeldBookingRequests(ctx, hostID, from, to, time.Now())
+	if err != nil {
+		return nil
+	}
+	slots := make([]caldto.TimeSlot, 0, len(requests))
+	for _, request := range requests {
+		if request.StartDate == nil || request.EndDate == nil {
+			continue
+		}
+		slots = append(slots, caldto.TimeSlot{
+			Start: request.StartDate.Format(time.RFC3339),
+			End:   request.EndDate.Format(time.RFC3339),
+		})
+	}
+	return slots
+}
+
+// SlotAvailable is the double-booking check of a booking request: the slot must be free on the host's calendar
+// and not held by another request waiting for the host
+func (s *bookingService) SlotAvailable(ctx context.Context, hostID uuid.UUID, start, end time.Time) (bool, *errors.AppError) {
+	var busy []caldto.TimeSlot
+	if s.calService != nil {
+		var err error
+		if busy, err = s.calService.GetFreeBusy(ctx, hostID, start, end); err != nil {
+			return false, errors.NewAppError(errors.ErrThirdParty, "Failed to check the host's calendar", err)
+		}
+	}
+	busy = append(busy, s.HeldBookingSlots(ctx, hostID, start, end)...)
+	for _, slot := range busy {
+		busyStart, err1 := time.Parse(time.RFC3339, slot.Start)
+		busyEnd, err2 := time.Parse(time.RFC3339, slot.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if start.Before(busyEnd) && end.After(busyStart) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// HandleExpiryTask is the worker handler of constants.TopicQueueBookingExpiry
+func (s *bookingService) HandleExpiryTask(ctx context.Context, _ []byte) error {
+	_, err := s.ExpirePendingRequests(ctx)
+	if _, waitlistErr := s.ExpireWaitlistOffers(ctx); waitlistErr != nil && err == nil {
+		err = waitlistErr
+	}
+	return err
+}
+
+// ExpirePendingRequests cancels the booking requests whose host did not answer within the approval window,
+// or whose order was not paid within the payment window. This releases the slot they held and settles their order,
+// and offers the guests other free times and the slot to the waitlist
+func (s *bookingService) ExpirePendingRequests(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		requests, err := s.bookingRepo.GetExpiredBookingRequests(ctx, time.Now(), bookingExpiryBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for i := range requests {
+			ok, err := s.bookingRepo.ExpireBookingRequest(ctx, requests[i].ID)
+			if err != nil {
+				return expired, err
+			}
+			if !ok {
+				continue // answered by the host in the meantime
+			}
+			expired++
+			if err := s.bookingRepo.RevokeActionTokens(ctx, requests[i].ID); err != nil {
+				logger.Warn("BookingService:ExpirePendingRequests:RevokeLinks:Error", "event_id", requests[i].ID, "error", err)
+			}
+			payment := s.ReleaseBookingPayment(ctx, requests[i].ID)
+			s.followUpExpiredRequest(ctx, &requests[i], payment)
+			if requests[i].StartDate != nil && requests[i].EndDate != nil {
+				s.OfferFreedSlot(ctx, requests[i].HostID, requests[i].EventType(), *requests[i].StartDate, *requests[i].EndDate)
+			}
+		}
+		if len(requests) < bookingExpiryBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		logger.Info("BookingService:ExpirePendingRequests:Done", "expired", expired)
+	}
+	return expired, nil
+}
+
+// followUpExpiredRequest tells the host and the guest that a request expired; payment is its released order, if any
+func (s *bookingService) followUpExpiredRequest(ctx context.Context, request *entity.PendingBookingRequest, payment *entity.BookingPayment) {
+	guestName, guestEmail := request.Guest()
+
+	data := map[string]interface{}{
+		"event_id":    request.ID.String(),
+		"host_id":     request.HostID.String(),
+		"title":       request.Title,
+		"status":      "cancelled",
+		"guest_email": guestEmail,
+		"expired_at":  request.ExpiresAt.Format(time.RFC3339),
+	}
+	if request.StartDate != nil && request.EndDate != nil {
+		data["start_time"] = request.StartDate.Format(time.RFC3339)
+		data["end_time"] = request.EndDate.Format(time.RFC3339)
+	}
+	if s.webhookSvc != nil {
+		s.webhookSvc.Emit(ctx, &request.HostID, constants.WebhookEventBookingExpired, data)
+	}
+
+	if s.notifSvc != nil {
+		_ = s.notifSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+			UserID:  request.HostID,
+			Title:   "Yêu cầu đặt lịch đã hết hạn",
+			Message: request.Title,
+			Type:    "booking_expired",
+			Data: map[string]interface{}{
+				"event_id":    request.ID.String(),
+				"guest_name":  guestName,
+				"guest_email": guestEmail,
+			},
+		})
+	}
+
+	if !utils.IsValidEmail(guestEmail) {
+		return
+	}
+	body := "<h3>Your booking request expired</h3><p>Title: " + html.EscapeString(request.Title) + "</p>" +
+		"<p>The host could not confirm this request in time, so it was cancelled.</p>"
+	if payment != nil && payment.Status == entity.BookingPaymentCancelled {
+		body = "<h3>Your booking request expired</h3><p>Title: " + html.EscapeString(request.Title) + "</p>" +
+			"<p>We did not receive the payment in time, so the order was cancelled and the time released.</p>"
+	}
+	if alternatives := s.alternativeSlots(ctx, request); len(alternatives) > 0 {
+		body += "<p>These times are still free:</p><ul>"
+		for _, slot := range alternatives {
+			body += "<li>" + html.EscapeString(slot) + "</li>"
+		}
+		body += "</ul>"
+	}
+	if bookingURL, appErr := s.GetPersonalBookingURL(ctx, request.HostID); appErr == nil {
+		body += `<p><a href="` + html.EscapeString(bookingURL.URL) + `">Pick another time</a></p>`
+	}
+	conf := utils.GetEmailConfig()
+	if err := utils.SendEmailTLS(*conf, utils.EmailMessage{
+		To:      []string{guestEmail},
+		Subject: "Your meeting request expired",
+		Body:    body,
+		IsHTML:  true,
+	}); err != nil {
+		logger.Warn("BookingService:followUpExpiredRequest:SendEmail:Error", "event_id", request.ID, "error", err)
+	}
+}
+
+// alternativeSlots lists free times of the host over the next days, formatted in the request's timezone
+func (s *bookingService) alternativeSlots(ctx context.Context, request *entity.PendingBookingRequest) []string {
+	if s.calService == nil {
+		return nil
+	}
+	duration := request.DurationMinutes
+	if duration <= 0 {
+		duration = 30
+	}
+	res, err := s.calService.FindAvailableSlots(ctx, &caldto.SuggestedSlotsRequest{
+		UserIDs:          []string{request.HostID.String()},
+		DurationMinutes:  duration,
+		DaysAhead:        alternativeSlotsDaysAhead,
+		WorkingHoursOnly: true,
+	})
+	if err != nil || res == nil {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation("Asia/Ho_Chi_Minh")
+	}
+	var result []string
+	for _, slot := range res.Slots {
+		if slot.SoftBusy {
+			continue
+		}
+		start, err1 := time.Parse(time.RFC3339, slot.StartTime)
+		end, err2 := time.Parse(time.RFC3339, slot.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s, %s - %s", start.In(loc).Format("02/01/2006"), start.In(loc).Format("15:04"), end.In(loc).Format("15:04")))
+		if len(result) == maxAlternativeSlots {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"html"
+	"math/bits"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	bookingRateWindow          = time.Hour
+	maxBookingRequestsPerIP    = 10
+	maxBookingRequestsPerEmail = 5
+	bookingChallengeDifficulty = 16 // leading zero bits, about 65k hashes in the browser
+	bookingChallengeTTL        = 10 * time.Minute
+	guestVerificationTTL       = 15 * time.Minute
+	maxGuestVerificationTries  = 5
+	maxBlockedGuests           = 500
+)
+
+// guestVerification is a booking request held in the cache until the guest confirms their email
+type guestVerification struct {
+	HostKey   string                   `json:"host_key"`
+	Request   dto.PublicBookingRequest `json:"request"`
+	Code      string                   `json:"code"`
+	Attempts  int                      `json:"attempts"`
+	ExpiresAt time.Time                `json:"expires_at"`
+}
+
+// CheckBookingRateLimit counts a booking request of the visitor's IP and of the guest email.
+// Cache errors let the request through rather than blocking every guest.
+func (s *bookingService) CheckBookingRateLimit(ctx context.Context, ip, email string) *errors.AppError {
+	if s.overRateLimit(ctx, constants.RedisKeyBookingRateIP+ip, maxBookingRequestsPerIP) {
+		return errors.NewAppError(errors.ErrLimitExceeded, "Too many booking requests, please try again later", nil)
+	}
+	if s.overRateLimit(ctx, constants.RedisKeyBookingRateEmail+strings.ToLower(strings.TrimSpace(email)), maxBookingRequestsPerEmail) {
+		return errors.NewAppError(errors.ErrLimitExceeded, "Too many booking requests for this email, please try again later", nil)
+	}
+	return nil
+}
+
+// overRateLimit counts a hit on key within bookingRateWindow, like the login attempt counter
+func (s *bookingService) overRateLimit(ctx context.Context, key string, limit int64) bool {
+	count, err := s.cache.Incr(ctx, key)
+	if err != nil {
+		logger.Warn("BookingService:overRateLimit:Incr:Error", "key", key, "error", err)
+		return false
+	}
+	if count == 1 {
+		if err := s.cache.Expire(ctx, key, bookingRateWindow); err != nil {
+			logger.Warn("BookingService:overRateLimit:Expire:Error", "key", key, "error", err)
+		}
+	}
+	return count > limit
+}
+
+// IssueBookingChallenge hands the booking form a single-use proof-of-work challenge
+func (s *bookingService) IssueBookingChallenge(ctx context.Context) (*dto.BookingChallengeResponse, *errors.AppError) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to create challenge", err)
+	}
+	challenge := hex.EncodeToString(buf)
+	if err := s.cache.Set(ctx, constants.RedisKeyBookingChallenge+challenge, 1, bookingChallengeTTL); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to create challenge", err)
+	}
+	return &dto.BookingChallengeResponse{
+		Challenge:  challenge,
+		Difficulty: bookingChallengeDifficulty,
+		ExpiresAt:  time.Now().Add(bookingChallengeTTL),
+	}, nil
+}
+
+// VerifyBookingChallenge checks the proof of work of a booking request and uses the challenge up
+func (s *bookingService) VerifyBookingChallenge(ctx context.Context, challenge, nonce string) *errors.AppError {
+	if challenge == "" || nonce == "" {
+		return errors.NewAppError(errors.ErrInvalidInput, "challenge and nonce are required", nil)
+	}
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	if leadingZeroBits(sum[:]) < bookingChallengeDifficulty {
+		return errors.NewAppError(errors.ErrInvalidInput, "invalid challenge solution", nil)
+	}
+	deleted, err := s.cache.GetClient().Del(ctx, constants.RedisKeyBookingChallenge+challenge).Result()
+	if err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to verify challenge", err)
+	}
+	if deleted == 0 {
+		return errors.NewAppError(errors.ErrInvalidInput, "challenge expired or already used", nil)
+	}
+	return nil
+}
+
+// StartGuestVerification holds a booking request until the guest confirms their email
+// with the emailed code or link; nothing reaches the host before that
+func (s *bookingService) StartGuestVerification(ctx context.Context, hostKey string, req *dto.PublicBookingRequest) (*dto.GuestVerificationResponse, *errors.AppError) {
+	email := strings.TrimSpace(req.Email)
+	if !utils.IsValidEmail(email) {
+		return nil, errors.NewAppError(errors.ErrInvalidEmail, "A valid email is required", nil)
+	}
+	code := utils.GenerateOTP()
+	if code == "" {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to create verification code", nil)
+	}
+
+	id := uuid.New().String()
+	verification := &guestVerification{
+		HostKey:   hostKey,
+		Request:   *req,
+		Code:      code,
+		ExpiresAt: time.Now().Add(guestVerificationTTL),
+	}
+	if appErr := s.saveGuestVerification(ctx, id, verification); appErr != nil {
+		return nil, appErr
+	}
+
+	link := PublicBaseURL() + "/p/" + url.PathEscape(hostKey) + "?verification=" + id + "&code=" + code
+	body := "<h3>Confirm your booking request</h3><p>Your code: <b>" + code + "</b></p>" +
+		"<p>Or <a href=\"" + html.EscapeString(link) + "\">confirm your email</a> to send the request to the host.</p>" +
+		"<p>The code expires in 15 minutes.</p>"
+	if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+		To:      []string{email},
+		Subject: "Confirm your booking request",
+		Body:    body,
+		IsHTML:  true,
+	}); err != nil {
+		_ = s.cache.Del(ctx, constants.RedisKeyBookingGuestVerification+id)
+		return nil, errors.NewAppError(errors.ErrThirdParty, "Failed to send verification email", err)
+	}
+
+	return &dto.GuestVerificationResponse{VerificationID: id, ExpiresAt: verification.ExpiresAt}, nil
+}
+
+// ConfirmGuestVerification checks the guest's code and releases the held booking request once
+func (s *bookingService) ConfirmGuestVerification(ctx context.Context, id, code string) (string, *dto.PublicBookingRequest, *errors.AppError) {
+	key := constants.RedisKeyBookingGuestVerification + id
+	raw, err := s.cache.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil, errors.NewAppError(errors.ErrNotFound, "Verification not found or expired", nil)
+	}
+	if err != nil {
+		return "", nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get verification", err)
+	}
+	var verification guestVerification
+	if err := json.Unmarshal([]byte(raw), &verification); err != nil {
+		return "", nil, errors.NewAppError(errors.ErrInternalServer, "Failed to read verification", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(code)), []byte(verification.Code)) != 1 {
+		verification.Attempts++
+		if verification.Attempts >= maxGuestVerificationTries {
+			_ = s.cache.Del(ctx, key)
+			return "", nil, errors.NewAppError(errors.ErrLimitExceeded, "Too many wrong codes, please book again", nil)
+		}
+		if appErr := s.saveGuestVerification(ctx, id, &verification); appErr != nil {
+			return "", nil, appErr
+		}
+		return "", nil, errors.NewAppError(errors.ErrInvalidInput, "Invalid verification code", nil)
+	}
+
+	// Only the caller that removes the entry submits the request
+	deleted, err := s.cache.GetClient().Del(ctx, key).Result()
+	if err != nil {
+		return "", nil, errors.NewAppError(errors.ErrInternalServer, "Failed to confirm verification", err)
+	}
+	if deleted == 0 {
+		return "", nil, errors.NewAppError(errors.ErrNotFound, "Verification not found or expired", nil)
+	}
+	return verification.HostKey, &verification.Request, nil
+}
+
+func (s *bookingService) saveGuestVerification(ctx context.Context, id string, verification *guestVerification) *errors.AppError {
+	ttl := time.Until(verification.ExpiresAt)
+	if ttl <= 0 {
+		return errors.NewAppError(errors.ErrNotFound, "Verification not found or expired", nil)
+	}
+	data, err := json.Marshal(verification)
+	if err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to save verification", err)
+	}
+	if err := s.cache.Set(ctx, constants.RedisKeyBookingGuestVerification+id, data, ttl); err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to save verification", err)
+	}
+	return nil
+}
+
+// IsGuestBlocked reports whether the host blocked the guest's email or domain, or blocks the guest for repeated
+// no-shows; lookup errors do not block
+func (s *bookingService) IsGuestBlocked(ctx context.Context, hostID uuid.UUID, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	domain := ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		domain = email[at+1:]
+	}
+	blocked, err := s.bookingRepo.IsGuestBlocked(ctx, hostID, email, domain)
+	if err != nil {
+		logger.Warn("BookingService:IsGuestBlocked:Error", "host_id", hostID, "error", err)
+		return false
+	}
+	return blocked || s.noShowRestriction(ctx, hostID, email) == entity.NoShowBlock
+}
+
+// ListBlockedGuests returns the host's block list
+func (s *bookingService) ListBlockedGuests(ctx context.Context, userID uuid.UUID) ([]dto.BlockedGuestResponse, *errors.AppError) {
+	blocked, err := s.bookingRepo.GetBlockedGuests(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get blocked guests", err)
+	}
+	result := make([]dto.BlockedGuestResponse, 0, len(blocked))
+	for i := range blocked {
+		result = append(result, toBlockedGuestResponse(&blocked[i]))
+	}
+	return result, nil
+}
+
+// BlockGuest adds an email or domain to the host's block list
+func (s *bookingService) BlockGuest(ctx context.Context, userID uuid.UUID, req *dto.BlockGuestRequest) (*dto.BlockedGuestResponse, *errors.AppError) {
+	blocked := &entity.BookingBlockedGuest{
+		UserID: userID,
+		Kind:   entity.BlockedGuestKind(strings.ToLower(strings.TrimSpace(req.Kind))),
+		Value:  strings.ToLower(strings.TrimSpace(req.Value)),
+	}
+	switch blocked.Kind {
+	case entity.BlockedGuestEmail:
+		if !utils.IsValidEmail(blocked.Value) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "value must be an email", nil)
+		}
+	case entity.BlockedGuestDomain:
+		blocked.Value = strings.TrimPrefix(blocked.Value, "@")
+		if !domainPattern.MatchString(blocked.Value) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "value must be a domain", nil)
+		}
+	default:
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "kind must be email or domain", nil)
+	}
+
+	existing, err := s.bookingRepo.GetBlockedGuests(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get blocked guests", err)
+	}
+	if len(existing) >= maxBlockedGuests {
+		return nil, errors.NewAppError(errors.ErrLimitExceeded, "The block list can hold at most 500 entries", nil)
+	}
+
+	if err := s.bookingRepo.BlockGuest(ctx, blocked); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to block guest", err)
+	}
+	response := toBlockedGuestResponse(blocked)
+	return &response, nil
+}
+
+// UnblockGuest removes an entry from the host's block list
+func (s *bookingService) UnblockGuest(ctx context.Context, userID, id uuid.UUID) *errors.AppError {
+	deleted, err := s.bookingRepo.UnblockGuest(ctx, userID, id)
+	if err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "Failed to unblock guest", err)
+	}
+	if !deleted {
+		return errors.NewAppError(errors.ErrNotFound, "Blocked guest not found", nil)
+	}
+	return nil
+}
+
+func toBlockedGuestResponse(blocked *entity.BookingBlockedGuest) dto.BlockedGuestResponse {
+	return dto.BlockedGuestResponse{
+		ID:        blocked.ID.String(),
+		Kind:      string(blocked.Kind),
+		Value:     blocked.Value,
+		CreatedAt: blocked.CreatedAt,
+	}
+}
+
+// leadingZeroBits counts the zero bits a hash starts with
+func leadingZeroBits(hash []byte) int {
+	count := 0
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
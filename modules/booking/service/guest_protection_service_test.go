@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestLeadingZeroBits(t *testing.T) {
+	tests := []struct {
+		name string
+		hash []byte
+		want int
+	}{
+		{name: "empty", hash: nil, want: 0},
+		{name: "first bit set", hash: []byte{0x80, 0x00}, want: 0},
+		{name: "one zero bit", hash: []byte{0x40}, want: 1},
+		{name: "seven zero bits", hash: []byte{0x01, 0xff}, want: 7},
+		{name: "whole zero byte", hash: []byte{0x00, 0xff}, want: 8},
+		{name: "zero byte then partial", hash: []byte{0x00, 0x0f}, want: 12},
+		{name: "two zero bytes then one", hash: []byte{0x00, 0x00, 0x01}, want: 23},
+		{name: "all zero", hash: []byte{0x00, 0x00, 0x00}, want: 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leadingZeroBits(tt.hash); got != tt.want {
+				t.Errorf("leadingZeroBits(%x) = %d, want %d", tt.hash, got, tt.want)
+			}
+		})
+	}
+}
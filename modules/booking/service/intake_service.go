@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxIntakeQuestions  = 20
+	maxQuestionLabel    = 200
+	maxQuestionOptions  = 30
+	maxQuestionOption   = 100
+	maxTextAnswer       = 500
+	maxLongTextAnswer   = 5000
+	maxIntakeExportRows = 5000
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ().-]{5,19}$`)
+
+// ListIntakeQuestions returns the host's booking form questions of an event type
+func (s *bookingService) ListIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string) ([]dto.IntakeQuestionResponse, *errors.AppError) {
+	questions, err := s.bookingRepo.GetIntakeQuestions(ctx, userID, NormalizeEventType(eventType))
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get intake questions", err)
+	}
+	return toIntakeQuestionResponses(questions), nil
+}
+
+// SaveIntakeQuestions replaces the host's booking form questions of an event type
+func (s *bookingService) SaveIntakeQuestions(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveIntakeQuestionsRequest) ([]dto.IntakeQuestionResponse, *errors.AppError) {
+	eventType = NormalizeEventType(eventType)
+	if len(eventType) > 64 || !slugPattern.MatchString(eventType) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event_type may only contain lowercase letters, digits and hyphens (max 64)", nil)
+	}
+	if len(req.Questions) > maxIntakeQuestions {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("A form can have at most %d questions", maxIntakeQuestions), nil)
+	}
+
+	existing, err := s.bookingRepo.GetIntakeQuestions(ctx, userID, eventType)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get intake questions", err)
+	}
+	known := make(map[uuid.UUID]bool, len(existing))
+	for _, q := range existing {
+		known[q.ID] = true
+	}
+
+	questions := make([]entity.BookingIntakeQuestion, 0, len(req.Questions))
+	seen := map[uuid.UUID]bool{}
+	for i, q := range req.Questions {
+		question := entity.BookingIntakeQuestion{
+			Label:    strings.TrimSpace(q.Label),
+			Type:     entity.IntakeQuestionType(strings.ToLower(strings.TrimSpace(q.Type))),
+			Options:  []string{},
+			Required: q.Required,
+		}
+		if q.ID != "" {
+			id, err := uuid.Parse(q.ID)
+			if err != nil || !known[id] || seen[id] {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d].id is not a question of this form", i), nil)
+			}
+			seen[id] = true
+			question.ID = id
+		}
+		if question.Label == "" || len([]rune(question.Label)) > maxQuestionLabel {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d].label must be 1-%d characters", i, maxQuestionLabel), nil)
+		}
+
+		switch question.Type {
+		case entity.IntakeQuestionText, entity.IntakeQuestionLongText, entity.IntakeQuestionPhone:
+		case entity.IntakeQuestionSingleChoice, entity.IntakeQuestionMultiChoice:
+			if len(q.Options) == 0 || len(q.Options) > maxQuestionOptions {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d] needs 1-%d options", i, maxQuestionOptions), nil)
+			}
+			options := map[string]bool{}
+			for _, raw := range q.Options {
+				option := strings.TrimSpace(raw)
+				if option == "" || len([]rune(option)) > maxQuestionOption {
+					return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d] options must be 1-%d characters", i, maxQuestionOption), nil)
+				}
+				if options[option] {
+					return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d] has duplicate option %q", i, option), nil)
+				}
+				options[option] = true
+				question.Options = append(question.Options, option)
+			}
+		default:
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("questions[%d].type must be text, long_text, phone, single_choice or multi_choice", i), nil)
+		}
+		questions = append(questions, question)
+	}
+
+	saved, err := s.bookingRepo.ReplaceIntakeQuestions(ctx, userID, eventType, questions)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save intake questions", err)
+	}
+	return toIntakeQuestionResponses(saved), nil
+}
+
+// ValidateIntakeAnswers checks a guest's answers against the host's form of the event type
+// and returns them in form order, with the question labels of booking time
+func (s *bookingService) ValidateIntakeAnswers(ctx context.Context, hostID uuid.UUID, eventType string, answers map[string]interface{}) ([]dto.IntakeAnswer, *errors.AppError) {
+	questions, err := s.bookingRepo.GetIntakeQuestions(ctx, hostID, NormalizeEventType(eventType))
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get intake questions", err)
+	}
+
+	result := make([]dto.IntakeAnswer, 0, len(questions))
+	for _, q := range questions {
+		values, ok := answerValues(answers[q.ID.String()])
+		if !ok {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("Invalid answer to %q", q.Label), nil)
+		}
+		if len(values) == 0 {
+			if q.Required {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("%q is required", q.Label), nil)
+			}
+			continue
+		}
+
+		switch q.Type {
+		case entity.IntakeQuestionText, entity.IntakeQuestionLongText:
+			limit := maxTextAnswer
+			if q.Type == entity.IntakeQuestionLongText {
+				limit = maxLongTextAnswer
+			}
+			if len(values) > 1 || len([]rune(values[0])) > limit {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("The answer to %q must be at most %d characters", q.Label, limit), nil)
+			}
+		case entity.IntakeQuestionPhone:
+			if len(values) > 1 || !phonePattern.MatchString(values[0]) {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("%q must be a phone number", q.Label), nil)
+			}
+		case entity.IntakeQuestionSingleChoice, entity.IntakeQuestionMultiChoice:
+			if q.Type == entity.IntakeQuestionSingleChoice && len(values) > 1 {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("Pick one option of %q", q.Label), nil)
+			}
+			for _, v := range values {
+				if !containsString(q.Options, v) {
+					return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("%q is not an option of %q", v, q.Label), nil)
+				}
+			}
+		}
+		result = append(result, dto.IntakeAnswer{
+			QuestionID: q.ID.String(),
+			Label:      q.Label,
+			Type:       string(q.Type),
+			Values:     values,
+		})
+	}
+	return result, nil
+}
+
+// ExportIntakeAnswers returns the host's booking requests with intake answers, newest first.
+// An empty eventType exports every event type.
+func (s *bookingService) ExportIntakeAnswers(ctx context.Context, userID uuid.UUID, eventType string) ([]dto.IntakeAnswersRecord, *errors.AppError) {
+	if eventType != "" {
+		eventType = NormalizeEventType(eventType)
+	}
+	requests, err := s.bookingRepo.GetBookingRequestsWithAnswers(ctx, userID, eventType, maxIntakeExportRows)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking answers", err)
+	}
+
+	result := make([]dto.IntakeAnswersRecord, 0, len(requests))
+	for _, r := range requests {
+		var prefs struct {
+			GuestName  string             `json:"guest_name"`
+			GuestEmail string             `json:"guest_email"`
+			EventType  string             `json:"event_type"`
+			Answers    []dto.IntakeAnswer `json:"answers"`
+		}
+		if r.Preferences != nil {
+			_ = json.Unmarshal([]byte(*r.Preferences), &prefs)
+		}
+		result = append(result, dto.IntakeAnswersRecord{
+			EventID:    r.ID.String(),
+			Title:      r.Title,
+			Status:     r.Status,
+			StartTime:  r.StartDate,
+			CreatedAt:  r.CreatedAt,
+			GuestName:  prefs.GuestName,
+			GuestEmail: prefs.GuestEmail,
+			EventType:  NormalizeEventType(prefs.EventType),
+			Answers:    prefs.Answers,
+		})
+	}
+	return result, nil
+}
+
+// answerValues reads an answer that is a string or a list of strings; blank values are dropped
+func answerValues(raw interface{}) ([]string, bool) {
+	var values []string
+	switch v := raw.(type) {
+	case nil:
+	case string:
+		values = append(values, v)
+	case []interface{}:
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, str)
+		}
+	default:
+		return nil, false
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !containsString(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result, true
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toIntakeQuestionResponses(questions []entity.BookingIntakeQuestion) []dto.IntakeQuestionResponse {
+	result := make([]dto.IntakeQuestionResponse, 0, len(questions))
+	for _, q := range questions {
+		options := []string(q.Options)
+		if options == nil {
+			options = []string{}
+		}
+		result = append(result, dto.IntakeQuestionResponse{
+			ID:       q.ID.String(),
+			Label:    q.Label,
+			Type:     string(q.Type),
+			Options:  options,
+			Required: q.Required,
+		})
+	}
+	return result
+}
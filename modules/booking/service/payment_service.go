@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+	notifdto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPaymentWindowMinutes = 30
+	minPaymentWindowMinutes     = 5
+	maxPaymentWindowMinutes     = 24 * 60
+	maxBookingPrice             = 9999999999.99 // NUMERIC(12, 2)
+	settledPaymentBatchSize     = 100
+)
+
+// ListBookingPrices returns the host's event type prices
+func (s *bookingService) ListBookingPrices(ctx context.Context, userID uuid.UUID) ([]dto.BookingPriceResponse, *errors.AppError) {
+	prices, err := s.bookingRepo.GetBookingPrices(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking prices", err)
+	}
+
+	result := make([]dto.BookingPriceResponse, 0, len(prices))
+	for i := range prices {
+		result = append(result, toBookingPriceResponse(&prices[i]))
+	}
+	return result, nil
+}
+
+// SaveBookingPrice creates or replaces what guests pay for booking an event type
+func (s *bookingService) SaveBookingPrice(ctx context.Context, userID uuid.UUID, eventType string, req *dto.SaveBookingPriceRequest) (*dto.BookingPriceResponse, *errors.AppError) {
+	eventType = NormalizeEventType(eventType)
+	if len(eventType) > 64 || !slugPattern.MatchString(eventType) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event_type may only contain lowercase letters, digits and hyphens (max 64)", nil)
+	}
+	if req.Amount < 0 || req.Amount > maxBookingPrice {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "amount must be between 0 and 9999999999.99", nil)
+	}
+	window := req.PaymentWindowMinutes
+	if window == 0 {
+		window = defaultPaymentWindowMinutes
+	}
+	if window < minPaymentWindowMinutes || window > maxPaymentWindowMinutes {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("payment_window_minutes must be between %d and %d", minPaymentWindowMinutes, maxPaymentWindowMinutes), nil)
+	}
+
+	price := &entity.BookingPrice{
+		UserID:               userID,
+		EventType:            eventType,
+		Amount:               req.Amount,
+		PaymentWindowMinutes: window,
+	}
+	if err := s.bookingRepo.SaveBookingPrice(ctx, price); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save booking price", err)
+	}
+	response := toBookingPriceResponse(price)
+	return &response, nil
+}
+
+// DeleteBookingPrice removes the host's price for an event type; its bookings fall back to the default price
+func (s *bookingService) DeleteBookingPrice(ctx context.Context, userID uuid.UUID, eventType string) *errors.AppError {
+	eventType = NormalizeEventType(eventType)
+	price, err := s.bookingRepo.GetBookingPrice(ctx, userID, eventType)
+	if err != nil {
+		return errors.NewAppError(errors.ErrGetFailed, "Failed to get booking price", err)
+	}
+	if price == nil {
+		return errors.NewAppError(errors.ErrNotFound, "Booking price not found", nil)
+	}
+	if err := s.bookingRepo.DeleteBookingPrice(ctx, userID, eventType); err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "Failed to delete booking price", err)
+	}
+	return nil
+}
+
+// bookingPrice returns the host's price of an event type, then their default price; nil when the booking is free
+func (s *bookingService) bookingPrice(ctx context.Context, hostID uuid.UUID, eventType string) (*entity.BookingPrice, error) {
+	eventType = NormalizeEventType(eventType)
+	price, err := s.bookingRepo.GetBookingPrice(ctx, hostID, eventType)
+	if err == nil && price == nil && eventType != entity.DefaultEventType {
+		price, err = s.bookingRepo.GetBookingPrice(ctx, hostID, entity.DefaultEventType)
+	}
+	if err != nil || price == nil || price.Amount <= 0 {
+		return nil, err
+	}
+	return price, nil
+}
+
+// GetPublicBookingPrice returns what a guest pays for booking an event type and the payment methods they can use
+func (s *bookingService) GetPublicBookingPrice(ctx context.Context, hostID uuid.UUID, eventType string) (*dto.PublicBookingPriceResponse, *errors.AppError) {
+	response := &dto.PublicBookingPriceResponse{
+		EventType:      NormalizeEventType(eventType),
+		PaymentMethods: []dto.PaymentMethodOption{},
+	}
+	price, err := s.bookingPrice(ctx, hostID, eventType)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking price", err)
+	}
+	if price == nil {
+		return response, nil
+	}
+
+	methods, err := s.bookingRepo.GetActivePaymentMethods(ctx)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get payment methods", err)
+	}
+	response.Amount = price.Amount
+	response.PaymentWindowMinutes = price.PaymentWindowMinutes
+	for _, method := range methods {
+		response.PaymentMethods = append(response.PaymentMethods, dto.PaymentMethodOption{
+			ID:       method.ID,
+			Name:     method.Name,
+			Provider: method.Provider,
+			Type:     method.Type,
+		})
+	}
+	return response, nil
+}
+
+// PriceBookingRequest returns the charge of a booking request for an event type of the host, or nil when it is free.
+// A priced event type needs one of the shop's active payment methods.
+func (s *bookingService) PriceBookingRequest(ctx context.Context, hostID uuid.UUID, eventType string, paymentMethodID int) (*dto.BookingCharge, *errors.AppError) {
+	price, err := s.bookingPrice(ctx, hostID, eventType)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking price", err)
+	}
+	if price == nil {
+		return nil, nil
+	}
+	if paymentMethodID <= 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "payment_method_id is required for this event type", nil)
+	}
+	method, err := s.bookingRepo.GetActivePaymentMethod(ctx, paymentMethodID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get payment method", err)
+	}
+	if method == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Payment method not found", nil)
+	}
+	return &dto.BookingCharge{
+		EventType:         price.EventType,
+		Amount:            price.Amount,
+		PaymentWindow:     time.Duration(price.PaymentWindowMinutes) * time.Minute,
+		PaymentMethodID:   method.ID,
+		PaymentMethodName: method.Name,
+	}, nil
+}
+
+// CreateBookingOrder places the order of a paid booking request. The request holds its slot until the payment
+// window ends; the guest is emailed the order to pay.
+func (s *bookingService) CreateBookingOrder(ctx context.Context, hostID, eventID uuid.UUID, title, guestName, guestEmail string, charge *dto.BookingCharge) (*dto.BookingPaymentResponse, *errors.AppError) {
+	payment := &entity.BookingPayment{
+		EventID:           eventID,
+		HostID:            hostID,
+		OrderNumber:       utils.GenerateOrderNumber(),
+		Amount:            charge.Amount,
+		PaymentMethodName: charge.PaymentMethodName,
+		HoldExpiresAt:     time.Now().Add(charge.PaymentWindow),
+	}
+	notes := "Booking " + eventID.String() + ": " + title
+	if err := s.bookingRepo.CreateBookingOrder(ctx, payment, charge.PaymentMethodID, guestName, guestEmail, notes); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to create the booking order", err)
+	}
+
+	if s.webhookSvc != nil {
+		s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventOrderPlaced, map[string]interface{}{
+			"order_id":       payment.OrderID.String(),
+			"order_number":   payment.OrderNumber,
+			"event_id":       eventID.String(),
+			"customer_email": guestEmail,
+			"total_amount":   payment.Amount,
+			"order_state":    constants.OrderStatePending,
+			"payment_status": constants.PaymentStatusPending,
+		})
+	}
+
+	if utils.IsValidEmail(guestEmail) {
+		payBy := payment.HoldExpiresAt.In(bookingEmailLocation()).Format("02/01/2006 15:04")
+		body := "<h3>Your booking is reserved</h3><p>Title: " + html.EscapeString(title) + "</p>" +
+			"<p>Order: <b>" + html.EscapeString(payment.OrderNumber) + "</b><br>Amount: " + html.EscapeString(formatBookingAmount(payment.Amount)) +
+			"<br>Payment method: " + html.EscapeString(payment.PaymentMethodName) + "</p>" +
+			"<p>We hold this time for you until " + html.EscapeString(payBy) + ". Your booking is confirmed as soon as the order is paid; " +
+			"unpaid orders are cancelled and the time is released.</p>"
+		if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+			To:      []string{guestEmail},
+			Subject: "Complete your payment to confirm your booking",
+			Body:    body,
+			IsHTML:  true,
+		}); err != nil {
+			logger.Warn("BookingService:CreateBookingOrder:SendEmail:Error", "event_id", eventID, "error", err)
+		}
+	}
+	response := toBookingPaymentResponse(payment)
+	return &response, nil
+}
+
+// GetBookingPayment returns the payment of a booking request, or nil when the booking is free
+func (s *bookingService) GetBookingPayment(ctx context.Context, eventID uuid.UUID) (*entity.BookingPayment, *errors.AppError) {
+	payment, err := s.bookingRepo.GetBookingPayment(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get the booking payment", err)
+	}
+	return payment, nil
+}
+
+// MarkBookingPaid records the payment of a booking request's order; the booking can be confirmed afterwards
+func (s *bookingService) MarkBookingPaid(ctx context.Context, eventID uuid.UUID) (*dto.BookingPaymentResponse, *errors.AppError) {
+	payment, err := s.bookingRepo.MarkBookingPaymentPaid(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to record the payment", err)
+	}
+	if payment == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "booking has no pending payment", nil)
+	}
+
+	if s.webhookSvc != nil {
+		s.webhookSvc.Emit(ctx, &payment.HostID, constants.WebhookEventBookingPaid, bookingPaymentWebhookData(payment))
+	}
+	response := toBookingPaymentResponse(payment)
+	return &response, nil
+}
+
+// ReleaseBookingPayment settles the order of a booking that was declined, cancelled or expired: an unpaid order
+// is cancelled, a paid one is refunded and the guest told so. It returns nil for free bookings.
+func (s *bookingService) ReleaseBookingPayment(ctx context.Context, eventID uuid.UUID) *entity.BookingPayment {
+	payment, err := s.bookingRepo.ReleaseBookingPayment(ctx, eventID)
+	if err != nil || payment == nil {
+		return nil
+	}
+	if payment.Status != entity.BookingPaymentRefunded {
+		return payment
+	}
+
+	// Money goes back through the payment method; the host and their bookkeeping are told what to refund
+	if s.webhookSvc != nil {
+		s.webhookSvc.Emit(ctx, &payment.HostID, constants.WebhookEventBookingRefunded, bookingPaymentWebhookData(payment))
+	}
+	if s.notifSvc != nil {
+		_ = s.notifSvc.Create(ctx, &notifdto.CreateNotificationRequest{
+			UserID:  payment.HostID,
+			Title:   "Cần hoàn tiền đặt lịch",
+			Message: "Đơn " + payment.OrderNumber + ": " + formatBookingAmount(payment.Amount),
+			Type:    "booking_refund",
+			Data:    bookingPaymentWebhookData(payment),
+		})
+	}
+	if guestEmail, ok := s.bookingGuestEmail(ctx, eventID); ok {
+		body := "<h3>Your payment will be refunded</h3><p>Your booking was cancelled, so order <b>" + html.EscapeString(payment.OrderNumber) +
+			"</b> was cancelled too.</p><p>" + html.EscapeString(formatBookingAmount(payment.Amount)) + " will be refunded through " +
+			html.EscapeString(payment.PaymentMethodName) + ".</p>"
+		if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+			To:      []string{guestEmail},
+			Subject: "Refund of your booking " + payment.OrderNumber,
+			Body:    body,
+			IsHTML:  true,
+		}); err != nil {
+			logger.Warn("BookingService:ReleaseBookingPayment:SendEmail:Error", "event_id", eventID, "error", err)
+		}
+	}
+	return payment
+}
+
+// SettledBookingPayments returns booking payments whose order was paid in the order system but whose booking
+// was not confirmed yet
+func (s *bookingService) SettledBookingPayments(ctx context.Context) ([]entity.BookingPayment, error) {
+	return s.bookingRepo.GetSettledBookingPayments(ctx, settledPaymentBatchSize)
+}
+
+// bookingGuestEmail returns the guest email stored on a booking request
+func (s *bookingService) bookingGuestEmail(ctx context.Context, eventID uuid.UUID) (string, bool) {
+	email, err := s.bookingRepo.GetBookingGuestEmail(ctx, eventID)
+	if err != nil || !utils.IsValidEmail(email) {
+		return "", false
+	}
+	return email, true
+}
+
+// bookingEmailLocation is the timezone times in booking emails are written in
+func bookingEmailLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// formatBookingAmount writes an amount in the shop's currency
+func formatBookingAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64) + " VND"
+}
+
+func bookingPaymentWebhookData(payment *entity.BookingPayment) map[string]interface{} {
+	return map[string]interface{}{
+		"event_id":       payment.EventID.String(),
+		"host_id":        payment.HostID.String(),
+		"order_id":       payment.OrderID.String(),
+		"order_number":   payment.OrderNumber,
+		"amount":         payment.Amount,
+		"payment_method": payment.PaymentMethodName,
+		"payment_status": string(payment.Status),
+	}
+}
+
+func toBookingPriceResponse(price *entity.BookingPrice) dto.BookingPriceResponse {
+	return dto.BookingPriceResponse{
+		EventType:            price.EventType,
+		Amount:               price.Amount,
+		PaymentWindowMinutes: price.PaymentWindowMinutes,
+	}
+}
+
+func toBookingPaymentResponse(payment *entity.BookingPayment) dto.BookingPaymentResponse {
+	return dto.BookingPaymentResponse{
+		EventID:       payment.EventID.String(),
+		OrderID:       payment.OrderID.String(),
+		OrderNumber:   payment.OrderNumber,
+		Amount:        payment.Amount,
+		PaymentMethod: payment.PaymentMethodName,
+		Status:        string(payment.Status),
+		PayBy:         payment.HoldExpiresAt,
+		PaidAt:        payment.PaidAt,
+	}
+}
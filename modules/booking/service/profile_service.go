@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go-api-starter/core/config"
+	"go-api-starter/core/errors"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minSlugLength      = 3
+	maxSlugLength      = 40
+	maxProfileTitle    = 120
+	maxWelcomeText     = 1000
+	defaultPageLocale  = "vi"
+	maxNoShowThreshold = 20
+)
+
+var (
+	slugPattern       = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	brandColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	// reservedSlugs would clash with routes or mislead guests
+	reservedSlugs = map[string]bool{
+		"admin": true, "api": true, "app": true, "assets": true, "booking": true, "help": true,
+		"login": true, "logout": true, "me": true, "new": true, "p": true, "personal-booking": true,
+		"settings": true, "signup": true, "static": true, "support": true, "verifications": true, "waitlist": true, "www": true,
+	}
+
+	supportedPageLocales = map[string]bool{"vi": true, "en": true}
+)
+
+// ResolvedBookingSlug is the host behind a booking page slug
+type ResolvedBookingSlug struct {
+	UserID uuid.UUID
+	// Slug is the host's current slug; it differs from the requested one when an old slug was used
+	Slug string
+	// Moved is true when the requested slug is one the host gave up and the page should redirect
+	Moved bool
+}
+
+// PublicBaseURL returns the externally reachable base URL of the server without a trailing slash
+func PublicBaseURL() string {
+	cfg := config.Get()
+	base := strings.TrimRight(cfg.Server.BaseURL, "/")
+	if base != "" {
+		return base
+	}
+	host := cfg.Server.Host
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s:%d", host, cfg.Server.Port)
+}
+
+// BookingPageURL returns the public URL of a booking page slug
+func BookingPageURL(slug string) string {
+	return PublicBaseURL() + "/p/" + url.PathEscape(slug)
+}
+
+// GetBookingProfile returns the host's booking page profile, with defaults when none was saved yet
+func (s *bookingService) GetBookingProfile(ctx context.Context, userID uuid.UUID) (*dto.BookingProfileResponse, *errors.AppError) {
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking profile", err)
+	}
+	if profile == nil {
+		profile = defaultBookingProfile(userID)
+	}
+
+	history, err := s.bookingRepo.GetSlugHistoryByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking profile", err)
+	}
+	return toBookingProfileResponse(profile, history), nil
+}
+
+// UpdateBookingProfile saves the page title, welcome text, avatar, brand colour, locale, approval window, guest checks
+// and attendance settings
+func (s *bookingService) UpdateBookingProfile(ctx context.Context, userID uuid.UUID, req *dto.UpdateBookingProfileRequest) (*dto.BookingProfileResponse, *errors.AppError) {
+	profile, err := s.bookingRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get booking profile", err)
+	}
+	if profile == nil {
+		profile = defaultBookingProfile(userID)
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if len([]rune(title)) > maxProfileTitle {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("title must be at most %d characters", maxProfileTitle), nil)
+		}
+		profile.Title = optionalString(title)
+	}
+	if req.WelcomeText != nil {
+		text := strings.TrimSpace(*req.WelcomeText)
+		if len([]rune(text)) > maxWelcomeText {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("welcome_text must be at most %d characters", maxWelcomeText), nil)
+		}
+		profile.WelcomeText = optionalString(text)
+	}
+	if req.AvatarURL != nil {
+		avatar := strings.TrimSpace(*req.AvatarURL)
+		if avatar != "" {
+			u, err := url.Parse(avatar)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, "avatar_url must be an http(s) URL", nil)
+			}
+		}
+		profile.AvatarURL = optionalString(avatar)
+	}
+	if req.BrandColor != nil {
+		color := strings.TrimSpace(*req.BrandColor)
+		if color != "" && !brandColorPattern.MatchString(color) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "brand_color must look like #RRGGBB", nil)
+		}
+		profile.BrandColor = optionalString(strings.ToLower(color))
+	}
+	if req.Locale != nil {
+		locale := strings.ToLower(strings.TrimSpace(*req.Locale))
+		if !supportedPageLocales[locale] {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "locale must be vi or en", nil)
+		}
+		profile.Locale = locale
+	}
+	if req.ApprovalWindowHours != nil {
+		hours := *req.ApprovalWindowHours
+		if hours < minApprovalWindowHours || hours > maxApprovalWindowHours {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("approval_window_hours must be between %d and %d", minApprovalWindowHours, maxApprovalWindowHours), nil)
+		}
+		profile.ApprovalWindowMinutes = hours * 60
+	}
+	if req.RequireEmailVerification != nil {
+		profile.RequireEmailVerification = *req.RequireEmailVerification
+	}
+	if req.RequireChallenge != nil {
+		profile.RequireChallenge = *req.RequireChallenge
+	}
+	if req.AttendancePrompts != nil {
+		profile.AttendancePrompts = *req.AttendancePrompts
+	}
+	if req.NoShowThreshold != nil {
+		if *req.NoShowThreshold < 0 || *req.NoShowThreshold > maxNoShowThreshold {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("no_show_threshold must be between 0 and %d", maxNoShowThreshold), nil)
+		}
+		profile.NoShowThreshold = *req.NoShowThreshold
+	}
+	if req.NoShowAction != nil {
+		action := entity.NoShowAction(strings.ToLower(strings.TrimSpace(*req.NoShowAction)))
+		if action != entity.NoShowRequireApproval && action != entity.NoShowBlock {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "no_show_action must be require_approval or block", nil)
+		}
+		profile.NoShowAction = action
+	}
+
+	if err := s.bookingRepo.SaveProfile(ctx, profile); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save booking profile", err)
+	}
+	return s.GetBookingProfile(ctx, userID)
+}
+
+// ChangeBookingSlug claims a new slug for the host; the previous one keeps redirecting to it
+func (s *bookingService) ChangeBookingSlug(ctx context.Context, userID uuid.UUID, req *dto.ChangeBookingSlugRequest) (*dto.BookingProfileResponse, *errors.AppError) {
+	slug := normalizeSlug(req.Slug)
+	if reason := validateSlug(slug); reason != "" {
+		return nil, errors.NewAppError(errors.ErrInvalidSlug, reason, nil)
+	}
+	reason, appErr := s.slugTakenReason(ctx, userID, slug)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if reason != "" {
+		return nil, errors.NewAppError(errors.ErrDuplicateSlug, reason, nil)
+	}
+
+	if _, err := s.bookingRepo.ChangeSlug(ctx, userID, slug); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to change booking page slug", err)
+	}
+	return s.GetBookingProfile(ctx, userID)
+}
+
+// CheckSlugAvailability tells whether the host could claim slug
+func (s *bookingService) CheckSlugAvailability(ctx context.Context, userID uuid.UUID, slug string) (*dto.SlugAvailabilityResponse, *errors.AppError) {
+	slug = normalizeSlug(slug)
+	response := &dto.SlugAvailabilityResponse{Slug: slug}
+	if reason := validateSlug(slug); reason != "" {
+		response.Reason = reason
+		return response, nil
+	}
+	reason, appErr := s.slugTakenReason(ctx, userID, slug)
+	if appErr != nil {
+		return nil, appErr
+	}
+	response.Reason = reason
+	response.Available = reason == ""
+	return response, nil
+}
+
+// ResolveBookingSlug finds the host owning slug now or in the past; nil when no profile knows it
+func (s *bookingService) ResolveBookingSlug(ctx context.Context, slug string) (*ResolvedBookingSlug, *errors.AppError) {
+	profile, err := s.bookingRepo.GetProfileBySlug(ctx, slug)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to resolve booking page", err)
+	}
+	if profile != nil && profile.Slug != nil {
+		return &ResolvedBookingSlug{UserID: profile.UserID, Slug: *profile.Slug}, nil
+	}
+
+	history, err := s.bookingRepo.GetSlugHistory(ctx, slug)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to resolve booking page", err)
+	}
+	if history == nil {
+		return nil, nil
+	}
+	current, err := s.bookingRepo.GetProfileByUserID(ctx, history.UserID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to resolve booking page", err)
+	}
+	if current == nil || current.Slug == nil {
+		return nil, nil
+	}
+	return &ResolvedBookingSlug{UserID: history.UserID, Slug: *current.Slug, Moved: true}, nil
+}
+
+// slugTakenReason explains why slug belongs to someone else, or returns "" when the host may use it
+func (s *bookingService) slugTakenReason(ctx context.Context, userID uuid.UUID, slug string) (string, *errors.AppError) {
+	profile, err := s.bookingRepo.GetProfileBySlug(ctx, slug)
+	if err != nil {
+		return "", errors.NewAppError(errors.ErrGetFailed, "Failed to check slug", err)
+	}
+	if profile != nil && profile.UserID != userID {
+		return "This slug is already taken", nil
+	}
+
+	history, err := s.bookingRepo.GetSlugHistory(ctx, slug)
+	if err != nil {
+		return "", errors.NewAppError(errors.ErrGetFailed, "Failed to check slug", err)
+	}
+	if history != nil && history.UserID != userID {
+		return "This slug was used by another booking page and still redirects there", nil
+	}
+
+	// Pages are also reachable by the host's Google username, so those stay with their owner
+	if sl, appErr := s.authService.GetSocialLoginBySlug(ctx, slug); appErr == nil && sl != nil && sl.UserID != userID {
+		return "This slug is already taken", nil
+	}
+	return "", nil
+}
+
+// defaultBookingProfile is the profile of a host who has not saved one yet
+func defaultBookingProfile(userID uuid.UUID) *entity.BookingProfile {
+	return &entity.BookingProfile{
+		UserID:                userID,
+		Locale:                defaultPageLocale,
+		ApprovalWindowMinutes: defaultApprovalWindowMinutes,
+		NoShowAction:          entity.NoShowRequireApproval,
+	}
+}
+
+func normalizeSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+// validateSlug returns why slug is not a valid booking page slug, or "" when it is
+func validateSlug(slug string) string {
+	if len(slug) < minSlugLength || len(slug) > maxSlugLength {
+		return fmt.Sprintf("Slug must be between %d and %d characters", minSlugLength, maxSlugLength)
+	}
+	if !slugPattern.MatchString(slug) {
+		return "Slug may only contain lowercase letters, digits and single hyphens between them"
+	}
+	if _, err := uuid.Parse(slug); err == nil {
+		return "Slug cannot be a UUID"
+	}
+	if reservedSlugs[slug] {
+		return "This slug is reserved"
+	}
+	return ""
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func toBookingProfileResponse(profile *entity.BookingProfile, history []entity.BookingSlugHistory) *dto.BookingProfileResponse {
+	response := &dto.BookingProfileResponse{
+		Locale:                   profile.Locale,
+		ApprovalWindowHours:      profile.ApprovalWindowMinutes / 60,
+		RequireEmailVerification: profile.RequireEmailVerification,
+		RequireChallenge:         profile.RequireChallenge,
+		AttendancePrompts:        profile.AttendancePrompts,
+		NoShowThreshold:          profile.NoShowThreshold,
+		NoShowAction:             string(profile.NoShowAction),
+		PreviousSlugs:            make([]string, 0, len(history)),
+	}
+	if profile.Slug != nil {
+		response.Slug = *profile.Slug
+		response.URL = BookingPageURL(*profile.Slug)
+	}
+	if profile.Title != nil {
+		response.Title = *profile.Title
+	}
+	if profile.WelcomeText != nil {
+		response.WelcomeText = *profile.WelcomeText
+	}
+	if profile.AvatarURL != nil {
+		response.AvatarURL = *profile.AvatarURL
+	}
+	if profile.BrandColor != nil {
+		response.BrandColor = *profile.BrandColor
+	}
+	for _, h := range history {
+		response.PreviousSlugs = append(response.PreviousSlugs, h.Slug)
+	}
+	return response
+}
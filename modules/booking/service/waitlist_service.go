@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/booking/dto"
+	"go-api-starter/modules/booking/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	waitlistOfferTTL        = 2 * time.Hour
+	minWaitlistOfferTTL     = 10 * time.Minute
+	maxWaitlistDaysAhead    = 90
+	maxWaitlistGuestName    = 255
+	waitlistExpiryBatchSize = 100
+	defaultWaitlistTimezone = "Asia/Ho_Chi_Minh"
+)
+
+// JoinWaitlist puts a guest on the host's waitlist for a day (any day when req.Date is empty) and event type.
+// hostKey is the booking page the guest joined from; a claimed slot is booked through it.
+func (s *bookingService) JoinWaitlist(ctx context.Context, hostID uuid.UUID, hostKey string, req *dto.JoinWaitlistRequest) (*dto.WaitlistEntryResponse, *errors.AppError) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxWaitlistGuestName {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("name must be 1-%d characters", maxWaitlistGuestName), nil)
+	}
+	email := strings.TrimSpace(req.Email)
+	if !utils.IsValidEmail(email) {
+		return nil, errors.NewAppError(errors.ErrInvalidEmail, "A valid email is required", nil)
+	}
+	eventType := NormalizeEventType(req.EventType)
+	if len(eventType) > 64 || !slugPattern.MatchString(eventType) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event_type may only contain lowercase letters, digits and hyphens (max 64)", nil)
+	}
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = defaultWaitlistTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "Unknown timezone", err)
+	}
+
+	entry := &entity.BookingWaitlistEntry{
+		HostID:     hostID,
+		HostKey:    hostKey,
+		GuestName:  name,
+		GuestEmail: email,
+		EventType:  eventType,
+		Timezone:   timezone,
+	}
+	if req.Date != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "date must be YYYY-MM-DD", err)
+		}
+		now := time.Now().In(loc)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		if date.Before(today) || date.After(today.AddDate(0, 0, maxWaitlistDaysAhead)) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("date must be within the next %d days", maxWaitlistDaysAhead), nil)
+		}
+		waitDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		entry.WaitDate = &waitDate
+	}
+
+	// The answers and payment method are checked now and sent with the booking request once the guest claims a slot
+	if _, appErr := s.ValidateIntakeAnswers(ctx, hostID, eventType, req.Answers); appErr != nil {
+		return nil, appErr
+	}
+	charge, appErr := s.PriceBookingRequest(ctx, hostID, eventType, req.PaymentMethodID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if charge != nil {
+		entry.PaymentMethodID = &charge.PaymentMethodID
+	}
+	if len(req.Answers) > 0 {
+		answers, _ := json.Marshal(req.Answers)
+		answersJSON := string(answers)
+		entry.Answers = &answersJSON
+	}
+
+	if err := s.bookingRepo.JoinWaitlist(ctx, entry); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to join the waitlist", err)
+	}
+	response := toWaitlistEntryResponse(entry)
+	return &response, nil
+}
+
+// ListWaitlist returns the guests on the host's waitlist with their place in the queue of their event type
+func (s *bookingService) ListWaitlist(ctx context.Context, userID uuid.UUID) ([]dto.WaitlistEntryResponse, *errors.AppError) {
+	entries, err := s.bookingRepo.GetWaitlistEntries(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get the waitlist", err)
+	}
+	positions := map[string]int{}
+	result := make([]dto.WaitlistEntryResponse, 0, len(entries))
+	for i := range entries {
+		positions[entries[i].EventType]++
+		response := toWaitlistEntryResponse(&entries[i])
+		response.Position = positions[entries[i].EventType]
+		result = append(result, response)
+	}
+	return result, nil
+}
+
+// RemoveWaitlistEntry takes a guest off the host's waitlist
+func (s *bookingService) RemoveWaitlistEntry(ctx context.Context, userID, id uuid.UUID) *errors.AppError {
+	removed, err := s.bookingRepo.RemoveWaitlistEntry(ctx, userID, id)
+	if err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "Failed to remove the waitlist entry", err)
+	}
+	if !removed {
+		return errors.NewAppError(errors.ErrNotFound, "Waitlist entry not found", nil)
+	}
+	return nil
+}
+
+// OfferFreedSlot offers a slot freed by a cancelled, declined or expired booking to the next waitlisted guest.
+// The offer expires after waitlistOfferTTL, or when the slot starts, and then moves on to the guest after them.
+func (s *bookingService) OfferFreedSlot(ctx context.Context, hostID uuid.UUID, eventType string, start, end time.Time) {
+	ttl := waitlistOfferTTL
+	if untilStart := time.Until(start); untilStart < ttl {
+		ttl = untilStart
+	}
+	if ttl < minWaitlistOfferTTL {
+		return // too late for anyone to claim it
+	}
+
+	offerID := uuid.New()
+	// The token subject is the offer, like approval links are bound to their event
+	token, err := utils.GenerateToken(offerID, nil, nil, constants.ScopeTokenWaitlistClaim, ttl)
+	if err != nil {
+		logger.Error("BookingService:OfferFreedSlot:GenerateToken:Error", "host_id", hostID, "error", err)
+		return
+	}
+	offer, err := s.bookingRepo.CreateWaitlistOffer(ctx, &entity.BookingWaitlistOffer{
+		ID:        offerID,
+		HostID:    hostID,
+		EventType: NormalizeEventType(eventType),
+		StartTime: start.UTC(),
+		EndTime:   end.UTC(),
+		TokenHash: hashActionToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil || offer == nil {
+		return // nobody is waiting for this slot
+	}
+	entry, err := s.bookingRepo.GetWaitlistEntry(ctx, offer.EntryID)
+	if err != nil || entry == nil {
+		return
+	}
+
+	loc, err := time.LoadLocation(entry.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	slot := offer.StartTime.In(loc).Format("02/01/2006 15:04") + " - " + offer.EndTime.In(loc).Format("15:04") + " (" + loc.String() + ")"
+	claimURL := PublicBaseURL() + "/api/v1/public/booking/waitlist/offers/" + offer.ID.String() + "/claim?token=" + url.QueryEscape(token)
+	body := "<h3>A time opened up</h3><p>Hi " + html.EscapeString(entry.GuestName) + ", a slot you were waiting for is free:</p>" +
+		"<p><b>" + html.EscapeString(slot) + "</b></p>" +
+		"<p><a href=\"" + html.EscapeString(claimURL) + "\">Claim this time</a></p>" +
+		"<p>The link works until " + html.EscapeString(offer.ExpiresAt.In(loc).Format("02/01/2006 15:04")) +
+		". After that the time is offered to the next guest on the waitlist.</p>"
+	if err := utils.SendEmailTLS(*utils.GetEmailConfig(), utils.EmailMessage{
+		To:      []string{entry.GuestEmail},
+		Subject: "A time you were waiting for is free",
+		Body:    body,
+		IsHTML:  true,
+	}); err != nil {
+		logger.Warn("BookingService:OfferFreedSlot:SendEmail:Error", "offer_id", offer.ID, "error", err)
+	}
+}
+
+// ExpireWaitlistOffers closes the offers nobody claimed in time and offers their slots to the next guests
+func (s *bookingService) ExpireWaitlistOffers(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		offers, err := s.bookingRepo.ExpireWaitlistOffers(ctx, time.Now(), waitlistExpiryBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for i := range offers {
+			expired++
+			s.OfferFreedSlot(ctx, offers[i].HostID, offers[i].EventType, offers[i].StartTime, offers[i].EndTime)
+		}
+		if len(offers) < waitlistExpiryBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		logger.Info("BookingService:ExpireWaitlistOffers:Done", "expired", expired)
+	}
+	return expired, nil
+}
+
+// openWaitlistOffer returns an offer token is valid for, with its waitlist entry, as long as it can still be claimed
+func (s *bookingService) openWaitlistOffer(ctx context.Context, offerID uuid.UUID, token string) (*entity.BookingWaitlistOffer, *entity.BookingWaitlistEntry, *errors.AppError) {
+	claims, err := utils.ValidateAndParseToken(token)
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized, "invalid token", err)
+	}
+	if !utils.ValidateTokenScope(claims, constants.ScopeTokenWaitlistClaim) || claims.UserID != offerID {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized, "token is not valid for this offer", nil)
+	}
+	offer, err := s.bookingRepo.GetWaitlistOffer(ctx, offerID)
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get the offer", err)
+	}
+	if offer == nil || offer.TokenHash != hashActionToken(token) {
+		return nil, nil, errors.NewAppError(errors.ErrUnauthorized, "token is not valid for this offer", nil)
+	}
+	switch {
+	case offer.Status == entity.WaitlistOfferClaimed:
+		return nil, nil, errors.NewAppError(errors.ErrInvalidState, "This time was already claimed", nil)
+	case offer.Status == entity.WaitlistOfferTaken:
+		return nil, nil, errors.NewAppError(errors.ErrInvalidState, "This time was booked by someone else; you are still on the waitlist", nil)
+	case offer.Status == entity.WaitlistOfferExpired || !time.Now().Before(offer.ExpiresAt):
+		return nil, nil, errors.NewAppError(errors.ErrResourceExpired, "This offer has expired", nil)
+	}
+	entry, err := s.bookingRepo.GetWaitlistEntry(ctx, offer.EntryID)
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get the waitlist entry", err)
+	}
+	if entry == nil || entry.Status != entity.WaitlistStatusWaiting {
+		return nil, nil, errors.NewAppError(errors.ErrInvalidState, "You are no longer on the waitlist", nil)
+	}
+	return offer, entry, nil
+}
+
+// CheckWaitlistOffer validates an offer link without claiming it, for the confirmation page the link opens
+func (s *bookingService) CheckWaitlistOffer(ctx context.Context, offerID uuid.UUID, token string) (*entity.BookingWaitlistOffer, *errors.AppError) {
+	offer, _, appErr := s.openWaitlistOffer(ctx, offerID, token)
+	return offer, appErr
+}
+
+// ClaimWaitlistOffer checks that the slot of an offer is still free and uses the offer up. It returns the booking
+// page the guest joined from and the booking request to submit for them through it.
+func (s *bookingService) ClaimWaitlistOffer(ctx context.Context, offerID uuid.UUID, token string) (string, *dto.PublicBookingRequest, *errors.AppError) {
+	offer, entry, appErr := s.openWaitlistOffer(ctx, offerID, token)
+	if appErr != nil {
+		return "", nil, appErr
+	}
+
+	// The same double-booking check as every booking request; a slot booked meanwhile stays with its new guest
+	available, appErr := s.SlotAvailable(ctx, offer.HostID, offer.StartTime, offer.EndTime)
+	if appErr != nil {
+		return "", nil, appErr
+	}
+	if !available {
+		_ = s.bookingRepo.CloseWaitlistOffer(ctx, offer.ID, entity.WaitlistOfferTaken)
+		return "", nil, errors.NewAppError(errors.ErrInvalidState, "This time was booked by someone else; you are still on the waitlist", nil)
+	}
+	claimed, err := s.bookingRepo.ClaimWaitlistOffer(ctx, offer.ID, offer.TokenHash)
+	if err != nil {
+		return "", nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to claim the offer", err)
+	}
+	if claimed == nil {
+		return "", nil, errors.NewAppError(errors.ErrInvalidState, "This offer was already used or has expired", nil)
+	}
+
+	loc, err := time.LoadLocation(entry.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	req := &dto.PublicBookingRequest{
+		Name:      entry.GuestName,
+		Email:     entry.GuestEmail,
+		StartTime: claimed.StartTime.In(loc).Format(time.RFC3339),
+		EndTime:   claimed.EndTime.In(loc).Format(time.RFC3339),
+		EventType: entry.EventType,
+	}
+	if entry.Answers != nil {
+		_ = json.Unmarshal([]byte(*entry.Answers), &req.Answers)
+	}
+	if entry.PaymentMethodID != nil {
+		req.PaymentMethodID = *entry.PaymentMethodID
+	}
+	return entry.HostKey, req, nil
+}
+
+func toWaitlistEntryResponse(entry *entity.BookingWaitlistEntry) dto.WaitlistEntryResponse {
+	response := dto.WaitlistEntryResponse{
+		ID:         entry.ID.String(),
+		GuestName:  entry.GuestName,
+		GuestEmail: entry.GuestEmail,
+		EventType:  entry.EventType,
+		Timezone:   entry.Timezone,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if entry.WaitDate != nil {
+		response.Date = entry.WaitDate.Format("2006-01-02")
+	}
+	return response
+}
@@ -23,7 +23,7 @@ func NewCalendarController(service service.CalendarService) *CalendarController
 
 // GetConnections returns all calendar connections for the current user
 // @Summary Lấy danh sách kết nối lịch
-// @Description Trả về tất cả các lịch đã kết nối của người dùng hiện tại
+// @Description Trả về tất cả các lịch đã kết nối của người dùng hiện tại, kèm tình trạng kết nối (health), lần đồng bộ thành công gần nhất và liên kết kết nối lại khi quyền truy cập bị thu hồi
 // @Tags Calendar
 // @Security BearerAuth
 // @Produce json
@@ -46,6 +46,256 @@ func (c *CalendarController) GetConnections(ctx echo.Context) error {
 	})
 }
 
+// GetSyncStatus returns the two-way sync state of each calendar connection
+// @Summary Trạng thái đồng bộ lịch
+// @Description Trả về trạng thái đồng bộ hai chiều với Google Calendar của từng kết nối (lần đồng bộ cuối, lỗi, số thay đổi, xung đột)
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.CalendarSyncStatusResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/calendar/sync [get]
+func (c *CalendarController) GetSyncStatus(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	statuses, err := c.service.GetSyncStatus(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to get sync status", err))
+	}
+
+	return ctx.JSON(http.StatusOK, dto.CalendarSyncStatusResponse{
+		Connections: statuses,
+	})
+}
+
+// SyncNow runs a sync of the user's Google Calendar immediately
+// @Summary Đồng bộ lịch ngay
+// @Description Đồng bộ hai chiều các thay đổi giữa sự kiện nội bộ và Google Calendar ngay lập tức
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.CalendarSyncStatus
+// @Failure 401 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 502 {object} dto.CalendarSyncStatus
+// @Router /private/calendar/sync [post]
+func (c *CalendarController) SyncNow(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	status, err := c.service.SyncCalendar(ctx.Request().Context(), userID)
+	if err != nil {
+		if status != nil {
+			// The failure is recorded on the sync state returned to the client
+			return ctx.JSON(http.StatusBadGateway, status)
+		}
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrNotFound {
+			return ctx.JSON(http.StatusNotFound, appErr)
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to sync calendar", err))
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// GetFocusTime returns the user's focus-time policy and planned blocks
+// @Summary Cấu hình thời gian tập trung
+// @Description Trả về chính sách tự động chặn thời gian tập trung (số giờ mục tiêu mỗi tuần, khung giờ ưu tiên, độ dài khối) và các khối đã lên lịch trong tuần này và tuần sau
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.FocusTimePolicyResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/calendar/focus-time [get]
+func (c *CalendarController) GetFocusTime(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	policy, err := c.service.GetFocusPolicy(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to get focus time policy", err))
+	}
+
+	return ctx.JSON(http.StatusOK, policy)
+}
+
+// UpdateFocusTime updates the user's focus-time policy
+// @Summary Cập nhật thời gian tập trung
+// @Description Cập nhật chính sách thời gian tập trung; các khối "Focus time" trên Google Calendar được sắp xếp lại ngay sau đó
+// @Tags Calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateFocusTimePolicyRequest true "Chính sách thời gian tập trung"
+// @Success 200 {object} dto.FocusTimePolicyResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/calendar/focus-time [put]
+func (c *CalendarController) UpdateFocusTime(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	var req dto.UpdateFocusTimePolicyRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", nil))
+	}
+
+	policy, err := c.service.UpdateFocusPolicy(ctx.Request().Context(), userID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrInvalidInput {
+			return ctx.JSON(http.StatusBadRequest, appErr)
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to update focus time policy", err))
+	}
+
+	return ctx.JSON(http.StatusOK, policy)
+}
+
+// PlanFocusTime rebalances the user's focus-time blocks immediately
+// @Summary Sắp xếp lại thời gian tập trung
+// @Description Xóa các khối tập trung bị trùng cuộc họp và tạo thêm khối mới cho đủ số giờ mục tiêu của tuần này và tuần sau
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.FocusTimePlanResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 502 {object} errors.AppError
+// @Router /private/calendar/focus-time/plan [post]
+func (c *CalendarController) PlanFocusTime(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	result, err := c.service.PlanFocusTime(ctx.Request().Context(), userID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			switch appErr.Code {
+			case errors.ErrNotFound:
+				return ctx.JSON(http.StatusNotFound, appErr)
+			case errors.ErrThirdParty:
+				return ctx.JSON(http.StatusBadGateway, appErr)
+			}
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to plan focus time", err))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// ListOutOfOffice returns the user's current and upcoming out-of-office periods
+// @Summary Danh sách lịch nghỉ
+// @Description Trả về các khoảng thời gian vắng mặt (out of office) hiện tại và sắp tới của người dùng
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.OutOfOfficeListResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/calendar/out-of-office [get]
+func (c *CalendarController) ListOutOfOffice(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	periods, err := c.service.ListOutOfOffice(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to get out-of-office periods", err))
+	}
+
+	return ctx.JSON(http.StatusOK, dto.OutOfOfficeListResponse{
+		Periods: periods,
+	})
+}
+
+// CreateOutOfOffice adds an out-of-office period
+// @Summary Tạo lịch nghỉ
+// @Description Đánh dấu các ngày nghỉ: các ngày này bị loại khỏi khung giờ rảnh, trang đặt lịch hiển thị thông báo, yêu cầu đặt lịch và lời mời trong thời gian này được tự động từ chối hoặc chuyển cho người được ủy quyền
+// @Tags Calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateOutOfOfficeRequest true "Thông tin lịch nghỉ"
+// @Success 201 {object} dto.OutOfOfficeResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/calendar/out-of-office [post]
+func (c *CalendarController) CreateOutOfOffice(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	var req dto.CreateOutOfOfficeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", nil))
+	}
+
+	period, err := c.service.CreateOutOfOffice(ctx.Request().Context(), userID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			switch appErr.Code {
+			case errors.ErrInvalidInput:
+				return ctx.JSON(http.StatusBadRequest, appErr)
+			case errors.ErrNotFound:
+				return ctx.JSON(http.StatusNotFound, appErr)
+			}
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to create out-of-office period", err))
+	}
+
+	return ctx.JSON(http.StatusCreated, period)
+}
+
+// DeleteOutOfOffice removes an out-of-office period
+// @Summary Xóa lịch nghỉ
+// @Description Xóa một khoảng thời gian vắng mặt và sự kiện "Out of office" tương ứng trên Google Calendar
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID lịch nghỉ"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/calendar/out-of-office/{id} [delete]
+func (c *CalendarController) DeleteOutOfOffice(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid out-of-office ID", nil))
+	}
+
+	if err := c.service.DeleteOutOfOffice(ctx.Request().Context(), userID, id); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			switch appErr.Code {
+			case errors.ErrNotFound:
+				return ctx.JSON(http.StatusNotFound, appErr)
+			case errors.ErrForbidden:
+				return ctx.JSON(http.StatusForbidden, appErr)
+			}
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, "Failed to delete out-of-office period", err))
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Out-of-office period deleted"})
+}
+
 // DisconnectCalendar disconnects a calendar provider
 // DELETE /api/v1/private/calendar/connections/:provider
 func (c *CalendarController) DisconnectCalendar(ctx echo.Context) error {
@@ -163,6 +413,46 @@ func (c *CalendarController) CreateEvent(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, result)
 }
 
+// UpdateEvent updates a calendar event
+// @Summary Cập nhật sự kiện lịch
+// @Description Cập nhật sự kiện trên Google Calendar và thông báo thay đổi cho người được mời (đổi giờ đáng kể sẽ yêu cầu xác nhận lại)
+// @Tags Calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.UpdateEventRequest true "Thông tin cập nhật"
+// @Success 200 {object} dto.UpdateEventResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/calendar/events/{id} [put]
+func (c *CalendarController) UpdateEvent(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusUnauthorized, errors.NewAppError(errors.ErrUnauthorized, "Invalid user", nil))
+	}
+
+	eventID := ctx.Param("id")
+	if eventID == "" {
+		return ctx.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Event ID is required", nil))
+	}
+
+	var req dto.UpdateEventRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, errors.NewAppError(errors.ErrInvalidInput, "Invalid request body", nil))
+	}
+
+	result, err := c.service.UpdateEvent(ctx.Request().Context(), userID, eventID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrInvalidInput {
+			return ctx.JSON(http.StatusBadRequest, appErr)
+		}
+		return ctx.JSON(http.StatusInternalServerError, errors.NewAppError(errors.ErrInternalServer, err.Error(), err))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
 // DeleteEvent deletes or declines a calendar event
 // DELETE /api/v1/private/calendar/events/:id
 func (c *CalendarController) DeleteEvent(ctx echo.Context) error {
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"go-api-starter/core/controller"
+	"go-api-starter/core/googlecalendar"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsController exposes the request metrics of the shared Google Calendar client
+type MetricsController struct {
+	controller.BaseController
+	client *googlecalendar.Client
+}
+
+func NewMetricsController(client *googlecalendar.Client) *MetricsController {
+	return &MetricsController{
+		BaseController: controller.NewBaseController(),
+		client:         client,
+	}
+}
+
+// GetGoogleCalendarMetrics returns the Google Calendar API request counters per operation
+// @Summary Số liệu gọi Google Calendar API
+// @Description Số request, lần thử lại, bị giới hạn tốc độ, mã trạng thái và độ trễ của từng thao tác Google Calendar API (cần quyền metrics:read)
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} googlecalendar.OperationStats
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/admin/metrics/google-calendar [get]
+func (c *MetricsController) GetGoogleCalendarMetrics(ctx echo.Context) error {
+	return c.SuccessResponse(ctx, c.client.Metrics().Snapshot(), "Get Google Calendar metrics successfully")
+}
@@ -10,11 +10,17 @@ const (
 
 // CalendarConnectionResponse represents a calendar connection
 type CalendarConnectionResponse struct {
-	ID            string `json:"id"`
-	Provider      string `json:"provider"`
-	CalendarEmail string `json:"calendar_email"`
-	IsActive      bool   `json:"is_active"`
-	ConnectedAt   string `json:"connected_at"`
+	ID              string `json:"id,omitempty"`
+	Provider        string `json:"provider"`
+	CalendarEmail   string `json:"calendar_email"`
+	IsActive        bool   `json:"is_active"`
+	ConnectedAt     string `json:"connected_at,omitempty"`
+	Health          string `json:"health"` // healthy, unhealthy (reconnect needed)
+	HealthError     string `json:"health_error,omitempty"`
+	UnhealthySince  string `json:"unhealthy_since,omitempty"`
+	ReconnectURL    string `json:"reconnect_url,omitempty"`
+	LastRefreshedAt string `json:"last_refreshed_at,omitempty"` // last successful token refresh
+	LastSyncedAt    string `json:"last_synced_at,omitempty"`    // last successful sync
 }
 
 // CalendarConnectionListResponse represents list of connections
@@ -22,6 +28,32 @@ type CalendarConnectionListResponse struct {
 	Connections []CalendarConnectionResponse `json:"connections"`
 }
 
+// ========== Sync DTOs ==========
+
+// CalendarSyncStatus reports the two-way sync state of a connection
+type CalendarSyncStatus struct {
+	Provider       string `json:"provider"`
+	CalendarEmail  string `json:"calendar_email,omitempty"`
+	Status         string `json:"status"` // never, ok, error
+	LastError      string `json:"last_error,omitempty"`
+	LastSyncedAt   string `json:"last_synced_at,omitempty"`
+	LastFullSyncAt string `json:"last_full_sync_at,omitempty"`
+	LastSuccessAt  string `json:"last_success_at,omitempty"`
+	EventsPulled   int    `json:"events_pulled"` // changes applied from the provider in the last run
+	EventsPushed   int    `json:"events_pushed"` // local changes sent to the provider in the last run
+	Conflicts      int    `json:"conflicts"`     // events edited on both sides in the last run
+}
+
+// CalendarSyncStatusResponse lists the sync state of the user's connections
+type CalendarSyncStatusResponse struct {
+	Connections []CalendarSyncStatus `json:"connections"`
+}
+
+// CalendarSyncTask is the payload of the calendar sync job; an empty user ID syncs every connected user
+type CalendarSyncTask struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
 // ========== Free/Busy DTOs ==========
 
 // FreeBusyRequest request for free/busy info
@@ -69,6 +101,28 @@ type CreateEventResponse struct {
 	StartTime   string `json:"start_time"`
 	EndTime     string `json:"end_time"`
 	MeetingLink string `json:"meeting_link,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+}
+
+// UpdateEventRequest request to update a calendar event; omitted fields are left unchanged
+type UpdateEventRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	StartTime   *string `json:"start_time"` // RFC3339
+	EndTime     *string `json:"end_time"`   // RFC3339
+	Timezone    *string `json:"timezone"`
+	Location    *string `json:"location"`
+	MeetingLink *string `json:"meeting_link"`
+}
+
+// UpdateEventResponse response after updating event
+type UpdateEventResponse struct {
+	EventID       string `json:"event_id"`
+	Title         string `json:"title"`
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+	MeetingLink   string `json:"meeting_link,omitempty"`
+	EventRevision int    `json:"event_revision"` // 0 when the event has no invitations
 }
 
 // ========== OAuth DTOs ==========
@@ -98,6 +152,7 @@ type SuggestedSlot struct {
 	Score          int    `json:"score"`      // 0-100 based on availability
 	AvailableCount int    `json:"available_count"`
 	TotalCount     int    `json:"total_count"`
+	SoftBusy       bool   `json:"soft_busy,omitempty"` // overlaps a participant's focus time; offered only as a last resort
 }
 
 // DisconnectedUser represents a user without calendar connection
@@ -116,3 +171,89 @@ type SuggestedSlotsResponse struct {
 	DisconnectedUsers []DisconnectedUser `json:"disconnected_users,omitempty"`
 	Warning           string             `json:"warning,omitempty"`
 }
+
+// ========== Focus Time DTOs ==========
+
+// FocusWindowDTO is a preferred period of the day for focus time
+type FocusWindowDTO struct {
+	Weekdays []int  `json:"weekdays,omitempty"` // ISO weekdays (1 = Monday); empty means Monday to Friday
+	Start    string `json:"start"`              // HH:MM
+	End      string `json:"end"`                // HH:MM
+}
+
+// UpdateFocusTimePolicyRequest updates the focus-time policy; omitted fields keep their current value
+type UpdateFocusTimePolicyRequest struct {
+	Enabled            *bool            `json:"enabled"`
+	TargetHoursPerWeek *float64         `json:"target_hours_per_week"`
+	MinBlockMinutes    *int             `json:"min_block_minutes"`
+	MaxBlockMinutes    *int             `json:"max_block_minutes"`
+	PreferredWindows   []FocusWindowDTO `json:"preferred_windows"`
+	Timezone           *string          `json:"timezone"`
+}
+
+// FocusBlockResponse is a focus-time block on the user's calendar
+type FocusBlockResponse struct {
+	ID              string `json:"id"`
+	ProviderEventID string `json:"provider_event_id"`
+	StartTime       string `json:"start_time"` // RFC3339
+	EndTime         string `json:"end_time"`   // RFC3339
+}
+
+// FocusTimePolicyResponse is the user's focus-time policy with the blocks planned for this and next week
+type FocusTimePolicyResponse struct {
+	Enabled            bool                 `json:"enabled"`
+	TargetHoursPerWeek float64              `json:"target_hours_per_week"`
+	MinBlockMinutes    int                  `json:"min_block_minutes"`
+	MaxBlockMinutes    int                  `json:"max_block_minutes"`
+	PreferredWindows   []FocusWindowDTO     `json:"preferred_windows"`
+	Timezone           string               `json:"timezone"`
+	LastPlannedAt      string               `json:"last_planned_at,omitempty"`
+	Blocks             []FocusBlockResponse `json:"blocks"`
+}
+
+// FocusTimePlanResponse reports the outcome of a focus-time rebalance
+type FocusTimePlanResponse struct {
+	Created        int                  `json:"created"`
+	Removed        int                  `json:"removed"`
+	PlannedMinutes map[string]int       `json:"planned_minutes"` // week start (YYYY-MM-DD) -> focus minutes on the calendar
+	Blocks         []FocusBlockResponse `json:"blocks"`
+}
+
+// FocusTimeTask is the payload of the focus-time job; an empty user ID plans every enabled policy
+type FocusTimeTask struct {
+	UserID string `json:"user_id"`
+}
+
+// ========== Out of Office DTOs ==========
+
+// CreateOutOfOfficeRequest creates an out-of-office period covering whole days
+type CreateOutOfOfficeRequest struct {
+	StartDate           string `json:"start_date"`            // YYYY-MM-DD, first day off
+	EndDate             string `json:"end_date"`              // YYYY-MM-DD, last day off (inclusive)
+	Timezone            string `json:"timezone"`              // default Asia/Ho_Chi_Minh
+	Message             string `json:"message"`               // shown on booking pages and sent with auto-replies
+	AutoAction          string `json:"auto_action"`           // "decline" (default) or "delegate"
+	DelegateUserID      string `json:"delegate_user_id"`      // required when auto_action is "delegate"
+	CreateProviderEvent bool   `json:"create_provider_event"` // also block the days on Google Calendar
+}
+
+// OutOfOfficeResponse represents an out-of-office period
+type OutOfOfficeResponse struct {
+	ID              string `json:"id"`
+	UserID          string `json:"user_id"`
+	StartDate       string `json:"start_date"` // YYYY-MM-DD
+	EndDate         string `json:"end_date"`   // YYYY-MM-DD, inclusive
+	StartTime       string `json:"start_time"` // RFC3339
+	EndTime         string `json:"end_time"`   // RFC3339
+	Timezone        string `json:"timezone"`
+	Message         string `json:"message,omitempty"`
+	AutoAction      string `json:"auto_action"`
+	DelegateUserID  string `json:"delegate_user_id,omitempty"`
+	ProviderEventID string `json:"provider_event_id,omitempty"`
+	Active          bool   `json:"active"`
+}
+
+// OutOfOfficeListResponse lists the user's current and upcoming out-of-office periods
+type OutOfOfficeListResponse struct {
+	Periods []OutOfOfficeResponse `json:"periods"`
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go-api-starter/core/entity"
+)
+
+// SyncStatus is the outcome of the last sync run of a connection
+type SyncStatus string
+
+const (
+	SyncStatusNever SyncStatus = "never"
+	SyncStatusOK    SyncStatus = "ok"
+	SyncStatusError SyncStatus = "error"
+)
+
+// CalendarSyncState tracks incremental sync (provider sync token and last run stats) for a user's connection
+type CalendarSyncState struct {
+	entity.BaseEntity
+	UserID         uuid.UUID  `db:"user_id" json:"user_id"`
+	Provider       string     `db:"provider" json:"provider"`
+	SyncToken      *string    `db:"sync_token" json:"-"`
+	Status         SyncStatus `db:"status" json:"status"`
+	LastError      *string    `db:"last_error" json:"last_error,omitempty"`
+	LastSyncedAt   *time.Time `db:"last_synced_at" json:"last_synced_at,omitempty"`
+	LastSuccessAt  *time.Time `db:"last_success_at" json:"last_success_at,omitempty"`
+	LastFullSyncAt *time.Time `db:"last_full_sync_at" json:"last_full_sync_at,omitempty"`
+	EventsPulled   int        `db:"events_pulled" json:"events_pulled"`
+	EventsPushed   int        `db:"events_pushed" json:"events_pushed"`
+	Conflicts      int        `db:"conflicts" json:"conflicts"`
+}
+
+// TableName returns the table name for GORM
+func (CalendarSyncState) TableName() string {
+	return "calendar_sync_states"
+}
+
+// SyncedEvent is the local mirror of an event on the user's provider calendar
+type SyncedEvent struct {
+	ID                uuid.UUID  `db:"id" json:"id"`
+	UserID            uuid.UUID  `db:"user_id" json:"user_id"`
+	Provider          string     `db:"provider" json:"provider"`
+	ProviderEventID   string     `db:"provider_event_id" json:"provider_event_id"`
+	Etag              *string    `db:"etag" json:"-"`
+	Summary           *string    `db:"summary" json:"summary,omitempty"`
+	Status            string     `db:"status" json:"status"`
+	EventType         string     `db:"event_type" json:"event_type"`
+	Transparency      string     `db:"transparency" json:"transparency"`
+	StartTime         time.Time  `db:"start_time" json:"start_time"`
+	EndTime           time.Time  `db:"end_time" json:"end_time"`
+	AllDay            bool       `db:"all_day" json:"all_day"`
+	OrganizerEmail    *string    `db:"organizer_email" json:"organizer_email,omitempty"`
+	IsOrganizer       bool       `db:"is_organizer" json:"is_organizer"`
+	Attendees         string     `db:"attendees" json:"attendees"` // JSONB as string
+	LocalEventID      *uuid.UUID `db:"local_event_id" json:"local_event_id,omitempty"`
+	ProviderUpdatedAt *time.Time `db:"provider_updated_at" json:"provider_updated_at,omitempty"`
+	SyncedAt          time.Time  `db:"synced_at" json:"synced_at"`
+}
+
+// TableName returns the table name for GORM
+func (SyncedEvent) TableName() string {
+	return "calendar_synced_events"
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConnectionHealthStatus tells whether a connection's tokens still work
+type ConnectionHealthStatus string
+
+const (
+	ConnectionHealthy   ConnectionHealthStatus = "healthy"
+	ConnectionUnhealthy ConnectionHealthStatus = "unhealthy"
+)
+
+// ConnectionHealth is the health of a user's calendar connection (from calendar_connection_health table)
+type ConnectionHealth struct {
+	UserID          uuid.UUID              `db:"user_id" json:"user_id"`
+	Provider        string                 `db:"provider" json:"provider"`
+	Status          ConnectionHealthStatus `db:"status" json:"status"`
+	LastError       *string                `db:"last_error" json:"last_error,omitempty"`
+	FailedAt        *time.Time             `db:"failed_at" json:"failed_at,omitempty"`
+	NotifiedAt      *time.Time             `db:"notified_at" json:"notified_at,omitempty"`
+	LastRefreshedAt *time.Time             `db:"last_refreshed_at" json:"last_refreshed_at,omitempty"`
+	CreatedAt       time.Time              `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time              `db:"updated_at" json:"updated_at"`
+}
+
+// NeedsReconnect reports whether the connection failed after its tokens were last stored.
+// Tokens stored after the failure (the user signed in again) make it stale.
+func (h *ConnectionHealth) NeedsReconnect(tokensUpdatedAt time.Time) bool {
+	return h != nil && h.Status == ConnectionUnhealthy && h.FailedAt != nil && !h.FailedAt.Before(tokensUpdatedAt)
+}
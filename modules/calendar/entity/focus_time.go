@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go-api-starter/core/entity"
+)
+
+// FocusBlockStatus is the lifecycle state of a planned focus-time block
+type FocusBlockStatus string
+
+const (
+	FocusBlockStatusActive  FocusBlockStatus = "active"
+	FocusBlockStatusRemoved FocusBlockStatus = "removed"
+)
+
+// FocusWindow is a preferred period of the day for focus time, in the policy's timezone
+type FocusWindow struct {
+	Weekdays []int  `json:"weekdays,omitempty"` // ISO weekdays (1 = Monday); empty means Monday to Friday
+	Start    string `json:"start"`              // HH:MM
+	End      string `json:"end"`                // HH:MM
+}
+
+type FocusWindows []FocusWindow
+
+func (w FocusWindows) Value() (driver.Value, error) {
+	if w == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(w)
+}
+
+func (w *FocusWindows) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, w)
+}
+
+// FocusTimePolicy is a user's weekly focus-time target and where it may be placed
+type FocusTimePolicy struct {
+	entity.BaseEntity
+	UserID               uuid.UUID    `db:"user_id" json:"user_id"`
+	Enabled              bool         `db:"enabled" json:"enabled"`
+	TargetMinutesPerWeek int          `db:"target_minutes_per_week" json:"target_minutes_per_week"`
+	MinBlockMinutes      int          `db:"min_block_minutes" json:"min_block_minutes"`
+	MaxBlockMinutes      int          `db:"max_block_minutes" json:"max_block_minutes"`
+	PreferredWindows     FocusWindows `db:"preferred_windows" json:"preferred_windows"`
+	Timezone             string       `db:"timezone" json:"timezone"`
+	LastPlannedAt        *time.Time   `db:"last_planned_at" json:"last_planned_at,omitempty"`
+}
+
+// TableName returns the table name for GORM
+func (FocusTimePolicy) TableName() string {
+	return "focus_time_policies"
+}
+
+// FocusTimeBlock is a "Focus time" busy event created on the user's provider calendar
+type FocusTimeBlock struct {
+	ID              uuid.UUID        `db:"id" json:"id"`
+	UserID          uuid.UUID        `db:"user_id" json:"user_id"`
+	Provider        string           `db:"provider" json:"provider"`
+	ProviderEventID string           `db:"provider_event_id" json:"provider_event_id"`
+	StartTime       time.Time        `db:"start_time" json:"start_time"`
+	EndTime         time.Time        `db:"end_time" json:"end_time"`
+	Status          FocusBlockStatus `db:"status" json:"status"`
+	CreatedAt       time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for GORM
+func (FocusTimeBlock) TableName() string {
+	return "focus_time_blocks"
+}
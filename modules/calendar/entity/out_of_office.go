@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go-api-starter/core/entity"
+)
+
+// OutOfOfficeAction is what happens to booking requests and invitations during an out-of-office period
+type OutOfOfficeAction string
+
+const (
+	OutOfOfficeActionDecline  OutOfOfficeAction = "decline"
+	OutOfOfficeActionDelegate OutOfOfficeAction = "delegate"
+)
+
+// OutOfOfficePeriod is a leave during which the user is unavailable for bookings and invitations
+type OutOfOfficePeriod struct {
+	entity.BaseEntity
+	UserID          uuid.UUID         `db:"user_id" json:"user_id"`
+	StartTime       time.Time         `db:"start_time" json:"start_time"`
+	EndTime         time.Time         `db:"end_time" json:"end_time"`
+	Timezone        string            `db:"timezone" json:"timezone"`
+	Message         *string           `db:"message" json:"message,omitempty"`
+	AutoAction      OutOfOfficeAction `db:"auto_action" json:"auto_action"`
+	DelegateUserID  *uuid.UUID        `db:"delegate_user_id" json:"delegate_user_id,omitempty"`
+	ProviderEventID *string           `db:"provider_event_id" json:"provider_event_id,omitempty"`
+}
+
+// TableName returns the table name for GORM
+func (OutOfOfficePeriod) TableName() string {
+	return "out_of_office_periods"
+}
+
+// Delegates reports whether requests during the period go to another user instead of being declined
+func (p *OutOfOfficePeriod) Delegates() bool {
+	return p.AutoAction == OutOfOfficeActionDelegate && p.DelegateUserID != nil
+}
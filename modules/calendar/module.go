@@ -2,15 +2,20 @@ package calendar
 
 import (
 	"go-api-starter/core/cache"
+	"go-api-starter/core/constants"
 	"go-api-starter/core/database"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/middleware"
 	authRepo "go-api-starter/modules/auth/repository"
+	authService "go-api-starter/modules/auth/service"
 	"go-api-starter/modules/calendar/controller"
 	"go-api-starter/modules/calendar/repository"
 	"go-api-starter/modules/calendar/router"
 	"go-api-starter/modules/calendar/service"
 	invitService "go-api-starter/modules/invitation/service"
+	meetRepo "go-api-starter/modules/meeting/repository"
 	notifService "go-api-starter/modules/notification/service"
+	"go-api-starter/workers"
 
 	"github.com/labstack/echo/v4"
 )
@@ -20,12 +25,30 @@ func Init(e *echo.Echo, db database.Database, cache cache.Cache, notifService *n
 	repo := repository.NewCalendarRepository(db)
 	userRepo := authRepo.NewAuthRepository(db)
 
-	calendarService := service.NewCalendarService(repo, userRepo, notifService, invitationService)
+	meetingRepo := meetRepo.NewMeetingRepository(db)
+
+	calendarService := service.NewCalendarService(repo, userRepo, notifService, invitationService, meetingRepo)
+	if invitationService != nil {
+		invitationService.SetEventScheduler(service.NewInvitationScheduler(calendarService))
+	}
 	calendarController := controller.NewCalendarController(calendarService)
+	metricsController := controller.NewMetricsController(googlecalendar.Default())
 
-	// Get middleware for auth
-	mw := middleware.NewMiddleware(nil)
+	// Get middleware for auth; PermissionMiddleware looks permissions up through the auth service
+	mw := middleware.NewMiddleware(authService.NewAuthService(userRepo, cache))
 
 	// Setup routes
-	router.NewCalendarRouter(calendarController).Setup(e, mw)
+	router.NewCalendarRouter(calendarController, metricsController).Setup(e, mw)
+
+	// Incremental two-way sync with Google Calendar every 15 minutes
+	workers.RegisterHandler(constants.TopicQueueCalendarSync, calendarService.HandleSyncTask)
+	workers.RegisterPeriodicTask("*/15 * * * *", constants.TopicQueueCalendarSync)
+
+	// Hourly focus-time rebalance; calendar changes also queue a rebalance for the affected user
+	workers.RegisterHandler(constants.TopicQueueFocusTime, calendarService.HandleFocusTimeTask)
+	workers.RegisterPeriodicTask("0 * * * *", constants.TopicQueueFocusTime)
+
+	// Refresh tokens before they expire and ask users with a revoked grant to reconnect
+	workers.RegisterHandler(constants.TopicQueueConnectionHealth, calendarService.HandleConnectionHealthTask)
+	workers.RegisterPeriodicTask("*/10 * * * *", constants.TopicQueueConnectionHealth)
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"go-api-starter/core/database"
+	"go-api-starter/core/encryption"
+	"go-api-starter/core/logger"
 	"go-api-starter/modules/calendar/entity"
 
 	"github.com/google/uuid"
@@ -18,9 +20,43 @@ type CalendarRepository interface {
 	GetConnectionsByUserID(ctx context.Context, userID uuid.UUID) ([]entity.CalendarConnection, error)
 	UpdateConnection(ctx context.Context, conn *entity.CalendarConnection) error
 	DeleteConnection(ctx context.Context, userID uuid.UUID, provider string) error
+	ReencryptConnectionTokens(ctx context.Context, rotate bool) (int, error)
+
+	// Connection health
+	GetConnectionHealthByUserID(ctx context.Context, userID uuid.UUID) ([]entity.ConnectionHealth, error)
+	MarkConnectionHealthy(ctx context.Context, userID uuid.UUID, provider string) error
+	MarkConnectionUnhealthy(ctx context.Context, userID uuid.UUID, provider string, reason string, tokensUpdatedAt time.Time) error
+	ClaimReconnectNotification(ctx context.Context, userID uuid.UUID, provider string) (bool, error)
+	GetExpiringConnectionUserIDs(ctx context.Context, provider string, expiresBefore time.Time, limit int) ([]uuid.UUID, error)
 
 	// Get connections by multiple user IDs (for free/busy lookup)
 	GetConnectionsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]entity.CalendarConnection, error)
+
+	// Two-way sync
+	GetSyncUserIDs(ctx context.Context, provider string) ([]uuid.UUID, error)
+	GetSyncState(ctx context.Context, userID uuid.UUID, provider string) (*entity.CalendarSyncState, error)
+	GetSyncStatesByUserID(ctx context.Context, userID uuid.UUID) ([]entity.CalendarSyncState, error)
+	SaveSyncState(ctx context.Context, state *entity.CalendarSyncState) error
+	UpsertSyncedEvent(ctx context.Context, event *entity.SyncedEvent) error
+	DeleteSyncedEvent(ctx context.Context, userID uuid.UUID, provider string, providerEventID string) error
+	ClearSyncedEvents(ctx context.Context, userID uuid.UUID, provider string) error
+	GetSyncedEventsInRange(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.SyncedEvent, error)
+
+	// Focus time
+	GetFocusPolicy(ctx context.Context, userID uuid.UUID) (*entity.FocusTimePolicy, error)
+	GetEnabledFocusPolicies(ctx context.Context) ([]entity.FocusTimePolicy, error)
+	SaveFocusPolicy(ctx context.Context, policy *entity.FocusTimePolicy) error
+	GetActiveFocusBlocks(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.FocusTimeBlock, error)
+	CreateFocusBlock(ctx context.Context, block *entity.FocusTimeBlock) error
+	RemoveFocusBlock(ctx context.Context, id uuid.UUID) error
+	RemoveFocusBlockByProviderEventID(ctx context.Context, userID uuid.UUID, provider string, providerEventID string) error
+
+	// Out of office
+	CreateOutOfOffice(ctx context.Context, period *entity.OutOfOfficePeriod) error
+	GetOutOfOfficeByID(ctx context.Context, id uuid.UUID) (*entity.OutOfOfficePeriod, error)
+	GetUpcomingOutOfOffice(ctx context.Context, userID uuid.UUID) ([]entity.OutOfOfficePeriod, error)
+	GetOutOfOfficeInRange(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.OutOfOfficePeriod, error)
+	DeleteOutOfOffice(ctx context.Context, id uuid.UUID) error
 }
 
 type calendarRepository struct {
@@ -38,9 +74,13 @@ func (r *calendarRepository) CreateConnection(ctx context.Context, conn *entity.
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
-	err := r.db.QueryRowContext(
+	accessToken, refreshToken, err := encryptConnectionTokens(conn)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.QueryRowContext(
 		ctx, query,
-		conn.UserID, conn.Provider, conn.AccessToken, conn.RefreshToken,
+		conn.UserID, conn.Provider, accessToken, refreshToken,
 		conn.TokenExpiresAt, conn.CalendarEmail, conn.IsActive,
 	).Scan(&conn.ID, &conn.CreatedAt, &conn.UpdatedAt)
 
@@ -54,7 +94,7 @@ func (r *calendarRepository) CreateConnection(ctx context.Context, conn *entity.
 func (r *calendarRepository) GetConnectionByUserAndProvider(ctx context.Context, userID uuid.UUID, provider string) (*entity.CalendarConnection, error) {
 	// Query from social_logins table where tokens are actually stored during Google login
 	query := `
-		SELECT sl.user_id, sl.provider_email, sl.access_token, sl.refresh_token, sl.token_expires_at
+		SELECT sl.user_id, sl.provider_email, sl.access_token, sl.refresh_token, sl.token_expires_at, sl.created_at, sl.updated_at
 		FROM social_logins sl
 		JOIN oauth_providers op ON sl.provider_id = op.id
 		WHERE sl.user_id = $1 
@@ -67,7 +107,7 @@ func (r *calendarRepository) GetConnectionByUserAndProvider(ctx context.Context,
 	var expiresAtPtr *time.Time
 
 	err := r.db.QueryRowContext(ctx, query, userID, provider).Scan(
-		&conn.UserID, &emailPtr, &accessTokenPtr, &refreshTokenPtr, &expiresAtPtr,
+		&conn.UserID, &emailPtr, &accessTokenPtr, &refreshTokenPtr, &expiresAtPtr, &conn.CreatedAt, &conn.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -90,6 +130,9 @@ func (r *calendarRepository) GetConnectionByUserAndProvider(ctx context.Context,
 	if expiresAtPtr != nil {
 		conn.TokenExpiresAt = *expiresAtPtr
 	}
+	if err := decryptConnectionTokens(&conn); err != nil {
+		return nil, err
+	}
 
 	return &conn, nil
 }
@@ -117,6 +160,9 @@ func (r *calendarRepository) GetConnectionsByUserID(ctx context.Context, userID
 		); err != nil {
 			return nil, err
 		}
+		if err := decryptConnectionTokens(&conn); err != nil {
+			return nil, err
+		}
 		connections = append(connections, conn)
 	}
 	return connections, nil
@@ -129,8 +175,12 @@ func (r *calendarRepository) UpdateConnection(ctx context.Context, conn *entity.
 		SET access_token = $1, refresh_token = $2, token_expires_at = $3, is_active = $4, updated_at = NOW()
 		WHERE id = $5
 	`
+	accessToken, refreshToken, err := encryptConnectionTokens(conn)
+	if err != nil {
+		return err
+	}
 	return r.db.ExecContext(ctx, query,
-		conn.AccessToken, conn.RefreshToken, conn.TokenExpiresAt, conn.IsActive, conn.ID,
+		accessToken, refreshToken, conn.TokenExpiresAt, conn.IsActive, conn.ID,
 	)
 }
 
@@ -144,6 +194,96 @@ func (r *calendarRepository) DeleteConnection(ctx context.Context, userID uuid.U
 	return r.db.ExecContext(ctx, query, userID, provider)
 }
 
+// ReencryptConnectionTokens encrypts the tokens of calendar connections that are still stored in plaintext,
+// or with rotate also those encrypted under a retired key. It returns how many rows were rewritten.
+// A token refreshed while the rows are processed is left for the next run instead of being overwritten.
+func (r *calendarRepository) ReencryptConnectionTokens(ctx context.Context, rotate bool) (int, error) {
+	const batchSize = 200
+	query := `
+		SELECT id, access_token, refresh_token FROM calendar_connections
+		WHERE id > $1
+		AND ((access_token <> '' AND access_token NOT LIKE $2) OR (refresh_token <> '' AND refresh_token NOT LIKE $2))
+		ORDER BY id
+		LIMIT $3
+	`
+	update := `
+		UPDATE calendar_connections SET access_token = $2, refresh_token = $3
+		WHERE id = $1 AND access_token = $4 AND refresh_token = $5
+	`
+
+	type tokenRow struct {
+		ID           uuid.UUID `db:"id"`
+		AccessToken  string    `db:"access_token"`
+		RefreshToken string    `db:"refresh_token"`
+	}
+	// changed counts rows whose tokens were refreshed between reading and rewriting them
+	rewritten, changed := 0, 0
+	lastID := uuid.Nil
+	for {
+		var rows []tokenRow
+		if err := r.db.SelectContext(ctx, &rows, query, lastID, encryption.UpToDatePattern(rotate), batchSize); err != nil {
+			logger.Error("CalendarRepository:ReencryptConnectionTokens:Error:", err)
+			return rewritten, err
+		}
+		for _, row := range rows {
+			lastID = row.ID
+			accessToken, err := encryption.ReencryptToken(row.AccessToken, rotate)
+			if err != nil {
+				logger.Error("CalendarRepository:ReencryptConnectionTokens:Skip:", err)
+				continue
+			}
+			refreshToken, err := encryption.ReencryptToken(row.RefreshToken, rotate)
+			if err != nil {
+				logger.Error("CalendarRepository:ReencryptConnectionTokens:Skip:", err)
+				continue
+			}
+			result, err := r.db.SQLx().ExecContext(ctx, update, row.ID, accessToken, refreshToken, row.AccessToken, row.RefreshToken)
+			if err != nil {
+				logger.Error("CalendarRepository:ReencryptConnectionTokens:Error:", err)
+				return rewritten, err
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				logger.Error("CalendarRepository:ReencryptConnectionTokens:RowsAffected:Error:", err)
+				return rewritten, err
+			}
+			if rowsAffected == 0 {
+				changed++
+				continue
+			}
+			rewritten++
+		}
+		if len(rows) < batchSize {
+			if changed > 0 {
+				logger.Info("CalendarRepository:ReencryptConnectionTokens:ChangedMeanwhile", "skipped", changed)
+			}
+			return rewritten, nil
+		}
+	}
+}
+
+// encryptConnectionTokens returns the connection's tokens as they are stored
+func encryptConnectionTokens(conn *entity.CalendarConnection) (string, string, error) {
+	accessToken, err := encryption.EncryptToken(conn.AccessToken)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := encryption.EncryptToken(conn.RefreshToken)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func decryptConnectionTokens(conn *entity.CalendarConnection) error {
+	var err error
+	if conn.AccessToken, err = encryption.DecryptToken(conn.AccessToken); err != nil {
+		return err
+	}
+	conn.RefreshToken, err = encryption.DecryptToken(conn.RefreshToken)
+	return err
+}
+
 // GetConnectionsByUserIDs gets Google calendar connections for multiple users from social_logins
 func (r *calendarRepository) GetConnectionsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]entity.CalendarConnection, error) {
 	if len(userIDs) == 0 {
@@ -158,7 +298,7 @@ func (r *calendarRepository) GetConnectionsByUserIDs(ctx context.Context, userID
 
 	// Query social_logins table for Google tokens (where auth stores them)
 	query := `
-		SELECT sl.user_id, sl.provider_email, sl.access_token, sl.refresh_token, sl.token_expires_at
+		SELECT sl.user_id, sl.provider_email, sl.access_token, sl.refresh_token, sl.token_expires_at, sl.created_at, sl.updated_at
 		FROM social_logins sl
 		JOIN oauth_providers op ON sl.provider_id = op.id
 		WHERE sl.user_id = ANY($1::uuid[]) 
@@ -178,7 +318,7 @@ func (r *calendarRepository) GetConnectionsByUserIDs(ctx context.Context, userID
 		var emailPtr, accessTokenPtr, refreshTokenPtr *string
 		var expiresAtPtr *time.Time
 
-		if err := rows.Scan(&conn.UserID, &emailPtr, &accessTokenPtr, &refreshTokenPtr, &expiresAtPtr); err != nil {
+		if err := rows.Scan(&conn.UserID, &emailPtr, &accessTokenPtr, &refreshTokenPtr, &expiresAtPtr, &conn.CreatedAt, &conn.UpdatedAt); err != nil {
 			return nil, err
 		}
 
@@ -196,6 +336,9 @@ func (r *calendarRepository) GetConnectionsByUserIDs(ctx context.Context, userID
 		if expiresAtPtr != nil {
 			conn.TokenExpiresAt = *expiresAtPtr
 		}
+		if err := decryptConnectionTokens(&conn); err != nil {
+			return nil, err
+		}
 
 		connections = append(connections, conn)
 	}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+func (r *calendarRepository) GetFocusPolicy(ctx context.Context, userID uuid.UUID) (*entity.FocusTimePolicy, error) {
+	var policy entity.FocusTimePolicy
+	query := `SELECT * FROM focus_time_policies WHERE user_id = $1`
+	if err := r.db.GetContext(ctx, &policy, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("CalendarRepository:GetFocusPolicy:Error:", err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetEnabledFocusPolicies returns every policy the focus-time planner should run for
+func (r *calendarRepository) GetEnabledFocusPolicies(ctx context.Context) ([]entity.FocusTimePolicy, error) {
+	var policies []entity.FocusTimePolicy
+	query := `SELECT * FROM focus_time_policies WHERE enabled = true ORDER BY user_id`
+	if err := r.db.SelectContext(ctx, &policies, query); err != nil {
+		logger.Error("CalendarRepository:GetEnabledFocusPolicies:Error:", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SaveFocusPolicy inserts or replaces a user's focus-time policy
+func (r *calendarRepository) SaveFocusPolicy(ctx context.Context, policy *entity.FocusTimePolicy) error {
+	query := `
+		INSERT INTO focus_time_policies (user_id, enabled, target_minutes_per_week, min_block_minutes, max_block_minutes,
+			preferred_windows, timezone, last_planned_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			target_minutes_per_week = EXCLUDED.target_minutes_per_week,
+			min_block_minutes = EXCLUDED.min_block_minutes,
+			max_block_minutes = EXCLUDED.max_block_minutes,
+			preferred_windows = EXCLUDED.preferred_windows,
+			timezone = EXCLUDED.timezone,
+			last_planned_at = EXCLUDED.last_planned_at,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		policy.UserID, policy.Enabled, policy.TargetMinutesPerWeek, policy.MinBlockMinutes, policy.MaxBlockMinutes,
+		policy.PreferredWindows, policy.Timezone, policy.LastPlannedAt,
+	).Scan(&policy.ID, &policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		logger.Error("CalendarRepository:SaveFocusPolicy:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetActiveFocusBlocks returns active focus blocks of the given users overlapping [from, to)
+func (r *calendarRepository) GetActiveFocusBlocks(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.FocusTimeBlock, error) {
+	if len(userIDs) == 0 {
+		return []entity.FocusTimeBlock{}, nil
+	}
+
+	userIDStrings := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		userIDStrings[i] = id.String()
+	}
+
+	var blocks []entity.FocusTimeBlock
+	query := `
+		SELECT * FROM focus_time_blocks
+		WHERE user_id = ANY($1::uuid[]) AND status = 'active' AND start_time < $3 AND end_time > $2
+		ORDER BY user_id, start_time
+	`
+	if err := r.db.SelectContext(ctx, &blocks, query, "{"+joinStrings(userIDStrings, ",")+"}", from, to); err != nil {
+		logger.Error("CalendarRepository:GetActiveFocusBlocks:Error:", err)
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (r *calendarRepository) CreateFocusBlock(ctx context.Context, block *entity.FocusTimeBlock) error {
+	query := `
+		INSERT INTO focus_time_blocks (user_id, provider, provider_event_id, start_time, end_time, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		block.UserID, block.Provider, block.ProviderEventID, block.StartTime, block.EndTime, block.Status,
+	).Scan(&block.ID, &block.CreatedAt, &block.UpdatedAt)
+	if err != nil {
+		logger.Error("CalendarRepository:CreateFocusBlock:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) RemoveFocusBlock(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE focus_time_blocks SET status = 'removed', updated_at = NOW() WHERE id = $1`
+	if err := r.db.ExecContext(ctx, query, id); err != nil {
+		logger.Error("CalendarRepository:RemoveFocusBlock:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveFocusBlockByProviderEventID marks a block removed after its event was deleted on the provider
+func (r *calendarRepository) RemoveFocusBlockByProviderEventID(ctx context.Context, userID uuid.UUID, provider string, providerEventID string) error {
+	query := `
+		UPDATE focus_time_blocks SET status = 'removed', updated_at = NOW()
+		WHERE user_id = $1 AND provider = $2 AND provider_event_id = $3 AND status = 'active'
+	`
+	if err := r.db.ExecContext(ctx, query, userID, provider, providerEventID); err != nil {
+		logger.Error("CalendarRepository:RemoveFocusBlockByProviderEventID:Error:", err)
+		return err
+	}
+	return nil
+}
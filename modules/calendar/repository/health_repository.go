@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+func (r *calendarRepository) GetConnectionHealthByUserID(ctx context.Context, userID uuid.UUID) ([]entity.ConnectionHealth, error) {
+	var health []entity.ConnectionHealth
+	query := `SELECT * FROM calendar_connection_health WHERE user_id = $1`
+	if err := r.db.SelectContext(ctx, &health, query, userID); err != nil {
+		logger.Error("CalendarRepository:GetConnectionHealthByUserID:Error:", err)
+		return nil, err
+	}
+	return health, nil
+}
+
+// MarkConnectionHealthy records a successful token refresh and closes any open incident
+func (r *calendarRepository) MarkConnectionHealthy(ctx context.Context, userID uuid.UUID, provider string) error {
+	query := `
+		INSERT INTO calendar_connection_health (user_id, provider, status, last_refreshed_at, created_at, updated_at)
+		VALUES ($1, $2, 'healthy', NOW(), NOW(), NOW())
+		ON CONFLICT (user_id, provider) DO UPDATE SET
+			status = 'healthy',
+			last_error = NULL,
+			failed_at = NULL,
+			notified_at = NULL,
+			last_refreshed_at = NOW(),
+			updated_at = NOW()
+	`
+	if err := r.db.ExecContext(ctx, query, userID, provider); err != nil {
+		logger.Error("CalendarRepository:MarkConnectionHealthy:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// MarkConnectionUnhealthy records that the provider rejected the connection's tokens.
+// A failure is a new incident (to notify about) unless one is open since tokensUpdatedAt.
+func (r *calendarRepository) MarkConnectionUnhealthy(ctx context.Context, userID uuid.UUID, provider string, reason string, tokensUpdatedAt time.Time) error {
+	query := `
+		INSERT INTO calendar_connection_health (user_id, provider, status, last_error, failed_at, created_at, updated_at)
+		VALUES ($1, $2, 'unhealthy', $3, NOW(), NOW(), NOW())
+		ON CONFLICT (user_id, provider) DO UPDATE SET
+			status = 'unhealthy',
+			last_error = EXCLUDED.last_error,
+			failed_at = CASE WHEN calendar_connection_health.status = 'unhealthy' AND calendar_connection_health.failed_at >= $4
+				THEN calendar_connection_health.failed_at ELSE NOW() END,
+			notified_at = CASE WHEN calendar_connection_health.status = 'unhealthy' AND calendar_connection_health.failed_at >= $4
+				THEN calendar_connection_health.notified_at ELSE NULL END,
+			updated_at = NOW()
+	`
+	if err := r.db.ExecContext(ctx, query, userID, provider, reason, tokensUpdatedAt); err != nil {
+		logger.Error("CalendarRepository:MarkConnectionUnhealthy:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ClaimReconnectNotification reports whether the caller should ask the user to reconnect;
+// it returns true once per incident
+func (r *calendarRepository) ClaimReconnectNotification(ctx context.Context, userID uuid.UUID, provider string) (bool, error) {
+	query := `
+		UPDATE calendar_connection_health SET notified_at = NOW()
+		WHERE user_id = $1 AND provider = $2 AND status = 'unhealthy' AND notified_at IS NULL
+		RETURNING user_id
+	`
+	var claimed []uuid.UUID
+	if err := r.db.SelectContext(ctx, &claimed, query, userID, provider); err != nil {
+		logger.Error("CalendarRepository:ClaimReconnectNotification:Error:", err)
+		return false, err
+	}
+	return len(claimed) > 0, nil
+}
+
+// GetExpiringConnectionUserIDs returns users whose provider access token expires before the given time,
+// skipping connections that already need a reconnect
+func (r *calendarRepository) GetExpiringConnectionUserIDs(ctx context.Context, provider string, expiresBefore time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT sl.user_id
+		FROM social_logins sl
+		JOIN oauth_providers op ON sl.provider_id = op.id
+		LEFT JOIN calendar_connection_health h ON h.user_id = sl.user_id AND h.provider = op.name
+		WHERE op.name = $1
+		AND sl.is_active = true
+		AND sl.access_token IS NOT NULL
+		AND sl.token_expires_at < $2
+		AND (h.user_id IS NULL OR h.status <> 'unhealthy' OR h.failed_at < sl.updated_at)
+		ORDER BY sl.token_expires_at
+		LIMIT $3
+	`
+	var userIDs []uuid.UUID
+	if err := r.db.SelectContext(ctx, &userIDs, query, provider, expiresBefore, limit); err != nil {
+		logger.Error("CalendarRepository:GetExpiringConnectionUserIDs:Error:", err)
+		return nil, err
+	}
+	return userIDs, nil
+}
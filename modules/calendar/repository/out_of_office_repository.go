@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+func (r *calendarRepository) CreateOutOfOffice(ctx context.Context, period *entity.OutOfOfficePeriod) error {
+	query := `
+		INSERT INTO out_of_office_periods (user_id, start_time, end_time, timezone, message, auto_action, delegate_user_id,
+			provider_event_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		period.UserID, period.StartTime, period.EndTime, period.Timezone, period.Message, period.AutoAction,
+		period.DelegateUserID, period.ProviderEventID,
+	).Scan(&period.ID, &period.CreatedAt, &period.UpdatedAt)
+	if err != nil {
+		logger.Error("CalendarRepository:CreateOutOfOffice:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) GetOutOfOfficeByID(ctx context.Context, id uuid.UUID) (*entity.OutOfOfficePeriod, error) {
+	var period entity.OutOfOfficePeriod
+	query := `SELECT * FROM out_of_office_periods WHERE id = $1`
+	if err := r.db.GetContext(ctx, &period, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("CalendarRepository:GetOutOfOfficeByID:Error:", err)
+		return nil, err
+	}
+	return &period, nil
+}
+
+// GetUpcomingOutOfOffice returns the user's periods that have not ended yet
+func (r *calendarRepository) GetUpcomingOutOfOffice(ctx context.Context, userID uuid.UUID) ([]entity.OutOfOfficePeriod, error) {
+	var periods []entity.OutOfOfficePeriod
+	query := `SELECT * FROM out_of_office_periods WHERE user_id = $1 AND end_time > NOW() ORDER BY start_time`
+	if err := r.db.SelectContext(ctx, &periods, query, userID); err != nil {
+		logger.Error("CalendarRepository:GetUpcomingOutOfOffice:Error:", err)
+		return nil, err
+	}
+	return periods, nil
+}
+
+// GetOutOfOfficeInRange returns periods of the given users overlapping [from, to)
+func (r *calendarRepository) GetOutOfOfficeInRange(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.OutOfOfficePeriod, error) {
+	if len(userIDs) == 0 {
+		return []entity.OutOfOfficePeriod{}, nil
+	}
+
+	userIDStrings := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		userIDStrings[i] = id.String()
+	}
+
+	var periods []entity.OutOfOfficePeriod
+	query := `
+		SELECT * FROM out_of_office_periods
+		WHERE user_id = ANY($1::uuid[]) AND start_time < $3 AND end_time > $2
+		ORDER BY user_id, start_time
+	`
+	if err := r.db.SelectContext(ctx, &periods, query, "{"+joinStrings(userIDStrings, ",")+"}", from, to); err != nil {
+		logger.Error("CalendarRepository:GetOutOfOfficeInRange:Error:", err)
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (r *calendarRepository) DeleteOutOfOffice(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM out_of_office_periods WHERE id = $1`
+	if err := r.db.ExecContext(ctx, query, id); err != nil {
+		logger.Error("CalendarRepository:DeleteOutOfOffice:Error:", err)
+		return err
+	}
+	return nil
+}
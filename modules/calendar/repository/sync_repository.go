@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetSyncUserIDs returns users whose provider tokens allow a calendar sync
+func (r *calendarRepository) GetSyncUserIDs(ctx context.Context, provider string) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT sl.user_id
+		FROM social_logins sl
+		JOIN oauth_providers op ON sl.provider_id = op.id
+		WHERE op.name = $1
+		AND sl.is_active = true
+		AND sl.access_token IS NOT NULL
+	`
+	var userIDs []uuid.UUID
+	if err := r.db.SelectContext(ctx, &userIDs, query, provider); err != nil {
+		logger.Error("CalendarRepository:GetSyncUserIDs:Error:", err)
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *calendarRepository) GetSyncState(ctx context.Context, userID uuid.UUID, provider string) (*entity.CalendarSyncState, error) {
+	var state entity.CalendarSyncState
+	query := `SELECT * FROM calendar_sync_states WHERE user_id = $1 AND provider = $2`
+	if err := r.db.GetContext(ctx, &state, query, userID, provider); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("CalendarRepository:GetSyncState:Error:", err)
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *calendarRepository) GetSyncStatesByUserID(ctx context.Context, userID uuid.UUID) ([]entity.CalendarSyncState, error) {
+	var states []entity.CalendarSyncState
+	query := `SELECT * FROM calendar_sync_states WHERE user_id = $1 ORDER BY provider`
+	if err := r.db.SelectContext(ctx, &states, query, userID); err != nil {
+		logger.Error("CalendarRepository:GetSyncStatesByUserID:Error:", err)
+		return nil, err
+	}
+	return states, nil
+}
+
+// SaveSyncState inserts or replaces the sync state of a user's connection
+func (r *calendarRepository) SaveSyncState(ctx context.Context, state *entity.CalendarSyncState) error {
+	query := `
+		INSERT INTO calendar_sync_states (user_id, provider, sync_token, status, last_error, last_synced_at,
+			last_full_sync_at, events_pulled, events_pushed, conflicts, last_success_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+		ON CONFLICT (user_id, provider) DO UPDATE SET
+			sync_token = EXCLUDED.sync_token,
+			status = EXCLUDED.status,
+			last_error = EXCLUDED.last_error,
+			last_synced_at = EXCLUDED.last_synced_at,
+			last_full_sync_at = EXCLUDED.last_full_sync_at,
+			events_pulled = EXCLUDED.events_pulled,
+			events_pushed = EXCLUDED.events_pushed,
+			conflicts = EXCLUDED.conflicts,
+			last_success_at = EXCLUDED.last_success_at,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		state.UserID, state.Provider, state.SyncToken, state.Status, state.LastError, state.LastSyncedAt,
+		state.LastFullSyncAt, state.EventsPulled, state.EventsPushed, state.Conflicts, state.LastSuccessAt,
+	).Scan(&state.ID, &state.CreatedAt, &state.UpdatedAt)
+	if err != nil {
+		logger.Error("CalendarRepository:SaveSyncState:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) UpsertSyncedEvent(ctx context.Context, event *entity.SyncedEvent) error {
+	query := `
+		INSERT INTO calendar_synced_events (user_id, provider, provider_event_id, etag, summary, status, event_type,
+			transparency, start_time, end_time, all_day, organizer_email, is_organizer, attendees, local_event_id,
+			provider_updated_at, synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
+		ON CONFLICT (user_id, provider, provider_event_id) DO UPDATE SET
+			etag = EXCLUDED.etag,
+			summary = EXCLUDED.summary,
+			status = EXCLUDED.status,
+			event_type = EXCLUDED.event_type,
+			transparency = EXCLUDED.transparency,
+			start_time = EXCLUDED.start_time,
+			end_time = EXCLUDED.end_time,
+			all_day = EXCLUDED.all_day,
+			organizer_email = EXCLUDED.organizer_email,
+			is_organizer = EXCLUDED.is_organizer,
+			attendees = EXCLUDED.attendees,
+			local_event_id = EXCLUDED.local_event_id,
+			provider_updated_at = EXCLUDED.provider_updated_at,
+			synced_at = NOW()
+	`
+	err := r.db.ExecContext(ctx, query,
+		event.UserID, event.Provider, event.ProviderEventID, event.Etag, event.Summary, event.Status, event.EventType,
+		event.Transparency, event.StartTime, event.EndTime, event.AllDay, event.OrganizerEmail, event.IsOrganizer,
+		event.Attendees, event.LocalEventID, event.ProviderUpdatedAt,
+	)
+	if err != nil {
+		logger.Error("CalendarRepository:UpsertSyncedEvent:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) DeleteSyncedEvent(ctx context.Context, userID uuid.UUID, provider string, providerEventID string) error {
+	query := `DELETE FROM calendar_synced_events WHERE user_id = $1 AND provider = $2 AND provider_event_id = $3`
+	if err := r.db.ExecContext(ctx, query, userID, provider, providerEventID); err != nil {
+		logger.Error("CalendarRepository:DeleteSyncedEvent:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ClearSyncedEvents drops the mirror of a connection before a full resync
+func (r *calendarRepository) ClearSyncedEvents(ctx context.Context, userID uuid.UUID, provider string) error {
+	query := `DELETE FROM calendar_synced_events WHERE user_id = $1 AND provider = $2`
+	if err := r.db.ExecContext(ctx, query, userID, provider); err != nil {
+		logger.Error("CalendarRepository:ClearSyncedEvents:Error:", err)
+		return err
+	}
+	return nil
+}
+
+// GetSyncedEventsInRange returns mirrored events of the given users overlapping [from, to)
+func (r *calendarRepository) GetSyncedEventsInRange(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) ([]entity.SyncedEvent, error) {
+	if len(userIDs) == 0 {
+		return []entity.SyncedEvent{}, nil
+	}
+
+	userIDStrings := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		userIDStrings[i] = id.String()
+	}
+
+	var events []entity.SyncedEvent
+	query := `
+		SELECT * FROM calendar_synced_events
+		WHERE user_id = ANY($1::uuid[]) AND start_time < $3 AND end_time > $2
+		ORDER BY user_id, start_time
+	`
+	if err := r.db.SelectContext(ctx, &events, query, "{"+joinStrings(userIDStrings, ",")+"}", from, to); err != nil {
+		logger.Error("CalendarRepository:GetSyncedEventsInRange:Error:", err)
+		return nil, err
+	}
+	return events, nil
+}
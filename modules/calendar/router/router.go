@@ -8,12 +8,14 @@ import (
 )
 
 type CalendarRouter struct {
-	controller *controller.CalendarController
+	controller        *controller.CalendarController
+	metricsController *controller.MetricsController
 }
 
-func NewCalendarRouter(controller *controller.CalendarController) *CalendarRouter {
+func NewCalendarRouter(controller *controller.CalendarController, metricsController *controller.MetricsController) *CalendarRouter {
 	return &CalendarRouter{
-		controller: controller,
+		controller:        controller,
+		metricsController: metricsController,
 	}
 }
 
@@ -28,13 +30,32 @@ func (r *CalendarRouter) Setup(e *echo.Echo, mw *middleware.Middleware) {
 	calendarRoutes.GET("/connections", r.controller.GetConnections)
 	calendarRoutes.DELETE("/connections/:provider", r.controller.DisconnectCalendar)
 
+	// Two-way sync
+	calendarRoutes.GET("/sync", r.controller.GetSyncStatus)
+	calendarRoutes.POST("/sync", r.controller.SyncNow)
+
+	// Focus time
+	calendarRoutes.GET("/focus-time", r.controller.GetFocusTime)
+	calendarRoutes.PUT("/focus-time", r.controller.UpdateFocusTime)
+	calendarRoutes.POST("/focus-time/plan", r.controller.PlanFocusTime)
+
+	// Out of office
+	calendarRoutes.GET("/out-of-office", r.controller.ListOutOfOffice)
+	calendarRoutes.POST("/out-of-office", r.controller.CreateOutOfOffice)
+	calendarRoutes.DELETE("/out-of-office/:id", r.controller.DeleteOutOfOffice)
+
 	// Free/Busy
 	calendarRoutes.GET("/free-busy", r.controller.GetFreeBusy)
 
 	// Events
 	calendarRoutes.POST("/events", r.controller.CreateEvent)
+	calendarRoutes.PUT("/events/:id", r.controller.UpdateEvent)
 	calendarRoutes.DELETE("/events/:id", r.controller.DeleteEvent)
 
 	// Suggested Slots
 	calendarRoutes.POST("/suggested-slots", r.controller.GetSuggestedSlots)
+
+	// Google Calendar API request metrics (requests, retries, throttling, statuses, latency per operation)
+	v1.GET("/private/admin/metrics/google-calendar", r.metricsController.GetGoogleCalendarMetrics,
+		mw.AuthMiddleware(), mw.PermissionMiddleware("metrics:read"))
 }
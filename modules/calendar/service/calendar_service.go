@@ -3,32 +3,34 @@ package service
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
-	"go-api-starter/core/config"
 	"go-api-starter/core/errors"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
 	authRepo "go-api-starter/modules/auth/repository"
+	authService "go-api-starter/modules/auth/service"
 	"go-api-starter/modules/calendar/dto"
 	"go-api-starter/modules/calendar/entity"
 	"go-api-starter/modules/calendar/repository"
 	invitDto "go-api-starter/modules/invitation/dto"
 	invitService "go-api-starter/modules/invitation/service"
+	meetRepo "go-api-starter/modules/meeting/repository"
 	notifDto "go-api-starter/modules/notification/dto"
 	notifService "go-api-starter/modules/notification/service"
 
 	"github.com/google/uuid"
 )
 
+// Google Calendar API paths, relative to the client's base URL
 const (
-	googleCalendarAPIBase = "https://www.googleapis.com/calendar/v3"
-	googleFreeBusyAPI     = googleCalendarAPIBase + "/freeBusy"
-	googleEventsAPI       = googleCalendarAPIBase + "/calendars/primary/events"
+	googleFreeBusyAPI = "/freeBusy"
+	googleEventsAPI   = "/calendars/primary/events"
 )
 
 type CalendarService interface {
@@ -36,13 +38,35 @@ type CalendarService interface {
 	SaveGoogleConnection(ctx context.Context, userID uuid.UUID, accessToken, refreshToken string, expiresAt time.Time, email string) (*entity.CalendarConnection, error)
 	GetConnections(ctx context.Context, userID uuid.UUID) ([]dto.CalendarConnectionResponse, error)
 	DisconnectCalendar(ctx context.Context, userID uuid.UUID, provider string) error
+	HandleConnectionHealthTask(ctx context.Context, payload []byte) error
 
 	// Calendar operations
 	GetFreeBusy(ctx context.Context, userID uuid.UUID, startTime, endTime time.Time) ([]dto.TimeSlot, error)
 	GetFreeBusyForUsers(ctx context.Context, userIDs []uuid.UUID, startTime, endTime time.Time) ([]dto.UserFreeBusy, error)
 	CreateEvent(ctx context.Context, userID uuid.UUID, req *dto.CreateEventRequest) (*dto.CreateEventResponse, error)
+	UpdateEvent(ctx context.Context, userID uuid.UUID, eventID string, req *dto.UpdateEventRequest) (*dto.UpdateEventResponse, error)
 	DeleteEvent(ctx context.Context, userID uuid.UUID, eventID string) error
 	FindAvailableSlots(ctx context.Context, req *dto.SuggestedSlotsRequest) (*dto.SuggestedSlotsResponse, error)
+
+	// Two-way sync
+	SyncCalendar(ctx context.Context, userID uuid.UUID) (*dto.CalendarSyncStatus, error)
+	SyncAllCalendars(ctx context.Context) error
+	GetSyncStatus(ctx context.Context, userID uuid.UUID) ([]dto.CalendarSyncStatus, error)
+	HandleSyncTask(ctx context.Context, payload []byte) error
+
+	// Focus time
+	GetFocusPolicy(ctx context.Context, userID uuid.UUID) (*dto.FocusTimePolicyResponse, error)
+	UpdateFocusPolicy(ctx context.Context, userID uuid.UUID, req *dto.UpdateFocusTimePolicyRequest) (*dto.FocusTimePolicyResponse, error)
+	PlanFocusTime(ctx context.Context, userID uuid.UUID) (*dto.FocusTimePlanResponse, error)
+	PlanAllFocusTime(ctx context.Context) error
+	HandleFocusTimeTask(ctx context.Context, payload []byte) error
+
+	// Out of office
+	CreateOutOfOffice(ctx context.Context, userID uuid.UUID, req *dto.CreateOutOfOfficeRequest) (*dto.OutOfOfficeResponse, error)
+	ListOutOfOffice(ctx context.Context, userID uuid.UUID) ([]dto.OutOfOfficeResponse, error)
+	DeleteOutOfOffice(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	GetOutOfOfficeDuring(ctx context.Context, userID uuid.UUID, start, end time.Time) (*dto.OutOfOfficeResponse, error)
+	GetOutOfOfficeNotice(ctx context.Context, userID uuid.UUID) (*dto.OutOfOfficeResponse, error)
 }
 
 type calendarService struct {
@@ -50,6 +74,9 @@ type calendarService struct {
 	userRepo     *authRepo.AuthRepository
 	notifService *notifService.NotificationService
 	invitService *invitService.InvitationService
+	meetingRepo  meetRepo.MeetingRepositoryInterface
+	tokens       *authService.TokenStore
+	google       *googlecalendar.Client
 }
 
 func NewCalendarService(
@@ -57,46 +84,26 @@ func NewCalendarService(
 	userRepo *authRepo.AuthRepository,
 	notifService *notifService.NotificationService,
 	invitService *invitService.InvitationService,
+	meetingRepo meetRepo.MeetingRepositoryInterface,
 ) CalendarService {
 	return &calendarService{
 		repo:         repo,
 		userRepo:     userRepo,
 		notifService: notifService,
 		invitService: invitService,
+		meetingRepo:  meetingRepo,
+		tokens:       authService.NewTokenStore(userRepo),
+		google:       googlecalendar.Default(),
 	}
 }
 
-// SaveGoogleConnection saves or updates a Google Calendar connection
+// SaveGoogleConnection stores new Google tokens for the user. Google tokens are kept on the user's
+// Google sign-in, so the user must have signed in with Google; the calendar email is that account's.
 func (s *calendarService) SaveGoogleConnection(ctx context.Context, userID uuid.UUID, accessToken, refreshToken string, expiresAt time.Time, email string) (*entity.CalendarConnection, error) {
-	// Check if connection already exists
-	existing, _ := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
-
-	if existing != nil {
-		// Update existing connection
-		existing.AccessToken = accessToken
-		existing.RefreshToken = refreshToken
-		existing.TokenExpiresAt = expiresAt
-		existing.CalendarEmail = email
-		existing.IsActive = true
-
-		if err := s.repo.UpdateConnection(ctx, existing); err != nil {
-			return nil, err
-		}
-		return existing, nil
-	}
-
-	// Create new connection
-	conn := &entity.CalendarConnection{
-		UserID:         userID,
-		Provider:       dto.ProviderGoogle,
-		AccessToken:    accessToken,
-		RefreshToken:   refreshToken,
-		TokenExpiresAt: expiresAt,
-		CalendarEmail:  email,
-		IsActive:       true,
+	if err := s.tokens.Save(ctx, userID, dto.ProviderGoogle, accessToken, refreshToken, expiresAt); err != nil {
+		return nil, err
 	}
-
-	return s.repo.CreateConnection(ctx, conn)
+	return s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
 }
 
 // GetConnections returns all calendar connections for a user
@@ -106,15 +113,49 @@ func (s *calendarService) GetConnections(ctx context.Context, userID uuid.UUID)
 		return nil, err
 	}
 
-	var result []dto.CalendarConnectionResponse
+	// Google sign-in stores its tokens on the social login; show that connection when there is no other
+	hasGoogle := false
 	for _, conn := range connections {
-		result = append(result, dto.CalendarConnectionResponse{
-			ID:            conn.ID.String(),
+		if conn.Provider == dto.ProviderGoogle {
+			hasGoogle = true
+		}
+	}
+	if !hasGoogle {
+		if conn, _ := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle); conn != nil {
+			connections = append(connections, *conn)
+		}
+	}
+
+	healthRows, err := s.repo.GetConnectionHealthByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	health := make(map[string]*entity.ConnectionHealth, len(healthRows))
+	for i := range healthRows {
+		health[healthRows[i].Provider] = &healthRows[i]
+	}
+	states, err := s.repo.GetSyncStatesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	syncStates := make(map[string]*entity.CalendarSyncState, len(states))
+	for i := range states {
+		syncStates[states[i].Provider] = &states[i]
+	}
+
+	var result []dto.CalendarConnectionResponse
+	for i, conn := range connections {
+		resp := dto.CalendarConnectionResponse{
+			ID:            connectionResponseID(conn.ID),
 			Provider:      conn.Provider,
 			CalendarEmail: conn.CalendarEmail,
 			IsActive:      conn.IsActive,
-			ConnectedAt:   conn.CreatedAt.Format(time.RFC3339),
-		})
+		}
+		if !conn.CreatedAt.IsZero() {
+			resp.ConnectedAt = conn.CreatedAt.Format(time.RFC3339)
+		}
+		connectionHealth(&resp, &connections[i], health[conn.Provider], syncStates[conn.Provider])
+		result = append(result, resp)
 	}
 	return result, nil
 }
@@ -138,11 +179,14 @@ func (s *calendarService) GetFreeBusy(ctx context.Context, userID uuid.UUID, sta
 	}
 
 	// Call Google Calendar FreeBusy API
-	busySlots, err := s.callGoogleFreeBusy(accessToken, conn.CalendarEmail, startTime, endTime)
+	busySlots, err := s.callGoogleFreeBusy(ctx, conn.UserID, accessToken, conn.CalendarEmail, startTime, endTime)
 	if err != nil {
 		return nil, err
 	}
 
+	// Out-of-office days are busy even when nothing is on the calendar
+	busySlots = append(busySlots, s.outOfOfficeBusy(ctx, []uuid.UUID{userID}, startTime, endTime)[userID.String()]...)
+
 	return busySlots, nil
 }
 
@@ -158,6 +202,8 @@ func (s *calendarService) GetFreeBusyForUsers(ctx context.Context, userIDs []uui
 
 	logger.Info("GetFreeBusyForUsers:Connections", "count", len(connections))
 
+	outOfOffice := s.outOfOfficeBusy(ctx, userIDs, startTime, endTime)
+
 	var results []dto.UserFreeBusy
 	for _, conn := range connections {
 		logger.Info("GetFreeBusyForUsers:ProcessingUser", "user_id", conn.UserID, "email", conn.CalendarEmail)
@@ -168,7 +214,7 @@ func (s *calendarService) GetFreeBusyForUsers(ctx context.Context, userIDs []uui
 			continue
 		}
 
-		busySlots, err := s.callGoogleFreeBusy(accessToken, conn.CalendarEmail, startTime, endTime)
+		busySlots, err := s.callGoogleFreeBusy(ctx, conn.UserID, accessToken, conn.CalendarEmail, startTime, endTime)
 		if err != nil {
 			logger.Error("Failed to get free/busy for user", "user_id", conn.UserID, "error", err)
 			continue
@@ -182,7 +228,7 @@ func (s *calendarService) GetFreeBusyForUsers(ctx context.Context, userIDs []uui
 		results = append(results, dto.UserFreeBusy{
 			UserID:    conn.UserID.String(),
 			Email:     conn.CalendarEmail,
-			BusySlots: busySlots,
+			BusySlots: append(busySlots, outOfOffice[conn.UserID.String()]...),
 		})
 	}
 
@@ -224,18 +270,15 @@ func (s *calendarService) CreateEvent(ctx context.Context, userID uuid.UUID, req
 		event["attendees"] = attendees
 	}
 
+	// hangoutLink is read-only and conferenceData only takes Google Meet or add-on conferences,
+	// so a custom meeting link is sent as the event location
 	if req.MeetingLink != "" {
-		event["hangoutLink"] = req.MeetingLink
+		event["location"] = req.MeetingLink
 	}
 
 	// Call Google Calendar Events API
 	eventJSON, _ := json.Marshal(event)
-	httpReq, _ := http.NewRequest("POST", googleEventsAPI, strings.NewReader(string(eventJSON)))
-	httpReq.Header.Set("Authorization", "Bearer "+accessToken)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.doGoogleRequest(ctx, userID, http.MethodPost, googleEventsAPI, accessToken, eventJSON, "")
 	if err != nil {
 		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to create event", err)
 	}
@@ -252,12 +295,14 @@ func (s *calendarService) CreateEvent(ctx context.Context, userID uuid.UUID, req
 	}
 
 	eventID := result["id"].(string)
+	etag, _ := result["etag"].(string)
 
 	// Create invitations for attendees if there are any
 	logger.Info("CreateEvent:Attendees", "count", len(req.Attendees), "emails", req.Attendees)
 	if len(req.Attendees) > 0 && s.invitService != nil {
-		// Resolve attendee emails to user IDs
+		// Resolve attendee emails to user IDs; attendees without an account are invited by email
 		inviteeIDs := make([]uuid.UUID, 0)
+		inviteeEmails := make([]string, 0)
 		for _, email := range req.Attendees {
 			user, err := s.userRepo.GetUserByIdentifier(ctx, email)
 			if err != nil {
@@ -265,19 +310,25 @@ func (s *calendarService) CreateEvent(ctx context.Context, userID uuid.UUID, req
 				continue
 			}
 			if user == nil {
-				logger.Warn("CreateEvent:GetUserByIdentifier:UserNotFound", "email", email)
+				if utils.IsValidEmail(email) {
+					logger.Info("CreateEvent:GetUserByIdentifier:UserNotFound:InviteByEmail", "email", email)
+					inviteeEmails = append(inviteeEmails, email)
+				} else {
+					logger.Warn("CreateEvent:GetUserByIdentifier:UserNotFound", "email", email)
+				}
 				continue
 			}
 			logger.Info("CreateEvent:FoundUser", "email", email, "user_id", user.ID)
 			inviteeIDs = append(inviteeIDs, user.ID)
 		}
 
-		logger.Info("CreateEvent:InviteeIDs", "count", len(inviteeIDs), "ids", inviteeIDs)
-		if len(inviteeIDs) > 0 {
+		logger.Info("CreateEvent:InviteeIDs", "count", len(inviteeIDs), "ids", inviteeIDs, "emails", len(inviteeEmails))
+		if len(inviteeIDs) > 0 || len(inviteeEmails) > 0 {
 			invitReq := &invitDto.CreateInvitationRequest{
 				EventGoogleID: eventID,
 				CreatorID:     userID,
 				InviteeIDs:    inviteeIDs,
+				InviteeEmails: inviteeEmails,
 				EventData: invitDto.EventDataDTO{
 					Title:       req.Title,
 					Description: req.Description,
@@ -292,17 +343,128 @@ func (s *calendarService) CreateEvent(ctx context.Context, userID uuid.UUID, req
 				logger.Error("CreateEvent:CreateInvitations:Error:", err)
 				// Don't fail the event creation for invitation errors
 			} else {
-				logger.Info("CreateEvent:CreateInvitations:Success", "count", len(inviteeIDs))
+				logger.Info("CreateEvent:CreateInvitations:Success", "count", len(inviteeIDs)+len(inviteeEmails))
 			}
 		}
 	}
 
+	// Move focus-time blocks out of the way of the new meeting
+	s.requestFocusRebalance(ctx, userID)
+
 	return &dto.CreateEventResponse{
 		EventID:     eventID,
 		Title:       req.Title,
 		StartTime:   req.StartTime,
 		EndTime:     req.EndTime,
 		MeetingLink: req.MeetingLink,
+		ETag:        etag,
+	}, nil
+}
+
+// UpdateEvent patches an event on Google Calendar and propagates the change to its invitations
+func (s *calendarService) UpdateEvent(ctx context.Context, userID uuid.UUID, eventID string, req *dto.UpdateEventRequest) (*dto.UpdateEventResponse, error) {
+	conn, err := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "No Google Calendar connected", err)
+	}
+
+	accessToken, err := s.ensureValidToken(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{}
+	if req.Title != nil {
+		patch["summary"] = *req.Title
+	}
+	if req.Description != nil {
+		patch["description"] = *req.Description
+	}
+	// Like CreateEvent, a meeting link goes to the location; an explicit location takes precedence
+	if req.Location != nil {
+		patch["location"] = *req.Location
+	} else if req.MeetingLink != nil {
+		patch["location"] = *req.MeetingLink
+	}
+	var timezone *string
+	if req.StartTime != nil || req.EndTime != nil || req.Timezone != nil {
+		if req.StartTime == nil || req.EndTime == nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "start_time and end_time must be updated together", nil)
+		}
+		tz := "Asia/Ho_Chi_Minh"
+		if req.Timezone != nil && *req.Timezone != "" {
+			tz = *req.Timezone
+		}
+		timezone = &tz
+		patch["start"] = map[string]string{"dateTime": *req.StartTime, "timeZone": tz}
+		patch["end"] = map[string]string{"dateTime": *req.EndTime, "timeZone": tz}
+	}
+	if len(patch) == 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "Nothing to update", nil)
+	}
+
+	eventURL := fmt.Sprintf("%s/%s", googleEventsAPI, eventID)
+	patchJSON, _ := json.Marshal(patch)
+	resp, err := s.doGoogleRequest(ctx, userID, http.MethodPatch, eventURL, accessToken, patchJSON, "")
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to update event", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Event not found", nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.NewAppError(errors.ErrInternalServer, fmt.Sprintf("Google API error: %s", string(body)), nil)
+	}
+
+	var updated struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		HangoutLink string `json:"hangoutLink"`
+		Start       struct {
+			DateTime string `json:"dateTime"`
+		} `json:"start"`
+		End struct {
+			DateTime string `json:"dateTime"`
+		} `json:"end"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to parse event", err)
+	}
+
+	revision := 0
+	if s.invitService != nil {
+		// Only the fields this request changed are copied; Google's copy carries the meeting link as its location
+		revision, err = s.invitService.PropagateEventUpdate(ctx, userID, eventID, invitDto.EventDataUpdateDTO{
+			Title:       req.Title,
+			Description: req.Description,
+			StartTime:   req.StartTime,
+			EndTime:     req.EndTime,
+			Location:    req.Location,
+			MeetingLink: req.MeetingLink,
+			Timezone:    timezone,
+		})
+		if err != nil {
+			// Google is the source of truth; invitations catch up on the next update
+			logger.Error("UpdateEvent:PropagateEventUpdate:Error", "event_id", eventID, "error", err)
+		}
+	}
+
+	s.requestFocusRebalance(ctx, userID)
+
+	meetingLink := updated.HangoutLink
+	if req.MeetingLink != nil {
+		meetingLink = *req.MeetingLink
+	}
+	return &dto.UpdateEventResponse{
+		EventID:       eventID,
+		Title:         updated.Summary,
+		StartTime:     updated.Start.DateTime,
+		EndTime:       updated.End.DateTime,
+		MeetingLink:   meetingLink,
+		EventRevision: revision,
 	}, nil
 }
 
@@ -320,11 +482,7 @@ func (s *calendarService) DeleteEvent(ctx context.Context, userID uuid.UUID, eve
 
 	// 1. Get event details to check if user is organizer
 	eventURL := fmt.Sprintf("%s/%s", googleEventsAPI, eventID)
-	getReq, _ := http.NewRequest("GET", eventURL, nil)
-	getReq.Header.Set("Authorization", "Bearer "+accessToken)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	getResp, err := client.Do(getReq)
+	getResp, err := s.doGoogleRequest(ctx, userID, http.MethodGet, eventURL, accessToken, nil, "")
 	if err != nil {
 		return errors.NewAppError(errors.ErrInternalServer, "Failed to get event", err)
 	}
@@ -351,10 +509,7 @@ func (s *calendarService) DeleteEvent(ctx context.Context, userID uuid.UUID, eve
 	// 2. Check if current user is the organizer
 	if eventData.Organizer.Self {
 		// User is organizer - DELETE the event entirely
-		deleteReq, _ := http.NewRequest("DELETE", eventURL, nil)
-		deleteReq.Header.Set("Authorization", "Bearer "+accessToken)
-
-		deleteResp, err := client.Do(deleteReq)
+		deleteResp, err := s.doGoogleRequest(ctx, userID, http.MethodDelete, eventURL, accessToken, nil, "")
 		if err != nil {
 			return errors.NewAppError(errors.ErrInternalServer, "Failed to delete event", err)
 		}
@@ -364,6 +519,13 @@ func (s *calendarService) DeleteEvent(ctx context.Context, userID uuid.UUID, eve
 			return errors.NewAppError(errors.ErrInternalServer, "Google API error when deleting", nil)
 		}
 
+		// Cancel local invitations (email-only invitees are emailed by the invitation service)
+		if s.invitService != nil {
+			if err := s.invitService.CancelEventInvitations(ctx, userID, eventID); err != nil {
+				logger.Error("DeleteEvent:CancelEventInvitations:Error", "event_id", eventID, "error", err)
+			}
+		}
+
 		// Send notifications to attendees about cancellation
 		if len(eventData.Attendees) > 0 && s.notifService != nil {
 			organizerEmail := eventData.Organizer.Email
@@ -424,11 +586,7 @@ func (s *calendarService) DeleteEvent(ctx context.Context, userID uuid.UUID, eve
 	}
 
 	jsonBody, _ := json.Marshal(patchPayload)
-	patchReq, _ := http.NewRequest("PATCH", eventURL, strings.NewReader(string(jsonBody)))
-	patchReq.Header.Set("Authorization", "Bearer "+accessToken)
-	patchReq.Header.Set("Content-Type", "application/json")
-
-	patchResp, err := client.Do(patchReq)
+	patchResp, err := s.doGoogleRequest(ctx, userID, http.MethodPatch, eventURL, accessToken, jsonBody, "")
 	if err != nil {
 		return errors.NewAppError(errors.ErrInternalServer, "Failed to decline event", err)
 	}
@@ -441,69 +599,37 @@ func (s *calendarService) DeleteEvent(ctx context.Context, userID uuid.UUID, eve
 	return nil
 }
 
-// ensureValidToken refreshes token if expired
+// ensureValidToken returns an access token of the connection that stays valid for a few more minutes
 func (s *calendarService) ensureValidToken(ctx context.Context, conn *entity.CalendarConnection) (string, error) {
-	if time.Now().Before(conn.TokenExpiresAt.Add(-5 * time.Minute)) {
-		return conn.AccessToken, nil
-	}
-
-	logger.Info("ensureValidToken:RefreshingToken", "user_id", conn.UserID)
-
-	// Token expired, refresh it
-	cfg, _ := config.GetSafe()
-
-	data := url.Values{}
-	data.Set("client_id", cfg.GoogleAPI.ClientID)
-	data.Set("client_secret", cfg.GoogleAPI.ClientSecret)
-	data.Set("refresh_token", conn.RefreshToken)
-	data.Set("grant_type", "refresh_token")
+	return s.ensureTokenValidFor(ctx, conn, authService.TokenExpiryLeeway)
+}
 
-	resp, err := http.PostForm("https://oauth2.googleapis.com/token", data)
+// ensureTokenValidFor refreshes the connection's access token through the shared token store when it
+// expires within minValidity. A revoked grant marks the connection for reconnecting.
+func (s *calendarService) ensureTokenValidFor(ctx context.Context, conn *entity.CalendarConnection, minValidity time.Duration) (string, error) {
+	token, err := s.tokens.ValidToken(ctx, conn.UserID, conn.Provider, minValidity)
 	if err != nil {
-		logger.Error("ensureValidToken:PostFormError", "error", err)
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		logger.Error("ensureValidToken:DecodeError", "error", err)
+		if goerrors.Is(err, authService.ErrTokenRevoked) || goerrors.Is(err, authService.ErrTokenNotFound) {
+			s.markReconnectRequired(ctx, conn, err.Error())
+			return "", fmt.Errorf("%w: %v", errReconnectRequired, err)
+		}
+		logger.Error("ensureValidToken:Error", "user_id", conn.UserID, "error", err)
 		return "", err
 	}
 
-	// Check for error in response
-	if errMsg, ok := result["error"].(string); ok {
-		errDesc, _ := result["error_description"].(string)
-		logger.Error("ensureValidToken:GoogleError", "error", errMsg, "description", errDesc)
-		return "", fmt.Errorf("Google token refresh error: %s - %s", errMsg, errDesc)
-	}
-
-	// Safe type assertions
-	accessToken, ok := result["access_token"].(string)
-	if !ok || accessToken == "" {
-		logger.Error("ensureValidToken:NoAccessToken", "result", result)
-		return "", fmt.Errorf("no access_token in response")
-	}
-
-	expiresInFloat, ok := result["expires_in"].(float64)
-	if !ok {
-		expiresInFloat = 3600 // Default 1 hour
-	}
-	expiresIn := int(expiresInFloat)
-
-	conn.AccessToken = accessToken
-	conn.TokenExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
-
-	if err := s.repo.UpdateConnection(ctx, conn); err != nil {
-		logger.Error("Failed to update token", "error", err)
+	conn.AccessToken = token.AccessToken
+	conn.RefreshToken = token.RefreshToken
+	conn.TokenExpiresAt = token.ExpiresAt
+	if token.Refreshed {
+		if err := s.repo.MarkConnectionHealthy(ctx, conn.UserID, conn.Provider); err != nil {
+			logger.Error("ensureValidToken:MarkConnectionHealthy:Error", "error", err)
+		}
 	}
-
-	logger.Info("ensureValidToken:Success", "user_id", conn.UserID)
-	return accessToken, nil
+	return token.AccessToken, nil
 }
 
 // callGoogleFreeBusy calls Google Calendar FreeBusy API
-func (s *calendarService) callGoogleFreeBusy(accessToken, email string, startTime, endTime time.Time) ([]dto.TimeSlot, error) {
+func (s *calendarService) callGoogleFreeBusy(ctx context.Context, userID uuid.UUID, accessToken, email string, startTime, endTime time.Time) ([]dto.TimeSlot, error) {
 	payload := map[string]interface{}{
 		"timeMin": startTime.Format(time.RFC3339),
 		"timeMax": endTime.Format(time.RFC3339),
@@ -513,12 +639,15 @@ func (s *calendarService) callGoogleFreeBusy(accessToken, email string, startTim
 	}
 
 	payloadJSON, _ := json.Marshal(payload)
-	req, _ := http.NewRequest("POST", googleFreeBusyAPI, strings.NewReader(string(payloadJSON)))
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	// A freeBusy query only reads, so it is safe to retry after a server error
+	resp, err := s.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      http.MethodPost,
+		Path:        googleFreeBusyAPI,
+		AccessToken: accessToken,
+		Body:        payloadJSON,
+		Idempotent:  true,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -612,6 +741,9 @@ func (s *calendarService) FindAvailableSlots(ctx context.Context, req *dto.Sugge
 		busyData = []dto.UserFreeBusy{}
 	}
 
+	// Focus-time blocks are soft-busy: they are taken out of the busy data and only booked as a last resort
+	softBusy := s.splitFocusBusy(ctx, userIDs, busyData, startTime, endTime)
+
 	// Track connected vs disconnected users
 	connectedUserIDs := make(map[string]bool)
 	for _, userData := range busyData {
@@ -701,7 +833,7 @@ func (s *calendarService) FindAvailableSlots(ctx context.Context, req *dto.Sugge
 	candidates := s.generateCandidateSlots(startTime, req.DaysAhead, req.DurationMinutes, req.WorkingHoursOnly)
 
 	// Step 4: Check each candidate against merged busy intervals
-	var slots []dto.SuggestedSlot
+	var slots, softSlots []dto.SuggestedSlot
 	for _, candidate := range candidates {
 		isFree := true
 		for _, busy := range mergedBusy {
@@ -728,13 +860,28 @@ func (s *calendarService) FindAvailableSlots(ctx context.Context, req *dto.Sugge
 		startVN := candidate.start.In(vnLoc)
 		endVN := candidate.end.In(vnLoc)
 		
-		slots = append(slots, dto.SuggestedSlot{
+		slot := dto.SuggestedSlot{
 			StartTime:      startVN.Format(time.RFC3339),
 			EndTime:        endVN.Format(time.RFC3339),
 			Score:          100,
 			AvailableCount: connectedCount,
 			TotalCount:     connectedCount,
-		})
+		}
+
+		// Slots over a participant's focus time are kept aside in case nothing else is free
+		if overlapsAny(candidate.start, candidate.end, softBusy) {
+			slot.Score = 50
+			slot.SoftBusy = true
+			softSlots = append(softSlots, slot)
+			continue
+		}
+
+		slots = append(slots, slot)
+	}
+
+	if len(slots) == 0 && len(softSlots) > 0 {
+		logger.Info("FindAvailableSlots:FallbackToFocusTime", "soft_slots", len(softSlots))
+		slots = softSlots
 	}
 
 	// Filter and sort slots based on time preference
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-starter/core/config"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/dto"
+	"go-api-starter/modules/calendar/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// tokenRefreshAhead is how long before expiry the health job refreshes an access token
+	tokenRefreshAhead = 30 * time.Minute
+	// tokenRefreshBatch caps the connections one health job run refreshes; the rest wait for the next run
+	tokenRefreshBatch = 500
+)
+
+// errReconnectRequired means the provider rejected the refresh token and the user has to sign in again
+var errReconnectRequired = fmt.Errorf("calendar connection needs to be reconnected")
+
+// HandleConnectionHealthTask is the asynq handler for TopicQueueConnectionHealth.
+// It refreshes access tokens that are about to expire, so a revoked grant is found (and the user asked
+// to reconnect) before the user silently drops out of availability lookups.
+func (s *calendarService) HandleConnectionHealthTask(ctx context.Context, _ []byte) error {
+	userIDs, err := s.repo.GetExpiringConnectionUserIDs(ctx, dto.ProviderGoogle, time.Now().Add(tokenRefreshAhead), tokenRefreshBatch)
+	if err != nil {
+		return err
+	}
+
+	refreshed, reconnect, failed := 0, 0, 0
+	for _, userID := range userIDs {
+		conn, err := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+		if err != nil || conn == nil {
+			continue
+		}
+		if _, err := s.ensureTokenValidFor(ctx, conn, tokenRefreshAhead); err != nil {
+			if goerrors.Is(err, errReconnectRequired) {
+				reconnect++
+			} else {
+				failed++
+			}
+			continue
+		}
+		refreshed++
+	}
+
+	logger.Info("CalendarService:HandleConnectionHealthTask:Done", "refreshed", refreshed, "reconnect", reconnect, "failed", failed)
+	return nil
+}
+
+// markReconnectRequired flags the connection unhealthy and asks the user, once per incident, to reconnect
+func (s *calendarService) markReconnectRequired(ctx context.Context, conn *entity.CalendarConnection, reason string) {
+	if err := s.repo.MarkConnectionUnhealthy(ctx, conn.UserID, conn.Provider, reason, conn.UpdatedAt); err != nil {
+		return
+	}
+	claimed, err := s.repo.ClaimReconnectNotification(ctx, conn.UserID, conn.Provider)
+	if err != nil || !claimed || s.notifService == nil {
+		return
+	}
+
+	message := "Ứng dụng không còn quyền truy cập Google Calendar của bạn nên lịch bận/rảnh của bạn sẽ không được dùng khi xếp lịch. Hãy kết nối lại tài khoản Google."
+	if conn.CalendarEmail != "" {
+		message = fmt.Sprintf("Ứng dụng không còn quyền truy cập Google Calendar của %s nên lịch bận/rảnh của bạn sẽ không được dùng khi xếp lịch. Hãy kết nối lại tài khoản Google.", conn.CalendarEmail)
+	}
+	if err := s.notifService.Create(ctx, &notifDto.CreateNotificationRequest{
+		UserID:  conn.UserID,
+		Title:   "Cần kết nối lại Google Calendar",
+		Message: message,
+		Type:    "calendar_reconnect",
+		Data: map[string]interface{}{
+			"provider":      conn.Provider,
+			"reconnect_url": reconnectURL(conn.Provider),
+			"reason":        reason,
+		},
+	}); err != nil {
+		logger.Error("CalendarService:markReconnectRequired:CreateNotification:Error", "user_id", conn.UserID, "error", err)
+	}
+}
+
+// connectionHealth fills the health and last successful sync of a connection response
+func connectionHealth(resp *dto.CalendarConnectionResponse, conn *entity.CalendarConnection, health *entity.ConnectionHealth, state *entity.CalendarSyncState) {
+	resp.Health = string(entity.ConnectionHealthy)
+	if health != nil {
+		if health.NeedsReconnect(conn.UpdatedAt) {
+			resp.Health = string(entity.ConnectionUnhealthy)
+			resp.UnhealthySince = health.FailedAt.Format(time.RFC3339)
+			resp.ReconnectURL = reconnectURL(conn.Provider)
+			if health.LastError != nil {
+				resp.HealthError = *health.LastError
+			}
+		}
+		if health.LastRefreshedAt != nil {
+			resp.LastRefreshedAt = health.LastRefreshedAt.Format(time.RFC3339)
+		}
+	}
+	if state != nil && state.LastSuccessAt != nil {
+		resp.LastSyncedAt = state.LastSuccessAt.Format(time.RFC3339)
+	}
+}
+
+// reconnectURL is where the user signs in with the provider again to grant fresh tokens
+func reconnectURL(provider string) string {
+	cfg := config.Get()
+	base := strings.TrimRight(cfg.Server.BaseURL, "/")
+	if base == "" {
+		host := cfg.Server.Host
+		if host == "" || host == "0.0.0.0" {
+			host = "localhost"
+		}
+		base = fmt.Sprintf("http://%s:%d", host, cfg.Server.Port)
+	}
+	return base + "/api/v1/auth/" + provider
+}
+
+func connectionResponseID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,688 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/dto"
+	"go-api-starter/modules/calendar/entity"
+	"go-api-starter/workers"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// focusEventSummary is the title of planned blocks; analytics counts events with this title as focus time
+	focusEventSummary = "Focus time"
+	// focusPlanWeeks is how many weeks (starting with the current one) the planner keeps filled
+	focusPlanWeeks = 2
+	// focusGranularity aligns block boundaries and lengths
+	focusGranularity = 15 * time.Minute
+	// focusRebalanceDelay debounces rebalances when several meetings land at once
+	focusRebalanceDelay = time.Minute
+
+	defaultFocusTimezone        = "Asia/Ho_Chi_Minh"
+	defaultFocusTargetMinutes   = 600
+	defaultFocusMinBlockMinutes = 60
+	defaultFocusMaxBlockMinutes = 120
+	maxFocusTargetMinutes       = 40 * 60
+	maxFocusBlockMinutes        = 8 * 60
+	maxFocusWindows             = 21
+)
+
+// defaultFocusWindows are used when a policy has no preferred windows: working hours, Monday to Friday
+var defaultFocusWindows = entity.FocusWindows{{Start: "08:00", End: "18:00"}}
+
+// GetFocusPolicy returns the user's focus-time policy (a disabled default when none is saved)
+// with the blocks planned for this and next week
+func (s *calendarService) GetFocusPolicy(ctx context.Context, userID uuid.UUID) (*dto.FocusTimePolicyResponse, error) {
+	policy, err := s.loadFocusPolicy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := focusLocation(policy.Timezone)
+	from := focusWeekStart(time.Now().In(loc))
+	blocks, err := s.repo.GetActiveFocusBlocks(ctx, []uuid.UUID{userID}, from, from.AddDate(0, 0, 7*focusPlanWeeks))
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get focus time blocks", err)
+	}
+
+	return toFocusPolicyResponse(policy, blocks), nil
+}
+
+// UpdateFocusPolicy validates and saves the user's focus-time policy, then queues a rebalance
+// so the calendar reflects it (disabling the policy removes upcoming blocks)
+func (s *calendarService) UpdateFocusPolicy(ctx context.Context, userID uuid.UUID, req *dto.UpdateFocusTimePolicyRequest) (*dto.FocusTimePolicyResponse, error) {
+	policy, err := s.loadFocusPolicy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if req.TargetHoursPerWeek != nil {
+		policy.TargetMinutesPerWeek = int(*req.TargetHoursPerWeek * 60)
+	}
+	if req.MinBlockMinutes != nil {
+		policy.MinBlockMinutes = *req.MinBlockMinutes
+	}
+	if req.MaxBlockMinutes != nil {
+		policy.MaxBlockMinutes = *req.MaxBlockMinutes
+	}
+	if req.PreferredWindows != nil {
+		windows := make(entity.FocusWindows, 0, len(req.PreferredWindows))
+		for _, w := range req.PreferredWindows {
+			windows = append(windows, entity.FocusWindow{Weekdays: w.Weekdays, Start: w.Start, End: w.End})
+		}
+		policy.PreferredWindows = windows
+	}
+	if req.Timezone != nil {
+		policy.Timezone = *req.Timezone
+	}
+
+	if err := validateFocusPolicy(policy); err != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, err.Error(), nil)
+	}
+
+	if policy.Enabled {
+		conn, _ := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+		if conn == nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "Connect Google Calendar before enabling focus time", nil)
+		}
+	}
+
+	if err := s.repo.SaveFocusPolicy(ctx, policy); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save focus time policy", err)
+	}
+
+	s.enqueueFocusPlan(userID)
+
+	return s.GetFocusPolicy(ctx, userID)
+}
+
+// HandleFocusTimeTask is the asynq handler for TopicQueueFocusTime
+func (s *calendarService) HandleFocusTimeTask(ctx context.Context, payload []byte) error {
+	var task dto.FocusTimeTask
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &task); err != nil {
+			logger.Error("CalendarService:HandleFocusTimeTask:Unmarshal:Error", "error", err)
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+	}
+	if task.UserID == "" {
+		return s.PlanAllFocusTime(ctx)
+	}
+	userID, err := uuid.Parse(task.UserID)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	_, err = s.PlanFocusTime(ctx, userID)
+	if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrNotFound {
+		// Policy or connection removed since the task was queued
+		return nil
+	}
+	return err
+}
+
+// PlanAllFocusTime rebalances focus time for every enabled policy. Failures are logged and do not stop the run.
+func (s *calendarService) PlanAllFocusTime(ctx context.Context) error {
+	policies, err := s.repo.GetEnabledFocusPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, policy := range policies {
+		if _, err := s.PlanFocusTime(ctx, policy.UserID); err != nil {
+			logger.Error("CalendarService:PlanAllFocusTime:Error", "user_id", policy.UserID, "error", err)
+			failed++
+		}
+	}
+
+	logger.Info("CalendarService:PlanAllFocusTime:Done", "users", len(policies), "failed", failed)
+	return nil
+}
+
+// PlanFocusTime rebalances the user's focus-time blocks for this and next week:
+//   - upcoming blocks that now overlap a meeting are deleted from Google
+//   - each week is topped up to the target with blocks placed in the largest free gaps of the preferred windows
+//
+// A disabled policy removes all upcoming blocks.
+func (s *calendarService) PlanFocusTime(ctx context.Context, userID uuid.UUID) (*dto.FocusTimePlanResponse, error) {
+	policy, err := s.repo.GetFocusPolicy(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get focus time policy", err)
+	}
+	if policy == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Focus time is not configured", nil)
+	}
+
+	conn, err := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+	if err != nil || conn == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "No Google Calendar connected", err)
+	}
+	accessToken, err := s.ensureValidToken(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := focusLocation(policy.Timezone)
+	now := time.Now().In(loc)
+	horizonStart := focusWeekStart(now)
+	horizonEnd := horizonStart.AddDate(0, 0, 7*focusPlanWeeks)
+
+	blocks, err := s.repo.GetActiveFocusBlocks(ctx, []uuid.UUID{userID}, horizonStart, horizonEnd)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get focus time blocks", err)
+	}
+
+	result := &dto.FocusTimePlanResponse{PlannedMinutes: map[string]int{}}
+
+	var meetings []candidateSlot
+	if policy.Enabled {
+		own := make(map[string]bool, len(blocks))
+		for _, block := range blocks {
+			own[block.ProviderEventID] = true
+		}
+		meetings, err = s.listGoogleMeetings(ctx, userID, accessToken, now, horizonEnd, own)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrThirdParty, "Failed to list calendar events", err)
+		}
+		// No focus time on out-of-office days
+		outOfOffice := s.outOfOfficeBusy(ctx, []uuid.UUID{userID}, now, horizonEnd)[userID.String()]
+		meetings = mergeIntervals(append(meetings, parseTimeSlots(outOfOffice)...))
+	}
+
+	kept := make([]entity.FocusTimeBlock, 0, len(blocks))
+	for _, block := range blocks {
+		upcoming := block.EndTime.After(now)
+		if upcoming && (!policy.Enabled || overlapsAny(block.StartTime, block.EndTime, meetings)) {
+			if err := s.deleteFocusBlock(ctx, accessToken, &block); err != nil {
+				logger.Error("CalendarService:PlanFocusTime:DeleteBlock:Error", "user_id", userID, "block_id", block.ID, "error", err)
+				kept = append(kept, block)
+				continue
+			}
+			result.Removed++
+			continue
+		}
+		kept = append(kept, block)
+	}
+
+	if policy.Enabled {
+		for week := 0; week < focusPlanWeeks; week++ {
+			weekStart := horizonStart.AddDate(0, 0, 7*week)
+			weekEnd := weekStart.AddDate(0, 0, 7)
+
+			planned := 0
+			for _, block := range kept {
+				if !block.StartTime.Before(weekStart) && block.StartTime.Before(weekEnd) {
+					planned += int(block.EndTime.Sub(block.StartTime).Minutes())
+				}
+			}
+
+			from := weekStart
+			if now.After(from) {
+				from = now
+			}
+			gaps := subtractIntervals(focusWindowIntervals(policy, loc, from, weekEnd), append(focusIntervals(kept), meetings...))
+			sort.SliceStable(gaps, func(i, j int) bool {
+				li, lj := gaps[i].end.Sub(gaps[i].start), gaps[j].end.Sub(gaps[j].start)
+				if li != lj {
+					return li > lj
+				}
+				return gaps[i].start.Before(gaps[j].start)
+			})
+
+			for _, gap := range gaps {
+				need := policy.TargetMinutesPerWeek - planned
+				if need < policy.MinBlockMinutes {
+					break
+				}
+				length := gap.end.Sub(gap.start)
+				if maxLength := time.Duration(policy.MaxBlockMinutes) * time.Minute; length > maxLength {
+					length = maxLength
+				}
+				if remaining := time.Duration(need) * time.Minute; length > remaining {
+					length = remaining
+				}
+				length = length.Truncate(focusGranularity)
+				if length < time.Duration(policy.MinBlockMinutes)*time.Minute {
+					continue
+				}
+
+				block, err := s.createFocusBlock(ctx, accessToken, userID, gap.start, gap.start.Add(length), loc)
+				if err != nil {
+					logger.Error("CalendarService:PlanFocusTime:CreateBlock:Error", "user_id", userID, "error", err)
+					continue
+				}
+				kept = append(kept, *block)
+				planned += int(length.Minutes())
+				result.Created++
+			}
+
+			result.PlannedMinutes[weekStart.Format("2006-01-02")] = planned
+		}
+	}
+
+	policy.LastPlannedAt = &now
+	if err := s.repo.SaveFocusPolicy(ctx, policy); err != nil {
+		logger.Error("CalendarService:PlanFocusTime:SavePolicy:Error", "user_id", userID, "error", err)
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].StartTime.Before(kept[j].StartTime) })
+	result.Blocks = toFocusBlockResponses(kept, loc)
+
+	logger.Info("CalendarService:PlanFocusTime:Done", "user_id", userID, "created", result.Created, "removed", result.Removed)
+	return result, nil
+}
+
+// requestFocusRebalance queues a focus-time rebalance after the user's calendar changed
+func (s *calendarService) requestFocusRebalance(ctx context.Context, userID uuid.UUID) {
+	policy, err := s.repo.GetFocusPolicy(ctx, userID)
+	if err != nil || policy == nil || !policy.Enabled {
+		return
+	}
+	s.enqueueFocusPlan(userID)
+}
+
+func (s *calendarService) enqueueFocusPlan(userID uuid.UUID) {
+	payload, _ := json.Marshal(dto.FocusTimeTask{UserID: userID.String()})
+	_, err := workers.EnqueueIn(constants.TopicQueueFocusTime, payload, focusRebalanceDelay, asynq.Unique(focusRebalanceDelay))
+	if err != nil && !goerrors.Is(err, asynq.ErrDuplicateTask) {
+		logger.Warn("CalendarService:enqueueFocusPlan:Error", "user_id", userID, "error", err)
+	}
+}
+
+// splitFocusBusy removes the participants' focus blocks from their hard busy slots and returns them
+// separately, so FindAvailableSlots can treat focus time as soft-busy
+func (s *calendarService) splitFocusBusy(ctx context.Context, userIDs []uuid.UUID, busyData []dto.UserFreeBusy, from, to time.Time) []candidateSlot {
+	blocks, err := s.repo.GetActiveFocusBlocks(ctx, userIDs, from, to)
+	if err != nil || len(blocks) == 0 {
+		// Without block data focus time simply stays hard-busy
+		return nil
+	}
+
+	byUser := make(map[string][]entity.FocusTimeBlock)
+	for _, block := range blocks {
+		byUser[block.UserID.String()] = append(byUser[block.UserID.String()], block)
+	}
+
+	for i := range busyData {
+		userBlocks := byUser[busyData[i].UserID]
+		if len(userBlocks) == 0 {
+			continue
+		}
+		hard := subtractIntervals(parseTimeSlots(busyData[i].BusySlots), focusIntervals(userBlocks))
+		busyData[i].BusySlots = make([]dto.TimeSlot, 0, len(hard))
+		for _, interval := range hard {
+			busyData[i].BusySlots = append(busyData[i].BusySlots, dto.TimeSlot{
+				Start: interval.start.Format(time.RFC3339),
+				End:   interval.end.Format(time.RFC3339),
+			})
+		}
+	}
+
+	return focusIntervals(blocks)
+}
+
+// listGoogleMeetings returns the busy intervals of the user's Google events in [from, to), leaving out
+// our own focus blocks, free (transparent) events and invitations the user declined
+func (s *calendarService) listGoogleMeetings(ctx context.Context, userID uuid.UUID, accessToken string, from, to time.Time, exclude map[string]bool) ([]candidateSlot, error) {
+	var meetings []candidateSlot
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("singleEvents", "true")
+		query.Set("maxResults", "250")
+		query.Set("timeMin", from.Format(time.RFC3339))
+		query.Set("timeMax", to.Format(time.RFC3339))
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		resp, err := s.doGoogleRequest(ctx, userID, http.MethodGet, googleEventsAPI+"?"+query.Encode(), accessToken, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("google calendar API returned status %d", resp.StatusCode)
+		}
+
+		var page googleEventList
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			if exclude[item.ID] || item.Status == "cancelled" || item.Transparency == "transparent" {
+				continue
+			}
+			declined := false
+			for _, a := range item.Attendees {
+				if a.Self && a.ResponseStatus == "declined" {
+					declined = true
+				}
+			}
+			if declined {
+				continue
+			}
+			start, _ := parseGoogleEventTime(item.Start)
+			end, _ := parseGoogleEventTime(item.End)
+			if !start.IsZero() && start.Before(end) {
+				meetings = append(meetings, candidateSlot{start: start, end: end})
+			}
+		}
+
+		if page.NextPageToken == "" {
+			return mergeIntervals(meetings), nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (s *calendarService) createFocusBlock(ctx context.Context, accessToken string, userID uuid.UUID, start, end time.Time, loc *time.Location) (*entity.FocusTimeBlock, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"summary":      focusEventSummary,
+		"description":  "Blocked automatically to protect focus time. It moves when meetings are booked over it.",
+		"start":        map[string]string{"dateTime": start.In(loc).Format(time.RFC3339), "timeZone": loc.String()},
+		"end":          map[string]string{"dateTime": end.In(loc).Format(time.RFC3339), "timeZone": loc.String()},
+		"transparency": "opaque",
+		"reminders":    map[string]interface{}{"useDefault": false},
+		"extendedProperties": map[string]interface{}{
+			"private": map[string]string{"focusTimeBlock": "true"},
+		},
+	})
+
+	resp, err := s.doGoogleRequest(ctx, userID, http.MethodPost, googleEventsAPI, accessToken, body, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google calendar API returned status %d", resp.StatusCode)
+	}
+
+	var created googleEvent
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	block := &entity.FocusTimeBlock{
+		UserID:          userID,
+		Provider:        dto.ProviderGoogle,
+		ProviderEventID: created.ID,
+		StartTime:       start,
+		EndTime:         end,
+		Status:          entity.FocusBlockStatusActive,
+	}
+	if err := s.repo.CreateFocusBlock(ctx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func (s *calendarService) deleteFocusBlock(ctx context.Context, accessToken string, block *entity.FocusTimeBlock) error {
+	resp, err := s.doGoogleRequest(ctx, block.UserID, http.MethodDelete, googleEventsAPI+"/"+url.PathEscape(block.ProviderEventID), accessToken, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 404/410: already deleted on Google
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone {
+		return fmt.Errorf("google calendar API returned status %d", resp.StatusCode)
+	}
+	return s.repo.RemoveFocusBlock(ctx, block.ID)
+}
+
+func (s *calendarService) loadFocusPolicy(ctx context.Context, userID uuid.UUID) (*entity.FocusTimePolicy, error) {
+	policy, err := s.repo.GetFocusPolicy(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get focus time policy", err)
+	}
+	if policy == nil {
+		policy = &entity.FocusTimePolicy{
+			UserID:               userID,
+			TargetMinutesPerWeek: defaultFocusTargetMinutes,
+			MinBlockMinutes:      defaultFocusMinBlockMinutes,
+			MaxBlockMinutes:      defaultFocusMaxBlockMinutes,
+			PreferredWindows:     entity.FocusWindows{},
+			Timezone:             defaultFocusTimezone,
+		}
+	}
+	return policy, nil
+}
+
+func validateFocusPolicy(policy *entity.FocusTimePolicy) error {
+	if policy.TargetMinutesPerWeek < 0 || policy.TargetMinutesPerWeek > maxFocusTargetMinutes {
+		return fmt.Errorf("target_hours_per_week must be between 0 and %d", maxFocusTargetMinutes/60)
+	}
+	if policy.MinBlockMinutes < int(focusGranularity.Minutes()) || policy.MinBlockMinutes > maxFocusBlockMinutes ||
+		policy.MinBlockMinutes%int(focusGranularity.Minutes()) != 0 {
+		return fmt.Errorf("min_block_minutes must be a multiple of 15 between 15 and %d", maxFocusBlockMinutes)
+	}
+	if policy.MaxBlockMinutes < policy.MinBlockMinutes || policy.MaxBlockMinutes > maxFocusBlockMinutes ||
+		policy.MaxBlockMinutes%int(focusGranularity.Minutes()) != 0 {
+		return fmt.Errorf("max_block_minutes must be a multiple of 15 between min_block_minutes and %d", maxFocusBlockMinutes)
+	}
+	if _, err := time.LoadLocation(policy.Timezone); err != nil || policy.Timezone == "" {
+		return fmt.Errorf("invalid timezone %q", policy.Timezone)
+	}
+	if len(policy.PreferredWindows) > maxFocusWindows {
+		return fmt.Errorf("at most %d preferred windows are allowed", maxFocusWindows)
+	}
+	for _, w := range policy.PreferredWindows {
+		start, err1 := time.Parse("15:04", w.Start)
+		end, err2 := time.Parse("15:04", w.End)
+		if err1 != nil || err2 != nil || !start.Before(end) {
+			return fmt.Errorf("preferred window %s-%s must use HH:MM with start before end", w.Start, w.End)
+		}
+		if end.Sub(start) < time.Duration(policy.MinBlockMinutes)*time.Minute {
+			return fmt.Errorf("preferred window %s-%s is shorter than min_block_minutes", w.Start, w.End)
+		}
+		for _, day := range w.Weekdays {
+			if day < 1 || day > 7 {
+				return fmt.Errorf("weekdays must be between 1 (Monday) and 7 (Sunday)")
+			}
+		}
+	}
+	return nil
+}
+
+// focusWindowIntervals expands the policy's preferred windows into concrete intervals within [from, to),
+// starting no earlier than from rounded up to the block granularity
+func focusWindowIntervals(policy *entity.FocusTimePolicy, loc *time.Location, from, to time.Time) []candidateSlot {
+	windows := policy.PreferredWindows
+	if len(windows) == 0 {
+		windows = defaultFocusWindows
+	}
+
+	from = from.In(loc)
+	if rounded := from.Truncate(focusGranularity); rounded.Before(from) {
+		from = rounded.Add(focusGranularity)
+	}
+
+	var intervals []candidateSlot
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, w := range windows {
+			if !focusWindowAppliesTo(w, day.Weekday()) {
+				continue
+			}
+			start, err1 := time.Parse("15:04", w.Start)
+			end, err2 := time.Parse("15:04", w.End)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			interval := candidateSlot{
+				start: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc),
+				end:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc),
+			}
+			if interval.start.Before(from) {
+				interval.start = from
+			}
+			if interval.end.After(to) {
+				interval.end = to
+			}
+			if interval.start.Before(interval.end) {
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+	return mergeIntervals(intervals)
+}
+
+func focusWindowAppliesTo(w entity.FocusWindow, weekday time.Weekday) bool {
+	iso := int(weekday)
+	if iso == 0 {
+		iso = 7
+	}
+	if len(w.Weekdays) == 0 {
+		return iso <= 5
+	}
+	for _, day := range w.Weekdays {
+		if day == iso {
+			return true
+		}
+	}
+	return false
+}
+
+func focusIntervals(blocks []entity.FocusTimeBlock) []candidateSlot {
+	intervals := make([]candidateSlot, 0, len(blocks))
+	for _, block := range blocks {
+		intervals = append(intervals, candidateSlot{start: block.StartTime, end: block.EndTime})
+	}
+	return intervals
+}
+
+func parseTimeSlots(slots []dto.TimeSlot) []candidateSlot {
+	intervals := make([]candidateSlot, 0, len(slots))
+	for _, slot := range slots {
+		start, err1 := time.Parse(time.RFC3339, slot.Start)
+		end, err2 := time.Parse(time.RFC3339, slot.End)
+		if err1 == nil && err2 == nil && start.Before(end) {
+			intervals = append(intervals, candidateSlot{start: start, end: end})
+		}
+	}
+	return intervals
+}
+
+// mergeIntervals sorts intervals and merges the overlapping or touching ones
+func mergeIntervals(intervals []candidateSlot) []candidateSlot {
+	sorted := append([]candidateSlot(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	var merged []candidateSlot
+	for _, curr := range sorted {
+		if len(merged) == 0 || merged[len(merged)-1].end.Before(curr.start) {
+			merged = append(merged, curr)
+		} else if curr.end.After(merged[len(merged)-1].end) {
+			merged[len(merged)-1].end = curr.end
+		}
+	}
+	return merged
+}
+
+// subtractIntervals returns the parts of base not covered by any of cut
+func subtractIntervals(base, cut []candidateSlot) []candidateSlot {
+	cut = mergeIntervals(cut)
+
+	var result []candidateSlot
+	for _, interval := range mergeIntervals(base) {
+		start := interval.start
+		for _, c := range cut {
+			if !c.end.After(start) {
+				continue
+			}
+			if !c.start.Before(interval.end) {
+				break
+			}
+			if c.start.After(start) {
+				result = append(result, candidateSlot{start: start, end: c.start})
+			}
+			start = c.end
+			if !start.Before(interval.end) {
+				break
+			}
+		}
+		if start.Before(interval.end) {
+			result = append(result, candidateSlot{start: start, end: interval.end})
+		}
+	}
+	return result
+}
+
+func overlapsAny(start, end time.Time, intervals []candidateSlot) bool {
+	for _, interval := range intervals {
+		if start.Before(interval.end) && end.After(interval.start) {
+			return true
+		}
+	}
+	return false
+}
+
+func focusLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultFocusTimezone)
+	}
+	return loc
+}
+
+// focusWeekStart returns Monday 00:00 of the week containing t, in t's location
+func focusWeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	day := t.AddDate(0, 0, -offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func toFocusBlockResponses(blocks []entity.FocusTimeBlock, loc *time.Location) []dto.FocusBlockResponse {
+	result := make([]dto.FocusBlockResponse, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, dto.FocusBlockResponse{
+			ID:              block.ID.String(),
+			ProviderEventID: block.ProviderEventID,
+			StartTime:       block.StartTime.In(loc).Format(time.RFC3339),
+			EndTime:         block.EndTime.In(loc).Format(time.RFC3339),
+		})
+	}
+	return result
+}
+
+func toFocusPolicyResponse(policy *entity.FocusTimePolicy, blocks []entity.FocusTimeBlock) *dto.FocusTimePolicyResponse {
+	loc := focusLocation(policy.Timezone)
+	windows := make([]dto.FocusWindowDTO, 0, len(policy.PreferredWindows))
+	for _, w := range policy.PreferredWindows {
+		windows = append(windows, dto.FocusWindowDTO{Weekdays: w.Weekdays, Start: w.Start, End: w.End})
+	}
+
+	response := &dto.FocusTimePolicyResponse{
+		Enabled:            policy.Enabled,
+		TargetHoursPerWeek: float64(policy.TargetMinutesPerWeek) / 60,
+		MinBlockMinutes:    policy.MinBlockMinutes,
+		MaxBlockMinutes:    policy.MaxBlockMinutes,
+		PreferredWindows:   windows,
+		Timezone:           policy.Timezone,
+		Blocks:             toFocusBlockResponses(blocks, loc),
+	}
+	if policy.LastPlannedAt != nil {
+		response.LastPlannedAt = policy.LastPlannedAt.In(loc).Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// slot builds a slot on 2030-03-04 from whole hours
+func slot(startHour, endHour int) candidateSlot {
+	day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+	return candidateSlot{start: day.Add(time.Duration(startHour) * time.Hour), end: day.Add(time.Duration(endHour) * time.Hour)}
+}
+
+func TestMergeIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []candidateSlot
+		want      []candidateSlot
+	}{
+		{name: "empty", intervals: nil, want: nil},
+		{name: "disjoint stay apart", intervals: []candidateSlot{slot(9, 10), slot(11, 12)}, want: []candidateSlot{slot(9, 10), slot(11, 12)}},
+		{name: "unsorted input is sorted", intervals: []candidateSlot{slot(13, 14), slot(9, 10)}, want: []candidateSlot{slot(9, 10), slot(13, 14)}},
+		{name: "overlapping merge", intervals: []candidateSlot{slot(9, 11), slot(10, 12)}, want: []candidateSlot{slot(9, 12)}},
+		{name: "touching merge", intervals: []candidateSlot{slot(9, 10), slot(10, 11)}, want: []candidateSlot{slot(9, 11)}},
+		{name: "contained is absorbed", intervals: []candidateSlot{slot(9, 17), slot(10, 11)}, want: []candidateSlot{slot(9, 17)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeIntervals(tt.intervals); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeIntervals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubtractIntervals(t *testing.T) {
+	tests := []struct {
+		name string
+		base []candidateSlot
+		cut  []candidateSlot
+		want []candidateSlot
+	}{
+		{name: "nothing to cut", base: []candidateSlot{slot(9, 17)}, cut: nil, want: []candidateSlot{slot(9, 17)}},
+		{name: "cut in the middle", base: []candidateSlot{slot(9, 17)}, cut: []candidateSlot{slot(12, 13)}, want: []candidateSlot{slot(9, 12), slot(13, 17)}},
+		{name: "cut the start", base: []candidateSlot{slot(9, 17)}, cut: []candidateSlot{slot(8, 10)}, want: []candidateSlot{slot(10, 17)}},
+		{name: "cut the end", base: []candidateSlot{slot(9, 17)}, cut: []candidateSlot{slot(16, 18)}, want: []candidateSlot{slot(9, 16)}},
+		{name: "cut everything", base: []candidateSlot{slot(9, 17)}, cut: []candidateSlot{slot(8, 18)}, want: nil},
+		{name: "cut outside", base: []candidateSlot{slot(9, 12)}, cut: []candidateSlot{slot(13, 14)}, want: []candidateSlot{slot(9, 12)}},
+		{
+			name: "overlapping cuts across several bases",
+			base: []candidateSlot{slot(9, 12), slot(13, 17)},
+			cut:  []candidateSlot{slot(10, 11), slot(10, 14), slot(15, 16)},
+			want: []candidateSlot{slot(9, 10), slot(14, 15), slot(16, 17)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subtractIntervals(tt.base, tt.cut); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subtractIntervals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
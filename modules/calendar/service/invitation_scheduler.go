@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-starter/modules/calendar/dto"
+	invitService "go-api-starter/modules/invitation/service"
+
+	"github.com/google/uuid"
+)
+
+// InvitationScheduler exposes free/busy lookups and rescheduling to the invitation module
+type InvitationScheduler struct {
+	calendar CalendarService
+}
+
+func NewInvitationScheduler(calendar CalendarService) *InvitationScheduler {
+	return &InvitationScheduler{calendar: calendar}
+}
+
+func (s *InvitationScheduler) IsBusy(ctx context.Context, userIDs []uuid.UUID, start, end time.Time) (bool, error) {
+	results, err := s.calendar.GetFreeBusyForUsers(ctx, userIDs, start, end)
+	if err != nil {
+		return false, err
+	}
+	for _, user := range results {
+		for _, slot := range user.BusySlots {
+			busyStart, errStart := time.Parse(time.RFC3339, slot.Start)
+			busyEnd, errEnd := time.Parse(time.RFC3339, slot.End)
+			if errStart != nil || errEnd != nil {
+				continue
+			}
+			if start.Before(busyEnd) && end.After(busyStart) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *InvitationScheduler) Reschedule(ctx context.Context, creatorID uuid.UUID, eventGoogleID string, startTime, endTime, timezone string) error {
+	req := &dto.UpdateEventRequest{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+	}
+	if timezone != "" {
+		req.Timezone = &timezone
+	}
+	if _, err := s.calendar.UpdateEvent(ctx, creatorID, eventGoogleID, req); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+	return nil
+}
+
+func (s *InvitationScheduler) OutOfOffice(ctx context.Context, userID uuid.UUID, start, end time.Time) (*invitService.OutOfOffice, error) {
+	period, err := s.calendar.GetOutOfOfficeDuring(ctx, userID, start, end)
+	if err != nil || period == nil {
+		return nil, err
+	}
+
+	until, _ := time.Parse("2006-01-02", period.EndDate)
+	ooo := &invitService.OutOfOffice{
+		Message: period.Message,
+		Until:   until,
+	}
+	if delegateID, err := uuid.Parse(period.DelegateUserID); err == nil {
+		ooo.DelegateID = &delegateID
+	}
+	return ooo, nil
+}
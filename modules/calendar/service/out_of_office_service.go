@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/dto"
+	"go-api-starter/modules/calendar/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxOutOfOfficeDays bounds a single period
+	maxOutOfOfficeDays = 366
+	// outOfOfficeNoticeAhead is how early booking pages start announcing an upcoming period
+	outOfOfficeNoticeAhead = 30 * 24 * time.Hour
+	outOfOfficeSummary     = "Out of office"
+)
+
+// CreateOutOfOffice saves a leave period for the user, optionally blocking it on Google Calendar
+func (s *calendarService) CreateOutOfOffice(ctx context.Context, userID uuid.UUID, req *dto.CreateOutOfOfficeRequest) (*dto.OutOfOfficeResponse, error) {
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = defaultFocusTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "Invalid timezone", err)
+	}
+
+	firstDay, err1 := time.ParseInLocation("2006-01-02", req.StartDate, loc)
+	lastDay, err2 := time.ParseInLocation("2006-01-02", req.EndDate, loc)
+	if err1 != nil || err2 != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "start_date and end_date must use YYYY-MM-DD", nil)
+	}
+	if lastDay.Before(firstDay) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "end_date must not be before start_date", nil)
+	}
+	end := lastDay.AddDate(0, 0, 1)
+	if end.Sub(firstDay) > maxOutOfOfficeDays*24*time.Hour {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("An out-of-office period can last at most %d days", maxOutOfOfficeDays), nil)
+	}
+	if !end.After(time.Now()) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "The out-of-office period is already over", nil)
+	}
+
+	period := &entity.OutOfOfficePeriod{
+		UserID:     userID,
+		StartTime:  firstDay,
+		EndTime:    end,
+		Timezone:   timezone,
+		Message:    optionalString(strings.TrimSpace(req.Message)),
+		AutoAction: entity.OutOfOfficeActionDecline,
+	}
+
+	switch entity.OutOfOfficeAction(req.AutoAction) {
+	case "", entity.OutOfOfficeActionDecline:
+	case entity.OutOfOfficeActionDelegate:
+		delegateID, err := uuid.Parse(req.DelegateUserID)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "delegate_user_id is required to delegate", nil)
+		}
+		if delegateID == userID {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "You cannot delegate to yourself", nil)
+		}
+		delegate, err := s.userRepo.PrivateGetUser(ctx, delegateID)
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get delegate", err)
+		}
+		if delegate == nil {
+			return nil, errors.NewAppError(errors.ErrNotFound, "Delegate not found", nil)
+		}
+		period.AutoAction = entity.OutOfOfficeActionDelegate
+		period.DelegateUserID = &delegateID
+	default:
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "auto_action must be decline or delegate", nil)
+	}
+
+	existing, err := s.repo.GetOutOfOfficeInRange(ctx, []uuid.UUID{userID}, period.StartTime, period.EndTime)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to check out-of-office periods", err)
+	}
+	if len(existing) > 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "The period overlaps an existing out-of-office period", nil)
+	}
+
+	if req.CreateProviderEvent {
+		// The period is still saved when Google rejects the event; the response shows no provider event
+		eventID, err := s.createOutOfOfficeEvent(ctx, period)
+		if err != nil {
+			logger.Warn("CalendarService:CreateOutOfOffice:ProviderEvent:Error", "user_id", userID, "error", err)
+		} else {
+			period.ProviderEventID = &eventID
+		}
+	}
+
+	if err := s.repo.CreateOutOfOffice(ctx, period); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "Failed to save out-of-office period", err)
+	}
+
+	// Focus time planned on the days off is no longer needed
+	s.requestFocusRebalance(ctx, userID)
+
+	response := toOutOfOfficeResponse(period)
+	return &response, nil
+}
+
+// ListOutOfOffice returns the user's current and upcoming out-of-office periods
+func (s *calendarService) ListOutOfOffice(ctx context.Context, userID uuid.UUID) ([]dto.OutOfOfficeResponse, error) {
+	periods, err := s.repo.GetUpcomingOutOfOffice(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get out-of-office periods", err)
+	}
+
+	result := make([]dto.OutOfOfficeResponse, 0, len(periods))
+	for i := range periods {
+		result = append(result, toOutOfOfficeResponse(&periods[i]))
+	}
+	return result, nil
+}
+
+// DeleteOutOfOffice removes one of the user's periods and its Google event
+func (s *calendarService) DeleteOutOfOffice(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	period, err := s.repo.GetOutOfOfficeByID(ctx, id)
+	if err != nil {
+		return errors.NewAppError(errors.ErrGetFailed, "Failed to get out-of-office period", err)
+	}
+	if period == nil {
+		return errors.NewAppError(errors.ErrNotFound, "Out-of-office period not found", nil)
+	}
+	if period.UserID != userID {
+		return errors.NewAppError(errors.ErrForbidden, "Not your out-of-office period", nil)
+	}
+
+	if period.ProviderEventID != nil {
+		if err := s.deleteOutOfOfficeEvent(ctx, userID, *period.ProviderEventID); err != nil {
+			logger.Warn("CalendarService:DeleteOutOfOffice:ProviderEvent:Error", "user_id", userID, "error", err)
+		}
+	}
+
+	if err := s.repo.DeleteOutOfOffice(ctx, id); err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "Failed to delete out-of-office period", err)
+	}
+
+	s.requestFocusRebalance(ctx, userID)
+	return nil
+}
+
+// GetOutOfOfficeDuring returns the user's out-of-office period overlapping [start, end), or nil when available
+func (s *calendarService) GetOutOfOfficeDuring(ctx context.Context, userID uuid.UUID, start, end time.Time) (*dto.OutOfOfficeResponse, error) {
+	periods, err := s.repo.GetOutOfOfficeInRange(ctx, []uuid.UUID{userID}, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	response := toOutOfOfficeResponse(&periods[0])
+	return &response, nil
+}
+
+// GetOutOfOfficeNotice returns the period booking pages should announce: the current one,
+// otherwise the next one starting within outOfOfficeNoticeAhead
+func (s *calendarService) GetOutOfOfficeNotice(ctx context.Context, userID uuid.UUID) (*dto.OutOfOfficeResponse, error) {
+	now := time.Now()
+	return s.GetOutOfOfficeDuring(ctx, userID, now, now.Add(outOfOfficeNoticeAhead))
+}
+
+// outOfOfficeBusy returns the out-of-office periods of the users as busy slots clipped to [from, to), keyed by user ID
+func (s *calendarService) outOfOfficeBusy(ctx context.Context, userIDs []uuid.UUID, from, to time.Time) map[string][]dto.TimeSlot {
+	periods, err := s.repo.GetOutOfOfficeInRange(ctx, userIDs, from, to)
+	if err != nil {
+		return nil
+	}
+
+	busy := make(map[string][]dto.TimeSlot)
+	for _, period := range periods {
+		start, end := period.StartTime, period.EndTime
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		busy[period.UserID.String()] = append(busy[period.UserID.String()], dto.TimeSlot{
+			Start: start.Format(time.RFC3339),
+			End:   end.Format(time.RFC3339),
+		})
+	}
+	return busy
+}
+
+// createOutOfOfficeEvent creates an "Out of office" event on Google that also declines new invitations on Google's side
+func (s *calendarService) createOutOfOfficeEvent(ctx context.Context, period *entity.OutOfOfficePeriod) (string, error) {
+	conn, err := s.repo.GetConnectionByUserAndProvider(ctx, period.UserID, dto.ProviderGoogle)
+	if err != nil || conn == nil {
+		return "", fmt.Errorf("no Google Calendar connected")
+	}
+	accessToken, err := s.ensureValidToken(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+
+	declineMessage := "I'm out of office."
+	if period.Message != nil {
+		declineMessage = *period.Message
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"summary":      outOfOfficeSummary,
+		"eventType":    "outOfOffice",
+		"start":        map[string]string{"dateTime": period.StartTime.Format(time.RFC3339), "timeZone": period.Timezone},
+		"end":          map[string]string{"dateTime": period.EndTime.Format(time.RFC3339), "timeZone": period.Timezone},
+		"transparency": "opaque",
+		"outOfOfficeProperties": map[string]string{
+			"autoDeclineMode": "declineOnlyNewConflictingInvitations",
+			"declineMessage":  declineMessage,
+		},
+	})
+
+	resp, err := s.doGoogleRequest(ctx, period.UserID, http.MethodPost, googleEventsAPI, accessToken, body, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("google calendar API returned status %d", resp.StatusCode)
+	}
+
+	var created googleEvent
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (s *calendarService) deleteOutOfOfficeEvent(ctx context.Context, userID uuid.UUID, eventID string) error {
+	conn, err := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+	if err != nil || conn == nil {
+		return fmt.Errorf("no Google Calendar connected")
+	}
+	accessToken, err := s.ensureValidToken(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.doGoogleRequest(ctx, userID, http.MethodDelete, googleEventsAPI+"/"+url.PathEscape(eventID), accessToken, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone {
+		return fmt.Errorf("google calendar API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func toOutOfOfficeResponse(period *entity.OutOfOfficePeriod) dto.OutOfOfficeResponse {
+	loc := focusLocation(period.Timezone)
+	now := time.Now()
+	response := dto.OutOfOfficeResponse{
+		ID:         period.ID.String(),
+		UserID:     period.UserID.String(),
+		StartDate:  period.StartTime.In(loc).Format("2006-01-02"),
+		EndDate:    period.EndTime.In(loc).AddDate(0, 0, -1).Format("2006-01-02"),
+		StartTime:  period.StartTime.In(loc).Format(time.RFC3339),
+		EndTime:    period.EndTime.In(loc).Format(time.RFC3339),
+		Timezone:   period.Timezone,
+		AutoAction: string(period.AutoAction),
+		Active:     !now.Before(period.StartTime) && now.Before(period.EndTime),
+	}
+	if period.Message != nil {
+		response.Message = *period.Message
+	}
+	if period.DelegateUserID != nil {
+		response.DelegateUserID = period.DelegateUserID.String()
+	}
+	if period.ProviderEventID != nil {
+		response.ProviderEventID = *period.ProviderEventID
+	}
+	return response
+}
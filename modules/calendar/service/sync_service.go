@@ -0,0 +1,604 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/googlecalendar"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/calendar/dto"
+	"go-api-starter/modules/calendar/entity"
+	meetEntity "go-api-starter/modules/meeting/entity"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// syncWindowPast is how far back a full sync mirrors provider events
+const syncWindowPast = 90 * 24 * time.Hour
+
+var (
+	// errSyncTokenInvalid means the provider dropped the sync token (HTTP 410) and a full sync is needed
+	errSyncTokenInvalid = fmt.Errorf("sync token is no longer valid")
+	// errSyncConflict means the provider copy changed since our etag (HTTP 412); the next pull resolves it
+	errSyncConflict = fmt.Errorf("provider event changed since last sync")
+)
+
+type googleEventTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type googleEvent struct {
+	ID           string          `json:"id"`
+	Etag         string          `json:"etag"`
+	Status       string          `json:"status"`
+	Summary      string          `json:"summary"`
+	Description  string          `json:"description"`
+	Location     string          `json:"location"`
+	EventType    string          `json:"eventType"`
+	Transparency string          `json:"transparency"`
+	Updated      string          `json:"updated"`
+	Start        googleEventTime `json:"start"`
+	End          googleEventTime `json:"end"`
+	Organizer    struct {
+		Email string `json:"email"`
+		Self  bool   `json:"self"`
+	} `json:"organizer"`
+	Attendees []struct {
+		Email          string `json:"email"`
+		ResponseStatus string `json:"responseStatus"`
+		Self           bool   `json:"self"`
+	} `json:"attendees"`
+}
+
+type googleEventList struct {
+	Items         []googleEvent `json:"items"`
+	NextPageToken string        `json:"nextPageToken"`
+	NextSyncToken string        `json:"nextSyncToken"`
+}
+
+// HandleSyncTask is the asynq handler for TopicQueueCalendarSync
+func (s *calendarService) HandleSyncTask(ctx context.Context, payload []byte) error {
+	var task dto.CalendarSyncTask
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &task); err != nil {
+			logger.Error("CalendarService:HandleSyncTask:Unmarshal:Error", "error", err)
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+	}
+	if task.UserID == "" {
+		return s.SyncAllCalendars(ctx)
+	}
+	userID, err := uuid.Parse(task.UserID)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	_, err = s.SyncCalendar(ctx, userID)
+	return err
+}
+
+// SyncAllCalendars runs an incremental sync for every user with a Google connection.
+// Failures are recorded on each connection's sync state and do not stop the run.
+func (s *calendarService) SyncAllCalendars(ctx context.Context) error {
+	userIDs, err := s.repo.GetSyncUserIDs(ctx, dto.ProviderGoogle)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, userID := range userIDs {
+		if _, err := s.SyncCalendar(ctx, userID); err != nil {
+			failed++
+		}
+	}
+
+	logger.Info("CalendarService:SyncAllCalendars:Done", "users", len(userIDs), "failed", failed)
+	return nil
+}
+
+// SyncCalendar pulls changes from the user's Google Calendar since the last sync token, reconciles them
+// with linked local events and pushes local edits/cancellations back. Conflict rules:
+//   - a cancellation on either side wins over an edit on the other
+//   - when both sides changed, the most recent change wins (Google wins ties)
+func (s *calendarService) SyncCalendar(ctx context.Context, userID uuid.UUID) (*dto.CalendarSyncStatus, error) {
+	conn, err := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+	if err != nil || conn == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "No Google Calendar connected", err)
+	}
+
+	state, err := s.repo.GetSyncState(ctx, userID, dto.ProviderGoogle)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "Failed to get sync state", err)
+	}
+	if state == nil {
+		state = &entity.CalendarSyncState{UserID: userID, Provider: dto.ProviderGoogle, Status: entity.SyncStatusNever}
+	}
+
+	syncErr := s.runSync(ctx, conn, state)
+	now := time.Now()
+	state.LastSyncedAt = &now
+	if syncErr != nil {
+		msg := syncErr.Error()
+		state.Status = entity.SyncStatusError
+		state.LastError = &msg
+		logger.Error("CalendarService:SyncCalendar:Error", "user_id", userID, "error", syncErr)
+	} else {
+		state.Status = entity.SyncStatusOK
+		state.LastError = nil
+		state.LastSuccessAt = &now
+	}
+
+	if err := s.repo.SaveSyncState(ctx, state); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "Failed to save sync state", err)
+	}
+
+	status := toSyncStatus(state, conn.CalendarEmail)
+	if syncErr != nil {
+		return &status, errors.NewAppError(errors.ErrThirdParty, "Calendar sync failed", syncErr)
+	}
+	return &status, nil
+}
+
+// GetSyncStatus returns the sync state of the user's calendar connections
+func (s *calendarService) GetSyncStatus(ctx context.Context, userID uuid.UUID) ([]dto.CalendarSyncStatus, error) {
+	states, err := s.repo.GetSyncStatesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := ""
+	conn, _ := s.repo.GetConnectionByUserAndProvider(ctx, userID, dto.ProviderGoogle)
+	if conn != nil {
+		email = conn.CalendarEmail
+	}
+
+	result := make([]dto.CalendarSyncStatus, 0, len(states)+1)
+	hasGoogle := false
+	for i := range states {
+		if states[i].Provider == dto.ProviderGoogle {
+			hasGoogle = true
+		}
+		result = append(result, toSyncStatus(&states[i], email))
+	}
+	if !hasGoogle && conn != nil {
+		result = append(result, dto.CalendarSyncStatus{
+			Provider:      dto.ProviderGoogle,
+			CalendarEmail: email,
+			Status:        string(entity.SyncStatusNever),
+		})
+	}
+	return result, nil
+}
+
+// runSync performs one pull/push cycle and updates the counters and sync token on state
+func (s *calendarService) runSync(ctx context.Context, conn *entity.CalendarConnection, state *entity.CalendarSyncState) error {
+	accessToken, err := s.ensureValidToken(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	syncToken := ""
+	if state.SyncToken != nil {
+		syncToken = *state.SyncToken
+	}
+
+	items, nextSyncToken, err := s.listGoogleChanges(ctx, conn.UserID, accessToken, syncToken)
+	if err == errSyncTokenInvalid {
+		logger.Warn("CalendarService:runSync:SyncTokenExpired", "user_id", conn.UserID)
+		syncToken = ""
+		items, nextSyncToken, err = s.listGoogleChanges(ctx, conn.UserID, accessToken, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	if syncToken == "" {
+		if err := s.repo.ClearSyncedEvents(ctx, conn.UserID, dto.ProviderGoogle); err != nil {
+			return err
+		}
+		now := time.Now()
+		state.LastFullSyncAt = &now
+	}
+
+	state.EventsPulled, state.EventsPushed, state.Conflicts = 0, 0, 0
+	calendarChanged := false
+	for i := range items {
+		if items[i].Summary != focusEventSummary {
+			calendarChanged = true
+		}
+		pulled, pushed, conflict, err := s.pullEvent(ctx, conn, accessToken, &items[i])
+		if err != nil {
+			logger.Error("CalendarService:runSync:PullEvent:Error", "user_id", conn.UserID, "event_id", items[i].ID, "error", err)
+			continue
+		}
+		if pulled {
+			state.EventsPulled++
+		}
+		if pushed {
+			state.EventsPushed++
+		}
+		if conflict {
+			state.Conflicts++
+		}
+	}
+
+	pushed, conflicts := s.pushLocalChanges(ctx, conn, accessToken)
+	state.EventsPushed += pushed
+	state.Conflicts += conflicts
+
+	if nextSyncToken != "" {
+		state.SyncToken = &nextSyncToken
+	}
+
+	// Meetings that landed on Google may now overlap focus-time blocks
+	if calendarChanged {
+		s.requestFocusRebalance(ctx, conn.UserID)
+	}
+	return nil
+}
+
+// listGoogleChanges lists events changed since syncToken, or all events in the sync window when syncToken is empty
+func (s *calendarService) listGoogleChanges(ctx context.Context, userID uuid.UUID, accessToken, syncToken string) ([]googleEvent, string, error) {
+	var items []googleEvent
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("singleEvents", "true")
+		query.Set("maxResults", "250")
+		if syncToken != "" {
+			query.Set("syncToken", syncToken)
+		} else {
+			query.Set("timeMin", time.Now().Add(-syncWindowPast).Format(time.RFC3339))
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		resp, err := s.doGoogleRequest(ctx, userID, http.MethodGet, googleEventsAPI+"?"+query.Encode(), accessToken, nil, "")
+		if err != nil {
+			return nil, "", err
+		}
+
+		if resp.StatusCode == http.StatusGone {
+			resp.Body.Close()
+			return nil, "", errSyncTokenInvalid
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, "", fmt.Errorf("Google API error: %s", string(body))
+		}
+
+		var page googleEventList
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, page.Items...)
+		if page.NextPageToken == "" {
+			return items, page.NextSyncToken, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// pullEvent mirrors a changed Google event locally and reconciles the local event linked to it
+func (s *calendarService) pullEvent(ctx context.Context, conn *entity.CalendarConnection, accessToken string, item *googleEvent) (pulled, pushed, conflict bool, err error) {
+	var local *meetEntity.Event
+	if s.meetingRepo != nil {
+		local, err = s.meetingRepo.GetEventByProviderEventID(ctx, conn.UserID, dto.ProviderGoogle, item.ID)
+		if err != nil {
+			return false, false, false, err
+		}
+	}
+
+	if err := s.mirrorEvent(ctx, conn.UserID, item, local); err != nil {
+		return false, false, false, err
+	}
+
+	if local == nil || (local.ProviderEtag != nil && *local.ProviderEtag == item.Etag) {
+		return false, false, false, nil
+	}
+
+	remoteUpdated := parseGoogleTime(item.Updated)
+	local.ProviderEtag = &item.Etag
+	if !remoteUpdated.IsZero() {
+		local.ProviderUpdatedAt = &remoteUpdated
+	}
+
+	// Cancellation on Google wins over any local edit
+	if item.Status == "cancelled" {
+		if local.Status == meetEntity.EventStatusCancelled {
+			return false, false, false, s.meetingRepo.MarkProviderSynced(ctx, local.ID, item.Etag, local.ProviderUpdatedAt)
+		}
+		local.Status = meetEntity.EventStatusCancelled
+		return true, false, false, s.meetingRepo.ApplyProviderChange(ctx, local)
+	}
+
+	if local.HasLocalChanges() {
+		// Local cancellation wins over a Google edit; otherwise the most recent change wins
+		if local.Status == meetEntity.EventStatusCancelled || local.UpdatedAt.After(remoteUpdated) {
+			if err := s.pushEvent(ctx, conn.UserID, accessToken, local, item.Etag); err != nil {
+				return false, false, true, err
+			}
+			return false, true, true, nil
+		}
+		conflict = true
+	}
+
+	applyGoogleEvent(local, item)
+	if err := s.meetingRepo.ApplyProviderChange(ctx, local); err != nil {
+		return false, false, conflict, err
+	}
+	return true, false, conflict, nil
+}
+
+// mirrorEvent stores or removes the local copy of a Google event used for availability and analytics
+func (s *calendarService) mirrorEvent(ctx context.Context, userID uuid.UUID, item *googleEvent, local *meetEntity.Event) error {
+	if item.Status == "cancelled" {
+		// A focus block deleted on Google no longer counts towards the weekly target
+		if err := s.repo.RemoveFocusBlockByProviderEventID(ctx, userID, dto.ProviderGoogle, item.ID); err != nil {
+			return err
+		}
+		return s.repo.DeleteSyncedEvent(ctx, userID, dto.ProviderGoogle, item.ID)
+	}
+
+	start, startAllDay := parseGoogleEventTime(item.Start)
+	end, _ := parseGoogleEventTime(item.End)
+	if start.IsZero() || end.IsZero() {
+		return nil
+	}
+
+	type attendee struct {
+		Email          string `json:"email"`
+		ResponseStatus string `json:"response_status"`
+		Self           bool   `json:"self,omitempty"`
+	}
+	attendees := make([]attendee, 0, len(item.Attendees))
+	for _, a := range item.Attendees {
+		attendees = append(attendees, attendee{Email: a.Email, ResponseStatus: a.ResponseStatus, Self: a.Self})
+	}
+	attendeesJSON, _ := json.Marshal(attendees)
+
+	synced := &entity.SyncedEvent{
+		UserID:          userID,
+		Provider:        dto.ProviderGoogle,
+		ProviderEventID: item.ID,
+		Etag:            optionalString(item.Etag),
+		Summary:         optionalString(item.Summary),
+		Status:          item.Status,
+		EventType:       item.EventType,
+		Transparency:    item.Transparency,
+		StartTime:       start,
+		EndTime:         end,
+		AllDay:          startAllDay,
+		OrganizerEmail:  optionalString(item.Organizer.Email),
+		IsOrganizer:     item.Organizer.Self,
+		Attendees:       string(attendeesJSON),
+	}
+	if synced.Status == "" {
+		synced.Status = "confirmed"
+	}
+	if synced.EventType == "" {
+		synced.EventType = "default"
+	}
+	if synced.Transparency == "" {
+		synced.Transparency = "opaque"
+	}
+	if updated := parseGoogleTime(item.Updated); !updated.IsZero() {
+		synced.ProviderUpdatedAt = &updated
+	}
+	if local != nil {
+		synced.LocalEventID = &local.ID
+	}
+
+	return s.repo.UpsertSyncedEvent(ctx, synced)
+}
+
+// pushLocalChanges sends linked events edited or cancelled locally since their last sync to Google
+func (s *calendarService) pushLocalChanges(ctx context.Context, conn *entity.CalendarConnection, accessToken string) (pushed, conflicts int) {
+	if s.meetingRepo == nil {
+		return 0, 0
+	}
+
+	events, err := s.meetingRepo.GetEventsWithLocalChanges(ctx, conn.UserID, dto.ProviderGoogle)
+	if err != nil {
+		logger.Error("CalendarService:pushLocalChanges:Error", "user_id", conn.UserID, "error", err)
+		return 0, 0
+	}
+
+	for i := range events {
+		etag := ""
+		if events[i].ProviderEtag != nil {
+			etag = *events[i].ProviderEtag
+		}
+		err := s.pushEvent(ctx, conn.UserID, accessToken, &events[i], etag)
+		if err == errSyncConflict {
+			// Google changed too; the next incremental pull sees that change and applies the conflict rules
+			conflicts++
+			continue
+		}
+		if err != nil {
+			logger.Error("CalendarService:pushLocalChanges:PushEvent:Error", "event_id", events[i].ID, "error", err)
+			continue
+		}
+		pushed++
+	}
+	return pushed, conflicts
+}
+
+// pushEvent patches (or deletes, when cancelled) the Google copy of a local event, guarded by etag
+func (s *calendarService) pushEvent(ctx context.Context, userID uuid.UUID, accessToken string, ev *meetEntity.Event, etag string) error {
+	eventURL := fmt.Sprintf("%s/%s", googleEventsAPI, url.PathEscape(*ev.ProviderEventID))
+
+	if ev.Status == meetEntity.EventStatusCancelled {
+		resp, err := s.doGoogleRequest(ctx, userID, http.MethodDelete, eventURL, accessToken, nil, etag)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+			return s.meetingRepo.MarkProviderSynced(ctx, ev.ID, "", nil)
+		case http.StatusPreconditionFailed:
+			return errSyncConflict
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("Google API error: %s", string(body))
+		}
+	}
+
+	patch := map[string]interface{}{
+		"summary": ev.Title,
+	}
+	if ev.Description != nil {
+		patch["description"] = *ev.Description
+	}
+	if ev.Address != nil {
+		patch["location"] = *ev.Address
+	}
+	if ev.StartDate != nil && ev.EndDate != nil {
+		offset := time.Duration(ev.ProviderOffset) * time.Minute
+		timezone := ev.Timezone
+		if timezone == "" {
+			timezone = "Asia/Ho_Chi_Minh"
+		}
+		patch["start"] = googleEventTime{DateTime: ev.StartDate.Add(offset).Format(time.RFC3339), TimeZone: timezone}
+		patch["end"] = googleEventTime{DateTime: ev.EndDate.Add(offset).Format(time.RFC3339), TimeZone: timezone}
+	}
+
+	patchJSON, _ := json.Marshal(patch)
+	resp, err := s.doGoogleRequest(ctx, userID, http.MethodPatch, eventURL, accessToken, patchJSON, etag)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return errSyncConflict
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Google API error: %s", string(body))
+	}
+
+	var updated googleEvent
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return err
+	}
+
+	var updatedAt *time.Time
+	if t := parseGoogleTime(updated.Updated); !t.IsZero() {
+		updatedAt = &t
+	}
+	return s.meetingRepo.MarkProviderSynced(ctx, ev.ID, updated.Etag, updatedAt)
+}
+
+// doGoogleRequest sends a Google Calendar API request for userID through the shared client,
+// which rate limits and retries it
+func (s *calendarService) doGoogleRequest(ctx context.Context, userID uuid.UUID, method, path, accessToken string, body []byte, etag string) (*http.Response, error) {
+	return s.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      method,
+		Path:        path,
+		AccessToken: accessToken,
+		Body:        body,
+		ETag:        etag,
+	})
+}
+
+// applyGoogleEvent copies a Google event's fields onto its linked local event, keeping the stored time offset
+func applyGoogleEvent(local *meetEntity.Event, item *googleEvent) {
+	local.Title = item.Summary
+	if item.Description != "" || local.Description != nil {
+		description := item.Description
+		local.Description = &description
+	}
+
+	if item.Start.DateTime == "" || item.End.DateTime == "" {
+		return
+	}
+	start := parseGoogleTime(item.Start.DateTime)
+	end := parseGoogleTime(item.End.DateTime)
+	if start.IsZero() || end.IsZero() {
+		return
+	}
+
+	offset := time.Duration(local.ProviderOffset) * time.Minute
+	start = start.Add(-offset)
+	end = end.Add(-offset)
+	local.StartDate = &start
+	local.EndDate = &end
+	local.DurationMinutes = int(end.Sub(start).Minutes())
+}
+
+// parseGoogleEventTime returns the instant of a Google start/end and whether it is an all-day date
+func parseGoogleEventTime(t googleEventTime) (time.Time, bool) {
+	if t.DateTime != "" {
+		return parseGoogleTime(t.DateTime), false
+	}
+	if t.Date != "" {
+		loc := time.UTC
+		if t.TimeZone != "" {
+			if l, err := time.LoadLocation(t.TimeZone); err == nil {
+				loc = l
+			}
+		}
+		date, err := time.ParseInLocation("2006-01-02", t.Date, loc)
+		if err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseGoogleTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func toSyncStatus(state *entity.CalendarSyncState, calendarEmail string) dto.CalendarSyncStatus {
+	status := dto.CalendarSyncStatus{
+		Provider:      state.Provider,
+		CalendarEmail: calendarEmail,
+		Status:        string(state.Status),
+		EventsPulled:  state.EventsPulled,
+		EventsPushed:  state.EventsPushed,
+		Conflicts:     state.Conflicts,
+	}
+	if state.LastError != nil {
+		status.LastError = *state.LastError
+	}
+	if state.LastSyncedAt != nil {
+		status.LastSyncedAt = state.LastSyncedAt.Format(time.RFC3339)
+	}
+	if state.LastFullSyncAt != nil {
+		status.LastFullSyncAt = state.LastFullSyncAt.Format(time.RFC3339)
+	}
+	if state.LastSuccessAt != nil {
+		status.LastSuccessAt = state.LastSuccessAt.Format(time.RFC3339)
+	}
+	return status
+}
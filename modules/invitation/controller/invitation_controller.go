@@ -1,10 +1,16 @@
 package controller
 
 import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
 	"go-api-starter/core/controller"
 	"go-api-starter/core/errors"
 	"go-api-starter/core/logger"
 	"go-api-starter/core/utils"
+	"go-api-starter/modules/invitation/dto"
 	"go-api-starter/modules/invitation/service"
 
 	"github.com/google/uuid"
@@ -156,3 +162,316 @@ func (c *InvitationController) CountPending(ctx echo.Context) error {
 
 	return c.SuccessResponse(ctx, map[string]int{"count": count}, "Pending count retrieved")
 }
+
+// ProposeNewTime suggests alternative slots for an invitation
+// @Summary Đề xuất thời gian khác
+// @Description Người được mời đề xuất một hoặc nhiều khung giờ khác; có thể lọc theo lịch rảnh của cả hai bên
+// @Tags Invitation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Param request body dto.ProposeTimeRequest true "Các khung giờ đề xuất"
+// @Success 200 {object} dto.ProposalResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/invitations/{id}/proposals [post]
+func (c *InvitationController) ProposeNewTime(ctx echo.Context) error {
+	userID, err := c.GetUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid invitation ID", nil)
+	}
+
+	req := new(dto.ProposeTimeRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, appErr := c.service.ProposeNewTime(ctx.Request().Context(), id, userID, req)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Time proposal sent")
+}
+
+// ListProposals lists the time proposals of an invitation
+// @Summary Danh sách đề xuất thời gian
+// @Description Trả về các đề xuất thời gian của lời mời kèm lịch sử trạng thái (người tạo hoặc người được mời)
+// @Tags Invitation
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Success 200 {array} dto.ProposalResponse
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/invitations/{id}/proposals [get]
+func (c *InvitationController) ListProposals(ctx echo.Context) error {
+	userID, err := c.GetUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid invitation ID", nil)
+	}
+
+	result, appErr := c.service.ListProposals(ctx.Request().Context(), id, userID)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Proposals retrieved")
+}
+
+// ListIncomingProposals lists proposals waiting for the current user's decision
+// @Summary Đề xuất thời gian chờ duyệt
+// @Description Trả về các đề xuất thời gian đang chờ người tổ chức quyết định
+// @Tags Invitation
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.ProposalResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/invitations/proposals/incoming [get]
+func (c *InvitationController) ListIncomingProposals(ctx echo.Context) error {
+	userID, err := c.GetUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	result, appErr := c.service.ListIncomingProposals(ctx.Request().Context(), userID)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Proposals retrieved")
+}
+
+// AcceptProposal accepts a time proposal and reschedules the event
+// @Summary Chấp nhận đề xuất thời gian
+// @Description Người tổ chức chọn một khung giờ đề xuất; sự kiện (và Google Calendar) được dời lịch và mọi người được thông báo
+// @Tags Invitation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param proposalId path string true "Proposal ID"
+// @Param request body dto.AcceptProposalRequest true "Khung giờ được chọn"
+// @Success 200 {object} dto.ProposalResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/invitations/proposals/{proposalId}/accept [post]
+func (c *InvitationController) AcceptProposal(ctx echo.Context) error {
+	userID, err := c.GetUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("proposalId"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid proposal ID", nil)
+	}
+
+	req := new(dto.AcceptProposalRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, appErr := c.service.AcceptProposal(ctx.Request().Context(), id, userID, req)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Proposal accepted")
+}
+
+// RejectProposal rejects a time proposal
+// @Summary Từ chối đề xuất thời gian
+// @Description Người tổ chức từ chối đề xuất; sự kiện giữ nguyên thời gian
+// @Tags Invitation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param proposalId path string true "Proposal ID"
+// @Param request body dto.RejectProposalRequest false "Lý do"
+// @Success 200 {object} dto.ProposalResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Router /private/invitations/proposals/{proposalId}/reject [post]
+func (c *InvitationController) RejectProposal(ctx echo.Context) error {
+	userID, err := c.GetUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("proposalId"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid proposal ID", nil)
+	}
+
+	req := new(dto.RejectProposalRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, appErr := c.service.RejectProposal(ctx.Request().Context(), id, userID, req)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Proposal rejected")
+}
+
+// PublicRSVP shows the confirmation page of a link from an invitation email
+// @Summary Xác nhận phản hồi lời mời qua email
+// @Description Hiển thị trang xác nhận cho người được mời (chưa có tài khoản). Phản hồi chỉ được ghi nhận khi người dùng bấm nút (POST), không phải khi mở liên kết.
+// @Tags Invitation
+// @Produce html
+// @Param id path string true "Invitation ID"
+// @Param response query string false "accepted | tentative | declined"
+// @Param token query string true "RSVP token"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {string} string "HTML page"
+// @Router /public/invitations/{id}/rsvp [get]
+func (c *InvitationController) PublicRSVP(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Liên kết không hợp lệ", "Không tìm thấy lời mời.", ""))
+	}
+
+	token := ctx.QueryParam("token")
+	result, err := c.service.PreviewRSVP(ctx.Request().Context(), id, token)
+	if err != nil {
+		logger.Error("InvitationController:PublicRSVP:Error", "error", err, "invitation_id", id)
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Liên kết không hợp lệ", "Liên kết không hợp lệ hoặc đã hết hạn.", ""))
+	}
+
+	message := fmt.Sprintf("Sự kiện: %s. Chọn phản hồi của bạn.", result.EventData.Title)
+	return ctx.HTML(http.StatusOK, rsvpPage("Phản hồi lời mời", message, rsvpForm(id, token, ctx.QueryParam("response"))))
+}
+
+// PublicRSVPSubmit records the response confirmed on the RSVP page
+// @Summary Ghi nhận phản hồi lời mời qua email
+// @Description Người được mời (chưa có tài khoản) xác nhận tham gia / có thể / từ chối
+// @Tags Invitation
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id path string true "Invitation ID"
+// @Param response formData string true "accepted | tentative | declined"
+// @Param token formData string true "RSVP token"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {string} string "HTML page"
+// @Router /public/invitations/{id}/rsvp [post]
+func (c *InvitationController) PublicRSVPSubmit(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Liên kết không hợp lệ", "Không tìm thấy lời mời.", ""))
+	}
+
+	token := ctx.FormValue("token")
+	result, err := c.service.RespondByToken(ctx.Request().Context(), id, token, ctx.FormValue("response"))
+	if err != nil {
+		logger.Error("InvitationController:PublicRSVPSubmit:Error", "error", err, "invitation_id", id)
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Không thể ghi nhận phản hồi", "Liên kết không hợp lệ hoặc đã hết hạn.", ""))
+	}
+
+	var title string
+	switch result.Status {
+	case "accepted":
+		title = "Bạn đã xác nhận tham gia"
+	case "tentative":
+		title = "Bạn đã phản hồi: Có thể tham gia"
+	default:
+		title = "Bạn đã từ chối lời mời"
+	}
+	message := fmt.Sprintf("Sự kiện: %s. Bạn có thể thay đổi phản hồi bằng các liên kết khác trong email.", result.EventData.Title)
+	return ctx.HTML(http.StatusOK, rsvpPage(title, message, signupForm(id, token)))
+}
+
+// PublicRSVPSignup emails the invitee a short-lived token to register with their invited address
+// @Summary Gửi mã xác minh email để đăng ký
+// @Description Gửi tới email được mời một mã xác minh ngắn hạn; dùng mã này làm invitation_token khi đăng ký
+// @Tags Invitation
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id path string true "Invitation ID"
+// @Param token formData string true "RSVP token"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {string} string "HTML page"
+// @Router /public/invitations/{id}/signup [post]
+func (c *InvitationController) PublicRSVPSignup(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Liên kết không hợp lệ", "Không tìm thấy lời mời.", ""))
+	}
+
+	if err := c.service.SendSignupVerification(ctx.Request().Context(), id, ctx.FormValue("token")); err != nil {
+		logger.Error("InvitationController:PublicRSVPSignup:Error", "error", err, "invitation_id", id)
+		return ctx.HTML(http.StatusBadRequest, rsvpPage("Không thể gửi mã xác minh", "Liên kết không hợp lệ hoặc đã hết hạn.", ""))
+	}
+
+	return ctx.HTML(http.StatusOK, rsvpPage("Đã gửi mã xác minh", "Kiểm tra hộp thư của bạn và dùng mã trong email khi đăng ký.", ""))
+}
+
+// rsvpForm renders the three answers as buttons posting back to the RSVP endpoint; the one picked in the email comes first
+func rsvpForm(id uuid.UUID, token, picked string) string {
+	answers := []struct{ status, label, color string }{
+		{"accepted", "Tham gia", "#10b981"},
+		{"tentative", "Có thể", "#f59e0b"},
+		{"declined", "Từ chối", "#ef4444"},
+	}
+	for i, answer := range answers {
+		if answer.status == picked && i > 0 {
+			answers[0], answers[i] = answers[i], answers[0]
+		}
+	}
+
+	var buttons strings.Builder
+	for _, answer := range answers {
+		fmt.Fprintf(&buttons, `<button type="submit" name="response" value="%s" style="background: %s">%s</button>`,
+			answer.status, answer.color, html.EscapeString(answer.label))
+	}
+	return fmt.Sprintf(`<form method="POST" action="/api/v1/public/invitations/%s/rsvp">
+			<input type="hidden" name="token" value="%s">
+			%s
+		</form>`, id, html.EscapeString(token), buttons.String())
+}
+
+// signupForm offers a sign up verification email once the invitee has answered
+func signupForm(id uuid.UUID, token string) string {
+	return fmt.Sprintf(`<form method="POST" action="/api/v1/public/invitations/%s/signup">
+			<input type="hidden" name="token" value="%s">
+			<p>Muốn quản lý lời mời trong ứng dụng?</p>
+			<button type="submit" style="background: #667eea">Gửi mã xác minh để đăng ký</button>
+		</form>`, id, html.EscapeString(token))
+}
+
+func rsvpPage(title, message, actions string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="vi">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>%s</title>
+	<style>
+		body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; background: #f4f5fb; margin: 0; padding: 40px 20px; }
+		.container { max-width: 480px; margin: 0 auto; background: white; border-radius: 15px; box-shadow: 0 10px 30px rgba(0,0,0,0.1); padding: 32px; text-align: center; }
+		h1 { font-size: 22px; color: #333; }
+		p { color: #555; }
+		button { border: 0; border-radius: 8px; color: white; cursor: pointer; font-size: 15px; font-weight: 600; margin: 4px; padding: 10px 22px; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<h1>%s</h1>
+		<p>%s</p>
+		%s
+	</div>
+</body>
+</html>`, html.EscapeString(title), html.EscapeString(title), html.EscapeString(message), actions)
+}
@@ -16,10 +16,22 @@ type EventDataDTO struct {
 	Timezone    string `json:"timezone"`
 }
 
+// EventDataUpdateDTO is a change to an event snapshot; nil fields keep their current value
+type EventDataUpdateDTO struct {
+	Title       *string
+	Description *string
+	StartTime   *string
+	EndTime     *string
+	Location    *string
+	MeetingLink *string
+	Timezone    *string
+}
+
 type CreateInvitationRequest struct {
 	EventGoogleID string       `json:"event_google_id"`
 	CreatorID     uuid.UUID    `json:"creator_id"`
 	InviteeIDs    []uuid.UUID  `json:"invitee_ids"`
+	InviteeEmails []string     `json:"invitee_emails"` // attendees without an account
 	EventData     EventDataDTO `json:"event_data"`
 }
 
@@ -29,10 +41,59 @@ type InvitationResponse struct {
 	CreatorID     uuid.UUID    `json:"creator_id"`
 	Status        string       `json:"status"`
 	EventData     EventDataDTO `json:"event_data"`
+	EventRevision int          `json:"event_revision"`
 	CreatedAt     time.Time    `json:"created_at"`
 }
 
+type RSVPResponse struct {
+	InvitationID uuid.UUID    `json:"invitation_id"`
+	Status       string       `json:"status"`
+	EventData    EventDataDTO `json:"event_data"`
+}
+
 type PendingInvitationsResponse struct {
 	Invitations []InvitationResponse `json:"invitations"`
 	Total       int                  `json:"total"`
 }
+
+type ProposalSlotDTO struct {
+	StartTime string `json:"start_time"` // RFC3339
+	EndTime   string `json:"end_time"`   // RFC3339
+}
+
+type ProposeTimeRequest struct {
+	Slots            []ProposalSlotDTO `json:"slots"`
+	Note             string            `json:"note"`
+	OnlyMutuallyFree bool              `json:"only_mutually_free"` // drop slots where the creator or invitee is busy
+}
+
+type AcceptProposalRequest struct {
+	SlotIndex int    `json:"slot_index"`
+	Note      string `json:"note"`
+}
+
+type RejectProposalRequest struct {
+	Note string `json:"note"`
+}
+
+type ProposalHistoryResponse struct {
+	Status    string     `json:"status"`
+	ActorID   *uuid.UUID `json:"actor_id"`
+	Note      *string    `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ProposalResponse struct {
+	ID                uuid.UUID                 `json:"id"`
+	InvitationID      uuid.UUID                 `json:"invitation_id"`
+	EventGoogleID     string                    `json:"event_google_id"`
+	CreatorID         uuid.UUID                 `json:"creator_id"`
+	ProposerID        uuid.UUID                 `json:"proposer_id"`
+	Slots             []ProposalSlotDTO         `json:"slots"`
+	Note              *string                   `json:"note"`
+	Status            string                    `json:"status"`
+	AcceptedSlotIndex *int                      `json:"accepted_slot_index"`
+	DecidedAt         *time.Time                `json:"decided_at"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	History           []ProposalHistoryResponse `json:"history"`
+}
@@ -12,9 +12,11 @@ import (
 type InvitationStatus string
 
 const (
-	InvitationStatusPending  InvitationStatus = "pending"
-	InvitationStatusAccepted InvitationStatus = "accepted"
-	InvitationStatusDeclined InvitationStatus = "declined"
+	InvitationStatusPending   InvitationStatus = "pending"
+	InvitationStatusAccepted  InvitationStatus = "accepted"
+	InvitationStatusDeclined  InvitationStatus = "declined"
+	InvitationStatusTentative InvitationStatus = "tentative"
+	InvitationStatusCancelled InvitationStatus = "cancelled"
 )
 
 type EventData struct {
@@ -46,9 +48,11 @@ type EventInvitation struct {
 	ID            uuid.UUID        `db:"id" json:"id"`
 	EventGoogleID string           `db:"event_google_id" json:"event_google_id"`
 	CreatorID     uuid.UUID        `db:"creator_id" json:"creator_id"`
-	InviteeID     uuid.UUID        `db:"invitee_id" json:"invitee_id"`
+	InviteeID     *uuid.UUID       `db:"invitee_id" json:"invitee_id"`       // nil until an email-only invitee has an account
+	InviteeEmail  *string          `db:"invitee_email" json:"invitee_email"` // set for invitees invited by email
 	Status        InvitationStatus `db:"status" json:"status"`
 	EventData     EventData        `db:"event_data" json:"event_data"`
+	EventRevision int              `db:"event_revision" json:"event_revision"` // bumped on every creator update
 	RespondedAt   *time.Time       `db:"responded_at" json:"responded_at"`
 	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time        `db:"updated_at" json:"updated_at"`
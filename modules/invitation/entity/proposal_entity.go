@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProposalStatus string
+
+const (
+	ProposalStatusPending    ProposalStatus = "pending"
+	ProposalStatusAccepted   ProposalStatus = "accepted"
+	ProposalStatusRejected   ProposalStatus = "rejected"
+	ProposalStatusSuperseded ProposalStatus = "superseded"
+)
+
+type ProposalSlot struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type ProposalSlots []ProposalSlot
+
+func (p ProposalSlots) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *ProposalSlots) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, p)
+}
+
+type InvitationProposal struct {
+	ID                uuid.UUID      `db:"id" json:"id"`
+	InvitationID      uuid.UUID      `db:"invitation_id" json:"invitation_id"`
+	EventGoogleID     string         `db:"event_google_id" json:"event_google_id"`
+	CreatorID         uuid.UUID      `db:"creator_id" json:"creator_id"`
+	ProposerID        uuid.UUID      `db:"proposer_id" json:"proposer_id"`
+	Slots             ProposalSlots  `db:"slots" json:"slots"`
+	Note              *string        `db:"note" json:"note"`
+	Status            ProposalStatus `db:"status" json:"status"`
+	AcceptedSlotIndex *int           `db:"accepted_slot_index" json:"accepted_slot_index"`
+	DecidedAt         *time.Time     `db:"decided_at" json:"decided_at"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type ProposalHistory struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	ProposalID uuid.UUID      `db:"proposal_id" json:"proposal_id"`
+	Status     ProposalStatus `db:"status" json:"status"`
+	ActorID    *uuid.UUID     `db:"actor_id" json:"actor_id"`
+	Note       *string        `db:"note" json:"note"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+}
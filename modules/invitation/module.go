@@ -9,19 +9,21 @@ import (
 	"go-api-starter/modules/invitation/router"
 	"go-api-starter/modules/invitation/service"
 	notifService "go-api-starter/modules/notification/service"
+	webhookService "go-api-starter/modules/webhook/service"
 
 	"github.com/labstack/echo/v4"
 )
 
 // Init initializes the invitation module and returns the service for use by other modules
-func Init(g *echo.Group, db database.Database, mw *middleware.Middleware, notificationService *notifService.NotificationService) *service.InvitationService {
+func Init(e *echo.Echo, db database.Database, mw *middleware.Middleware, notificationService *notifService.NotificationService, webhookSvc *webhookService.WebhookService) *service.InvitationService {
 	repo := repository.NewInvitationRepository(db)
 	authRepository := authRepo.NewAuthRepository(db)
-	svc := service.NewInvitationService(repo, notificationService, authRepository)
+	svc := service.NewInvitationService(repo, notificationService, authRepository, webhookSvc)
 	ctrl := controller.NewInvitationController(svc)
 	r := router.NewInvitationRouter(ctrl)
 
-	r.Register(g, mw)
+	r.Register(e.Group("/api/v1/private"), mw)
+	r.RegisterPublic(e.Group("/api/v1/public"))
 
 	return svc
 }
@@ -22,8 +22,8 @@ func NewInvitationRepository(db database.Database) *InvitationRepository {
 // Create creates a new invitation
 func (r *InvitationRepository) Create(ctx context.Context, invitation *entity.EventInvitation) error {
 	query := `
-		INSERT INTO event_invitations (event_google_id, creator_id, invitee_id, status, event_data, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO event_invitations (event_google_id, creator_id, invitee_id, invitee_email, status, event_data, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 	now := time.Now()
@@ -43,6 +43,7 @@ func (r *InvitationRepository) Create(ctx context.Context, invitation *entity.Ev
 		invitation.EventGoogleID,
 		invitation.CreatorID,
 		invitation.InviteeID,
+		invitation.InviteeEmail,
 		invitation.Status,
 		eventDataValue,
 		invitation.CreatedAt,
@@ -54,7 +55,7 @@ func (r *InvitationRepository) Create(ctx context.Context, invitation *entity.Ev
 // GetByID gets an invitation by ID
 func (r *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.EventInvitation, error) {
 	query := `
-		SELECT id, event_google_id, creator_id, invitee_id, status, event_data, responded_at, created_at, updated_at
+		SELECT id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
 		FROM event_invitations
 		WHERE id = $1
 	`
@@ -70,7 +71,7 @@ func (r *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*enti
 // GetPendingByInviteeID gets all pending invitations for a user
 func (r *InvitationRepository) GetPendingByInviteeID(ctx context.Context, inviteeID uuid.UUID) ([]entity.EventInvitation, error) {
 	query := `
-		SELECT id, event_google_id, creator_id, invitee_id, status, event_data, responded_at, created_at, updated_at
+		SELECT id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
 		FROM event_invitations
 		WHERE invitee_id = $1 AND status = 'pending'
 		ORDER BY created_at DESC
@@ -111,3 +112,82 @@ func (r *InvitationRepository) CountPendingByInviteeID(ctx context.Context, invi
 	}
 	return count, nil
 }
+
+// AttachToUser links email-only invitations sent to email to the user that now owns the address
+func (r *InvitationRepository) AttachToUser(ctx context.Context, email string, userID uuid.UUID) ([]entity.EventInvitation, error) {
+	query := `
+		UPDATE event_invitations
+		SET invitee_id = $1, updated_at = NOW()
+		WHERE invitee_id IS NULL AND LOWER(invitee_email) = LOWER($2)
+		RETURNING id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
+	`
+	var invitations []entity.EventInvitation
+	err := r.db.SelectContext(ctx, &invitations, query, userID, email)
+	if err != nil {
+		logger.Error("InvitationRepository:AttachToUser:Error:", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// GetActiveByEvent returns the invitations of an event that have not been declined or cancelled
+func (r *InvitationRepository) GetActiveByEvent(ctx context.Context, creatorID uuid.UUID, eventGoogleID string) ([]entity.EventInvitation, error) {
+	query := `
+		SELECT id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
+		FROM event_invitations
+		WHERE creator_id = $1 AND event_google_id = $2 AND status IN ('pending', 'accepted', 'tentative')
+		ORDER BY created_at
+	`
+	var invitations []entity.EventInvitation
+	err := r.db.SelectContext(ctx, &invitations, query, creatorID, eventGoogleID)
+	if err != nil {
+		logger.Error("InvitationRepository:GetActiveByEvent:Error:", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// UpdateEventSnapshot stores the new event data on active invitations and bumps their revision.
+// When resetResponses is true, accepted and tentative answers go back to pending.
+func (r *InvitationRepository) UpdateEventSnapshot(ctx context.Context, creatorID uuid.UUID, eventGoogleID string, data entity.EventData, resetResponses bool) ([]entity.EventInvitation, error) {
+	query := `
+		UPDATE event_invitations
+		SET event_data = $3,
+			event_revision = event_revision + 1,
+			status = CASE WHEN $4 AND status IN ('accepted', 'tentative') THEN 'pending' ELSE status END,
+			responded_at = CASE WHEN $4 AND status IN ('accepted', 'tentative') THEN NULL ELSE responded_at END,
+			updated_at = NOW()
+		WHERE creator_id = $1 AND event_google_id = $2 AND status IN ('pending', 'accepted', 'tentative')
+		RETURNING id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
+	`
+	eventDataValue, err := data.Value()
+	if err != nil {
+		logger.Error("InvitationRepository:UpdateEventSnapshot:EventDataValue:Error:", err)
+		return nil, err
+	}
+
+	var invitations []entity.EventInvitation
+	err = r.db.SelectContext(ctx, &invitations, query, creatorID, eventGoogleID, eventDataValue, resetResponses)
+	if err != nil {
+		logger.Error("InvitationRepository:UpdateEventSnapshot:Error:", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// CancelByEvent marks every non-declined invitation of an event as cancelled
+func (r *InvitationRepository) CancelByEvent(ctx context.Context, creatorID uuid.UUID, eventGoogleID string) ([]entity.EventInvitation, error) {
+	query := `
+		UPDATE event_invitations
+		SET status = 'cancelled', event_revision = event_revision + 1, updated_at = NOW()
+		WHERE creator_id = $1 AND event_google_id = $2 AND status IN ('pending', 'accepted', 'tentative')
+		RETURNING id, event_google_id, creator_id, invitee_id, invitee_email, status, event_data, event_revision, responded_at, created_at, updated_at
+	`
+	var invitations []entity.EventInvitation
+	err := r.db.SelectContext(ctx, &invitations, query, creatorID, eventGoogleID)
+	if err != nil {
+		logger.Error("InvitationRepository:CancelByEvent:Error:", err)
+		return nil, err
+	}
+	return invitations, nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/invitation/entity"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CreateProposal stores a new counter-proposal
+func (r *InvitationRepository) CreateProposal(ctx context.Context, proposal *entity.InvitationProposal) error {
+	query := `
+		INSERT INTO invitation_proposals (invitation_id, event_google_id, creator_id, proposer_id, slots, note, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	slotsValue, err := proposal.Slots.Value()
+	if err != nil {
+		logger.Error("InvitationRepository:CreateProposal:SlotsValue:Error:", err)
+		return err
+	}
+
+	err = r.db.QueryRowContext(ctx, query,
+		proposal.InvitationID,
+		proposal.EventGoogleID,
+		proposal.CreatorID,
+		proposal.ProposerID,
+		slotsValue,
+		proposal.Note,
+		proposal.Status,
+	).Scan(&proposal.ID, &proposal.CreatedAt, &proposal.UpdatedAt)
+	if err != nil {
+		logger.Error("InvitationRepository:CreateProposal:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *InvitationRepository) GetProposalByID(ctx context.Context, id uuid.UUID) (*entity.InvitationProposal, error) {
+	var proposal entity.InvitationProposal
+	err := r.db.GetContext(ctx, &proposal, `SELECT * FROM invitation_proposals WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("InvitationRepository:GetProposalByID:Error:", err)
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (r *InvitationRepository) GetProposalsByInvitationID(ctx context.Context, invitationID uuid.UUID) ([]entity.InvitationProposal, error) {
+	var proposals []entity.InvitationProposal
+	query := `SELECT * FROM invitation_proposals WHERE invitation_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &proposals, query, invitationID)
+	if err != nil {
+		logger.Error("InvitationRepository:GetProposalsByInvitationID:Error:", err)
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// GetPendingProposalsByCreatorID lists proposals waiting for the event creator's decision
+func (r *InvitationRepository) GetPendingProposalsByCreatorID(ctx context.Context, creatorID uuid.UUID) ([]entity.InvitationProposal, error) {
+	var proposals []entity.InvitationProposal
+	query := `SELECT * FROM invitation_proposals WHERE creator_id = $1 AND status = 'pending' ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &proposals, query, creatorID)
+	if err != nil {
+		logger.Error("InvitationRepository:GetPendingProposalsByCreatorID:Error:", err)
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// DecideProposal moves a pending proposal to its final status. Returns false when it was no longer pending.
+func (r *InvitationRepository) DecideProposal(ctx context.Context, id uuid.UUID, status entity.ProposalStatus, acceptedSlotIndex *int) (bool, error) {
+	query := `
+		UPDATE invitation_proposals
+		SET status = $2, accepted_slot_index = $3, decided_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING id
+	`
+	var updatedID uuid.UUID
+	err := r.db.QueryRowContext(ctx, query, id, status, acceptedSlotIndex).Scan(&updatedID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		logger.Error("InvitationRepository:DecideProposal:Error:", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ClaimAcceptedProposal accepts a pending proposal and supersedes the other pending proposals of its event in
+// one statement. The event's pending proposals are locked first, so of two concurrent accepts for the same
+// event only one claims anything. claimed is false when the proposal was no longer pending.
+func (r *InvitationRepository) ClaimAcceptedProposal(ctx context.Context, id uuid.UUID, creatorID uuid.UUID, eventGoogleID string, acceptedSlotIndex int) (claimed bool, supersededIDs []uuid.UUID, err error) {
+	query := `
+		WITH event_proposals AS (
+			SELECT id FROM invitation_proposals
+			WHERE creator_id = $2 AND event_google_id = $3 AND status = 'pending'
+			ORDER BY id
+			FOR UPDATE
+		), accepted AS (
+			UPDATE invitation_proposals
+			SET status = 'accepted', accepted_slot_index = $4, decided_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND id IN (SELECT id FROM event_proposals)
+			RETURNING id, status
+		), superseded AS (
+			UPDATE invitation_proposals
+			SET status = 'superseded', decided_at = NOW(), updated_at = NOW()
+			WHERE id <> $1 AND id IN (SELECT id FROM event_proposals) AND EXISTS (SELECT 1 FROM accepted)
+			RETURNING id, status
+		)
+		SELECT id, status FROM accepted
+		UNION ALL
+		SELECT id, status FROM superseded
+	`
+	var rows []struct {
+		ID     uuid.UUID             `db:"id"`
+		Status entity.ProposalStatus `db:"status"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, id, creatorID, eventGoogleID, acceptedSlotIndex); err != nil {
+		logger.Error("InvitationRepository:ClaimAcceptedProposal:Error:", err)
+		return false, nil, err
+	}
+	for _, row := range rows {
+		if row.Status == entity.ProposalStatusAccepted {
+			claimed = true
+		} else {
+			supersededIDs = append(supersededIDs, row.ID)
+		}
+	}
+	return claimed, supersededIDs, nil
+}
+
+// ReopenProposals undoes ClaimAcceptedProposal when applying the decision failed: the accepted proposal and
+// the ones it superseded are pending again
+func (r *InvitationRepository) ReopenProposals(ctx context.Context, acceptedID uuid.UUID, supersededIDs []uuid.UUID) error {
+	query := `
+		UPDATE invitation_proposals
+		SET status = 'pending', accepted_slot_index = NULL, decided_at = NULL, updated_at = NOW()
+		WHERE (id = $1 AND status = 'accepted') OR (id = ANY($2) AND status = 'superseded')
+	`
+	err := r.db.ExecContext(ctx, query, acceptedID, pq.Array(supersededIDs))
+	if err != nil {
+		logger.Error("InvitationRepository:ReopenProposals:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *InvitationRepository) AddProposalHistory(ctx context.Context, history *entity.ProposalHistory) error {
+	query := `
+		INSERT INTO invitation_proposal_history (proposal_id, status, actor_id, note, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, history.ProposalID, history.Status, history.ActorID, history.Note).
+		Scan(&history.ID, &history.CreatedAt)
+	if err != nil {
+		logger.Error("InvitationRepository:AddProposalHistory:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *InvitationRepository) GetProposalHistory(ctx context.Context, proposalID uuid.UUID) ([]entity.ProposalHistory, error) {
+	var history []entity.ProposalHistory
+	query := `SELECT * FROM invitation_proposal_history WHERE proposal_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &history, query, proposalID)
+	if err != nil {
+		logger.Error("InvitationRepository:GetProposalHistory:Error:", err)
+		return nil, err
+	}
+	return history, nil
+}
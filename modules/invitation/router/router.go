@@ -25,4 +25,17 @@ func (r *InvitationRouter) Register(g *echo.Group, mw *middleware.Middleware) {
 	invitations.GET("/count", r.controller.CountPending)
 	invitations.POST("/:id/accept", r.controller.AcceptInvitation)
 	invitations.POST("/:id/decline", r.controller.DeclineInvitation)
+
+	// Counter-proposals
+	invitations.GET("/proposals/incoming", r.controller.ListIncomingProposals)
+	invitations.POST("/proposals/:proposalId/accept", r.controller.AcceptProposal)
+	invitations.POST("/proposals/:proposalId/reject", r.controller.RejectProposal)
+	invitations.POST("/:id/proposals", r.controller.ProposeNewTime)
+	invitations.GET("/:id/proposals", r.controller.ListProposals)
+}
+
+func (r *InvitationRouter) RegisterPublic(g *echo.Group) {
+	g.GET("/invitations/:id/rsvp", r.controller.PublicRSVP)
+	g.POST("/invitations/:id/rsvp", r.controller.PublicRSVPSubmit)
+	g.POST("/invitations/:id/signup", r.controller.PublicRSVPSignup)
 }
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-api-starter/core/config"
+	"go-api-starter/core/constants"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/invitation/dto"
+	"go-api-starter/modules/invitation/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const (
+	rsvpEmailTemplate   = "invitation_rsvp.html"
+	signupEmailTemplate = "invitation_signup.html"
+)
+
+type rsvpEmailKind int
+
+const (
+	rsvpEmailInvite rsvpEmailKind = iota
+	rsvpEmailUpdate
+	rsvpEmailCancel
+)
+
+type rsvpEmailData struct {
+	Title        string
+	Intro        string
+	Changes      []string
+	Organizer    string
+	EventTitle   string
+	Time         string
+	Location     string
+	MeetingLink  string
+	Description  string
+	AcceptURL    string
+	TentativeURL string
+	DeclineURL   string
+}
+
+type signupEmailData struct {
+	Title            string
+	EventTitle       string
+	Token            string
+	ExpiresInMinutes int
+}
+
+// createEmailInvitations stores invitations for attendees without an account and emails them RSVP links
+func (s *InvitationService) createEmailInvitations(ctx context.Context, req *dto.CreateInvitationRequest) {
+	seen := make(map[string]bool)
+	for _, raw := range req.InviteeEmails {
+		email := strings.TrimSpace(raw)
+		key := strings.ToLower(email)
+		if email == "" || seen[key] || !utils.IsValidEmail(email) {
+			continue
+		}
+		seen[key] = true
+
+		invitation := &entity.EventInvitation{
+			EventGoogleID: req.EventGoogleID,
+			CreatorID:     req.CreatorID,
+			InviteeEmail:  &email,
+			Status:        entity.InvitationStatusPending,
+			EventData: entity.EventData{
+				Title:       req.EventData.Title,
+				Description: req.EventData.Description,
+				StartTime:   req.EventData.StartTime,
+				EndTime:     req.EventData.EndTime,
+				Location:    req.EventData.Location,
+				MeetingLink: req.EventData.MeetingLink,
+				Timezone:    req.EventData.Timezone,
+			},
+		}
+
+		if err := s.repo.Create(ctx, invitation); err != nil {
+			logger.Error("InvitationService:createEmailInvitations:Create:Error:", err)
+			continue
+		}
+
+		if err := s.sendRSVPEmail(ctx, invitation, rsvpEmailInvite, nil); err != nil {
+			logger.Error("InvitationService:createEmailInvitations:SendEmail:Error", "email", email, "error", err)
+		}
+	}
+}
+
+// sendRSVPEmail emails an email-only invitee about an invitation, an update (with changes) or a cancellation.
+// Invite and update emails carry tokenized accept/tentative/decline links.
+func (s *InvitationService) sendRSVPEmail(ctx context.Context, invitation *entity.EventInvitation, kind rsvpEmailKind, changes []string) error {
+	email := *invitation.InviteeEmail
+
+	organizer := "Người tổ chức"
+	if creator, err := s.authRepo.GetUserByIdentifier(ctx, invitation.CreatorID.String()); err == nil && creator != nil {
+		if creator.Username != nil && *creator.Username != "" {
+			organizer = *creator.Username
+		} else if creator.Email != nil {
+			organizer = *creator.Email
+		}
+	}
+
+	data := rsvpEmailData{
+		Changes:     changes,
+		Organizer:   organizer,
+		EventTitle:  invitation.EventData.Title,
+		Time:        formatEventTime(invitation.EventData),
+		Location:    invitation.EventData.Location,
+		MeetingLink: invitation.EventData.MeetingLink,
+		Description: invitation.EventData.Description,
+	}
+
+	switch kind {
+	case rsvpEmailUpdate:
+		data.Title = fmt.Sprintf("Cập nhật: %s", invitation.EventData.Title)
+		data.Intro = "đã thay đổi sự kiện bạn được mời:"
+	case rsvpEmailCancel:
+		data.Title = fmt.Sprintf("Đã hủy: %s", invitation.EventData.Title)
+		data.Intro = "đã hủy sự kiện:"
+	default:
+		data.Title = fmt.Sprintf("Lời mời: %s", invitation.EventData.Title)
+		data.Intro = "đã mời bạn tham gia sự kiện:"
+	}
+
+	if kind != rsvpEmailCancel {
+		token, err := utils.GenerateToken(invitation.ID, &email, nil, constants.ScopeTokenInvitationRSVP, constants.DefaultInvitationRSVPExpiry)
+		if err != nil {
+			return err
+		}
+		rsvpURL := func(response entity.InvitationStatus) string {
+			return fmt.Sprintf("%s/api/v1/public/invitations/%s/rsvp?response=%s&token=%s",
+				publicBaseURL(), invitation.ID, response, url.QueryEscape(token))
+		}
+		data.AcceptURL = rsvpURL(entity.InvitationStatusAccepted)
+		data.TentativeURL = rsvpURL(entity.InvitationStatusTentative)
+		data.DeclineURL = rsvpURL(entity.InvitationStatusDeclined)
+	}
+
+	return utils.SendTemplateEmailFromTemplatesDir([]string{email}, data.Title, rsvpEmailTemplate, data)
+}
+
+// invitationForRSVPToken loads the invitation an RSVP link token was issued for
+func (s *InvitationService) invitationForRSVPToken(ctx context.Context, invitationID uuid.UUID, token string) (*entity.EventInvitation, error) {
+	claims, err := utils.ValidateAndParseToken(token)
+	if err != nil || claims.Scope != constants.ScopeTokenInvitationRSVP || claims.UserID != invitationID {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+
+	invitation, err := s.repo.GetByID(ctx, invitationID)
+	if err != nil || invitation == nil {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	if invitation.InviteeEmail == nil || !strings.EqualFold(*invitation.InviteeEmail, claims.Email) {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+	if invitation.Status == entity.InvitationStatusCancelled {
+		return nil, fmt.Errorf("event has been cancelled")
+	}
+	return invitation, nil
+}
+
+// PreviewRSVP checks the link of an invitation email and returns the invitation without changing it.
+// Mail scanners prefetch links, so the answer itself is only recorded by RespondByToken on a POST.
+func (s *InvitationService) PreviewRSVP(ctx context.Context, invitationID uuid.UUID, token string) (*dto.RSVPResponse, error) {
+	invitation, err := s.invitationForRSVPToken(ctx, invitationID, token)
+	if err != nil {
+		return nil, err
+	}
+	return toRSVPResponse(invitation), nil
+}
+
+// RespondByToken records an RSVP coming from the link in an invitation email
+func (s *InvitationService) RespondByToken(ctx context.Context, invitationID uuid.UUID, token string, response string) (*dto.RSVPResponse, error) {
+	status := entity.InvitationStatus(response)
+	switch status {
+	case entity.InvitationStatusAccepted, entity.InvitationStatusDeclined, entity.InvitationStatusTentative:
+	default:
+		return nil, fmt.Errorf("invalid response")
+	}
+
+	invitation, err := s.invitationForRSVPToken(ctx, invitationID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.Status != status {
+		if err := s.repo.UpdateStatus(ctx, invitationID, string(status)); err != nil {
+			return nil, err
+		}
+		invitation.Status = status
+		s.emitResponded(ctx, invitation)
+
+		// The invitee has no Google token of their own, so update their attendee entry through the creator's calendar
+		go func() {
+			bgCtx := context.Background()
+			if err := s.updateGoogleEventStatus(bgCtx, invitation.CreatorID, *invitation.InviteeEmail, invitation.EventGoogleID, string(status)); err != nil {
+				logger.Error("RespondByToken:GoogleSync:Error", "error", err, "event_id", invitation.EventGoogleID)
+			}
+		}()
+	}
+
+	return toRSVPResponse(invitation), nil
+}
+
+// SendSignupVerification emails the invitee a short-lived sign up token. Unlike the RSVP token, which
+// lives for weeks and travels in every forwarded invitation, it proves the address when registering.
+func (s *InvitationService) SendSignupVerification(ctx context.Context, invitationID uuid.UUID, token string) error {
+	invitation, err := s.invitationForRSVPToken(ctx, invitationID, token)
+	if err != nil {
+		return err
+	}
+
+	email := *invitation.InviteeEmail
+	signupToken, err := utils.GenerateToken(invitation.ID, &email, nil, constants.ScopeTokenInvitationSignup, constants.DefaultInvitationSignupExpiry)
+	if err != nil {
+		return err
+	}
+	data := signupEmailData{
+		Title:            "Xác minh email để tạo tài khoản",
+		EventTitle:       invitation.EventData.Title,
+		Token:            signupToken,
+		ExpiresInMinutes: int(constants.DefaultInvitationSignupExpiry.Minutes()),
+	}
+	return utils.SendTemplateEmailFromTemplatesDir([]string{email}, data.Title, signupEmailTemplate, data)
+}
+
+func toRSVPResponse(invitation *entity.EventInvitation) *dto.RSVPResponse {
+	return &dto.RSVPResponse{
+		InvitationID: invitation.ID,
+		Status:       string(invitation.Status),
+		EventData: dto.EventDataDTO{
+			Title:       invitation.EventData.Title,
+			Description: invitation.EventData.Description,
+			StartTime:   invitation.EventData.StartTime,
+			EndTime:     invitation.EventData.EndTime,
+			Location:    invitation.EventData.Location,
+			MeetingLink: invitation.EventData.MeetingLink,
+			Timezone:    invitation.EventData.Timezone,
+		},
+	}
+}
+
+// AttachEmailInvitations links invitations sent to email to userID and notifies the user of pending ones.
+// Called by the auth module once a user registers or signs in with a verified address.
+func (s *InvitationService) AttachEmailInvitations(ctx context.Context, userID uuid.UUID, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	invitations, err := s.repo.AttachToUser(ctx, email, userID)
+	if err != nil {
+		return err
+	}
+	if len(invitations) > 0 {
+		logger.Info("InvitationService:AttachEmailInvitations:Attached", "user_id", userID, "count", len(invitations))
+	}
+
+	for _, inv := range invitations {
+		if inv.Status != entity.InvitationStatusPending {
+			continue
+		}
+		notification := &notifDto.CreateNotificationRequest{
+			UserID:  userID,
+			Title:   "Lời mời sự kiện mới",
+			Message: fmt.Sprintf("Bạn được mời tham gia sự kiện: %s", inv.EventData.Title),
+			Type:    "invitation",
+			Data: map[string]interface{}{
+				"invitation_id": inv.ID.String(),
+				"event_id":      inv.EventGoogleID,
+			},
+		}
+		if err := s.notifService.Create(ctx, notification); err != nil {
+			logger.Error("InvitationService:AttachEmailInvitations:Notify:Error:", err)
+		}
+	}
+
+	return nil
+}
+
+func publicBaseURL() string {
+	base := config.Get().Server.BaseURL
+	if base == "" {
+		base = "http://" + config.Get().Server.Host + ":" + fmt.Sprint(config.Get().Server.Port)
+	}
+	return strings.TrimRight(base, "/")
+}
+
+// formatEventTime renders the event start/end in the event's timezone, falling back to the raw values
+func formatEventTime(ev entity.EventData) string {
+	start, err := time.Parse(time.RFC3339, ev.StartTime)
+	if err != nil {
+		return ev.StartTime
+	}
+	loc := time.UTC
+	if ev.Timezone != "" {
+		if l, err := time.LoadLocation(ev.Timezone); err == nil {
+			loc = l
+		}
+	}
+	result := start.In(loc).Format("15:04 02/01/2006")
+	if end, err := time.Parse(time.RFC3339, ev.EndTime); err == nil {
+		result += " - " + end.In(loc).Format("15:04")
+	}
+	if ev.Timezone != "" {
+		result += " (" + ev.Timezone + ")"
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/invitation/dto"
+	"go-api-starter/modules/invitation/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+// materialTimeChange is the smallest start/end shift that invalidates existing RSVPs
+const materialTimeChange = 15 * time.Minute
+
+// PropagateEventUpdate applies the changed fields of an event to the snapshot on its pending, accepted and
+// tentative invitations, bumps their revision and tells each invitee what changed. Accepted/tentative answers
+// are reset to pending when the time moved materially. Returns the new revision (0 when the event has no invitations).
+func (s *InvitationService) PropagateEventUpdate(ctx context.Context, creatorID uuid.UUID, eventGoogleID string, update dto.EventDataUpdateDTO) (int, error) {
+	current, err := s.repo.GetActiveByEvent(ctx, creatorID, eventGoogleID)
+	if err != nil {
+		return 0, err
+	}
+	if len(current) == 0 {
+		return 0, nil
+	}
+
+	previous := current[0].EventData
+	next := applyEventUpdate(previous, update)
+
+	changes := describeEventChanges(previous, next)
+	if len(changes) == 0 {
+		return current[0].EventRevision, nil
+	}
+	resetResponses := isMaterialTimeChange(previous, next)
+
+	updated, err := s.repo.UpdateEventSnapshot(ctx, creatorID, eventGoogleID, next, resetResponses)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("InvitationService:PropagateEventUpdate", "event_id", eventGoogleID, "invitations", len(updated), "reset_responses", resetResponses)
+
+	revision := 0
+	for i := range updated {
+		inv := &updated[i]
+		revision = inv.EventRevision
+
+		if inv.InviteeID == nil {
+			if inv.InviteeEmail != nil {
+				if err := s.sendRSVPEmail(ctx, inv, rsvpEmailUpdate, changes); err != nil {
+					logger.Error("InvitationService:PropagateEventUpdate:SendEmail:Error", "email", *inv.InviteeEmail, "error", err)
+				}
+			}
+			continue
+		}
+
+		message := fmt.Sprintf("Sự kiện '%s' đã thay đổi: %s", next.Title, strings.Join(changes, "; "))
+		if resetResponses {
+			message += ". Vui lòng xác nhận lại."
+		}
+		notification := &notifDto.CreateNotificationRequest{
+			UserID:  *inv.InviteeID,
+			Title:   "Sự kiện đã được cập nhật",
+			Message: message,
+			Type:    "event_updated",
+			Data: map[string]interface{}{
+				"invitation_id":  inv.ID.String(),
+				"event_id":       eventGoogleID,
+				"event_revision": inv.EventRevision,
+				"changes":        changes,
+				"rsvp_reset":     resetResponses,
+			},
+		}
+		if err := s.notifService.Create(ctx, notification); err != nil {
+			logger.Error("InvitationService:PropagateEventUpdate:Notify:Error:", err)
+		}
+	}
+
+	return revision, nil
+}
+
+// CancelEventInvitations marks an event's invitations as cancelled after the creator deleted it.
+// Invitees with an account are notified by the calendar module; email-only invitees get an email here.
+func (s *InvitationService) CancelEventInvitations(ctx context.Context, creatorID uuid.UUID, eventGoogleID string) error {
+	cancelled, err := s.repo.CancelByEvent(ctx, creatorID, eventGoogleID)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("InvitationService:CancelEventInvitations", "event_id", eventGoogleID, "count", len(cancelled))
+
+	for i := range cancelled {
+		inv := &cancelled[i]
+		if inv.InviteeID != nil || inv.InviteeEmail == nil {
+			continue
+		}
+		if err := s.sendRSVPEmail(ctx, inv, rsvpEmailCancel, nil); err != nil {
+			logger.Error("InvitationService:CancelEventInvitations:SendEmail:Error", "email", *inv.InviteeEmail, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// applyEventUpdate returns the snapshot with the update's non-nil fields applied
+func applyEventUpdate(data entity.EventData, update dto.EventDataUpdateDTO) entity.EventData {
+	if update.Title != nil {
+		data.Title = *update.Title
+	}
+	if update.Description != nil {
+		data.Description = *update.Description
+	}
+	if update.StartTime != nil {
+		data.StartTime = *update.StartTime
+	}
+	if update.EndTime != nil {
+		data.EndTime = *update.EndTime
+	}
+	if update.Location != nil {
+		data.Location = *update.Location
+	}
+	if update.MeetingLink != nil {
+		data.MeetingLink = *update.MeetingLink
+	}
+	if update.Timezone != nil {
+		data.Timezone = *update.Timezone
+	}
+	return data
+}
+
+// describeEventChanges lists human readable differences between two event snapshots
+func describeEventChanges(previous, next entity.EventData) []string {
+	var changes []string
+	if previous.Title != next.Title {
+		changes = append(changes, fmt.Sprintf("Tiêu đề: %s → %s", previous.Title, next.Title))
+	}
+	if previous.StartTime != next.StartTime || previous.EndTime != next.EndTime || previous.Timezone != next.Timezone {
+		changes = append(changes, fmt.Sprintf("Thời gian: %s → %s", formatEventTime(previous), formatEventTime(next)))
+	}
+	if previous.Location != next.Location {
+		changes = append(changes, fmt.Sprintf("Địa điểm: %s → %s", orDash(previous.Location), orDash(next.Location)))
+	}
+	if previous.MeetingLink != next.MeetingLink {
+		changes = append(changes, fmt.Sprintf("Link họp: %s → %s", orDash(previous.MeetingLink), orDash(next.MeetingLink)))
+	}
+	if previous.Description != next.Description {
+		changes = append(changes, "Mô tả đã được cập nhật")
+	}
+	return changes
+}
+
+// isMaterialTimeChange reports whether start or end moved by at least materialTimeChange
+func isMaterialTimeChange(previous, next entity.EventData) bool {
+	if previous.StartTime == next.StartTime && previous.EndTime == next.EndTime {
+		return false
+	}
+	return shifted(previous.StartTime, next.StartTime) || shifted(previous.EndTime, next.EndTime)
+}
+
+func shifted(before, after string) bool {
+	a, errA := time.Parse(time.RFC3339, before)
+	b, errB := time.Parse(time.RFC3339, after)
+	if errA != nil || errB != nil {
+		return before != after
+	}
+	diff := b.Sub(a)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff >= materialTimeChange
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
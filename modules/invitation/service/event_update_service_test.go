@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	"go-api-starter/modules/invitation/dto"
+	"go-api-starter/modules/invitation/entity"
+)
+
+func TestIsMaterialTimeChange(t *testing.T) {
+	base := entity.EventData{StartTime: "2030-03-04T10:00:00+07:00", EndTime: "2030-03-04T11:00:00+07:00"}
+	moved := func(start, end string) entity.EventData {
+		next := base
+		next.StartTime, next.EndTime = start, end
+		return next
+	}
+
+	tests := []struct {
+		name string
+		next entity.EventData
+		want bool
+	}{
+		{name: "unchanged", next: base, want: false},
+		{name: "title only", next: entity.EventData{Title: "Renamed", StartTime: base.StartTime, EndTime: base.EndTime}, want: false},
+		{name: "same instant in another offset", next: moved("2030-03-04T03:00:00Z", "2030-03-04T04:00:00Z"), want: false},
+		{name: "start moved under the threshold", next: moved("2030-03-04T10:10:00+07:00", base.EndTime), want: false},
+		{name: "start moved by the threshold", next: moved("2030-03-04T10:15:00+07:00", base.EndTime), want: true},
+		{name: "start moved earlier", next: moved("2030-03-04T09:00:00+07:00", base.EndTime), want: true},
+		{name: "end extended", next: moved(base.StartTime, "2030-03-04T12:00:00+07:00"), want: true},
+		{name: "moved to another day", next: moved("2030-03-05T10:00:00+07:00", "2030-03-05T11:00:00+07:00"), want: true},
+		{name: "unparsable times that differ", next: moved("tomorrow", base.EndTime), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMaterialTimeChange(base, tt.next); got != tt.want {
+				t.Errorf("isMaterialTimeChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEventUpdate(t *testing.T) {
+	snapshot := entity.EventData{
+		Title:       "Weekly sync",
+		Description: "Agenda",
+		StartTime:   "2030-03-04T10:00:00+07:00",
+		EndTime:     "2030-03-04T11:00:00+07:00",
+		Location:    "Room 4",
+		MeetingLink: "https://meet.example.com/abc",
+		Timezone:    "Asia/Ho_Chi_Minh",
+	}
+	text := func(value string) *string { return &value }
+
+	tests := []struct {
+		name   string
+		update dto.EventDataUpdateDTO
+		want   func(*entity.EventData)
+	}{
+		{name: "nothing changed", update: dto.EventDataUpdateDTO{}, want: func(*entity.EventData) {}},
+		{
+			name:   "title only keeps the meeting link",
+			update: dto.EventDataUpdateDTO{Title: text("Planning")},
+			want:   func(d *entity.EventData) { d.Title = "Planning" },
+		},
+		{
+			name:   "cleared location",
+			update: dto.EventDataUpdateDTO{Location: text("")},
+			want:   func(d *entity.EventData) { d.Location = "" },
+		},
+		{
+			name:   "new time",
+			update: dto.EventDataUpdateDTO{StartTime: text("2030-03-05T10:00:00+07:00"), EndTime: text("2030-03-05T11:00:00+07:00"), Timezone: text("Asia/Ho_Chi_Minh")},
+			want: func(d *entity.EventData) {
+				d.StartTime, d.EndTime = "2030-03-05T10:00:00+07:00", "2030-03-05T11:00:00+07:00"
+			},
+		},
+		{
+			name:   "new meeting link",
+			update: dto.EventDataUpdateDTO{MeetingLink: text("https://meet.example.com/xyz")},
+			want:   func(d *entity.EventData) { d.MeetingLink = "https://meet.example.com/xyz" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := snapshot
+			tt.want(&want)
+			if got := applyEventUpdate(snapshot, tt.update); got != want {
+				t.Errorf("applyEventUpdate() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
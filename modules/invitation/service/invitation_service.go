@@ -1,21 +1,24 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+	"strings"
 
+	"go-api-starter/core/constants"
+	"go-api-starter/core/googlecalendar"
 	"go-api-starter/core/logger"
 	authRepo "go-api-starter/modules/auth/repository"
+	authService "go-api-starter/modules/auth/service"
 	"go-api-starter/modules/invitation/dto"
 	"go-api-starter/modules/invitation/entity"
 	"go-api-starter/modules/invitation/repository"
 	notifDto "go-api-starter/modules/notification/dto"
 	notifService "go-api-starter/modules/notification/service"
+	webhookService "go-api-starter/modules/webhook/service"
 
 	"github.com/google/uuid"
 )
@@ -24,13 +27,20 @@ type InvitationService struct {
 	repo         *repository.InvitationRepository
 	notifService *notifService.NotificationService
 	authRepo     authRepo.AuthRepositoryInterface
+	webhookSvc   *webhookService.WebhookService
+	scheduler    EventScheduler
+	tokens       *authService.TokenStore
+	google       *googlecalendar.Client
 }
 
-func NewInvitationService(repo *repository.InvitationRepository, notifService *notifService.NotificationService, authRepo authRepo.AuthRepositoryInterface) *InvitationService {
+func NewInvitationService(repo *repository.InvitationRepository, notifService *notifService.NotificationService, authRepo authRepo.AuthRepositoryInterface, webhookSvc *webhookService.WebhookService) *InvitationService {
 	return &InvitationService{
 		repo:         repo,
 		notifService: notifService,
 		authRepo:     authRepo,
+		webhookSvc:   webhookSvc,
+		tokens:       authService.NewTokenStore(authRepo),
+		google:       googlecalendar.Default(),
 	}
 }
 
@@ -41,45 +51,58 @@ func (s *InvitationService) CreateInvitations(ctx context.Context, req *dto.Crea
 		if inviteeID == req.CreatorID {
 			continue
 		}
+		s.inviteUser(ctx, req, inviteeID, true)
+	}
 
-		invitation := &entity.EventInvitation{
-			EventGoogleID: req.EventGoogleID,
-			CreatorID:     req.CreatorID,
-			InviteeID:     inviteeID,
-			Status:        entity.InvitationStatusPending,
-			EventData: entity.EventData{
-				Title:       req.EventData.Title,
-				Description: req.EventData.Description,
-				StartTime:   req.EventData.StartTime,
-				EndTime:     req.EventData.EndTime,
-				Location:    req.EventData.Location,
-				MeetingLink: req.EventData.MeetingLink,
-			},
-		}
+	// Attendees without an account are invited by email with RSVP links
+	s.createEmailInvitations(ctx, req)
 
-		if err := s.repo.Create(ctx, invitation); err != nil {
-			logger.Error("InvitationService:CreateInvitations:Create:Error:", err)
-			continue // Don't fail entire operation for one invitee
-		}
+	return nil
+}
 
-		// Create notification for invitee
-		notification := &notifDto.CreateNotificationRequest{
-			UserID:  inviteeID,
-			Title:   "Lời mời sự kiện mới",
-			Message: fmt.Sprintf("Bạn được mời tham gia sự kiện: %s", req.EventData.Title),
-			Type:    "invitation",
-			Data: map[string]interface{}{
-				"invitation_id": invitation.ID.String(),
-				"event_id":      req.EventGoogleID,
-			},
-		}
+// inviteUser creates one invitee's invitation and notifies them. Invitees who are out of office during the
+// event are answered automatically; allowDelegate controls whether their delegate may be invited instead.
+func (s *InvitationService) inviteUser(ctx context.Context, req *dto.CreateInvitationRequest, inviteeID uuid.UUID, allowDelegate bool) {
+	invitation := &entity.EventInvitation{
+		EventGoogleID: req.EventGoogleID,
+		CreatorID:     req.CreatorID,
+		InviteeID:     &inviteeID,
+		Status:        entity.InvitationStatusPending,
+		EventData: entity.EventData{
+			Title:       req.EventData.Title,
+			Description: req.EventData.Description,
+			StartTime:   req.EventData.StartTime,
+			EndTime:     req.EventData.EndTime,
+			Location:    req.EventData.Location,
+			MeetingLink: req.EventData.MeetingLink,
+			Timezone:    req.EventData.Timezone,
+		},
+	}
 
-		if err := s.notifService.Create(ctx, notification); err != nil {
-			logger.Error("InvitationService:CreateInvitations:Notify:Error:", err)
-		}
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		logger.Error("InvitationService:CreateInvitations:Create:Error:", err)
+		return // Don't fail entire operation for one invitee
 	}
 
-	return nil
+	if s.handleOutOfOffice(ctx, req, invitation, allowDelegate) {
+		return
+	}
+
+	// Create notification for invitee
+	notification := &notifDto.CreateNotificationRequest{
+		UserID:  inviteeID,
+		Title:   "Lời mời sự kiện mới",
+		Message: fmt.Sprintf("Bạn được mời tham gia sự kiện: %s", req.EventData.Title),
+		Type:    "invitation",
+		Data: map[string]interface{}{
+			"invitation_id": invitation.ID.String(),
+			"event_id":      req.EventGoogleID,
+		},
+	}
+
+	if err := s.notifService.Create(ctx, notification); err != nil {
+		logger.Error("InvitationService:CreateInvitations:Notify:Error:", err)
+	}
 }
 
 // GetPendingInvitations returns pending invitations for a user
@@ -112,6 +135,7 @@ func (s *InvitationService) GetPendingInvitations(ctx context.Context, userID uu
 			CreatorID:     inv.CreatorID,
 			Status:        string(inv.Status),
 			EventData:     eventData,
+			EventRevision: inv.EventRevision,
 			CreatedAt:     inv.CreatedAt,
 		})
 	}
@@ -134,7 +158,7 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, invitationID u
 		return nil, err
 	}
 
-	if invitation.InviteeID != userID {
+	if invitation.InviteeID == nil || *invitation.InviteeID != userID {
 		return nil, fmt.Errorf("unauthorized: not the invitee")
 	}
 
@@ -147,12 +171,13 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, invitationID u
 	}
 
 	invitation.Status = entity.InvitationStatusAccepted
+	s.emitResponded(ctx, invitation)
 
 	// Sync to Google Calendar
 	go func() {
 		// Use a background context or a new context with timeout
 		bgCtx := context.Background()
-		if err := s.updateGoogleEventStatus(bgCtx, userID, invitation.EventGoogleID, "accepted"); err != nil {
+		if err := s.updateGoogleEventStatus(bgCtx, userID, "", invitation.EventGoogleID, "accepted"); err != nil {
 			logger.Error("AcceptInvitation:GoogleSync:Error", "error", err, "event_id", invitation.EventGoogleID)
 		}
 	}()
@@ -167,7 +192,7 @@ func (s *InvitationService) DeclineInvitation(ctx context.Context, invitationID
 		return err
 	}
 
-	if invitation.InviteeID != userID {
+	if invitation.InviteeID == nil || *invitation.InviteeID != userID {
 		return fmt.Errorf("unauthorized: not the invitee")
 	}
 
@@ -179,10 +204,13 @@ func (s *InvitationService) DeclineInvitation(ctx context.Context, invitationID
 		return err
 	}
 
+	invitation.Status = entity.InvitationStatusDeclined
+	s.emitResponded(ctx, invitation)
+
 	// Sync to Google Calendar
 	go func() {
 		bgCtx := context.Background()
-		if err := s.updateGoogleEventStatus(bgCtx, userID, invitation.EventGoogleID, "declined"); err != nil {
+		if err := s.updateGoogleEventStatus(bgCtx, userID, "", invitation.EventGoogleID, "declined"); err != nil {
 			logger.Error("DeclineInvitation:GoogleSync:Error", "error", err, "event_id", invitation.EventGoogleID)
 		}
 	}()
@@ -190,41 +218,56 @@ func (s *InvitationService) DeclineInvitation(ctx context.Context, invitationID
 	return nil
 }
 
-// updateGoogleEventStatus updates the attendee response status on Google Calendar
-func (s *InvitationService) updateGoogleEventStatus(ctx context.Context, userID uuid.UUID, eventGoogleID string, status string) error {
-	logger.Info("updateGoogleEventStatus:Start", "user_id", userID, "event_id", eventGoogleID, "status", status)
-
-	// 1. Get Google provider and social login data (contains email and token)
-	provider, err := s.authRepo.GetOAuthProviderByName(ctx, "google")
-	if err != nil || provider == nil {
-		return fmt.Errorf("google provider not found")
+// emitResponded notifies the event creator's webhooks that an invitee responded
+func (s *InvitationService) emitResponded(ctx context.Context, invitation *entity.EventInvitation) {
+	creatorID := invitation.CreatorID
+	data := map[string]interface{}{
+		"invitation_id":   invitation.ID.String(),
+		"event_google_id": invitation.EventGoogleID,
+		"creator_id":      invitation.CreatorID.String(),
+		"status":          invitation.Status,
+		"event":           invitation.EventData,
 	}
-
-	socialData, err := s.authRepo.GetSocialLoginByUserIDAndProvider(ctx, userID, provider.ID)
-	if err != nil || socialData == nil {
-		return fmt.Errorf("google social login not found")
+	if invitation.InviteeID != nil {
+		data["invitee_id"] = invitation.InviteeID.String()
 	}
+	if invitation.InviteeEmail != nil {
+		data["invitee_email"] = *invitation.InviteeEmail
+	}
+	s.webhookSvc.Emit(ctx, &creatorID, constants.WebhookEventInvitationResponded, data)
+}
 
-	if socialData.AccessToken == nil {
-		return fmt.Errorf("google token not found")
+// updateGoogleEventStatus updates the attendee response status on Google Calendar using userID's Google token.
+// attendeeEmail defaults to the user's own Google address when empty.
+func (s *InvitationService) updateGoogleEventStatus(ctx context.Context, userID uuid.UUID, attendeeEmail string, eventGoogleID string, status string) error {
+	logger.Info("updateGoogleEventStatus:Start", "user_id", userID, "event_id", eventGoogleID, "status", status)
+
+	// 1. Get a valid Google token (refreshed when it is about to expire) and the account's email
+	googleToken, err := s.tokens.ValidToken(ctx, userID, "google", authService.TokenExpiryLeeway)
+	if err != nil {
+		logger.Error("updateGoogleEventStatus:Token:Error", "user_id", userID, "error", err)
+		return fmt.Errorf("google token not available: %w", err)
 	}
-	token := *socialData.AccessToken
+	token := googleToken.AccessToken
 
-	// Get email from social login
-	if socialData.ProviderEmail == nil {
-		return fmt.Errorf("google email not found in social login")
+	email := attendeeEmail
+	if email == "" {
+		if googleToken.Email == "" {
+			return fmt.Errorf("google email not found in social login")
+		}
+		email = googleToken.Email
 	}
-	email := *socialData.ProviderEmail
 	logger.Info("updateGoogleEventStatus:UserEmail", "email", email)
 
 	// 3. GET current event to retrieve all attendees
-	eventURL := fmt.Sprintf("https://www.googleapis.com/calendar/v3/calendars/primary/events/%s", eventGoogleID)
-
-	getReq, _ := http.NewRequest("GET", eventURL, nil)
-	getReq.Header.Set("Authorization", "Bearer "+token)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	getResp, err := client.Do(getReq)
+	eventPath := googlecalendar.EventPath(eventGoogleID)
+
+	getResp, err := s.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      http.MethodGet,
+		Path:        eventPath,
+		AccessToken: token,
+	})
 	if err != nil {
 		logger.Error("updateGoogleEventStatus:GET:Error", "error", err)
 		return err
@@ -255,7 +298,7 @@ func (s *InvitationService) updateGoogleEventStatus(ctx context.Context, userID
 		if !ok {
 			continue
 		}
-		if attendee["email"] == email {
+		if addr, _ := attendee["email"].(string); strings.EqualFold(addr, email) {
 			attendee["responseStatus"] = status
 			found = true
 			logger.Info("updateGoogleEventStatus:FoundAttendee", "email", email, "new_status", status)
@@ -274,11 +317,13 @@ func (s *InvitationService) updateGoogleEventStatus(ctx context.Context, userID
 	}
 
 	jsonBody, _ := json.Marshal(patchPayload)
-	patchReq, _ := http.NewRequest("PATCH", eventURL, bytes.NewBuffer(jsonBody))
-	patchReq.Header.Set("Authorization", "Bearer "+token)
-	patchReq.Header.Set("Content-Type", "application/json")
-
-	patchResp, err := client.Do(patchReq)
+	patchResp, err := s.google.Do(ctx, &googlecalendar.Request{
+		UserID:      userID,
+		Method:      http.MethodPatch,
+		Path:        eventPath,
+		AccessToken: token,
+		Body:        jsonBody,
+	})
 	if err != nil {
 		logger.Error("updateGoogleEventStatus:PATCH:Error", "error", err)
 		return err
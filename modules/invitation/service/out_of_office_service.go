@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/invitation/dto"
+	"go-api-starter/modules/invitation/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+// OutOfOffice is an invitee's leave overlapping an event
+type OutOfOffice struct {
+	Message    string
+	Until      time.Time
+	DelegateID *uuid.UUID // set when requests during the leave go to another user
+}
+
+// handleOutOfOffice answers a new invitation for an invitee who is out of office during the event:
+// the invitation is declined and, when the invitee has a delegate, the delegate is invited instead.
+// It reports whether the invitation was handled (and the invitee must not be notified).
+func (s *InvitationService) handleOutOfOffice(ctx context.Context, req *dto.CreateInvitationRequest, invitation *entity.EventInvitation, allowDelegate bool) bool {
+	if s.scheduler == nil || invitation.InviteeID == nil {
+		return false
+	}
+	start, errStart := time.Parse(time.RFC3339, req.EventData.StartTime)
+	end, errEnd := time.Parse(time.RFC3339, req.EventData.EndTime)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	inviteeID := *invitation.InviteeID
+	ooo, err := s.scheduler.OutOfOffice(ctx, inviteeID, start, end)
+	if err != nil {
+		logger.Error("InvitationService:handleOutOfOffice:Error", "invitee_id", inviteeID, "error", err)
+		return false
+	}
+	if ooo == nil {
+		return false
+	}
+
+	if err := s.repo.UpdateStatus(ctx, invitation.ID, string(entity.InvitationStatusDeclined)); err != nil {
+		logger.Error("InvitationService:handleOutOfOffice:Decline:Error", "invitation_id", invitation.ID, "error", err)
+		return false
+	}
+	invitation.Status = entity.InvitationStatusDeclined
+	s.emitResponded(ctx, invitation)
+
+	go func() {
+		bgCtx := context.Background()
+		if err := s.updateGoogleEventStatus(bgCtx, inviteeID, "", invitation.EventGoogleID, "declined"); err != nil {
+			logger.Error("handleOutOfOffice:GoogleSync:Error", "error", err, "event_id", invitation.EventGoogleID)
+		}
+	}()
+
+	inviteeName := "Người được mời"
+	if invitee, err := s.authRepo.GetUserByIdentifier(ctx, inviteeID.String()); err == nil && invitee != nil {
+		if invitee.Username != nil && *invitee.Username != "" {
+			inviteeName = *invitee.Username
+		} else if invitee.Email != nil {
+			inviteeName = *invitee.Email
+		}
+	}
+
+	message := fmt.Sprintf("%s đang nghỉ đến %s nên đã tự động từ chối sự kiện: %s", inviteeName, ooo.Until.Format("02/01/2006"), req.EventData.Title)
+	delegated := allowDelegate && ooo.DelegateID != nil && *ooo.DelegateID != req.CreatorID
+	if delegated {
+		message = fmt.Sprintf("%s đang nghỉ đến %s; lời mời sự kiện %s đã được chuyển cho người được ủy quyền", inviteeName, ooo.Until.Format("02/01/2006"), req.EventData.Title)
+	}
+	if ooo.Message != "" {
+		message += ": " + ooo.Message
+	}
+
+	data := map[string]interface{}{
+		"invitation_id": invitation.ID.String(),
+		"event_id":      req.EventGoogleID,
+		"invitee_id":    inviteeID.String(),
+		"until":         ooo.Until.Format(time.RFC3339),
+	}
+	if delegated {
+		data["delegate_id"] = ooo.DelegateID.String()
+	}
+	if err := s.notifService.Create(ctx, &notifDto.CreateNotificationRequest{
+		UserID:  req.CreatorID,
+		Title:   "Người được mời đang nghỉ",
+		Message: message,
+		Type:    "invitation_out_of_office",
+		Data:    data,
+	}); err != nil {
+		logger.Error("InvitationService:handleOutOfOffice:Notify:Error:", err)
+	}
+
+	if delegated {
+		// The delegate's own out-of-office period declines without delegating further
+		s.inviteUser(ctx, req, *ooo.DelegateID, false)
+	}
+
+	logger.Info("InvitationService:handleOutOfOffice:Handled", "invitation_id", invitation.ID, "delegated", delegated)
+	return true
+}
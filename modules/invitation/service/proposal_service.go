@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/invitation/dto"
+	"go-api-starter/modules/invitation/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+
+	"github.com/google/uuid"
+)
+
+const maxProposalSlots = 5
+
+// EventScheduler gives the invitation module access to calendars without importing the calendar module.
+// It is provided by the calendar module during Init.
+type EventScheduler interface {
+	// IsBusy reports whether any of the users is busy between start and end
+	IsBusy(ctx context.Context, userIDs []uuid.UUID, start, end time.Time) (bool, error)
+	// Reschedule moves the creator's event and propagates the change to every invitee
+	Reschedule(ctx context.Context, creatorID uuid.UUID, eventGoogleID string, startTime, endTime, timezone string) error
+	// OutOfOffice returns the user's out-of-office period overlapping start..end, or nil when available
+	OutOfOffice(ctx context.Context, userID uuid.UUID, start, end time.Time) (*OutOfOffice, error)
+}
+
+func (s *InvitationService) SetEventScheduler(scheduler EventScheduler) {
+	s.scheduler = scheduler
+}
+
+// ProposeNewTime lets an invitee suggest alternative slots for an event
+func (s *InvitationService) ProposeNewTime(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID, req *dto.ProposeTimeRequest) (*dto.ProposalResponse, *errors.AppError) {
+	invitation, err := s.repo.GetByID(ctx, invitationID)
+	if err != nil || invitation == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "invitation not found", err)
+	}
+	if invitation.InviteeID == nil || *invitation.InviteeID != userID {
+		return nil, errors.NewAppError(errors.ErrForbidden, "not the invitee", nil)
+	}
+	if invitation.Status == entity.InvitationStatusCancelled {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event has been cancelled", nil)
+	}
+
+	if len(req.Slots) == 0 || len(req.Slots) > maxProposalSlots {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("between 1 and %d slots are required", maxProposalSlots), nil)
+	}
+
+	now := time.Now()
+	slots := make(entity.ProposalSlots, 0, len(req.Slots))
+	for _, slot := range req.Slots {
+		start, errStart := time.Parse(time.RFC3339, slot.StartTime)
+		end, errEnd := time.Parse(time.RFC3339, slot.EndTime)
+		if errStart != nil || errEnd != nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "slot times must be RFC3339", nil)
+		}
+		if !end.After(start) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "slot end_time must be after start_time", nil)
+		}
+		if start.Before(now) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "slots must be in the future", nil)
+		}
+
+		if req.OnlyMutuallyFree && s.scheduler != nil {
+			busy, err := s.scheduler.IsBusy(ctx, []uuid.UUID{invitation.CreatorID, userID}, start, end)
+			if err != nil {
+				logger.Warn("InvitationService:ProposeNewTime:IsBusy:Error", "error", err)
+			} else if busy {
+				continue
+			}
+		}
+
+		slots = append(slots, entity.ProposalSlot{StartTime: slot.StartTime, EndTime: slot.EndTime})
+	}
+	if len(slots) == 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "none of the proposed slots are free for both parties", nil)
+	}
+
+	proposal := &entity.InvitationProposal{
+		InvitationID:  invitation.ID,
+		EventGoogleID: invitation.EventGoogleID,
+		CreatorID:     invitation.CreatorID,
+		ProposerID:    userID,
+		Slots:         slots,
+		Note:          optionalString(req.Note),
+		Status:        entity.ProposalStatusPending,
+	}
+	if err := s.repo.CreateProposal(ctx, proposal); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "failed to create proposal", err)
+	}
+	s.recordProposalHistory(ctx, proposal.ID, entity.ProposalStatusPending, &userID, proposal.Note)
+
+	notification := &notifDto.CreateNotificationRequest{
+		UserID:  invitation.CreatorID,
+		Title:   "Đề xuất thời gian mới",
+		Message: fmt.Sprintf("Một người được mời đã đề xuất %d khung giờ khác cho sự kiện: %s", len(slots), invitation.EventData.Title),
+		Type:    "time_proposal",
+		Data: map[string]interface{}{
+			"proposal_id":   proposal.ID.String(),
+			"invitation_id": invitation.ID.String(),
+			"event_id":      invitation.EventGoogleID,
+		},
+	}
+	if err := s.notifService.Create(ctx, notification); err != nil {
+		logger.Error("InvitationService:ProposeNewTime:Notify:Error:", err)
+	}
+
+	return s.toProposalResponse(ctx, proposal), nil
+}
+
+// ListProposals returns the proposals of an invitation; visible to its creator and invitee
+func (s *InvitationService) ListProposals(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) ([]dto.ProposalResponse, *errors.AppError) {
+	invitation, err := s.repo.GetByID(ctx, invitationID)
+	if err != nil || invitation == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "invitation not found", err)
+	}
+	isInvitee := invitation.InviteeID != nil && *invitation.InviteeID == userID
+	if invitation.CreatorID != userID && !isInvitee {
+		return nil, errors.NewAppError(errors.ErrForbidden, "not allowed to view proposals", nil)
+	}
+
+	proposals, err := s.repo.GetProposalsByInvitationID(ctx, invitationID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get proposals", err)
+	}
+	return s.toProposalResponses(ctx, proposals), nil
+}
+
+// ListIncomingProposals returns the proposals waiting for the creator's decision
+func (s *InvitationService) ListIncomingProposals(ctx context.Context, creatorID uuid.UUID) ([]dto.ProposalResponse, *errors.AppError) {
+	proposals, err := s.repo.GetPendingProposalsByCreatorID(ctx, creatorID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get proposals", err)
+	}
+	return s.toProposalResponses(ctx, proposals), nil
+}
+
+// AcceptProposal reschedules the event to the chosen slot, closes competing proposals and
+// marks the proposer as attending. Other invitees are re-notified by the event update propagation.
+func (s *InvitationService) AcceptProposal(ctx context.Context, proposalID uuid.UUID, creatorID uuid.UUID, req *dto.AcceptProposalRequest) (*dto.ProposalResponse, *errors.AppError) {
+	proposal, appErr := s.getProposalForCreator(ctx, proposalID, creatorID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if req.SlotIndex < 0 || req.SlotIndex >= len(proposal.Slots) {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid slot_index", nil)
+	}
+	if s.scheduler == nil {
+		return nil, errors.NewAppError(errors.ErrConfiguration, "calendar is not available", nil)
+	}
+
+	invitation, err := s.repo.GetByID(ctx, proposal.InvitationID)
+	if err != nil || invitation == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "invitation not found", err)
+	}
+	if invitation.Status == entity.InvitationStatusCancelled {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "event has been cancelled", nil)
+	}
+
+	// Claim the proposal and close its siblings before touching the calendar, so two concurrent accepts
+	// for the same event (of the same or different proposals) cannot both move it
+	claimed, supersededIDs, err := s.repo.ClaimAcceptedProposal(ctx, proposalID, creatorID, proposal.EventGoogleID, req.SlotIndex)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "failed to accept proposal", err)
+	}
+	if !claimed {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "proposal is no longer pending", nil)
+	}
+
+	slot := proposal.Slots[req.SlotIndex]
+	if err := s.scheduler.Reschedule(ctx, creatorID, proposal.EventGoogleID, slot.StartTime, slot.EndTime, invitation.EventData.Timezone); err != nil {
+		logger.Error("InvitationService:AcceptProposal:Reschedule:Error", "proposal_id", proposalID, "error", err)
+		// The event was not moved, so the proposals are still open for a later decision
+		if reopenErr := s.repo.ReopenProposals(ctx, proposalID, supersededIDs); reopenErr != nil {
+			logger.Error("InvitationService:AcceptProposal:Reopen:Error", "proposal_id", proposalID, "error", reopenErr)
+		}
+		return nil, errors.NewAppError(errors.ErrThirdParty, "failed to reschedule event", err)
+	}
+	s.recordProposalHistory(ctx, proposalID, entity.ProposalStatusAccepted, &creatorID, optionalString(req.Note))
+	for _, id := range supersededIDs {
+		s.recordProposalHistory(ctx, id, entity.ProposalStatusSuperseded, &creatorID, nil)
+	}
+
+	// The proposer asked for this time, so their RSVP is accepted rather than reset to pending
+	if err := s.repo.UpdateStatus(ctx, proposal.InvitationID, string(entity.InvitationStatusAccepted)); err != nil {
+		logger.Error("InvitationService:AcceptProposal:UpdateInvitationStatus:Error", "error", err)
+	}
+
+	s.notifyProposer(ctx, proposal, "Đề xuất thời gian đã được chấp nhận",
+		fmt.Sprintf("Sự kiện '%s' đã được dời sang %s", invitation.EventData.Title, formatEventTime(entity.EventData{
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+			Timezone:  invitation.EventData.Timezone,
+		})))
+
+	updated, err := s.repo.GetProposalByID(ctx, proposalID)
+	if err != nil || updated == nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get proposal", err)
+	}
+	return s.toProposalResponse(ctx, updated), nil
+}
+
+// RejectProposal declines a counter-proposal and keeps the event as is
+func (s *InvitationService) RejectProposal(ctx context.Context, proposalID uuid.UUID, creatorID uuid.UUID, req *dto.RejectProposalRequest) (*dto.ProposalResponse, *errors.AppError) {
+	proposal, appErr := s.getProposalForCreator(ctx, proposalID, creatorID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	decided, err := s.repo.DecideProposal(ctx, proposalID, entity.ProposalStatusRejected, nil)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "failed to reject proposal", err)
+	}
+	if !decided {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "proposal is no longer pending", nil)
+	}
+	s.recordProposalHistory(ctx, proposalID, entity.ProposalStatusRejected, &creatorID, optionalString(req.Note))
+
+	message := "Người tổ chức đã từ chối đề xuất thời gian của bạn"
+	if req.Note != "" {
+		message += ": " + req.Note
+	}
+	s.notifyProposer(ctx, proposal, "Đề xuất thời gian bị từ chối", message)
+
+	updated, err := s.repo.GetProposalByID(ctx, proposalID)
+	if err != nil || updated == nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get proposal", err)
+	}
+	return s.toProposalResponse(ctx, updated), nil
+}
+
+func (s *InvitationService) getProposalForCreator(ctx context.Context, proposalID uuid.UUID, creatorID uuid.UUID) (*entity.InvitationProposal, *errors.AppError) {
+	proposal, err := s.repo.GetProposalByID(ctx, proposalID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get proposal", err)
+	}
+	if proposal == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "proposal not found", nil)
+	}
+	if proposal.CreatorID != creatorID {
+		return nil, errors.NewAppError(errors.ErrForbidden, "only the event creator can decide on proposals", nil)
+	}
+	if proposal.Status != entity.ProposalStatusPending {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "proposal is no longer pending", nil)
+	}
+	return proposal, nil
+}
+
+func (s *InvitationService) recordProposalHistory(ctx context.Context, proposalID uuid.UUID, status entity.ProposalStatus, actorID *uuid.UUID, note *string) {
+	history := &entity.ProposalHistory{
+		ProposalID: proposalID,
+		Status:     status,
+		ActorID:    actorID,
+		Note:       note,
+	}
+	if err := s.repo.AddProposalHistory(ctx, history); err != nil {
+		logger.Error("InvitationService:recordProposalHistory:Error", "proposal_id", proposalID, "error", err)
+	}
+}
+
+func (s *InvitationService) notifyProposer(ctx context.Context, proposal *entity.InvitationProposal, title, message string) {
+	notification := &notifDto.CreateNotificationRequest{
+		UserID:  proposal.ProposerID,
+		Title:   title,
+		Message: message,
+		Type:    "time_proposal",
+		Data: map[string]interface{}{
+			"proposal_id":   proposal.ID.String(),
+			"invitation_id": proposal.InvitationID.String(),
+			"event_id":      proposal.EventGoogleID,
+		},
+	}
+	if err := s.notifService.Create(ctx, notification); err != nil {
+		logger.Error("InvitationService:notifyProposer:Error:", err)
+	}
+}
+
+func (s *InvitationService) toProposalResponses(ctx context.Context, proposals []entity.InvitationProposal) []dto.ProposalResponse {
+	result := make([]dto.ProposalResponse, 0, len(proposals))
+	for i := range proposals {
+		result = append(result, *s.toProposalResponse(ctx, &proposals[i]))
+	}
+	return result
+}
+
+func (s *InvitationService) toProposalResponse(ctx context.Context, proposal *entity.InvitationProposal) *dto.ProposalResponse {
+	slots := make([]dto.ProposalSlotDTO, 0, len(proposal.Slots))
+	for _, slot := range proposal.Slots {
+		slots = append(slots, dto.ProposalSlotDTO{StartTime: slot.StartTime, EndTime: slot.EndTime})
+	}
+
+	history := make([]dto.ProposalHistoryResponse, 0)
+	entries, err := s.repo.GetProposalHistory(ctx, proposal.ID)
+	if err != nil {
+		logger.Error("InvitationService:toProposalResponse:GetHistory:Error", "proposal_id", proposal.ID, "error", err)
+	}
+	for _, h := range entries {
+		history = append(history, dto.ProposalHistoryResponse{
+			Status:    string(h.Status),
+			ActorID:   h.ActorID,
+			Note:      h.Note,
+			CreatedAt: h.CreatedAt,
+		})
+	}
+
+	return &dto.ProposalResponse{
+		ID:                proposal.ID,
+		InvitationID:      proposal.InvitationID,
+		EventGoogleID:     proposal.EventGoogleID,
+		CreatorID:         proposal.CreatorID,
+		ProposerID:        proposal.ProposerID,
+		Slots:             slots,
+		Note:              proposal.Note,
+		Status:            string(proposal.Status),
+		AcceptedSlotIndex: proposal.AcceptedSlotIndex,
+		DecidedAt:         proposal.DecidedAt,
+		CreatedAt:         proposal.CreatedAt,
+		History:           history,
+	}
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,454 @@
+package controller
+
+import (
+	"go-api-starter/core/errors"
+	"go-api-starter/modules/meeting/dto"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GetAgenda handles GET /events/:id/agenda
+// @Summary Chương trình cuộc họp
+// @Description Các mục chương trình của sự kiện theo thứ tự, kèm người phụ trách và thời lượng. Chủ trì và người tham gia đều xem được
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} dto.AgendaResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/agenda [get]
+func (c *MeetingController) GetAgenda(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	result, appErr := c.MeetingService.GetAgenda(ctx.Request().Context(), eventID, userID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda retrieved")
+}
+
+// SaveAgenda handles PUT /events/:id/agenda
+// @Summary Cập nhật chương trình cuộc họp
+// @Description Thay toàn bộ chương trình của sự kiện (chỉ chủ trì). Giữ id để cập nhật mục cũ, bỏ trống id để thêm mục mới; mục không được gửi lên sẽ bị xoá. Người phụ trách phải là chủ trì hoặc người tham gia
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.SaveAgendaRequest true "Chương trình"
+// @Success 200 {object} dto.AgendaResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/agenda [put]
+func (c *MeetingController) SaveAgenda(ctx echo.Context) error {
+	hostID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	var req dto.SaveAgendaRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.SaveAgenda(ctx.Request().Context(), eventID, hostID, &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda saved successfully")
+}
+
+// ApplyAgendaTemplate handles POST /events/:id/agenda/apply-template
+// @Summary Áp dụng mẫu chương trình
+// @Description Thay chương trình của sự kiện bằng một mẫu của chủ trì. Người phụ trách không tham gia sự kiện sẽ được bỏ trống
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.ApplyAgendaTemplateRequest true "Mẫu chương trình"
+// @Success 200 {object} dto.AgendaResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/agenda/apply-template [post]
+func (c *MeetingController) ApplyAgendaTemplate(ctx echo.Context) error {
+	hostID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	var req dto.ApplyAgendaTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.ApplyAgendaTemplate(ctx.Request().Context(), eventID, hostID, &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda template applied successfully")
+}
+
+// GetNotes handles GET /events/:id/notes
+// @Summary Ghi chú cuộc họp
+// @Description Ghi chú chung của sự kiện. version dùng cho lần sửa tiếp theo
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} dto.NotesResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/notes [get]
+func (c *MeetingController) GetNotes(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	result, appErr := c.MeetingService.GetNotes(ctx.Request().Context(), eventID, userID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Notes retrieved")
+}
+
+// SaveNotes handles PUT /events/:id/notes
+// @Summary Cập nhật ghi chú cuộc họp
+// @Description Lưu ghi chú chung của sự kiện. Gửi kèm version đã đọc; nếu người khác đã sửa trước đó, yêu cầu bị từ chối và cần tải lại ghi chú
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.SaveNotesRequest true "Ghi chú"
+// @Success 200 {object} dto.NotesResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/notes [put]
+func (c *MeetingController) SaveNotes(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	var req dto.SaveNotesRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.SaveNotes(ctx.Request().Context(), eventID, userID, &req)
+	if appErr != nil {
+		status := http.StatusBadRequest
+		if appErr.Code == errors.ErrInvalidState {
+			status = http.StatusConflict
+		}
+		return ctx.JSON(status, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Notes saved successfully")
+}
+
+// ListActionItems handles GET /events/:id/action-items
+// @Summary Công việc sau cuộc họp
+// @Description Danh sách công việc của sự kiện
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {array} dto.ActionItemResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/action-items [get]
+func (c *MeetingController) ListActionItems(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	result, appErr := c.MeetingService.ListActionItems(ctx.Request().Context(), eventID, userID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Action items retrieved")
+}
+
+// CreateActionItem handles POST /events/:id/action-items
+// @Summary Thêm công việc
+// @Description Thêm công việc cho sự kiện. Người được giao phải là chủ trì hoặc người tham gia và sẽ nhận thông báo, kèm nhắc nhở trước hạn một ngày
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.CreateActionItemRequest true "Công việc"
+// @Success 200 {object} dto.ActionItemResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/action-items [post]
+func (c *MeetingController) CreateActionItem(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	var req dto.CreateActionItemRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.CreateActionItem(ctx.Request().Context(), eventID, userID, &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Action item created successfully")
+}
+
+// UpdateActionItem handles PUT /events/:id/action-items/:item_id
+// @Summary Cập nhật công việc
+// @Description Sửa tiêu đề, người được giao, hạn hoặc trạng thái (open/done) của công việc. Chủ trì, người tạo và người được giao có thể sửa
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param item_id path string true "Action item ID"
+// @Param request body dto.UpdateActionItemRequest true "Công việc"
+// @Success 200 {object} dto.ActionItemResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/action-items/{item_id} [put]
+func (c *MeetingController) UpdateActionItem(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	itemID, err := uuid.Parse(ctx.Param("item_id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid action item ID")
+	}
+
+	var req dto.UpdateActionItemRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.UpdateActionItem(ctx.Request().Context(), eventID, itemID, userID, &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Action item updated successfully")
+}
+
+// DeleteActionItem handles DELETE /events/:id/action-items/:item_id
+// @Summary Xoá công việc
+// @Description Xoá công việc của sự kiện (chủ trì hoặc người tạo)
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param item_id path string true "Action item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/action-items/{item_id} [delete]
+func (c *MeetingController) DeleteActionItem(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	itemID, err := uuid.Parse(ctx.Param("item_id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid action item ID")
+	}
+
+	appErr := c.MeetingService.DeleteActionItem(ctx.Request().Context(), eventID, itemID, userID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, nil, "Action item deleted successfully")
+}
+
+// GetMyActionItems handles GET /action-items/mine
+// @Summary Công việc của tôi
+// @Description Công việc được giao cho người dùng ở mọi sự kiện, sắp theo hạn. Mặc định chỉ trả về công việc đang mở
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "open (mặc định), done hoặc all"
+// @Success 200 {array} dto.ActionItemResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/action-items/mine [get]
+func (c *MeetingController) GetMyActionItems(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	result, appErr := c.MeetingService.GetMyActionItems(ctx.Request().Context(), userID, ctx.QueryParam("status"))
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Action items retrieved")
+}
+
+// ListAgendaTemplates handles GET /agenda-templates
+// @Summary Mẫu chương trình
+// @Description Danh sách mẫu chương trình của người dùng, dùng lại cho các cuộc họp định kỳ
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.AgendaTemplateResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/agenda-templates [get]
+func (c *MeetingController) ListAgendaTemplates(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	result, appErr := c.MeetingService.ListAgendaTemplates(ctx.Request().Context(), userID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda templates retrieved")
+}
+
+// CreateAgendaTemplate handles POST /agenda-templates
+// @Summary Tạo mẫu chương trình
+// @Description Tạo mẫu chương trình mới; tên mẫu không được trùng
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveAgendaTemplateRequest true "Mẫu chương trình"
+// @Success 200 {object} dto.AgendaTemplateResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/agenda-templates [post]
+func (c *MeetingController) CreateAgendaTemplate(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	var req dto.SaveAgendaTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.SaveAgendaTemplate(ctx.Request().Context(), userID, "", &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda template created successfully")
+}
+
+// UpdateAgendaTemplate handles PUT /agenda-templates/:id
+// @Summary Cập nhật mẫu chương trình
+// @Description Thay tên và các mục của mẫu chương trình. Chương trình đã áp dụng từ mẫu không bị thay đổi
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param request body dto.SaveAgendaTemplateRequest true "Mẫu chương trình"
+// @Success 200 {object} dto.AgendaTemplateResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/agenda-templates/{id} [put]
+func (c *MeetingController) UpdateAgendaTemplate(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	var req dto.SaveAgendaTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.SaveAgendaTemplate(ctx.Request().Context(), userID, ctx.Param("id"), &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Agenda template updated successfully")
+}
+
+// DeleteAgendaTemplate handles DELETE /agenda-templates/:id
+// @Summary Xoá mẫu chương trình
+// @Description Xoá mẫu chương trình; chương trình đã áp dụng từ mẫu được giữ nguyên
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.AppError
+// @Router /private/agenda-templates/{id} [delete]
+func (c *MeetingController) DeleteAgendaTemplate(ctx echo.Context) error {
+	userID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	appErr := c.MeetingService.DeleteAgendaTemplate(ctx.Request().Context(), userID, ctx.Param("id"))
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, nil, "Agenda template deleted successfully")
+}
@@ -250,3 +250,68 @@ func (c *MeetingController) SelectSlot(ctx echo.Context) error {
 
 	return c.SuccessResponse(ctx, result, "Slot selected successfully")
 }
+
+// GetAttendance handles GET /events/:id/attendance
+// @Summary Điểm danh sự kiện
+// @Description Danh sách người tham gia sự kiện (kể cả khách đặt lịch) cùng trạng thái điểm danh; status rỗng khi chưa điểm danh
+// @Tags Meeting
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} dto.AttendanceResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/attendance [get]
+func (c *MeetingController) GetAttendance(ctx echo.Context) error {
+	hostID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	result, appErr := c.MeetingService.GetAttendance(ctx.Request().Context(), eventID, hostID)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Attendance retrieved")
+}
+
+// MarkAttendance handles PUT /events/:id/attendance
+// @Summary Điểm danh người tham gia
+// @Description Đánh dấu từng người tham gia là attended, no_show hoặc late sau khi sự kiện đã bắt đầu. Người dùng xác định bằng user_id, khách đặt lịch bằng email. Số lần vắng mặt của khách được dùng cho chính sách no-show của trang đặt lịch
+// @Tags Meeting
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body dto.MarkAttendanceRequest true "Điểm danh"
+// @Success 200 {object} dto.AttendanceResponse
+// @Failure 400 {object} errors.AppError
+// @Router /private/meetings/{id}/attendance [put]
+func (c *MeetingController) MarkAttendance(ctx echo.Context) error {
+	hostID, err := c.getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid event ID")
+	}
+
+	var req dto.MarkAttendanceRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid request body")
+	}
+
+	result, appErr := c.MeetingService.MarkAttendance(ctx.Request().Context(), eventID, hostID, &req)
+	if appErr != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": appErr.Message})
+	}
+
+	return c.SuccessResponse(ctx, result, "Attendance marked successfully")
+}
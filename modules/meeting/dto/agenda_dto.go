@@ -0,0 +1,165 @@
+package dto
+
+import (
+	"go-api-starter/modules/meeting/entity"
+	"time"
+)
+
+// ===================== Request DTOs =====================
+
+// AgendaItemInput is one agenda point; items are kept in the order they are sent
+type AgendaItemInput struct {
+	ID              string `json:"id"`               // keep an existing item, empty for a new one
+	Title           string `json:"title"`            // required, max 200 characters
+	OwnerID         string `json:"owner_id"`         // host or participant presenting the item
+	DurationMinutes int    `json:"duration_minutes"` // time box, 0 for none
+}
+
+// SaveAgendaRequest replaces the agenda of an event
+type SaveAgendaRequest struct {
+	Items []AgendaItemInput `json:"items"`
+}
+
+// ApplyAgendaTemplateRequest replaces the agenda of an event with a template
+type ApplyAgendaTemplateRequest struct {
+	TemplateID string `json:"template_id" validate:"required"`
+}
+
+// SaveNotesRequest replaces the shared notes of an event
+type SaveNotesRequest struct {
+	Content string `json:"content"`
+	Version int    `json:"version"` // version the edit is based on, 0 for the first notes
+}
+
+// CreateActionItemRequest adds an action item to an event
+type CreateActionItemRequest struct {
+	Title      string `json:"title" validate:"required"`
+	AssigneeID string `json:"assignee_id"` // host or participant
+	DueDate    string `json:"due_date"`    // RFC3339
+}
+
+// UpdateActionItemRequest changes an action item; omitted fields are kept, empty strings clear them
+type UpdateActionItemRequest struct {
+	Title      *string `json:"title"`
+	AssigneeID *string `json:"assignee_id"`
+	DueDate    *string `json:"due_date"` // RFC3339
+	Status     *string `json:"status"`   // open or done
+}
+
+// SaveAgendaTemplateRequest creates or replaces an agenda template
+type SaveAgendaTemplateRequest struct {
+	Name  string            `json:"name" validate:"required"`
+	Items []AgendaItemInput `json:"items"` // id is ignored
+}
+
+// ===================== Response DTOs =====================
+
+// AgendaResponse is the agenda of an event
+type AgendaResponse struct {
+	EventID         string               `json:"event_id"`
+	Items           []AgendaItemResponse `json:"items"`
+	TotalMinutes    int                  `json:"total_minutes"`    // sum of the time boxes
+	DurationMinutes int                  `json:"duration_minutes"` // length of the event
+}
+
+// AgendaItemResponse is one agenda point
+type AgendaItemResponse struct {
+	ID              string `json:"id,omitempty"`
+	Position        int    `json:"position"`
+	Title           string `json:"title"`
+	OwnerID         string `json:"owner_id,omitempty"`
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
+}
+
+// NotesResponse is the shared notes document of an event
+type NotesResponse struct {
+	EventID   string     `json:"event_id"`
+	Content   string     `json:"content"`
+	Version   int        `json:"version"` // send it back with the next edit; 0 when there are no notes yet
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ActionItemResponse is an action item of an event
+type ActionItemResponse struct {
+	ID          string     `json:"id"`
+	EventID     string     `json:"event_id"`
+	EventTitle  string     `json:"event_title,omitempty"`
+	Title       string     `json:"title"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Status      string     `json:"status"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AgendaTemplateResponse is a reusable agenda
+type AgendaTemplateResponse struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Items        []AgendaItemResponse `json:"items"`
+	TotalMinutes int                  `json:"total_minutes"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// ===================== Mapper Functions =====================
+
+// ToAgendaItemResponse maps an agenda item entity to DTO
+func ToAgendaItemResponse(item *entity.AgendaItem) AgendaItemResponse {
+	resp := AgendaItemResponse{
+		ID:       item.ID.String(),
+		Position: item.Position,
+		Title:    item.Title,
+	}
+	if item.OwnerID != nil {
+		resp.OwnerID = item.OwnerID.String()
+	}
+	if item.DurationMinutes != nil {
+		resp.DurationMinutes = *item.DurationMinutes
+	}
+	return resp
+}
+
+// ToActionItemResponse maps an action item entity to DTO
+func ToActionItemResponse(item *entity.ActionItem) ActionItemResponse {
+	resp := ActionItemResponse{
+		ID:          item.ID.String(),
+		EventID:     item.EventID.String(),
+		EventTitle:  item.EventTitle,
+		Title:       item.Title,
+		DueDate:     item.DueDate,
+		Status:      string(item.Status),
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+	}
+	if item.AssigneeID != nil {
+		resp.AssigneeID = item.AssigneeID.String()
+	}
+	if item.CreatedBy != nil {
+		resp.CreatedBy = item.CreatedBy.String()
+	}
+	return resp
+}
+
+// ToAgendaTemplateResponse maps an agenda template entity to DTO
+func ToAgendaTemplateResponse(t *entity.AgendaTemplate) AgendaTemplateResponse {
+	resp := AgendaTemplateResponse{
+		ID:        t.ID.String(),
+		Name:      t.Name,
+		Items:     make([]AgendaItemResponse, 0, len(t.Items)),
+		UpdatedAt: t.UpdatedAt,
+	}
+	for i, item := range t.Items {
+		itemResp := AgendaItemResponse{Position: i, Title: item.Title}
+		if item.OwnerID != nil {
+			itemResp.OwnerID = item.OwnerID.String()
+		}
+		if item.DurationMinutes != nil {
+			itemResp.DurationMinutes = *item.DurationMinutes
+			resp.TotalMinutes += *item.DurationMinutes
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+	return resp
+}
@@ -49,6 +49,18 @@ type SelectSlotRequest struct {
 	EndTime   string `json:"end_time"`   // RFC3339 format
 }
 
+// MarkAttendanceRequest marks the attendance of participants after the event
+type MarkAttendanceRequest struct {
+	Attendance []AttendanceMark `json:"attendance" validate:"required"`
+}
+
+// AttendanceMark is the attendance of one participant, identified by user_id (meeting participant) or email (booking guest)
+type AttendanceMark struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Status string `json:"status"` // attended, no_show or late
+}
+
 // ===================== Response DTOs =====================
 
 // EventResponse for event details
@@ -77,6 +89,21 @@ type ParticipantResponse struct {
 	HasCalendarConnected bool   `json:"has_calendar_connected"`
 }
 
+// AttendanceResponse lists the participants of an event with their attendance
+type AttendanceResponse struct {
+	EventID      string                  `json:"event_id"`
+	Participants []ParticipantAttendance `json:"participants"`
+}
+
+// ParticipantAttendance is the attendance of one participant; Status is empty until the host marks it
+type ParticipantAttendance struct {
+	UserID   string     `json:"user_id,omitempty"`
+	Email    string     `json:"email,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Status   string     `json:"status"`
+	MarkedAt *time.Time `json:"marked_at,omitempty"`
+}
+
 // FindSlotsResponse for suggested time slots
 type FindSlotsResponse struct {
 	EventID      string                `json:"event_id"`
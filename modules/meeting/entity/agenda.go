@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgendaItem is one point of an event's agenda (from event_agenda_items table)
+type AgendaItem struct {
+	ID              uuid.UUID  `db:"id" json:"id"`
+	EventID         uuid.UUID  `db:"event_id" json:"event_id"`
+	Position        int        `db:"position" json:"position"`
+	Title           string     `db:"title" json:"title"`
+	OwnerID         *uuid.UUID `db:"owner_id" json:"owner_id,omitempty"`
+	DurationMinutes *int       `db:"duration_minutes" json:"duration_minutes,omitempty"` // time box
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// EventNotes is the shared notes document of an event (from event_notes table)
+type EventNotes struct {
+	EventID   uuid.UUID  `db:"event_id" json:"event_id"`
+	Content   string     `db:"content" json:"content"`
+	Version   int        `db:"version" json:"version"`
+	UpdatedBy *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// ActionItemStatus represents the status of an action item
+type ActionItemStatus string
+
+const (
+	ActionItemStatusOpen ActionItemStatus = "open"
+	ActionItemStatusDone ActionItemStatus = "done"
+)
+
+// ActionItem is a follow-up task agreed in an event (from event_action_items table)
+type ActionItem struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	EventID     uuid.UUID        `db:"event_id" json:"event_id"`
+	Title       string           `db:"title" json:"title"`
+	AssigneeID  *uuid.UUID       `db:"assignee_id" json:"assignee_id,omitempty"`
+	DueDate     *time.Time       `db:"due_date" json:"due_date,omitempty"`
+	Status      ActionItemStatus `db:"status" json:"status"`
+	CreatedBy   *uuid.UUID       `db:"created_by" json:"created_by,omitempty"`
+	CompletedAt *time.Time       `db:"completed_at" json:"completed_at,omitempty"`
+	RemindedAt  *time.Time       `db:"reminded_at" json:"reminded_at,omitempty"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at" json:"updated_at"`
+
+	// Title of the event, filled by queries across events
+	EventTitle string `db:"event_title" json:"event_title,omitempty"`
+}
+
+// AgendaTemplateItem is an agenda point of a template
+type AgendaTemplateItem struct {
+	Title           string     `json:"title"`
+	OwnerID         *uuid.UUID `json:"owner_id,omitempty"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+}
+
+type AgendaTemplateItems []AgendaTemplateItem
+
+func (i AgendaTemplateItems) Value() (driver.Value, error) {
+	if i == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(i)
+}
+
+func (i *AgendaTemplateItems) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, i)
+}
+
+// AgendaTemplate is a reusable agenda of a user (from agenda_templates table)
+type AgendaTemplate struct {
+	ID        uuid.UUID           `db:"id" json:"id"`
+	OwnerID   uuid.UUID           `db:"owner_id" json:"owner_id"`
+	Name      string              `db:"name" json:"name"`
+	Items     AgendaTemplateItems `db:"items" json:"items"`
+	CreatedAt time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceStatus records whether a participant showed up to a scheduled event
+type AttendanceStatus string
+
+const (
+	AttendanceAttended AttendanceStatus = "attended"
+	AttendanceNoShow   AttendanceStatus = "no_show"
+	AttendanceLate     AttendanceStatus = "late"
+)
+
+// EventAttendance is the attendance of one participant of an event (from event_attendance table).
+// Meeting participants have a UserID, the guest of a booking an Email.
+type EventAttendance struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	EventID   uuid.UUID        `db:"event_id" json:"event_id"`
+	UserID    *uuid.UUID       `db:"user_id" json:"user_id,omitempty"`
+	Email     *string          `db:"email" json:"email,omitempty"`
+	Status    AttendanceStatus `db:"status" json:"status"`
+	MarkedBy  *uuid.UUID       `db:"marked_by" json:"marked_by,omitempty"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time        `db:"updated_at" json:"updated_at"`
+}
@@ -31,6 +31,19 @@ type Event struct {
 	Preferences     *string     `db:"preferences" json:"preferences,omitempty"` // JSONB as string
 	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+
+	// Link to the copy of this event on the host's calendar provider (two-way sync)
+	Provider          *string    `db:"provider" json:"provider,omitempty"`
+	ProviderEventID   *string    `db:"provider_event_id" json:"provider_event_id,omitempty"`
+	ProviderEtag      *string    `db:"provider_etag" json:"-"`
+	ProviderUpdatedAt *time.Time `db:"provider_updated_at" json:"provider_updated_at,omitempty"`
+	LastSyncedAt      *time.Time `db:"last_synced_at" json:"last_synced_at,omitempty"`
+	ProviderOffset    int        `db:"provider_offset_minutes" json:"-"` // provider start minus local start
+}
+
+// HasLocalChanges reports whether the event was edited locally since it was last synced with its provider
+func (e *Event) HasLocalChanges() bool {
+	return e.LastSyncedAt == nil || e.UpdatedAt.After(*e.LastSyncedAt)
 }
 
 // EventPreferences represents event scheduling preferences
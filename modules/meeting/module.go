@@ -1,22 +1,30 @@
 package meeting
 
 import (
+	"go-api-starter/core/constants"
 	"go-api-starter/core/database"
 	"go-api-starter/core/middleware"
 	"go-api-starter/modules/meeting/controller"
 	"go-api-starter/modules/meeting/repository"
 	"go-api-starter/modules/meeting/router"
 	"go-api-starter/modules/meeting/service"
+	notifService "go-api-starter/modules/notification/service"
+	webhookService "go-api-starter/modules/webhook/service"
+	"go-api-starter/workers"
 
 	"github.com/labstack/echo/v4"
 )
 
 // Init initializes the meeting module and registers routes
-func Init(e *echo.Echo, db database.Database, mw *middleware.Middleware) {
+func Init(e *echo.Echo, db database.Database, mw *middleware.Middleware, notifSvc *notifService.NotificationService, webhookSvc *webhookService.WebhookService) {
 	repo := repository.NewMeetingRepository(db)
-	svc := service.NewMeetingService(repo)
+	svc := service.NewMeetingService(repo, notifSvc, webhookSvc)
 	ctrl := controller.NewMeetingController(svc)
 	rtr := router.NewMeetingRouter(ctrl)
 
 	rtr.Setup(e, mw)
+
+	// Remind assignees of open action items that are due soon
+	workers.RegisterHandler(constants.TopicQueueActionItemReminders, svc.HandleActionItemReminderTask)
+	workers.RegisterPeriodicTask("0 * * * *", constants.TopicQueueActionItemReminders)
 }
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/meeting/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===================== Members =====================
+
+// IsEventMember reports whether userID is the host or a participant of the event
+func (r *MeetingRepository) IsEventMember(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND host_id = $2)
+		    OR EXISTS (SELECT 1 FROM user_events WHERE event_id = $1 AND user_id = $2)
+	`
+
+	var member bool
+	err := r.DB.GetContext(ctx, &member, query, eventID, userID)
+	if err != nil {
+		logger.Error("MeetingRepository:IsEventMember", err)
+		return false, err
+	}
+	return member, nil
+}
+
+// ===================== Agenda (event_agenda_items) =====================
+
+func (r *MeetingRepository) GetAgendaItems(ctx context.Context, eventID uuid.UUID) ([]entity.AgendaItem, error) {
+	query := `SELECT * FROM event_agenda_items WHERE event_id = $1 ORDER BY position`
+
+	var items []entity.AgendaItem
+	err := r.DB.SelectContext(ctx, &items, query, eventID)
+	if err != nil {
+		logger.Error("MeetingRepository:GetAgendaItems", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceAgendaItems makes items the event's agenda, in order, in one statement.
+// Items with an ID of this event are updated in place; the rest are created and missing items removed.
+func (r *MeetingRepository) ReplaceAgendaItems(ctx context.Context, eventID uuid.UUID, items []entity.AgendaItem) ([]entity.AgendaItem, error) {
+	type incoming struct {
+		ID              *uuid.UUID `json:"id"`
+		Title           string     `json:"title"`
+		OwnerID         *uuid.UUID `json:"owner_id"`
+		DurationMinutes *int       `json:"duration_minutes"`
+	}
+	list := make([]incoming, 0, len(items))
+	for i := range items {
+		item := incoming{Title: items[i].Title, OwnerID: items[i].OwnerID, DurationMinutes: items[i].DurationMinutes}
+		if items[i].ID != uuid.Nil {
+			id := items[i].ID
+			item.ID = &id
+		}
+		list = append(list, item)
+	}
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH incoming AS (
+			SELECT COALESCE((i.value->>'id')::uuid, gen_random_uuid()) AS id,
+			       (i.ordinality - 1)::int AS position,
+			       i.value->>'title' AS title,
+			       (i.value->>'owner_id')::uuid AS owner_id,
+			       (i.value->>'duration_minutes')::int AS duration_minutes
+			FROM jsonb_array_elements($2::jsonb) WITH ORDINALITY AS i(value, ordinality)
+		), removed AS (
+			DELETE FROM event_agenda_items
+			WHERE event_id = $1 AND id NOT IN (SELECT id FROM incoming)
+		)
+		INSERT INTO event_agenda_items (id, event_id, position, title, owner_id, duration_minutes, created_at, updated_at)
+		SELECT id, $1, position, title, owner_id, duration_minutes, NOW(), NOW() FROM incoming
+		ON CONFLICT (id) DO UPDATE SET
+			position = EXCLUDED.position,
+			title = EXCLUDED.title,
+			owner_id = EXCLUDED.owner_id,
+			duration_minutes = EXCLUDED.duration_minutes,
+			updated_at = NOW()
+		WHERE event_agenda_items.event_id = $1
+		RETURNING *
+	`
+
+	var saved []entity.AgendaItem
+	err = r.DB.SelectContext(ctx, &saved, query, eventID, string(payload))
+	if err != nil {
+		logger.Error("MeetingRepository:ReplaceAgendaItems", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ===================== Notes (event_notes) =====================
+
+func (r *MeetingRepository) GetEventNotes(ctx context.Context, eventID uuid.UUID) (*entity.EventNotes, error) {
+	query := `SELECT * FROM event_notes WHERE event_id = $1`
+
+	var notes entity.EventNotes
+	err := r.DB.GetContext(ctx, &notes, query, eventID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("MeetingRepository:GetEventNotes", err)
+		return nil, err
+	}
+	return &notes, nil
+}
+
+// SaveEventNotes replaces the notes if they are still at version (0 when there are none yet).
+// It returns nil when someone else saved them in the meantime.
+func (r *MeetingRepository) SaveEventNotes(ctx context.Context, eventID uuid.UUID, content string, updatedBy uuid.UUID, version int) (*entity.EventNotes, error) {
+	query := `
+		INSERT INTO event_notes (event_id, content, version, updated_by, created_at, updated_at)
+		VALUES ($1, $2, 1, $3, NOW(), NOW())
+		ON CONFLICT (event_id) DO UPDATE SET
+			content = EXCLUDED.content,
+			version = event_notes.version + 1,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		WHERE event_notes.version = $4
+		RETURNING *
+	`
+
+	var notes entity.EventNotes
+	err := r.DB.GetContext(ctx, &notes, query, eventID, content, updatedBy, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("MeetingRepository:SaveEventNotes", err)
+		return nil, err
+	}
+	return &notes, nil
+}
+
+// ===================== Action items (event_action_items) =====================
+
+func (r *MeetingRepository) CreateActionItem(ctx context.Context, item *entity.ActionItem) error {
+	query := `
+		INSERT INTO event_action_items (event_id, title, assignee_id, due_date, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'open', $5, NOW(), NOW())
+		RETURNING *
+	`
+
+	err := r.DB.GetContext(ctx, item, query, item.EventID, item.Title, item.AssigneeID, item.DueDate, item.CreatedBy)
+	if err != nil {
+		logger.Error("MeetingRepository:CreateActionItem", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MeetingRepository) GetActionItemByID(ctx context.Context, id uuid.UUID) (*entity.ActionItem, error) {
+	query := `SELECT * FROM event_action_items WHERE id = $1`
+
+	var item entity.ActionItem
+	err := r.DB.GetContext(ctx, &item, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("MeetingRepository:GetActionItemByID", err)
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *MeetingRepository) GetActionItemsByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.ActionItem, error) {
+	query := `SELECT * FROM event_action_items WHERE event_id = $1 ORDER BY created_at`
+
+	var items []entity.ActionItem
+	err := r.DB.SelectContext(ctx, &items, query, eventID)
+	if err != nil {
+		logger.Error("MeetingRepository:GetActionItemsByEventID", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetActionItemsByAssignee returns the action items assigned to a user across events, earliest due first.
+// An empty status returns all of them.
+func (r *MeetingRepository) GetActionItemsByAssignee(ctx context.Context, assigneeID uuid.UUID, status string) ([]entity.ActionItem, error) {
+	query := `
+		SELECT a.*, e.title AS event_title
+		FROM event_action_items a
+		JOIN events e ON e.id = a.event_id
+		WHERE a.assignee_id = $1 AND ($2 = '' OR a.status = $2)
+		ORDER BY a.due_date NULLS LAST, a.created_at
+	`
+
+	var items []entity.ActionItem
+	err := r.DB.SelectContext(ctx, &items, query, assigneeID, status)
+	if err != nil {
+		logger.Error("MeetingRepository:GetActionItemsByAssignee", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateActionItem saves the title, assignee, due date and status of an action item
+func (r *MeetingRepository) UpdateActionItem(ctx context.Context, item *entity.ActionItem) error {
+	query := `
+		UPDATE event_action_items
+		SET title = $2, assignee_id = $3, due_date = $4, status = $5, completed_at = $6, reminded_at = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING *
+	`
+
+	err := r.DB.GetContext(ctx, item, query,
+		item.ID, item.Title, item.AssigneeID, item.DueDate, item.Status, item.CompletedAt, item.RemindedAt)
+	if err != nil {
+		logger.Error("MeetingRepository:UpdateActionItem", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MeetingRepository) DeleteActionItem(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM event_action_items WHERE id = $1`
+	err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.Error("MeetingRepository:DeleteActionItem", err)
+		return err
+	}
+	return nil
+}
+
+// ClaimDueActionItems marks up to limit open, assigned action items due before until as reminded and returns them,
+// so each assignee is reminded once per due date
+func (r *MeetingRepository) ClaimDueActionItems(ctx context.Context, until time.Time, limit int) ([]entity.ActionItem, error) {
+	query := `
+		UPDATE event_action_items a
+		SET reminded_at = NOW()
+		FROM events e
+		WHERE e.id = a.event_id AND a.id IN (
+			SELECT id FROM event_action_items
+			WHERE status = 'open' AND assignee_id IS NOT NULL AND reminded_at IS NULL AND due_date <= $1
+			ORDER BY due_date
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING a.*, e.title AS event_title
+	`
+
+	var items []entity.ActionItem
+	err := r.DB.SelectContext(ctx, &items, query, until, limit)
+	if err != nil {
+		logger.Error("MeetingRepository:ClaimDueActionItems", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// ===================== Agenda templates (agenda_templates) =====================
+
+func (r *MeetingRepository) GetAgendaTemplatesByOwner(ctx context.Context, ownerID uuid.UUID) ([]entity.AgendaTemplate, error) {
+	query := `SELECT * FROM agenda_templates WHERE owner_id = $1 ORDER BY name`
+
+	var templates []entity.AgendaTemplate
+	err := r.DB.SelectContext(ctx, &templates, query, ownerID)
+	if err != nil {
+		logger.Error("MeetingRepository:GetAgendaTemplatesByOwner", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *MeetingRepository) GetAgendaTemplateByID(ctx context.Context, id uuid.UUID) (*entity.AgendaTemplate, error) {
+	query := `SELECT * FROM agenda_templates WHERE id = $1`
+
+	var template entity.AgendaTemplate
+	err := r.DB.GetContext(ctx, &template, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("MeetingRepository:GetAgendaTemplateByID", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveAgendaTemplate creates a template, or updates it when it has an ID
+func (r *MeetingRepository) SaveAgendaTemplate(ctx context.Context, template *entity.AgendaTemplate) error {
+	query := `
+		INSERT INTO agenda_templates (owner_id, name, items, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING *
+	`
+	args := []any{template.OwnerID, template.Name, template.Items}
+	if template.ID != uuid.Nil {
+		query = `
+			UPDATE agenda_templates SET name = $2, items = $3, updated_at = NOW()
+			WHERE owner_id = $1 AND id = $4
+			RETURNING *
+		`
+		args = append(args, template.ID)
+	}
+
+	err := r.DB.GetContext(ctx, template, query, args...)
+	if err != nil {
+		logger.Error("MeetingRepository:SaveAgendaTemplate", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MeetingRepository) DeleteAgendaTemplate(ctx context.Context, ownerID uuid.UUID, id uuid.UUID) error {
+	query := `DELETE FROM agenda_templates WHERE owner_id = $1 AND id = $2`
+	err := r.DB.ExecContext(ctx, query, ownerID, id)
+	if err != nil {
+		logger.Error("MeetingRepository:DeleteAgendaTemplate", err)
+		return err
+	}
+	return nil
+}
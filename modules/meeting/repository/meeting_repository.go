@@ -30,12 +30,41 @@ type MeetingRepositoryInterface interface {
 	UpdateEvent(ctx context.Context, event *entity.Event) error
 	DeleteEvent(ctx context.Context, id uuid.UUID) error
 
+	// Provider sync (two-way calendar sync)
+	GetEventByProviderEventID(ctx context.Context, hostID uuid.UUID, provider string, providerEventID string) (*entity.Event, error)
+	GetEventsWithLocalChanges(ctx context.Context, hostID uuid.UUID, provider string) ([]entity.Event, error)
+	SetProviderLink(ctx context.Context, id uuid.UUID, provider string, providerEventID string, etag string, offsetMinutes int) error
+	MarkProviderSynced(ctx context.Context, id uuid.UUID, etag string, providerUpdatedAt *time.Time) error
+	ApplyProviderChange(ctx context.Context, event *entity.Event) error
+
 	// Participants (using user_events table)
 	AddParticipant(ctx context.Context, userEvent *entity.UserEvent) error
 	GetParticipantsByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.UserEvent, error)
 	UpdateParticipantCalendarStatus(ctx context.Context, userID uuid.UUID, eventID uuid.UUID, hasCalendar bool) error
 	RemoveParticipant(ctx context.Context, userID uuid.UUID, eventID uuid.UUID) error
 
+	// Attendance (using event_attendance table)
+	GetAttendanceByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.EventAttendance, error)
+	SaveAttendance(ctx context.Context, attendance *entity.EventAttendance) error
+
+	// Agendas, notes, action items and agenda templates
+	IsEventMember(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (bool, error)
+	GetAgendaItems(ctx context.Context, eventID uuid.UUID) ([]entity.AgendaItem, error)
+	ReplaceAgendaItems(ctx context.Context, eventID uuid.UUID, items []entity.AgendaItem) ([]entity.AgendaItem, error)
+	GetEventNotes(ctx context.Context, eventID uuid.UUID) (*entity.EventNotes, error)
+	SaveEventNotes(ctx context.Context, eventID uuid.UUID, content string, updatedBy uuid.UUID, version int) (*entity.EventNotes, error)
+	CreateActionItem(ctx context.Context, item *entity.ActionItem) error
+	GetActionItemByID(ctx context.Context, id uuid.UUID) (*entity.ActionItem, error)
+	GetActionItemsByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.ActionItem, error)
+	GetActionItemsByAssignee(ctx context.Context, assigneeID uuid.UUID, status string) ([]entity.ActionItem, error)
+	UpdateActionItem(ctx context.Context, item *entity.ActionItem) error
+	DeleteActionItem(ctx context.Context, id uuid.UUID) error
+	ClaimDueActionItems(ctx context.Context, until time.Time, limit int) ([]entity.ActionItem, error)
+	GetAgendaTemplatesByOwner(ctx context.Context, ownerID uuid.UUID) ([]entity.AgendaTemplate, error)
+	GetAgendaTemplateByID(ctx context.Context, id uuid.UUID) (*entity.AgendaTemplate, error)
+	SaveAgendaTemplate(ctx context.Context, template *entity.AgendaTemplate) error
+	DeleteAgendaTemplate(ctx context.Context, ownerID uuid.UUID, id uuid.UUID) error
+
 	// Slots (using event_slots table)
 	SaveSlots(ctx context.Context, slots []entity.EventSlot) error
 	GetSlotsByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.EventSlot, error)
@@ -49,7 +78,8 @@ func (r *MeetingRepository) CreateEvent(ctx context.Context, event *entity.Event
 		INSERT INTO events (host_id, title, description, address, duration_minutes, status, timezone, preferences)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, host_id, title, description, address, duration_minutes, status, timezone,
-		          start_date, end_date, meeting_link, preferences, created_at, updated_at
+		          start_date, end_date, meeting_link, preferences, created_at, updated_at,
+		          provider, provider_event_id, provider_etag, provider_updated_at, last_synced_at, provider_offset_minutes
 	`
 
 	var created entity.Event
@@ -68,7 +98,8 @@ func (r *MeetingRepository) CreateEvent(ctx context.Context, event *entity.Event
 func (r *MeetingRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
 	query := `
 		SELECT id, host_id, title, description, address, duration_minutes, status, timezone,
-		       start_date, end_date, meeting_link, preferences, created_at, updated_at
+		       start_date, end_date, meeting_link, preferences, created_at, updated_at,
+		       provider, provider_event_id, provider_etag, provider_updated_at, last_synced_at, provider_offset_minutes
 		FROM events WHERE id = $1
 	`
 
@@ -85,12 +116,15 @@ func (r *MeetingRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*en
 	return &event, nil
 }
 
+// GetEventsByHostID lists the host's events. Cancelled events are left out: a deleted event linked to a
+// calendar provider is kept as cancelled only until the sync job removes the provider copy.
 func (r *MeetingRepository) GetEventsByHostID(ctx context.Context, hostID uuid.UUID) ([]entity.Event, error) {
 	query := `
 		SELECT id, host_id, title, description, address, duration_minutes, status, timezone,
-		       start_date, end_date, meeting_link, preferences, created_at, updated_at
+		       start_date, end_date, meeting_link, preferences, created_at, updated_at,
+		       provider, provider_event_id, provider_etag, provider_updated_at, last_synced_at, provider_offset_minutes
 		FROM events 
-		WHERE host_id = $1
+		WHERE host_id = $1 AND status <> 'cancelled'
 		ORDER BY created_at DESC
 	`
 
@@ -148,6 +182,101 @@ func (r *MeetingRepository) DeleteEvent(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// ===================== Provider sync =====================
+
+func (r *MeetingRepository) GetEventByProviderEventID(ctx context.Context, hostID uuid.UUID, provider string, providerEventID string) (*entity.Event, error) {
+	query := `
+		SELECT id, host_id, title, description, address, duration_minutes, status, timezone,
+		       start_date, end_date, meeting_link, preferences, created_at, updated_at,
+		       provider, provider_event_id, provider_etag, provider_updated_at, last_synced_at, provider_offset_minutes
+		FROM events
+		WHERE host_id = $1 AND provider = $2 AND provider_event_id = $3
+	`
+
+	var event entity.Event
+	err := r.DB.GetContext(ctx, &event, query, hostID, provider, providerEventID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("MeetingRepository:GetEventByProviderEventID", err)
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// GetEventsWithLocalChanges returns linked events edited or cancelled locally since their last sync
+func (r *MeetingRepository) GetEventsWithLocalChanges(ctx context.Context, hostID uuid.UUID, provider string) ([]entity.Event, error) {
+	query := `
+		SELECT id, host_id, title, description, address, duration_minutes, status, timezone,
+		       start_date, end_date, meeting_link, preferences, created_at, updated_at,
+		       provider, provider_event_id, provider_etag, provider_updated_at, last_synced_at, provider_offset_minutes
+		FROM events
+		WHERE host_id = $1 AND provider = $2 AND provider_event_id IS NOT NULL
+		AND (last_synced_at IS NULL OR updated_at > last_synced_at)
+		ORDER BY updated_at
+	`
+
+	var events []entity.Event
+	err := r.DB.SelectContext(ctx, &events, query, hostID, provider)
+	if err != nil {
+		logger.Error("MeetingRepository:GetEventsWithLocalChanges", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SetProviderLink records the provider copy created for a local event.
+// offsetMinutes is how far the provider copy's start is from the local start.
+func (r *MeetingRepository) SetProviderLink(ctx context.Context, id uuid.UUID, provider string, providerEventID string, etag string, offsetMinutes int) error {
+	query := `
+		UPDATE events
+		SET provider = $2, provider_event_id = $3, provider_etag = NULLIF($4, ''), provider_offset_minutes = $5, last_synced_at = NOW()
+		WHERE id = $1
+	`
+	err := r.DB.ExecContext(ctx, query, id, provider, providerEventID, etag, offsetMinutes)
+	if err != nil {
+		logger.Error("MeetingRepository:SetProviderLink", err)
+		return err
+	}
+	return nil
+}
+
+// MarkProviderSynced stores the provider etag after a local change was pushed
+func (r *MeetingRepository) MarkProviderSynced(ctx context.Context, id uuid.UUID, etag string, providerUpdatedAt *time.Time) error {
+	query := `
+		UPDATE events
+		SET provider_etag = NULLIF($2, ''), provider_updated_at = COALESCE($3, provider_updated_at), last_synced_at = NOW()
+		WHERE id = $1
+	`
+	err := r.DB.ExecContext(ctx, query, id, etag, providerUpdatedAt)
+	if err != nil {
+		logger.Error("MeetingRepository:MarkProviderSynced", err)
+		return err
+	}
+	return nil
+}
+
+// ApplyProviderChange saves a change pulled from the provider without flagging it as a local edit
+func (r *MeetingRepository) ApplyProviderChange(ctx context.Context, event *entity.Event) error {
+	query := `
+		UPDATE events
+		SET title = $2, description = $3, status = $4, start_date = $5, end_date = $6, duration_minutes = $7,
+		    provider_etag = $8, provider_updated_at = $9, updated_at = NOW(), last_synced_at = NOW()
+		WHERE id = $1
+	`
+	err := r.DB.ExecContext(ctx, query,
+		event.ID, event.Title, event.Description, event.Status, event.StartDate, event.EndDate,
+		event.DurationMinutes, event.ProviderEtag, event.ProviderUpdatedAt)
+	if err != nil {
+		logger.Error("MeetingRepository:ApplyProviderChange", err)
+		return err
+	}
+	return nil
+}
+
 // ===================== Participants (user_events) =====================
 
 func (r *MeetingRepository) AddParticipant(ctx context.Context, userEvent *entity.UserEvent) error {
@@ -206,6 +335,44 @@ func (r *MeetingRepository) RemoveParticipant(ctx context.Context, userID uuid.U
 	return nil
 }
 
+// ===================== Attendance (event_attendance) =====================
+
+const attendanceColumns = `id, event_id, user_id, email, status, marked_by, created_at, updated_at`
+
+func (r *MeetingRepository) GetAttendanceByEventID(ctx context.Context, eventID uuid.UUID) ([]entity.EventAttendance, error) {
+	query := `SELECT ` + attendanceColumns + ` FROM event_attendance WHERE event_id = $1 ORDER BY created_at`
+
+	var attendance []entity.EventAttendance
+	err := r.DB.SelectContext(ctx, &attendance, query, eventID)
+	if err != nil {
+		logger.Error("MeetingRepository:GetAttendanceByEventID", err)
+		return nil, err
+	}
+
+	return attendance, nil
+}
+
+// SaveAttendance marks a participant's attendance; marking them again replaces the status
+func (r *MeetingRepository) SaveAttendance(ctx context.Context, attendance *entity.EventAttendance) error {
+	query := `
+		INSERT INTO event_attendance (event_id, user_id, email, status, marked_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (event_id, participant) DO UPDATE SET
+			email = EXCLUDED.email,
+			status = EXCLUDED.status,
+			marked_by = EXCLUDED.marked_by,
+			updated_at = NOW()
+		RETURNING ` + attendanceColumns
+
+	err := r.DB.GetContext(ctx, attendance, query,
+		attendance.EventID, attendance.UserID, attendance.Email, attendance.Status, attendance.MarkedBy)
+	if err != nil {
+		logger.Error("MeetingRepository:SaveAttendance", err)
+		return err
+	}
+	return nil
+}
+
 // ===================== Slots (event_slots) =====================
 
 func (r *MeetingRepository) SaveSlots(ctx context.Context, slots []entity.EventSlot) error {
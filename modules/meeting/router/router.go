@@ -38,6 +38,21 @@ func (r *MeetingRouter) Setup(e *echo.Echo, mw *middleware.Middleware) {
 	eventRoutes.POST("/:id/find-slots", r.MeetingController.FindSlots)
 	eventRoutes.POST("/:id/select-slot", r.MeetingController.SelectSlot)
 
+	// Attendance after the event
+	eventRoutes.GET("/:id/attendance", r.MeetingController.GetAttendance)
+	eventRoutes.PUT("/:id/attendance", r.MeetingController.MarkAttendance)
+
+	// Agenda, shared notes and action items
+	eventRoutes.GET("/:id/agenda", r.MeetingController.GetAgenda)
+	eventRoutes.PUT("/:id/agenda", r.MeetingController.SaveAgenda)
+	eventRoutes.POST("/:id/agenda/apply-template", r.MeetingController.ApplyAgendaTemplate)
+	eventRoutes.GET("/:id/notes", r.MeetingController.GetNotes)
+	eventRoutes.PUT("/:id/notes", r.MeetingController.SaveNotes)
+	eventRoutes.GET("/:id/action-items", r.MeetingController.ListActionItems)
+	eventRoutes.POST("/:id/action-items", r.MeetingController.CreateActionItem)
+	eventRoutes.PUT("/:id/action-items/:item_id", r.MeetingController.UpdateActionItem)
+	eventRoutes.DELETE("/:id/action-items/:item_id", r.MeetingController.DeleteActionItem)
+
 	// Also register /meetings endpoint for backward compatibility
 	meetingRoutes := privateRoutes.Group("/meetings", mw.AuthMiddleware())
 	meetingRoutes.POST("", r.MeetingController.CreateEvent)
@@ -47,4 +62,26 @@ func (r *MeetingRouter) Setup(e *echo.Echo, mw *middleware.Middleware) {
 	meetingRoutes.DELETE("/:id", r.MeetingController.DeleteEvent)
 	meetingRoutes.POST("/:id/find-slots", r.MeetingController.FindSlots)
 	meetingRoutes.POST("/:id/select-slot", r.MeetingController.SelectSlot)
+	meetingRoutes.GET("/:id/attendance", r.MeetingController.GetAttendance)
+	meetingRoutes.PUT("/:id/attendance", r.MeetingController.MarkAttendance)
+	meetingRoutes.GET("/:id/agenda", r.MeetingController.GetAgenda)
+	meetingRoutes.PUT("/:id/agenda", r.MeetingController.SaveAgenda)
+	meetingRoutes.POST("/:id/agenda/apply-template", r.MeetingController.ApplyAgendaTemplate)
+	meetingRoutes.GET("/:id/notes", r.MeetingController.GetNotes)
+	meetingRoutes.PUT("/:id/notes", r.MeetingController.SaveNotes)
+	meetingRoutes.GET("/:id/action-items", r.MeetingController.ListActionItems)
+	meetingRoutes.POST("/:id/action-items", r.MeetingController.CreateActionItem)
+	meetingRoutes.PUT("/:id/action-items/:item_id", r.MeetingController.UpdateActionItem)
+	meetingRoutes.DELETE("/:id/action-items/:item_id", r.MeetingController.DeleteActionItem)
+
+	// Action items assigned to the user across events
+	actionItemRoutes := privateRoutes.Group("/action-items", mw.AuthMiddleware())
+	actionItemRoutes.GET("/mine", r.MeetingController.GetMyActionItems)
+
+	// Reusable agenda templates
+	templateRoutes := privateRoutes.Group("/agenda-templates", mw.AuthMiddleware())
+	templateRoutes.GET("", r.MeetingController.ListAgendaTemplates)
+	templateRoutes.POST("", r.MeetingController.CreateAgendaTemplate)
+	templateRoutes.PUT("/:id", r.MeetingController.UpdateAgendaTemplate)
+	templateRoutes.DELETE("/:id", r.MeetingController.DeleteAgendaTemplate)
 }
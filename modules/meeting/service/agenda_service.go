@@ -0,0 +1,560 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/meeting/dto"
+	"go-api-starter/modules/meeting/entity"
+	notifDto "go-api-starter/modules/notification/dto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxAgendaItems          = 50
+	maxAgendaTitle          = 200
+	maxAgendaItemMinutes    = 480
+	maxNotesLength          = 100000
+	maxActionItemTitle      = 300
+	maxAgendaTemplates      = 50
+	maxAgendaTemplateName   = 120
+	actionItemReminderAhead = 24 * time.Hour
+	actionItemReminderBatch = 100
+)
+
+// ===================== Agenda =====================
+
+// GetAgenda returns the agenda of an event the user hosts or takes part in
+func (s *MeetingService) GetAgenda(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*dto.AgendaResponse, *errors.AppError) {
+	event, appErr := s.memberEvent(ctx, eventID, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	items, err := s.repo.GetAgendaItems(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get agenda", err)
+	}
+	return toAgendaResponse(event, items), nil
+}
+
+// SaveAgenda replaces the agenda of the host's event
+func (s *MeetingService) SaveAgenda(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.SaveAgendaRequest) (*dto.AgendaResponse, *errors.AppError) {
+	event, appErr := s.hostEvent(ctx, eventID, hostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	current, err := s.repo.GetAgendaItems(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get agenda", err)
+	}
+	existing := make(map[uuid.UUID]bool, len(current))
+	for _, item := range current {
+		existing[item.ID] = true
+	}
+
+	items, appErr := s.agendaItems(ctx, eventID, req.Items, true)
+	if appErr != nil {
+		return nil, appErr
+	}
+	for i, input := range req.Items {
+		// Unknown IDs are created as new items rather than moved from another event
+		if id, err := uuid.Parse(input.ID); err == nil && existing[id] {
+			items[i].ID = id
+		}
+	}
+	return s.replaceAgenda(ctx, event, items)
+}
+
+// ApplyAgendaTemplate replaces the agenda of the host's event with one of their templates.
+// Owners who do not take part in the event are left out.
+func (s *MeetingService) ApplyAgendaTemplate(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.ApplyAgendaTemplateRequest) (*dto.AgendaResponse, *errors.AppError) {
+	event, appErr := s.hostEvent(ctx, eventID, hostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	template, appErr := s.ownTemplate(ctx, hostID, req.TemplateID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	items := make([]entity.AgendaItem, 0, len(template.Items))
+	for _, t := range template.Items {
+		item := entity.AgendaItem{EventID: eventID, Title: t.Title, DurationMinutes: t.DurationMinutes}
+		if t.OwnerID != nil {
+			if member, err := s.repo.IsEventMember(ctx, eventID, *t.OwnerID); err == nil && member {
+				item.OwnerID = t.OwnerID
+			}
+		}
+		items = append(items, item)
+	}
+	return s.replaceAgenda(ctx, event, items)
+}
+
+func (s *MeetingService) replaceAgenda(ctx context.Context, event *entity.Event, items []entity.AgendaItem) (*dto.AgendaResponse, *errors.AppError) {
+	saved, err := s.repo.ReplaceAgendaItems(ctx, event.ID, items)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to save agenda", err)
+	}
+	return toAgendaResponse(event, saved), nil
+}
+
+// agendaItems validates agenda input. Owners must take part in the event unless checkOwners is false (templates).
+func (s *MeetingService) agendaItems(ctx context.Context, eventID uuid.UUID, inputs []dto.AgendaItemInput, checkOwners bool) ([]entity.AgendaItem, *errors.AppError) {
+	if len(inputs) > maxAgendaItems {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("An agenda can have at most %d items", maxAgendaItems), nil)
+	}
+	items := make([]entity.AgendaItem, 0, len(inputs))
+	for i, input := range inputs {
+		item := entity.AgendaItem{EventID: eventID, Position: i, Title: strings.TrimSpace(input.Title)}
+		if item.Title == "" || len([]rune(item.Title)) > maxAgendaTitle {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("Agenda item %d needs a title of at most %d characters", i+1, maxAgendaTitle), nil)
+		}
+		if input.DurationMinutes < 0 || input.DurationMinutes > maxAgendaItemMinutes {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("duration_minutes must be between 0 and %d", maxAgendaItemMinutes), nil)
+		}
+		if input.DurationMinutes > 0 {
+			minutes := input.DurationMinutes
+			item.DurationMinutes = &minutes
+		}
+		if input.OwnerID != "" {
+			ownerID, err := uuid.Parse(input.OwnerID)
+			if err != nil {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, "Invalid owner_id", err)
+			}
+			if checkOwners {
+				if appErr := s.checkMember(ctx, eventID, ownerID, "owner_id"); appErr != nil {
+					return nil, appErr
+				}
+			}
+			item.OwnerID = &ownerID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ===================== Notes =====================
+
+// GetNotes returns the shared notes of an event the user hosts or takes part in
+func (s *MeetingService) GetNotes(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*dto.NotesResponse, *errors.AppError) {
+	if _, appErr := s.memberEvent(ctx, eventID, userID); appErr != nil {
+		return nil, appErr
+	}
+	notes, err := s.repo.GetEventNotes(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get notes", err)
+	}
+	return toNotesResponse(eventID, notes), nil
+}
+
+// SaveNotes replaces the shared notes. The edit must be based on the current version so nobody's changes are lost.
+func (s *MeetingService) SaveNotes(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, req *dto.SaveNotesRequest) (*dto.NotesResponse, *errors.AppError) {
+	if _, appErr := s.memberEvent(ctx, eventID, userID); appErr != nil {
+		return nil, appErr
+	}
+	if len([]rune(req.Content)) > maxNotesLength {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("Notes can have at most %d characters", maxNotesLength), nil)
+	}
+	notes, err := s.repo.SaveEventNotes(ctx, eventID, req.Content, userID, req.Version)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to save notes", err)
+	}
+	if notes == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "The notes were changed by someone else, reload them and try again", nil)
+	}
+	return toNotesResponse(eventID, notes), nil
+}
+
+// ===================== Action items =====================
+
+// ListActionItems returns the action items of an event the user hosts or takes part in
+func (s *MeetingService) ListActionItems(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) ([]dto.ActionItemResponse, *errors.AppError) {
+	if _, appErr := s.memberEvent(ctx, eventID, userID); appErr != nil {
+		return nil, appErr
+	}
+	items, err := s.repo.GetActionItemsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get action items", err)
+	}
+	result := make([]dto.ActionItemResponse, 0, len(items))
+	for i := range items {
+		result = append(result, dto.ToActionItemResponse(&items[i]))
+	}
+	return result, nil
+}
+
+// GetMyActionItems returns the action items assigned to the user across events; status defaults to open, "all" returns every item
+func (s *MeetingService) GetMyActionItems(ctx context.Context, userID uuid.UUID, status string) ([]dto.ActionItemResponse, *errors.AppError) {
+	switch status = strings.ToLower(strings.TrimSpace(status)); status {
+	case "":
+		status = string(entity.ActionItemStatusOpen)
+	case "all":
+		status = ""
+	case string(entity.ActionItemStatusOpen), string(entity.ActionItemStatusDone):
+	default:
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "status must be open, done or all", nil)
+	}
+	items, err := s.repo.GetActionItemsByAssignee(ctx, userID, status)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get action items", err)
+	}
+	result := make([]dto.ActionItemResponse, 0, len(items))
+	for i := range items {
+		result = append(result, dto.ToActionItemResponse(&items[i]))
+	}
+	return result, nil
+}
+
+// CreateActionItem adds an action item to an event and notifies its assignee
+func (s *MeetingService) CreateActionItem(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, req *dto.CreateActionItemRequest) (*dto.ActionItemResponse, *errors.AppError) {
+	event, appErr := s.memberEvent(ctx, eventID, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	item := &entity.ActionItem{EventID: eventID, CreatedBy: &userID}
+	if appErr := s.setActionItemTitle(item, req.Title); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := s.setActionItemAssignee(ctx, item, req.AssigneeID); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := setActionItemDueDate(item, req.DueDate); appErr != nil {
+		return nil, appErr
+	}
+
+	if err := s.repo.CreateActionItem(ctx, item); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to create action item", err)
+	}
+	item.EventTitle = event.Title
+	s.notifyAssignee(ctx, item, userID)
+	response := dto.ToActionItemResponse(item)
+	return &response, nil
+}
+
+// UpdateActionItem changes an action item. The host, its creator and its assignee may update it;
+// a new assignee is notified and a new due date is reminded again.
+func (s *MeetingService) UpdateActionItem(ctx context.Context, eventID uuid.UUID, itemID uuid.UUID, userID uuid.UUID, req *dto.UpdateActionItemRequest) (*dto.ActionItemResponse, *errors.AppError) {
+	event, item, appErr := s.eventActionItem(ctx, eventID, itemID, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	isHost := event.HostID != nil && *event.HostID == userID
+	if !isHost && !sameUser(item.CreatedBy, userID) && !sameUser(item.AssigneeID, userID) {
+		return nil, errors.NewAppError(errors.ErrForbidden, "Not authorized", nil)
+	}
+
+	previousAssignee := item.AssigneeID
+	if req.Title != nil {
+		if appErr := s.setActionItemTitle(item, *req.Title); appErr != nil {
+			return nil, appErr
+		}
+	}
+	if req.AssigneeID != nil {
+		if appErr := s.setActionItemAssignee(ctx, item, *req.AssigneeID); appErr != nil {
+			return nil, appErr
+		}
+	}
+	if req.DueDate != nil {
+		previousDue := item.DueDate
+		if appErr := setActionItemDueDate(item, *req.DueDate); appErr != nil {
+			return nil, appErr
+		}
+		if previousDue == nil || item.DueDate == nil || !previousDue.Equal(*item.DueDate) {
+			item.RemindedAt = nil
+		}
+	}
+	if req.Status != nil {
+		switch status := entity.ActionItemStatus(strings.ToLower(strings.TrimSpace(*req.Status))); status {
+		case entity.ActionItemStatusOpen:
+			item.Status, item.CompletedAt = status, nil
+		case entity.ActionItemStatusDone:
+			if item.Status != entity.ActionItemStatusDone {
+				now := time.Now()
+				item.Status, item.CompletedAt = status, &now
+			}
+		default:
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "status must be open or done", nil)
+		}
+	}
+
+	if err := s.repo.UpdateActionItem(ctx, item); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to update action item", err)
+	}
+	item.EventTitle = event.Title
+	if item.AssigneeID != nil && !sameUser(previousAssignee, *item.AssigneeID) {
+		s.notifyAssignee(ctx, item, userID)
+	}
+	response := dto.ToActionItemResponse(item)
+	return &response, nil
+}
+
+// DeleteActionItem removes an action item; only the host and its creator may do so
+func (s *MeetingService) DeleteActionItem(ctx context.Context, eventID uuid.UUID, itemID uuid.UUID, userID uuid.UUID) *errors.AppError {
+	event, item, appErr := s.eventActionItem(ctx, eventID, itemID, userID)
+	if appErr != nil {
+		return appErr
+	}
+	if (event.HostID == nil || *event.HostID != userID) && !sameUser(item.CreatedBy, userID) {
+		return errors.NewAppError(errors.ErrForbidden, "Not authorized", nil)
+	}
+	if err := s.repo.DeleteActionItem(ctx, itemID); err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to delete action item", err)
+	}
+	return nil
+}
+
+// HandleActionItemReminderTask is the worker handler of constants.TopicQueueActionItemReminders.
+// It reminds assignees of open action items due within the next day, once per due date.
+func (s *MeetingService) HandleActionItemReminderTask(ctx context.Context, _ []byte) error {
+	for {
+		items, err := s.repo.ClaimDueActionItems(ctx, time.Now().Add(actionItemReminderAhead), actionItemReminderBatch)
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			s.notify(ctx, *items[i].AssigneeID, "Sắp đến hạn công việc sau cuộc họp", "action_item_due", &items[i])
+		}
+		if len(items) < actionItemReminderBatch {
+			return nil
+		}
+	}
+}
+
+func (s *MeetingService) eventActionItem(ctx context.Context, eventID uuid.UUID, itemID uuid.UUID, userID uuid.UUID) (*entity.Event, *entity.ActionItem, *errors.AppError) {
+	event, appErr := s.memberEvent(ctx, eventID, userID)
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+	item, err := s.repo.GetActionItemByID(ctx, itemID)
+	if err != nil {
+		return nil, nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get action item", err)
+	}
+	if item == nil || item.EventID != eventID {
+		return nil, nil, errors.NewAppError(errors.ErrNotFound, "Action item not found", nil)
+	}
+	return event, item, nil
+}
+
+func (s *MeetingService) setActionItemTitle(item *entity.ActionItem, title string) *errors.AppError {
+	title = strings.TrimSpace(title)
+	if title == "" || len([]rune(title)) > maxActionItemTitle {
+		return errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("title is required and can have at most %d characters", maxActionItemTitle), nil)
+	}
+	item.Title = title
+	return nil
+}
+
+// setActionItemAssignee assigns the item to a host or participant of its event; empty unassigns it
+func (s *MeetingService) setActionItemAssignee(ctx context.Context, item *entity.ActionItem, assignee string) *errors.AppError {
+	if strings.TrimSpace(assignee) == "" {
+		item.AssigneeID = nil
+		return nil
+	}
+	assigneeID, err := uuid.Parse(strings.TrimSpace(assignee))
+	if err != nil {
+		return errors.NewAppError(errors.ErrInvalidInput, "Invalid assignee_id", err)
+	}
+	if appErr := s.checkMember(ctx, item.EventID, assigneeID, "assignee_id"); appErr != nil {
+		return appErr
+	}
+	item.AssigneeID = &assigneeID
+	return nil
+}
+
+func setActionItemDueDate(item *entity.ActionItem, dueDate string) *errors.AppError {
+	if strings.TrimSpace(dueDate) == "" {
+		item.DueDate = nil
+		return nil
+	}
+	due, err := time.Parse(time.RFC3339, strings.TrimSpace(dueDate))
+	if err != nil {
+		return errors.NewAppError(errors.ErrInvalidInput, "Invalid due_date format", err)
+	}
+	item.DueDate = &due
+	return nil
+}
+
+// notifyAssignee tells the assignee about an action item someone else gave them
+func (s *MeetingService) notifyAssignee(ctx context.Context, item *entity.ActionItem, actorID uuid.UUID) {
+	if item.AssigneeID == nil || *item.AssigneeID == actorID {
+		return
+	}
+	s.notify(ctx, *item.AssigneeID, "Bạn được giao công việc sau cuộc họp", "action_item_assigned", item)
+}
+
+func (s *MeetingService) notify(ctx context.Context, userID uuid.UUID, title, notifType string, item *entity.ActionItem) {
+	if s.notifSvc == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"action_item_id": item.ID.String(),
+		"event_id":       item.EventID.String(),
+		"event_title":    item.EventTitle,
+	}
+	if item.DueDate != nil {
+		data["due_date"] = item.DueDate.Format(time.RFC3339)
+	}
+	if err := s.notifSvc.Create(ctx, &notifDto.CreateNotificationRequest{
+		UserID:  userID,
+		Title:   title,
+		Message: item.Title,
+		Type:    notifType,
+		Data:    data,
+	}); err != nil {
+		logger.Warn("MeetingService:notify:Error", "action_item_id", item.ID.String(), "error", err)
+	}
+}
+
+// ===================== Agenda templates =====================
+
+// ListAgendaTemplates returns the user's agenda templates
+func (s *MeetingService) ListAgendaTemplates(ctx context.Context, userID uuid.UUID) ([]dto.AgendaTemplateResponse, *errors.AppError) {
+	templates, err := s.repo.GetAgendaTemplatesByOwner(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get agenda templates", err)
+	}
+	result := make([]dto.AgendaTemplateResponse, 0, len(templates))
+	for i := range templates {
+		result = append(result, dto.ToAgendaTemplateResponse(&templates[i]))
+	}
+	return result, nil
+}
+
+// SaveAgendaTemplate creates an agenda template, or replaces the user's template templateID when it is set
+func (s *MeetingService) SaveAgendaTemplate(ctx context.Context, userID uuid.UUID, templateID string, req *dto.SaveAgendaTemplateRequest) (*dto.AgendaTemplateResponse, *errors.AppError) {
+	template := &entity.AgendaTemplate{OwnerID: userID}
+	if templateID != "" {
+		existing, appErr := s.ownTemplate(ctx, userID, templateID)
+		if appErr != nil {
+			return nil, appErr
+		}
+		template.ID = existing.ID
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	if template.Name == "" || len([]rune(template.Name)) > maxAgendaTemplateName {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, fmt.Sprintf("name is required and can have at most %d characters", maxAgendaTemplateName), nil)
+	}
+	items, appErr := s.agendaItems(ctx, uuid.Nil, req.Items, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+	template.Items = make(entity.AgendaTemplateItems, 0, len(items))
+	for _, item := range items {
+		template.Items = append(template.Items, entity.AgendaTemplateItem{Title: item.Title, OwnerID: item.OwnerID, DurationMinutes: item.DurationMinutes})
+	}
+
+	templates, err := s.repo.GetAgendaTemplatesByOwner(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get agenda templates", err)
+	}
+	if template.ID == uuid.Nil && len(templates) >= maxAgendaTemplates {
+		return nil, errors.NewAppError(errors.ErrLimitExceeded, fmt.Sprintf("You can have at most %d agenda templates", maxAgendaTemplates), nil)
+	}
+	for _, t := range templates {
+		if t.ID != template.ID && strings.EqualFold(t.Name, template.Name) {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "An agenda template with this name already exists", nil)
+		}
+	}
+
+	if err := s.repo.SaveAgendaTemplate(ctx, template); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to save agenda template", err)
+	}
+	response := dto.ToAgendaTemplateResponse(template)
+	return &response, nil
+}
+
+// DeleteAgendaTemplate removes one of the user's agenda templates; agendas made from it are kept
+func (s *MeetingService) DeleteAgendaTemplate(ctx context.Context, userID uuid.UUID, templateID string) *errors.AppError {
+	template, appErr := s.ownTemplate(ctx, userID, templateID)
+	if appErr != nil {
+		return appErr
+	}
+	if err := s.repo.DeleteAgendaTemplate(ctx, userID, template.ID); err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to delete agenda template", err)
+	}
+	return nil
+}
+
+func (s *MeetingService) ownTemplate(ctx context.Context, userID uuid.UUID, templateID string) (*entity.AgendaTemplate, *errors.AppError) {
+	id, err := uuid.Parse(templateID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "Invalid template ID", err)
+	}
+	template, err := s.repo.GetAgendaTemplateByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get agenda template", err)
+	}
+	if template == nil || template.OwnerID != userID {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Agenda template not found", nil)
+	}
+	return template, nil
+}
+
+// ===================== Helpers =====================
+
+// memberEvent returns an event the user hosts or takes part in
+func (s *MeetingService) memberEvent(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*entity.Event, *errors.AppError) {
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil || event == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Event not found", err)
+	}
+	if event.HostID != nil && *event.HostID == userID {
+		return event, nil
+	}
+	if appErr := s.checkMember(ctx, eventID, userID, ""); appErr != nil {
+		return nil, errors.NewAppError(errors.ErrForbidden, "Not authorized", nil)
+	}
+	return event, nil
+}
+
+// checkMember rejects a user who is neither host nor participant of the event; field names the offending input
+func (s *MeetingService) checkMember(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, field string) *errors.AppError {
+	member, err := s.repo.IsEventMember(ctx, eventID, userID)
+	if err != nil {
+		return errors.NewAppError(errors.ErrInternalServer, "Failed to check participants", err)
+	}
+	if !member {
+		return errors.NewAppError(errors.ErrInvalidInput, field+" must be the host or a participant of the event", nil)
+	}
+	return nil
+}
+
+func sameUser(id *uuid.UUID, userID uuid.UUID) bool {
+	return id != nil && *id == userID
+}
+
+func toAgendaResponse(event *entity.Event, items []entity.AgendaItem) *dto.AgendaResponse {
+	sort.Slice(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	response := &dto.AgendaResponse{
+		EventID:         event.ID.String(),
+		Items:           make([]dto.AgendaItemResponse, 0, len(items)),
+		DurationMinutes: event.DurationMinutes,
+	}
+	for i := range items {
+		response.Items = append(response.Items, dto.ToAgendaItemResponse(&items[i]))
+		if items[i].DurationMinutes != nil {
+			response.TotalMinutes += *items[i].DurationMinutes
+		}
+	}
+	return response
+}
+
+func toNotesResponse(eventID uuid.UUID, notes *entity.EventNotes) *dto.NotesResponse {
+	response := &dto.NotesResponse{EventID: eventID.String()}
+	if notes == nil {
+		return response
+	}
+	response.Content = notes.Content
+	response.Version = notes.Version
+	response.UpdatedAt = &notes.UpdatedAt
+	if notes.UpdatedBy != nil {
+		response.UpdatedBy = notes.UpdatedBy.String()
+	}
+	return response
+}
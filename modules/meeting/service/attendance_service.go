@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/meeting/dto"
+	"go-api-starter/modules/meeting/entity"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// bookingGuest is the guest a booking page request stores in the event preferences
+type bookingGuest struct {
+	GuestName  string `json:"guest_name"`
+	GuestEmail string `json:"guest_email"`
+}
+
+// GetAttendance lists the participants of the host's event with the attendance marked so far
+func (s *MeetingService) GetAttendance(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID) (*dto.AttendanceResponse, *errors.AppError) {
+	event, appErr := s.hostEvent(ctx, eventID, hostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return s.attendanceResponse(ctx, event)
+}
+
+// MarkAttendance records who attended a scheduled event once it has started.
+// Participants are the event's users and, for a booking, its guest; marking a participant again replaces the status.
+func (s *MeetingService) MarkAttendance(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.MarkAttendanceRequest) (*dto.AttendanceResponse, *errors.AppError) {
+	event, appErr := s.hostEvent(ctx, eventID, hostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if event.Status != entity.EventStatusScheduled || event.StartDate == nil {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "Attendance can only be marked for scheduled events", nil)
+	}
+	// Booking times are shifted on the calendar copy, so the meeting starts at start_date plus the provider offset
+	if event.StartDate.Add(time.Duration(event.ProviderOffset) * time.Minute).After(time.Now()) {
+		return nil, errors.NewAppError(errors.ErrInvalidState, "Attendance can only be marked once the event has started", nil)
+	}
+	if len(req.Attendance) == 0 {
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "attendance is required", nil)
+	}
+
+	participants, err := s.repo.GetParticipantsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get participants", err)
+	}
+	isParticipant := make(map[uuid.UUID]bool, len(participants))
+	for _, p := range participants {
+		isParticipant[p.UserID] = true
+	}
+	guest := eventBookingGuest(event)
+
+	marks := make([]entity.EventAttendance, 0, len(req.Attendance))
+	for _, mark := range req.Attendance {
+		attendance := entity.EventAttendance{
+			EventID:  eventID,
+			Status:   entity.AttendanceStatus(strings.ToLower(strings.TrimSpace(mark.Status))),
+			MarkedBy: &hostID,
+		}
+		switch attendance.Status {
+		case entity.AttendanceAttended, entity.AttendanceNoShow, entity.AttendanceLate:
+		default:
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "status must be attended, no_show or late", nil)
+		}
+
+		if mark.UserID != "" {
+			userID, err := uuid.Parse(mark.UserID)
+			if err != nil || !isParticipant[userID] {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, "Not a participant of this event: "+mark.UserID, nil)
+			}
+			attendance.UserID = &userID
+		} else {
+			email := strings.ToLower(strings.TrimSpace(mark.Email))
+			if !utils.IsValidEmail(email) || email != strings.ToLower(guest.GuestEmail) {
+				return nil, errors.NewAppError(errors.ErrInvalidInput, "Not a participant of this event: "+mark.Email, nil)
+			}
+			attendance.Email = &email
+		}
+		marks = append(marks, attendance)
+	}
+
+	for i := range marks {
+		if err := s.repo.SaveAttendance(ctx, &marks[i]); err != nil {
+			return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to save attendance", err)
+		}
+	}
+
+	response, appErr := s.attendanceResponse(ctx, event)
+	if appErr == nil {
+		s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventMeetingAttendance, response)
+	}
+	return response, appErr
+}
+
+// hostEvent returns an event owned by hostID
+func (s *MeetingService) hostEvent(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID) (*entity.Event, *errors.AppError) {
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil || event == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "Event not found", err)
+	}
+	if event.HostID == nil || *event.HostID != hostID {
+		return nil, errors.NewAppError(errors.ErrForbidden, "Not authorized", nil)
+	}
+	return event, nil
+}
+
+func (s *MeetingService) attendanceResponse(ctx context.Context, event *entity.Event) (*dto.AttendanceResponse, *errors.AppError) {
+	participants, err := s.repo.GetParticipantsByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get participants", err)
+	}
+	marked, err := s.repo.GetAttendanceByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to get attendance", err)
+	}
+	byParticipant := make(map[string]*entity.EventAttendance, len(marked))
+	for i := range marked {
+		if marked[i].UserID != nil {
+			byParticipant[marked[i].UserID.String()] = &marked[i]
+		} else if marked[i].Email != nil {
+			byParticipant[strings.ToLower(*marked[i].Email)] = &marked[i]
+		}
+	}
+
+	response := &dto.AttendanceResponse{
+		EventID:      event.ID.String(),
+		Participants: make([]dto.ParticipantAttendance, 0, len(participants)+1),
+	}
+	for _, p := range participants {
+		item := dto.ParticipantAttendance{UserID: p.UserID.String()}
+		applyAttendance(&item, byParticipant[item.UserID])
+		response.Participants = append(response.Participants, item)
+	}
+	if guest := eventBookingGuest(event); guest.GuestEmail != "" {
+		item := dto.ParticipantAttendance{Email: guest.GuestEmail, Name: guest.GuestName}
+		applyAttendance(&item, byParticipant[strings.ToLower(guest.GuestEmail)])
+		response.Participants = append(response.Participants, item)
+	}
+	return response, nil
+}
+
+func applyAttendance(item *dto.ParticipantAttendance, attendance *entity.EventAttendance) {
+	if attendance == nil {
+		return
+	}
+	item.Status = string(attendance.Status)
+	item.MarkedAt = &attendance.UpdatedAt
+}
+
+// eventBookingGuest reads the guest of a booking page request; it is empty for other events
+func eventBookingGuest(event *entity.Event) bookingGuest {
+	var guest bookingGuest
+	if event.Preferences != nil && *event.Preferences != "" {
+		_ = json.Unmarshal([]byte(*event.Preferences), &guest)
+	}
+	guest.GuestName = strings.TrimSpace(guest.GuestName)
+	guest.GuestEmail = strings.TrimSpace(guest.GuestEmail)
+	return guest
+}
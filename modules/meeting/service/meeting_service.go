@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"go-api-starter/core/constants"
 	"go-api-starter/core/errors"
 	"go-api-starter/modules/meeting/dto"
 	"go-api-starter/modules/meeting/entity"
 	"go-api-starter/modules/meeting/repository"
+	notifService "go-api-starter/modules/notification/service"
+	webhookService "go-api-starter/modules/webhook/service"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +19,8 @@ import (
 type MeetingService struct {
 	repo       repository.MeetingRepositoryInterface
 	slotFinder *SlotFinder
+	notifSvc   *notifService.NotificationService
+	webhookSvc *webhookService.WebhookService
 }
 
 // MeetingServiceInterface defines the service contract
@@ -27,13 +32,31 @@ type MeetingServiceInterface interface {
 	DeleteEvent(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID) *errors.AppError
 	FindSlots(ctx context.Context, eventID uuid.UUID, req *dto.FindSlotsRequest) (*dto.FindSlotsResponse, *errors.AppError)
 	SelectSlot(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.SelectSlotRequest) (*dto.EventResponse, *errors.AppError)
+	GetAttendance(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID) (*dto.AttendanceResponse, *errors.AppError)
+	MarkAttendance(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.MarkAttendanceRequest) (*dto.AttendanceResponse, *errors.AppError)
+	GetAgenda(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*dto.AgendaResponse, *errors.AppError)
+	SaveAgenda(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.SaveAgendaRequest) (*dto.AgendaResponse, *errors.AppError)
+	ApplyAgendaTemplate(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID, req *dto.ApplyAgendaTemplateRequest) (*dto.AgendaResponse, *errors.AppError)
+	GetNotes(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*dto.NotesResponse, *errors.AppError)
+	SaveNotes(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, req *dto.SaveNotesRequest) (*dto.NotesResponse, *errors.AppError)
+	ListActionItems(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) ([]dto.ActionItemResponse, *errors.AppError)
+	GetMyActionItems(ctx context.Context, userID uuid.UUID, status string) ([]dto.ActionItemResponse, *errors.AppError)
+	CreateActionItem(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, req *dto.CreateActionItemRequest) (*dto.ActionItemResponse, *errors.AppError)
+	UpdateActionItem(ctx context.Context, eventID uuid.UUID, itemID uuid.UUID, userID uuid.UUID, req *dto.UpdateActionItemRequest) (*dto.ActionItemResponse, *errors.AppError)
+	DeleteActionItem(ctx context.Context, eventID uuid.UUID, itemID uuid.UUID, userID uuid.UUID) *errors.AppError
+	HandleActionItemReminderTask(ctx context.Context, payload []byte) error
+	ListAgendaTemplates(ctx context.Context, userID uuid.UUID) ([]dto.AgendaTemplateResponse, *errors.AppError)
+	SaveAgendaTemplate(ctx context.Context, userID uuid.UUID, templateID string, req *dto.SaveAgendaTemplateRequest) (*dto.AgendaTemplateResponse, *errors.AppError)
+	DeleteAgendaTemplate(ctx context.Context, userID uuid.UUID, templateID string) *errors.AppError
 }
 
 // NewMeetingService creates a new meeting service
-func NewMeetingService(repo repository.MeetingRepositoryInterface) MeetingServiceInterface {
+func NewMeetingService(repo repository.MeetingRepositoryInterface, notifSvc *notifService.NotificationService, webhookSvc *webhookService.WebhookService) MeetingServiceInterface {
 	return &MeetingService{
 		repo:       repo,
 		slotFinder: NewSlotFinder(),
+		notifSvc:   notifSvc,
+		webhookSvc: webhookSvc,
 	}
 }
 
@@ -92,7 +115,10 @@ func (s *MeetingService) CreateEvent(ctx context.Context, hostID uuid.UUID, req
 		participants = append(participants, *participant)
 	}
 
-	return dto.ToEventResponse(created, participants), nil
+	response := dto.ToEventResponse(created, participants)
+	s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventMeetingCreated, response)
+
+	return response, nil
 }
 
 // GetEventByID retrieves an event by ID
@@ -155,13 +181,18 @@ func (s *MeetingService) UpdateEvent(ctx context.Context, eventID uuid.UUID, hos
 		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to update event", err)
 	}
 
-	return s.GetEventByID(ctx, eventID)
+	response, appErr := s.GetEventByID(ctx, eventID)
+	if appErr == nil {
+		s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventMeetingUpdated, response)
+	}
+	return response, appErr
 }
 
 // DeleteEvent deletes an event
 func (s *MeetingService) DeleteEvent(ctx context.Context, eventID uuid.UUID, hostID uuid.UUID) *errors.AppError {
 	event, err := s.repo.GetEventByID(ctx, eventID)
-	if err != nil || event == nil {
+	// A cancelled linked event was already deleted and only waits for the sync job
+	if err != nil || event == nil || (event.ProviderEventID != nil && event.Status == entity.EventStatusCancelled) {
 		return errors.NewAppError(errors.ErrNotFound, "Event not found", err)
 	}
 
@@ -169,11 +200,22 @@ func (s *MeetingService) DeleteEvent(ctx context.Context, eventID uuid.UUID, hos
 		return errors.NewAppError(errors.ErrForbidden, "Not authorized", nil)
 	}
 
-	err = s.repo.DeleteEvent(ctx, eventID)
+	if event.ProviderEventID != nil {
+		// Keep linked events as cancelled so the calendar sync job can remove the provider copy
+		event.Status = entity.EventStatusCancelled
+		err = s.repo.UpdateEvent(ctx, event)
+	} else {
+		err = s.repo.DeleteEvent(ctx, eventID)
+	}
 	if err != nil {
 		return errors.NewAppError(errors.ErrInternalServer, "Failed to delete event", err)
 	}
 
+	s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventMeetingDeleted, map[string]interface{}{
+		"id":    eventID.String(),
+		"title": event.Title,
+	})
+
 	return nil
 }
 
@@ -287,5 +329,9 @@ func (s *MeetingService) SelectSlot(ctx context.Context, eventID uuid.UUID, host
 		return nil, errors.NewAppError(errors.ErrInternalServer, "Failed to schedule event", err)
 	}
 
-	return s.GetEventByID(ctx, eventID)
+	response, appErr := s.GetEventByID(ctx, eventID)
+	if appErr == nil {
+		s.webhookSvc.Emit(ctx, &hostID, constants.WebhookEventMeetingScheduled, response)
+	}
+	return response, appErr
 }
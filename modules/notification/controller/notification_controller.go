@@ -125,6 +125,63 @@ func (c *NotificationController) CountUnread(ctx echo.Context) error {
 	return c.SuccessResponse(ctx, map[string]int{"count": count}, "Unread count retrieved")
 }
 
+// GetDigestSetting returns the user's notification digest preferences
+// @Summary Lấy cài đặt tóm tắt thông báo
+// @Description Trả về cài đặt email tóm tắt thông báo (hằng ngày/hằng tuần) của người dùng hiện tại
+// @Tags Notification
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.DigestSettingResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/notifications/digest-settings [get]
+func (c *NotificationController) GetDigestSetting(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	result, err := c.service.GetDigestSetting(ctx.Request().Context(), userID)
+	if err != nil {
+		return c.InternalServerError(errors.ErrInternalServer, "Failed to get digest settings", err)
+	}
+
+	return c.SuccessResponse(ctx, result, "Digest settings retrieved successfully")
+}
+
+// UpdateDigestSetting opts the user in or out of notification digests
+// @Summary Cập nhật cài đặt tóm tắt thông báo
+// @Description Bật/tắt email tóm tắt thông báo chưa đọc, chọn tần suất, ngôn ngữ, múi giờ và giờ gửi
+// @Tags Notification
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateDigestSettingRequest true "Cài đặt tóm tắt"
+// @Success 200 {object} dto.DigestSettingResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/notifications/digest-settings [put]
+func (c *NotificationController) UpdateDigestSetting(ctx echo.Context) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	req := new(dto.UpdateDigestSettingRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, err := c.service.UpdateDigestSetting(ctx.Request().Context(), userID, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrInvalidInput {
+			return c.BadRequest(appErr.Code, appErr.Message, nil)
+		}
+		return c.InternalServerError(errors.ErrInternalServer, "Failed to update digest settings", err)
+	}
+
+	return c.SuccessResponse(ctx, result, "Digest settings updated successfully")
+}
+
 // Helper function to get user ID from JWT context
 func getUserIDFromContext(ctx echo.Context) (uuid.UUID, error) {
 	token := ctx.Request().Header.Get("Authorization")
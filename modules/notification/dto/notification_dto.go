@@ -27,3 +27,20 @@ type CreateNotificationRequest struct {
 	Type    string                 `json:"type"`
 	Data    map[string]interface{} `json:"data"`
 }
+
+type UpdateDigestSettingRequest struct {
+	Frequency   string `json:"frequency" validate:"required,oneof=off daily weekly"`
+	Locale      string `json:"locale"`
+	Timezone    string `json:"timezone"`
+	SendHour    *int   `json:"send_hour"`
+	SendWeekday *int   `json:"send_weekday"`
+}
+
+type DigestSettingResponse struct {
+	Frequency   string     `json:"frequency"`
+	Locale      string     `json:"locale"`
+	Timezone    string     `json:"timezone"`
+	SendHour    int        `json:"send_hour"`
+	SendWeekday int        `json:"send_weekday"`
+	LastSentAt  *time.Time `json:"last_sent_at"`
+}
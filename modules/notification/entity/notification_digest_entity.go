@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"go-api-starter/core/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DigestFrequencyOff    = "off"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+type NotificationDigestSetting struct {
+	UserID      uuid.UUID  `db:"user_id" json:"user_id"`
+	Frequency   string     `db:"frequency" json:"frequency"`
+	Locale      string     `db:"locale" json:"locale"`
+	Timezone    string     `db:"timezone" json:"timezone"`
+	SendHour    int        `db:"send_hour" json:"send_hour"`
+	SendWeekday int        `db:"send_weekday" json:"send_weekday"`
+	LastSentAt  *time.Time `db:"last_sent_at" json:"last_sent_at"`
+	entity.BaseEntity
+}
+
+// DigestRecipient is a digest setting joined with the user's email address
+type DigestRecipient struct {
+	NotificationDigestSetting
+	Email string `db:"email"`
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"go-api-starter/core/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,6 +17,8 @@ type Notification struct {
 	Type    string    `db:"type" json:"type"`
 	Data    JSONB     `db:"data" json:"data"`
 	IsRead  bool      `db:"is_read" json:"is_read"`
+	// DigestedAt is set once the notification has been included in a digest email
+	DigestedAt *time.Time `db:"digested_at" json:"digested_at,omitempty"`
 	entity.BaseEntity
 }
 
@@ -1,12 +1,16 @@
 package notification
 
 import (
+	"context"
+	"go-api-starter/core/constants"
 	"go-api-starter/core/database"
 	"go-api-starter/core/middleware"
 	"go-api-starter/modules/notification/controller"
 	"go-api-starter/modules/notification/repository"
 	"go-api-starter/modules/notification/router"
 	"go-api-starter/modules/notification/service"
+	"go-api-starter/workers"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -18,5 +22,11 @@ func Init(e *echo.Group, db database.Database, mw *middleware.Middleware) *servi
 
 	router.NewNotificationRouter(ctrl).Register(e, mw)
 
+	// Digest job runs hourly and sends to users whose local send hour has come
+	workers.RegisterHandler(constants.TopicQueueNotificationDigest, func(ctx context.Context, _ []byte) error {
+		return svc.SendDueDigests(ctx, time.Now())
+	})
+	workers.RegisterPeriodicTask("0 * * * *", constants.TopicQueueNotificationDigest)
+
 	return svc
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go-api-starter/core/logger"
+	"go-api-starter/modules/notification/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func (r *NotificationRepository) GetDigestSetting(ctx context.Context, userID uuid.UUID) (*entity.NotificationDigestSetting, error) {
+	var setting entity.NotificationDigestSetting
+	query := `SELECT * FROM notification_digest_settings WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &setting, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("NotificationRepository:GetDigestSetting:Error:", err)
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *NotificationRepository) UpsertDigestSetting(ctx context.Context, setting *entity.NotificationDigestSetting) error {
+	query := `
+		INSERT INTO notification_digest_settings (user_id, frequency, locale, timezone, send_hour, send_weekday, created_at, updated_at)
+		VALUES (:user_id, :frequency, :locale, :timezone, :send_hour, :send_weekday, NOW(), NOW())
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			frequency = EXCLUDED.frequency,
+			locale = EXCLUDED.locale,
+			timezone = EXCLUDED.timezone,
+			send_hour = EXCLUDED.send_hour,
+			send_weekday = EXCLUDED.send_weekday,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, setting)
+	if err != nil {
+		logger.Error("NotificationRepository:UpsertDigestSetting:Error:", err)
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.Scan(&setting.ID, &setting.CreatedAt, &setting.UpdatedAt)
+	}
+	return nil
+}
+
+// GetDigestRecipients returns every opted-in user that has an email address
+func (r *NotificationRepository) GetDigestRecipients(ctx context.Context) ([]entity.DigestRecipient, error) {
+	var recipients []entity.DigestRecipient
+	query := `
+		SELECT s.*, u.email
+		FROM notification_digest_settings s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.frequency <> 'off' AND u.email IS NOT NULL AND u.email <> ''
+	`
+	err := r.db.SelectContext(ctx, &recipients, query)
+	if err != nil {
+		logger.Error("NotificationRepository:GetDigestRecipients:Error:", err)
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// GetUndigestedUnread returns unread notifications that have not been part of a digest yet
+func (r *NotificationRepository) GetUndigestedUnread(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	query := `
+		SELECT * FROM notifications
+		WHERE user_id = $1 AND is_read = false AND digested_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	err := r.db.SelectContext(ctx, &notifications, query, userID, limit)
+	if err != nil {
+		logger.Error("NotificationRepository:GetUndigestedUnread:Error:", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) MarkDigested(ctx context.Context, ids []uuid.UUID, digestedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`UPDATE notifications SET digested_at = ? WHERE id IN (?)`, digestedAt, ids)
+	if err != nil {
+		return err
+	}
+
+	query = r.db.SQLx().Rebind(query)
+	err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.Error("NotificationRepository:MarkDigested:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationRepository) UpdateDigestLastSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) error {
+	query := `UPDATE notification_digest_settings SET last_sent_at = $2, updated_at = NOW() WHERE user_id = $1`
+	err := r.db.ExecContext(ctx, query, userID, sentAt)
+	if err != nil {
+		logger.Error("NotificationRepository:UpdateDigestLastSent:Error:", err)
+		return err
+	}
+	return nil
+}
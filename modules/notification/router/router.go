@@ -21,4 +21,6 @@ func (r *NotificationRouter) Register(e *echo.Group, mw *middleware.Middleware)
 	group.GET("/unread-count", r.controller.CountUnread)
 	group.PUT("/mark-read", r.controller.MarkAsRead)
 	group.PUT("/mark-all-read", r.controller.MarkAllAsRead)
+	group.GET("/digest-settings", r.controller.GetDigestSetting)
+	group.PUT("/digest-settings", r.controller.UpdateDigestSetting)
 }
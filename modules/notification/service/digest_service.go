@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/notification/dto"
+	"go-api-starter/modules/notification/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDigestLocale   = "vi"
+	defaultDigestTimezone = "Asia/Ho_Chi_Minh"
+	defaultDigestSendHour = 8
+	digestMaxItems        = 50
+	digestTemplate        = "notification_digest.html"
+)
+
+// digestStrings holds the localized copy used by the digest email
+type digestStrings struct {
+	DailySubject  string
+	WeeklySubject string
+	Intro         string
+	Footer        string
+	TimeLayout    string
+	TypeLabels    map[string]string
+}
+
+var digestLocales = map[string]digestStrings{
+	"vi": {
+		DailySubject:  "Tóm tắt thông báo hôm nay (%d)",
+		WeeklySubject: "Tóm tắt thông báo trong tuần (%d)",
+		Intro:         "Bạn có %d thông báo chưa đọc:",
+		Footer:        "Bạn nhận được email này vì đã bật tóm tắt thông báo. Có thể tắt trong phần cài đặt thông báo.",
+		TimeLayout:    "15:04 02/01/2006",
+		TypeLabels: map[string]string{
+			"invitation":      "Lời mời",
+			"booking_request": "Yêu cầu đặt lịch",
+			"event_cancelled": "Sự kiện bị hủy",
+			"event_updated":   "Sự kiện thay đổi",
+			"time_proposal":   "Đề xuất thời gian",
+		},
+	},
+	"en": {
+		DailySubject:  "Your daily notification digest (%d)",
+		WeeklySubject: "Your weekly notification digest (%d)",
+		Intro:         "You have %d unread notifications:",
+		Footer:        "You are receiving this email because notification digests are enabled. You can turn them off in your notification settings.",
+		TimeLayout:    "Jan 2, 2006 3:04 PM",
+		TypeLabels: map[string]string{
+			"invitation":      "Invitation",
+			"booking_request": "Booking request",
+			"event_cancelled": "Event cancelled",
+			"event_updated":   "Event updated",
+			"time_proposal":   "Time proposal",
+		},
+	},
+}
+
+type digestItem struct {
+	Type    string
+	Title   string
+	Message string
+	Time    string
+}
+
+type digestEmailData struct {
+	Lang   string
+	Title  string
+	Intro  string
+	Items  []digestItem
+	Footer string
+}
+
+func (s *NotificationService) GetDigestSetting(ctx context.Context, userID uuid.UUID) (*dto.DigestSettingResponse, error) {
+	setting, err := s.repo.GetDigestSetting(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return &dto.DigestSettingResponse{
+			Frequency:   entity.DigestFrequencyOff,
+			Locale:      defaultDigestLocale,
+			Timezone:    defaultDigestTimezone,
+			SendHour:    defaultDigestSendHour,
+			SendWeekday: int(time.Monday),
+		}, nil
+	}
+	return toDigestSettingResponse(setting), nil
+}
+
+func (s *NotificationService) UpdateDigestSetting(ctx context.Context, userID uuid.UUID, req *dto.UpdateDigestSettingRequest) (*dto.DigestSettingResponse, error) {
+	switch req.Frequency {
+	case entity.DigestFrequencyOff, entity.DigestFrequencyDaily, entity.DigestFrequencyWeekly:
+	default:
+		return nil, errors.NewAppError(errors.ErrInvalidInput, "frequency must be one of off, daily, weekly", nil)
+	}
+
+	current, err := s.GetDigestSetting(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &entity.NotificationDigestSetting{
+		UserID:      userID,
+		Frequency:   req.Frequency,
+		Locale:      current.Locale,
+		Timezone:    current.Timezone,
+		SendHour:    current.SendHour,
+		SendWeekday: current.SendWeekday,
+	}
+
+	if req.Locale != "" {
+		if _, ok := digestLocales[req.Locale]; !ok {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "unsupported locale", nil)
+		}
+		setting.Locale = req.Locale
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "invalid timezone", err)
+		}
+		setting.Timezone = req.Timezone
+	}
+	if req.SendHour != nil {
+		if *req.SendHour < 0 || *req.SendHour > 23 {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "send_hour must be between 0 and 23", nil)
+		}
+		setting.SendHour = *req.SendHour
+	}
+	if req.SendWeekday != nil {
+		if *req.SendWeekday < 0 || *req.SendWeekday > 6 {
+			return nil, errors.NewAppError(errors.ErrInvalidInput, "send_weekday must be between 0 (Sunday) and 6", nil)
+		}
+		setting.SendWeekday = *req.SendWeekday
+	}
+
+	if err := s.repo.UpsertDigestSetting(ctx, setting); err != nil {
+		return nil, err
+	}
+	return s.GetDigestSetting(ctx, userID)
+}
+
+// SendDueDigests sends a digest to every opted-in user whose local send time matches now.
+// It is triggered hourly by the scheduler; last_sent_at guards against duplicate sends on retry.
+func (s *NotificationService) SendDueDigests(ctx context.Context, now time.Time) error {
+	recipients, err := s.repo.GetDigestRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("NotificationService:SendDueDigests:Start", "recipients", len(recipients))
+
+	for _, recipient := range recipients {
+		if !isDigestDue(&recipient.NotificationDigestSetting, now) {
+			continue
+		}
+		if err := s.sendDigest(ctx, &recipient, now); err != nil {
+			logger.Error("NotificationService:SendDueDigests:SendDigest:Error", "user_id", recipient.UserID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, recipient *entity.DigestRecipient, now time.Time) error {
+	notifications, err := s.repo.GetUndigestedUnread(ctx, recipient.UserID, digestMaxItems)
+	if err != nil {
+		return err
+	}
+	if len(notifications) == 0 {
+		return s.repo.UpdateDigestLastSent(ctx, recipient.UserID, now)
+	}
+
+	strs, ok := digestLocales[recipient.Locale]
+	if !ok {
+		strs = digestLocales[defaultDigestLocale]
+	}
+	loc := loadDigestLocation(recipient.Timezone)
+
+	subjectFormat := strs.DailySubject
+	if recipient.Frequency == entity.DigestFrequencyWeekly {
+		subjectFormat = strs.WeeklySubject
+	}
+	subject := fmt.Sprintf(subjectFormat, len(notifications))
+
+	data := digestEmailData{
+		Lang:   recipient.Locale,
+		Title:  subject,
+		Intro:  fmt.Sprintf(strs.Intro, len(notifications)),
+		Items:  make([]digestItem, 0, len(notifications)),
+		Footer: strs.Footer,
+	}
+	ids := make([]uuid.UUID, 0, len(notifications))
+	for _, n := range notifications {
+		label, ok := strs.TypeLabels[n.Type]
+		if !ok {
+			label = n.Type
+		}
+		data.Items = append(data.Items, digestItem{
+			Type:    label,
+			Title:   n.Title,
+			Message: n.Message,
+			Time:    n.CreatedAt.In(loc).Format(strs.TimeLayout),
+		})
+		ids = append(ids, n.ID)
+	}
+
+	if err := utils.SendTemplateEmailFromTemplatesDir([]string{recipient.Email}, subject, digestTemplate, data); err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkDigested(ctx, ids, now); err != nil {
+		return err
+	}
+
+	logger.Info("NotificationService:sendDigest:Sent", "user_id", recipient.UserID, "count", len(ids))
+	return s.repo.UpdateDigestLastSent(ctx, recipient.UserID, now)
+}
+
+func isDigestDue(setting *entity.NotificationDigestSetting, now time.Time) bool {
+	local := now.In(loadDigestLocation(setting.Timezone))
+	if local.Hour() != setting.SendHour {
+		return false
+	}
+
+	minGap := 20 * time.Hour
+	if setting.Frequency == entity.DigestFrequencyWeekly {
+		if int(local.Weekday()) != setting.SendWeekday {
+			return false
+		}
+		minGap = 6 * 24 * time.Hour
+	}
+
+	return setting.LastSentAt == nil || now.Sub(*setting.LastSentAt) >= minGap
+}
+
+func loadDigestLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultDigestTimezone)
+	}
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func toDigestSettingResponse(setting *entity.NotificationDigestSetting) *dto.DigestSettingResponse {
+	return &dto.DigestSettingResponse{
+		Frequency:   setting.Frequency,
+		Locale:      setting.Locale,
+		Timezone:    setting.Timezone,
+		SendHour:    setting.SendHour,
+		SendWeekday: setting.SendWeekday,
+		LastSentAt:  setting.LastSentAt,
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"go-api-starter/modules/notification/entity"
+)
+
+func TestIsDigestDue(t *testing.T) {
+	// Monday 2030-03-04 08:30 in Ho Chi Minh City (UTC+7)
+	now := time.Date(2030, 3, 4, 1, 30, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+
+	tests := []struct {
+		name    string
+		setting entity.NotificationDigestSetting
+		want    bool
+	}{
+		{
+			name:    "daily at the send hour, never sent",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8},
+			want:    true,
+		},
+		{
+			name:    "daily outside the send hour",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "Asia/Ho_Chi_Minh", SendHour: 9},
+			want:    false,
+		},
+		{
+			name:    "send hour is read in the user's timezone",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "UTC", SendHour: 8},
+			want:    false,
+		},
+		{
+			name:    "unknown timezone falls back to the default",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "Mars/Olympus", SendHour: 8},
+			want:    true,
+		},
+		{
+			name:    "daily already sent this hour",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8, LastSentAt: ago(10 * time.Minute)},
+			want:    false,
+		},
+		{
+			name:    "daily sent yesterday",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyDaily, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8, LastSentAt: ago(24 * time.Hour)},
+			want:    true,
+		},
+		{
+			name:    "weekly on the send weekday",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyWeekly, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8, SendWeekday: int(time.Monday), LastSentAt: ago(7 * 24 * time.Hour)},
+			want:    true,
+		},
+		{
+			name:    "weekly on another weekday",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyWeekly, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8, SendWeekday: int(time.Friday)},
+			want:    false,
+		},
+		{
+			name:    "weekly sent a day ago",
+			setting: entity.NotificationDigestSetting{Frequency: entity.DigestFrequencyWeekly, Timezone: "Asia/Ho_Chi_Minh", SendHour: 8, SendWeekday: int(time.Monday), LastSentAt: ago(24 * time.Hour)},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDigestDue(&tt.setting, now); got != tt.want {
+				t.Errorf("isDigestDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
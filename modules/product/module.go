@@ -10,13 +10,14 @@ import (
 	"go-api-starter/modules/product/repository"
 	"go-api-starter/modules/product/router"
 	"go-api-starter/modules/product/service"
+	webhookService "go-api-starter/modules/webhook/service"
 
 	"github.com/labstack/echo/v4"
 )
 
-func Init(e *echo.Echo, db database.Database, cache cache.Cache) {
+func Init(e *echo.Echo, db database.Database, cache cache.Cache, webhookSvc *webhookService.WebhookService) {
 	repository := repository.NewProductRepository(db)
-	service := service.NewProductService(repository)
+	service := service.NewProductService(repository, webhookSvc)
 	authRepository := authRepository.NewAuthRepository(db)
 	authService := authService.NewAuthService(authRepository, cache)
 	controller := controller.NewProductController(service, authService)
@@ -120,6 +120,17 @@ func (s *ProductService) PublicPlaceOrder(ctx context.Context, req *dto.PlaceOrd
 		return nil, errors.NewAppError(errors.ErrCreateFailed, "Create order items error", err)
 	}
 
+	// 8. Phát sự kiện webhook
+	s.webhookSvc.Emit(ctx, createdOrder.CustomerID, constants.WebhookEventOrderPlaced, map[string]interface{}{
+		"order_id":       createdOrder.ID.String(),
+		"order_number":   createdOrder.OrderNumber,
+		"customer_id":    createdOrder.CustomerID,
+		"customer_email": createdOrder.CustomerEmail,
+		"total_amount":   createdOrder.TotalAmount,
+		"order_state":    createdOrder.OrderState,
+		"payment_status": createdOrder.PaymentStatus,
+	})
+
 	// 6. Tạo response
 	return &dto.PlaceOrderResponse{
 		OrderNumber:            createdOrder.OrderNumber,
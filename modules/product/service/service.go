@@ -6,18 +6,20 @@ import (
 	"go-api-starter/core/params"
 	"go-api-starter/modules/product/dto"
 	"go-api-starter/modules/product/repository"
+	webhookService "go-api-starter/modules/webhook/service"
 	"sync"
 
 	"github.com/google/uuid"
 )
 
 type ProductService struct {
-	mu   sync.Mutex
-	repo repository.ProductRepositoryInterface
+	mu         sync.Mutex
+	repo       repository.ProductRepositoryInterface
+	webhookSvc *webhookService.WebhookService
 }
 
-func NewProductService(repo repository.ProductRepositoryInterface) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo repository.ProductRepositoryInterface, webhookSvc *webhookService.WebhookService) *ProductService {
+	return &ProductService{repo: repo, webhookSvc: webhookSvc}
 }
 
 type ProductServiceInterface interface {
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"go-api-starter/core/constants"
+	"go-api-starter/core/controller"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/params"
+	"go-api-starter/core/utils"
+	"go-api-starter/modules/webhook/dto"
+	"go-api-starter/modules/webhook/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookController serves both user-owned and admin (global) subscriptions.
+// The admin instance scopes every call to subscriptions without an owner.
+type WebhookController struct {
+	service *service.WebhookService
+	admin   bool
+	controller.BaseController
+}
+
+func NewWebhookController(service *service.WebhookService, admin bool) *WebhookController {
+	return &WebhookController{
+		service:        service,
+		admin:          admin,
+		BaseController: controller.NewBaseController(),
+	}
+}
+
+// ListSubscriptions lists webhook subscriptions
+// @Summary Lấy danh sách webhook
+// @Description Trả về các webhook đã đăng ký của người dùng hiện tại (hoặc webhook toàn hệ thống với quyền admin)
+// @Tags Webhook
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.SubscriptionResponse
+// @Failure 401 {object} errors.AppError
+// @Router /private/webhooks [get]
+func (c *WebhookController) ListSubscriptions(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	result, appErr := c.service.ListSubscriptions(ctx.Request().Context(), ownerID)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Webhook subscriptions retrieved successfully")
+}
+
+// CreateSubscription registers a webhook endpoint
+// @Summary Đăng ký webhook
+// @Description Đăng ký URL nhận sự kiện; payload được ký HMAC-SHA256 bằng secret (tự sinh nếu không truyền)
+// @Tags Webhook
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSubscriptionRequest true "Thông tin webhook"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Router /private/webhooks [post]
+func (c *WebhookController) CreateSubscription(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	req := new(dto.CreateSubscriptionRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, appErr := c.service.CreateSubscription(ctx.Request().Context(), ownerID, req)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Webhook subscription created successfully")
+}
+
+// UpdateSubscription updates a webhook subscription
+// @Summary Cập nhật webhook
+// @Description Cập nhật URL, loại sự kiện, trạng thái hoặc xoay vòng secret của webhook
+// @Tags Webhook
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body dto.UpdateSubscriptionRequest true "Thông tin cập nhật"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Router /private/webhooks/{id} [put]
+func (c *WebhookController) UpdateSubscription(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid webhook id", nil)
+	}
+
+	req := new(dto.UpdateSubscriptionRequest)
+	if err := ctx.Bind(req); err != nil {
+		return c.BadRequest(errors.ErrInvalidRequestData, "Invalid request body", nil)
+	}
+
+	result, appErr := c.service.UpdateSubscription(ctx.Request().Context(), ownerID, id, req)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Webhook subscription updated successfully")
+}
+
+// DeleteSubscription removes a webhook subscription
+// @Summary Xóa webhook
+// @Description Xóa webhook và lịch sử gửi của nó
+// @Tags Webhook
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} errors.AppError
+// @Router /private/webhooks/{id} [delete]
+func (c *WebhookController) DeleteSubscription(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid webhook id", nil)
+	}
+
+	if appErr := c.service.DeleteSubscription(ctx.Request().Context(), ownerID, id); appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, nil, "Webhook subscription deleted successfully")
+}
+
+// ListDeliveries returns the delivery log of a subscription
+// @Summary Lịch sử gửi webhook
+// @Description Trả về nhật ký các lần gửi webhook (trạng thái, số lần thử, phản hồi)
+// @Tags Webhook
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param page_number query int false "Số trang"
+// @Param page_size query int false "Số lượng mỗi trang"
+// @Success 200 {object} dto.PaginatedDeliveryResponse
+// @Failure 404 {object} errors.AppError
+// @Router /private/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveries(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid webhook id", nil)
+	}
+
+	queryParams := params.NewQueryParams(ctx)
+	result, appErr := c.service.ListDeliveries(ctx.Request().Context(), ownerID, id, *queryParams)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Webhook deliveries retrieved successfully")
+}
+
+// Redeliver re-sends a previous delivery
+// @Summary Gửi lại webhook
+// @Description Đưa một lần gửi webhook trước đó vào hàng đợi để gửi lại
+// @Tags Webhook
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} dto.DeliveryResponse
+// @Failure 404 {object} errors.AppError
+// @Router /private/webhooks/deliveries/{id}/redeliver [post]
+func (c *WebhookController) Redeliver(ctx echo.Context) error {
+	ownerID, err := c.ownerScope(ctx)
+	if err != nil {
+		return c.Unauthorized(errors.ErrUnauthorized, "Unauthorized", nil)
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return c.BadRequest(errors.ErrInvalidInput, "Invalid delivery id", nil)
+	}
+
+	result, appErr := c.service.Redeliver(ctx.Request().Context(), ownerID, id)
+	if appErr != nil {
+		return c.ErrorResponse(ctx, appErr)
+	}
+
+	return c.SuccessResponse(ctx, result, "Webhook delivery queued")
+}
+
+// ownerScope returns the caller's user ID, or nil for the admin controller
+func (c *WebhookController) ownerScope(ctx echo.Context) (*uuid.UUID, error) {
+	claims, ok := ctx.Get(constants.ContextTokenData).(*utils.TokenClaims)
+	if !ok || claims == nil {
+		return nil, errors.NewAppError(errors.ErrUnauthorized, "User not authenticated", nil)
+	}
+	if c.admin {
+		return nil, nil
+	}
+	userID := claims.UserID
+	return &userID, nil
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateSubscriptionRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	EventTypes  []string `json:"event_types" validate:"required"`
+	Secret      string   `json:"secret"`
+	Description string   `json:"description"`
+}
+
+type UpdateSubscriptionRequest struct {
+	URL          string   `json:"url"`
+	EventTypes   []string `json:"event_types"`
+	Description  *string  `json:"description"`
+	IsActive     *bool    `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+type SubscriptionResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      *uuid.UUID `json:"user_id"`
+	URL         string     `json:"url"`
+	EventTypes  []string   `json:"event_types"`
+	Description string     `json:"description"`
+	IsActive    bool       `json:"is_active"`
+	// Secret is only returned when a subscription is created or its secret is rotated
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status"`
+	ResponseBody   *string         `json:"response_body,omitempty"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type PaginatedDeliveryResponse struct {
+	Items      []DeliveryResponse `json:"items"`
+	TotalItems int                `json:"total_items"`
+	PageNumber int                `json:"page_number"`
+	PageSize   int                `json:"page_size"`
+}
+
+// EventPayload is the JSON body POSTed to subscribers
+type EventPayload struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// DeliveryTask is the asynq payload for a single delivery attempt
+type DeliveryTask struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
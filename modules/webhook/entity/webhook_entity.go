@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"go-api-starter/core/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// WebhookSubscription is an outbound endpoint; UserID is nil for admin-managed (global) subscriptions
+type WebhookSubscription struct {
+	UserID      *uuid.UUID     `db:"user_id"`
+	URL         string         `db:"url"`
+	Secret      string         `db:"secret"`
+	EventTypes  pq.StringArray `db:"event_types"`
+	Description *string        `db:"description"`
+	IsActive    bool           `db:"is_active"`
+	entity.BaseEntity
+}
+
+type WebhookDelivery struct {
+	SubscriptionID uuid.UUID  `db:"subscription_id"`
+	EventID        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        string     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	ResponseStatus *int       `db:"response_status"`
+	ResponseBody   *string    `db:"response_body"`
+	LastError      *string    `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	entity.BaseEntity
+}
+
+type PaginatedWebhookDeliveryEntity = entity.Pagination[WebhookDelivery]
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"go-api-starter/core/cache"
+	"go-api-starter/core/constants"
+	"go-api-starter/core/database"
+	"go-api-starter/core/middleware"
+	authRepository "go-api-starter/modules/auth/repository"
+	authService "go-api-starter/modules/auth/service"
+	"go-api-starter/modules/webhook/controller"
+	"go-api-starter/modules/webhook/repository"
+	"go-api-starter/modules/webhook/router"
+	"go-api-starter/modules/webhook/service"
+	"go-api-starter/workers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Init initializes the webhook module and returns the service other modules emit events through
+func Init(e *echo.Echo, db database.Database, cache cache.Cache) *service.WebhookService {
+	repo := repository.NewWebhookRepository(db)
+	svc := service.NewWebhookService(repo)
+
+	authRepo := authRepository.NewAuthRepository(db)
+	mw := middleware.NewMiddleware(authService.NewAuthService(authRepo, cache))
+
+	router.NewWebhookRouter(
+		controller.NewWebhookController(svc, false),
+		controller.NewWebhookController(svc, true),
+	).Setup(e, mw)
+
+	workers.RegisterHandler(constants.TopicQueueWebhookDelivery, svc.HandleDeliveryTask)
+
+	return svc
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go-api-starter/core/database"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/params"
+	"go-api-starter/modules/webhook/entity"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository struct {
+	db database.Database
+}
+
+func NewWebhookRepository(db database.Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (user_id, url, secret, event_types, description, is_active, created_at, updated_at)
+		VALUES (:user_id, :url, :secret, :event_types, :description, :is_active, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, sub)
+	if err != nil {
+		logger.Error("WebhookRepository:CreateSubscription:Error:", err)
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	var sub entity.WebhookSubscription
+	query := `SELECT * FROM webhook_subscriptions WHERE id = $1`
+	err := r.db.GetContext(ctx, &sub, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("WebhookRepository:GetSubscriptionByID:Error:", err)
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetSubscriptionsByOwner lists subscriptions of a user, or the global ones when userID is nil
+func (r *WebhookRepository) GetSubscriptionsByOwner(ctx context.Context, userID *uuid.UUID) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	var err error
+	if userID == nil {
+		err = r.db.SelectContext(ctx, &subs, `SELECT * FROM webhook_subscriptions WHERE user_id IS NULL ORDER BY created_at DESC`)
+	} else {
+		err = r.db.SelectContext(ctx, &subs, `SELECT * FROM webhook_subscriptions WHERE user_id = $1 ORDER BY created_at DESC`, *userID)
+	}
+	if err != nil {
+		logger.Error("WebhookRepository:GetSubscriptionsByOwner:Error:", err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetActiveSubscriptionsForEvent returns the owner's subscriptions plus global subscriptions listening to eventType
+func (r *WebhookRepository) GetActiveSubscriptionsForEvent(ctx context.Context, ownerID *uuid.UUID, eventType string) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	query := `
+		SELECT * FROM webhook_subscriptions
+		WHERE is_active = true
+		AND $2 = ANY(event_types)
+		AND (user_id IS NULL OR user_id = $1)
+	`
+	err := r.db.SelectContext(ctx, &subs, query, ownerID, eventType)
+	if err != nil {
+		logger.Error("WebhookRepository:GetActiveSubscriptionsForEvent:Error:", err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $2, event_types = $3, description = $4, is_active = $5, secret = $6, updated_at = NOW()
+		WHERE id = $1
+	`
+	err := r.db.ExecContext(ctx, query, sub.ID, sub.URL, sub.EventTypes, sub.Description, sub.IsActive, sub.Secret)
+	if err != nil {
+		logger.Error("WebhookRepository:UpdateSubscription:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		logger.Error("WebhookRepository:DeleteSubscription:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5, 0, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Status).
+		Scan(&delivery.ID, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		logger.Error("WebhookRepository:CreateDelivery:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	query := `SELECT * FROM webhook_deliveries WHERE id = $1`
+	err := r.db.GetContext(ctx, &delivery, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Error("WebhookRepository:GetDeliveryByID:Error:", err)
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, params params.QueryParams) (*entity.PaginatedWebhookDeliveryEntity, error) {
+	offset := (params.PageNumber - 1) * params.PageSize
+
+	var totalItems int
+	err := r.db.GetContext(ctx, &totalItems, `SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = $1`, subscriptionID)
+	if err != nil {
+		logger.Error("WebhookRepository:GetDeliveriesBySubscriptionID:Count:Error:", err)
+		return nil, err
+	}
+
+	query := `
+		SELECT * FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	var deliveries []entity.WebhookDelivery
+	err = r.db.SelectContext(ctx, &deliveries, query, subscriptionID, params.PageSize, offset)
+	if err != nil {
+		logger.Error("WebhookRepository:GetDeliveriesBySubscriptionID:Select:Error:", err)
+		return nil, err
+	}
+
+	return &entity.PaginatedWebhookDeliveryEntity{
+		Items:      deliveries,
+		TotalItems: totalItems,
+		PageNumber: params.PageNumber,
+		PageSize:   params.PageSize,
+	}, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, response_body = $4,
+			last_error = $5, delivered_at = $6, updated_at = NOW()
+		WHERE id = $1
+	`
+	err := r.db.ExecContext(ctx, query, delivery.ID, delivery.Status, delivery.ResponseStatus, delivery.ResponseBody, delivery.LastError, delivery.DeliveredAt)
+	if err != nil {
+		logger.Error("WebhookRepository:RecordAttempt:Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookRepository) UpdateDeliveryStatus(ctx context.Context, id uuid.UUID, status string) error {
+	err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = $2, updated_at = NOW() WHERE id = $1`, id, status)
+	if err != nil {
+		logger.Error("WebhookRepository:UpdateDeliveryStatus:Error:", err)
+		return err
+	}
+	return nil
+}
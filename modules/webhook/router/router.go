@@ -0,0 +1,38 @@
+package router
+
+import (
+	"go-api-starter/core/middleware"
+	"go-api-starter/modules/webhook/controller"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookRouter struct {
+	controller      *controller.WebhookController
+	adminController *controller.WebhookController
+}
+
+func NewWebhookRouter(controller *controller.WebhookController, adminController *controller.WebhookController) *WebhookRouter {
+	return &WebhookRouter{controller: controller, adminController: adminController}
+}
+
+func (r *WebhookRouter) Setup(e *echo.Echo, mw *middleware.Middleware) {
+	privateRoutes := e.Group("/api/v1/private")
+
+	group := privateRoutes.Group("/webhooks", mw.AuthMiddleware())
+	group.GET("", r.controller.ListSubscriptions)
+	group.POST("", r.controller.CreateSubscription)
+	group.PUT("/:id", r.controller.UpdateSubscription)
+	group.DELETE("/:id", r.controller.DeleteSubscription)
+	group.GET("/:id/deliveries", r.controller.ListDeliveries)
+	group.POST("/deliveries/:id/redeliver", r.controller.Redeliver)
+
+	// Admin-managed subscriptions receive events for every user
+	admin := privateRoutes.Group("/admin/webhooks", mw.AuthMiddleware(), mw.PermissionMiddleware("webhooks:manage"))
+	admin.GET("", r.adminController.ListSubscriptions)
+	admin.POST("", r.adminController.CreateSubscription)
+	admin.PUT("/:id", r.adminController.UpdateSubscription)
+	admin.DELETE("/:id", r.adminController.DeleteSubscription)
+	admin.GET("/:id/deliveries", r.adminController.ListDeliveries)
+	admin.POST("/deliveries/:id/redeliver", r.adminController.Redeliver)
+}
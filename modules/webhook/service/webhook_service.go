@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-api-starter/core/constants"
+	"go-api-starter/core/errors"
+	"go-api-starter/core/logger"
+	"go-api-starter/core/params"
+	"go-api-starter/modules/webhook/dto"
+	"go-api-starter/modules/webhook/entity"
+	"go-api-starter/modules/webhook/repository"
+	"go-api-starter/workers"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// Header names sent with every delivery
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	webhookMaxRetry        = 8
+	webhookRequestTimeout  = 10 * time.Second
+	webhookMaxResponseBody = 2048
+)
+
+type WebhookService struct {
+	repo       *repository.WebhookRepository
+	httpClient *http.Client
+}
+
+func NewWebhookService(repo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		httpClient: newWebhookHTTPClient(),
+	}
+}
+
+// Sign computes the signature header value for a payload.
+// Receivers recompute hex(HMAC-SHA256(secret, "<timestamp>.<body>")) and compare it with v1.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Emit fans an event out to the owner's subscriptions and to global subscriptions.
+// Failures are logged and never returned so callers' main paths are unaffected.
+// A nil service is a no-op, which keeps emitters usable when webhooks are not wired.
+func (s *WebhookService) Emit(ctx context.Context, ownerID *uuid.UUID, eventType string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	subs, err := s.repo.GetActiveSubscriptionsForEvent(ctx, ownerID, eventType)
+	if err != nil || len(subs) == 0 {
+		return
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(dto.EventPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		logger.Error("WebhookService:Emit:Marshal:Error", "event_type", eventType, "error", err)
+		return
+	}
+
+	for _, sub := range subs {
+		delivery := &entity.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         entity.DeliveryStatusPending,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			continue
+		}
+		if err := s.enqueue(delivery.ID); err != nil {
+			logger.Error("WebhookService:Emit:Enqueue:Error", "delivery_id", delivery.ID, "error", err)
+		}
+	}
+
+	logger.Info("WebhookService:Emit", "event_type", eventType, "subscriptions", len(subs))
+}
+
+// Deliver performs one delivery attempt; a returned error makes asynq retry with backoff
+func (s *WebhookService) Deliver(ctx context.Context, deliveryID uuid.UUID) error {
+	delivery, err := s.repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery == nil || delivery.Status == entity.DeliveryStatusSuccess {
+		return nil
+	}
+
+	sub, err := s.repo.GetSubscriptionByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	if sub == nil || !sub.IsActive {
+		return s.repo.UpdateDeliveryStatus(ctx, delivery.ID, entity.DeliveryStatusFailed)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return s.recordFailure(ctx, delivery, nil, nil, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SmartSchedule-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return s.recordFailure(ctx, delivery, nil, nil, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	status := resp.StatusCode
+	respText := string(respBody)
+
+	if status < 200 || status >= 300 {
+		return s.recordFailure(ctx, delivery, &status, &respText, fmt.Errorf("unexpected status %d", status))
+	}
+
+	now := time.Now()
+	delivery.Status = entity.DeliveryStatusSuccess
+	delivery.ResponseStatus = &status
+	delivery.ResponseBody = &respText
+	delivery.LastError = nil
+	delivery.DeliveredAt = &now
+	if err := s.repo.RecordAttempt(ctx, delivery); err != nil {
+		return nil
+	}
+
+	logger.Info("WebhookService:Deliver:Success", "delivery_id", delivery.ID, "status", status)
+	return nil
+}
+
+// recordFailure logs the attempt and marks the delivery failed once asynq has no retries left
+func (s *WebhookService) recordFailure(ctx context.Context, delivery *entity.WebhookDelivery, status *int, body *string, cause error) error {
+	errText := cause.Error()
+	delivery.Status = entity.DeliveryStatusPending
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+	delivery.LastError = &errText
+	delivery.DeliveredAt = nil
+
+	retried, hasRetry := asynq.GetRetryCount(ctx)
+	maxRetry, hasMax := asynq.GetMaxRetry(ctx)
+	if !hasRetry || !hasMax || retried >= maxRetry {
+		delivery.Status = entity.DeliveryStatusFailed
+	}
+
+	_ = s.repo.RecordAttempt(ctx, delivery)
+	logger.Warn("WebhookService:Deliver:Failed", "delivery_id", delivery.ID, "retry", retried, "error", errText)
+	return cause
+}
+
+func (s *WebhookService) enqueue(deliveryID uuid.UUID) error {
+	payload, err := json.Marshal(dto.DeliveryTask{DeliveryID: deliveryID})
+	if err != nil {
+		return err
+	}
+	_, err = workers.Enqueue(constants.TopicQueueWebhookDelivery, payload, asynq.MaxRetry(webhookMaxRetry), asynq.Queue("default"))
+	return err
+}
+
+// HandleDeliveryTask is the asynq handler for TopicQueueWebhookDelivery
+func (s *WebhookService) HandleDeliveryTask(ctx context.Context, payload []byte) error {
+	var task dto.DeliveryTask
+	if err := json.Unmarshal(payload, &task); err != nil {
+		logger.Error("WebhookService:HandleDeliveryTask:Unmarshal:Error", "error", err)
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	return s.Deliver(ctx, task.DeliveryID)
+}
+
+// ListSubscriptions returns the caller's subscriptions; ownerID nil means the admin scope (global subscriptions)
+func (s *WebhookService) ListSubscriptions(ctx context.Context, ownerID *uuid.UUID) ([]dto.SubscriptionResponse, *errors.AppError) {
+	subs, err := s.repo.GetSubscriptionsByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get webhook subscriptions", err)
+	}
+	result := make([]dto.SubscriptionResponse, 0, len(subs))
+	for i := range subs {
+		result = append(result, *toSubscriptionResponse(&subs[i], false))
+	}
+	return result, nil
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, ownerID *uuid.UUID, req *dto.CreateSubscriptionRequest) (*dto.SubscriptionResponse, *errors.AppError) {
+	if appErr := validateSubscription(req.URL, req.EventTypes); appErr != nil {
+		return nil, appErr
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrInternalServer, "failed to generate secret", err)
+		}
+		secret = generated
+	}
+
+	sub := &entity.WebhookSubscription{
+		UserID:     ownerID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		IsActive:   true,
+	}
+	if req.Description != "" {
+		sub.Description = &req.Description
+	}
+
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, errors.NewAppError(errors.ErrCreateFailed, "failed to create webhook subscription", err)
+	}
+	return toSubscriptionResponse(sub, true), nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, ownerID *uuid.UUID, id uuid.UUID, req *dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, *errors.AppError) {
+	sub, appErr := s.getOwnedSubscription(ctx, ownerID, id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if req.URL != "" {
+		sub.URL = req.URL
+	}
+	if req.EventTypes != nil {
+		sub.EventTypes = req.EventTypes
+	}
+	if appErr := validateSubscription(sub.URL, sub.EventTypes); appErr != nil {
+		return nil, appErr
+	}
+	if req.Description != nil {
+		sub.Description = req.Description
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+	if req.RotateSecret {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, errors.NewAppError(errors.ErrInternalServer, "failed to generate secret", err)
+		}
+		sub.Secret = secret
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "failed to update webhook subscription", err)
+	}
+	return toSubscriptionResponse(sub, req.RotateSecret), nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, ownerID *uuid.UUID, id uuid.UUID) *errors.AppError {
+	if _, appErr := s.getOwnedSubscription(ctx, ownerID, id); appErr != nil {
+		return appErr
+	}
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return errors.NewAppError(errors.ErrDeleteFailed, "failed to delete webhook subscription", err)
+	}
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, ownerID *uuid.UUID, subscriptionID uuid.UUID, queryParams params.QueryParams) (*dto.PaginatedDeliveryResponse, *errors.AppError) {
+	if _, appErr := s.getOwnedSubscription(ctx, ownerID, subscriptionID); appErr != nil {
+		return nil, appErr
+	}
+	page, err := s.repo.GetDeliveriesBySubscriptionID(ctx, subscriptionID, queryParams)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get webhook deliveries", err)
+	}
+
+	result := &dto.PaginatedDeliveryResponse{
+		Items:      make([]dto.DeliveryResponse, 0, len(page.Items)),
+		TotalItems: page.TotalItems,
+		PageNumber: page.PageNumber,
+		PageSize:   page.PageSize,
+	}
+	for i := range page.Items {
+		result.Items = append(result.Items, *toDeliveryResponse(&page.Items[i], ownerID == nil))
+	}
+	return result, nil
+}
+
+// Redeliver queues a fresh delivery attempt for an existing delivery log entry
+func (s *WebhookService) Redeliver(ctx context.Context, ownerID *uuid.UUID, deliveryID uuid.UUID) (*dto.DeliveryResponse, *errors.AppError) {
+	delivery, err := s.repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get webhook delivery", err)
+	}
+	if delivery == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "webhook delivery not found", nil)
+	}
+	if _, appErr := s.getOwnedSubscription(ctx, ownerID, delivery.SubscriptionID); appErr != nil {
+		return nil, appErr
+	}
+
+	if err := s.repo.UpdateDeliveryStatus(ctx, delivery.ID, entity.DeliveryStatusPending); err != nil {
+		return nil, errors.NewAppError(errors.ErrUpdateFailed, "failed to reset webhook delivery", err)
+	}
+	if err := s.enqueue(delivery.ID); err != nil {
+		return nil, errors.NewAppError(errors.ErrInternalServer, "failed to enqueue webhook delivery", err)
+	}
+
+	delivery.Status = entity.DeliveryStatusPending
+	return toDeliveryResponse(delivery, ownerID == nil), nil
+}
+
+func (s *WebhookService) getOwnedSubscription(ctx context.Context, ownerID *uuid.UUID, id uuid.UUID) (*entity.WebhookSubscription, *errors.AppError) {
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewAppError(errors.ErrGetFailed, "failed to get webhook subscription", err)
+	}
+	if sub == nil {
+		return nil, errors.NewAppError(errors.ErrNotFound, "webhook subscription not found", nil)
+	}
+	owned := (ownerID == nil && sub.UserID == nil) || (ownerID != nil && sub.UserID != nil && *ownerID == *sub.UserID)
+	if !owned {
+		return nil, errors.NewAppError(errors.ErrNotFound, "webhook subscription not found", nil)
+	}
+	return sub, nil
+}
+
+func validateSubscription(rawURL string, eventTypes []string) *errors.AppError {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.NewAppError(errors.ErrInvalidInput, "url must be a valid http(s) URL", nil)
+	}
+	if err := validateWebhookHost(u.Hostname()); err != nil {
+		return errors.NewAppError(errors.ErrInvalidInput, err.Error(), nil)
+	}
+	if len(eventTypes) == 0 {
+		return errors.NewAppError(errors.ErrInvalidInput, "event_types is required", nil)
+	}
+	for _, t := range eventTypes {
+		if !slices.Contains(constants.WebhookEventTypes, t) {
+			return errors.NewAppError(errors.ErrInvalidInput, "unsupported event type: "+t, nil)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func toSubscriptionResponse(sub *entity.WebhookSubscription, includeSecret bool) *dto.SubscriptionResponse {
+	res := &dto.SubscriptionResponse{
+		ID:         sub.ID,
+		UserID:     sub.UserID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		IsActive:   sub.IsActive,
+		CreatedAt:  sub.CreatedAt,
+		UpdatedAt:  sub.UpdatedAt,
+	}
+	if sub.Description != nil {
+		res.Description = *sub.Description
+	}
+	if includeSecret {
+		res.Secret = sub.Secret
+	}
+	return res
+}
+
+// toDeliveryResponse maps a delivery log entry. The receiver's response body is only shown in the
+// admin scope: to a subscription owner it would echo back whatever their URL made the server fetch.
+func toDeliveryResponse(d *entity.WebhookDelivery, includeResponseBody bool) *dto.DeliveryResponse {
+	res := &dto.DeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if includeResponseBody {
+		res.ResponseBody = d.ResponseBody
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package service
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	// Expected values are hex(HMAC-SHA256(secret, "<timestamp>.<body>")), computed independently
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			name:      "json body",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      `{"type":"booking.created"}`,
+			want:      "t=1700000000,v1=9c1e6e2200925288bc7d6ea44aea27c9ef40fa88f4d3f54595139cdf4de1d784",
+		},
+		{
+			name:      "empty body",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      "",
+			want:      "t=1700000000,v1=5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+		{
+			name:      "other secret and timestamp",
+			secret:    "another-secret",
+			timestamp: 1,
+			body:      "payload",
+			want:      "t=1,v1=3332f818c807bd7aaaa2f9c28543240b7b2d7b1290b4888fc989a7ecbe3878a3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
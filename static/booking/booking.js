@@ -0,0 +1,286 @@
+// Booking page: month calendar, free slots of the selected day and the booking form.
+// The page passes window.bookingConfig = {host, mode, locale, challenge, messages}.
+(function () {
+  const cfg = window.bookingConfig || {}
+  const t = key => (cfg.messages && cfg.messages[key]) || key
+  const $ = id => document.getElementById(id)
+  const api = '/api/v1/public/booking/' + encodeURIComponent(cfg.host)
+
+  let current = new Date()
+  let selectedSlot = null
+  let waitlistDate = null
+
+  const pad = n => ('0' + n).slice(-2)
+
+  // rfc3339 formats a date in the visitor's timezone, e.g. 2026-01-28T09:00:00+07:00
+  function rfc3339(date) {
+    const minutes = -date.getTimezoneOffset()
+    const sign = minutes >= 0 ? '+' : '-'
+    const abs = Math.abs(minutes)
+    return date.getFullYear() + '-' + pad(date.getMonth() + 1) + '-' + pad(date.getDate()) +
+      'T' + pad(date.getHours()) + ':' + pad(date.getMinutes()) + ':' + pad(date.getSeconds()) +
+      sign + pad(Math.floor(abs / 60)) + ':' + pad(abs % 60)
+  }
+  function dayStart(date) { return rfc3339(new Date(date.getFullYear(), date.getMonth(), date.getDate(), 0, 0, 0)) }
+  function dayEnd(date) { return rfc3339(new Date(date.getFullYear(), date.getMonth(), date.getDate(), 23, 59, 59)) }
+  function monthName(y, m) { return new Date(y, m, 1).toLocaleString(cfg.locale, { month: 'long', year: 'numeric' }) }
+  function timeLabel(date) { return date.toLocaleTimeString(cfg.locale, { hour: '2-digit', minute: '2-digit', hour12: false }) }
+
+  async function fetchSlots(date) {
+    if (cfg.mode === 'suggested') {
+      const body = { duration_minutes: 30, days_ahead: 1, start_date: date.toISOString().split('T')[0], time_preference: '', working_hours_only: false }
+      const res = await fetch(api + '/suggested-slots', { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(body) })
+      const data = await res.json()
+      return ((data && data.slots) || []).map(s => ({ start: s.start_time, end: s.end_time }))
+    }
+    const url = api + '/free?start_time=' + encodeURIComponent(dayStart(date)) + '&end_time=' + encodeURIComponent(dayEnd(date)) + '&interval=30'
+    const res = await fetch(url)
+    const data = await res.json()
+    return ((data && data.slots) || []).map(s => ({ start: s.start, end: s.end }))
+  }
+
+  function message(root, text) {
+    const el = document.createElement('div'); el.className = 'muted'; el.textContent = text
+    root.appendChild(el)
+  }
+
+  async function loadSlotsForDay(date) {
+    selectedSlot = null
+    $('book').disabled = true
+    $('waitlist').hidden = true
+    const root = $('slots'); root.innerHTML = ''
+    let slots
+    try {
+      slots = await fetchSlots(date)
+    } catch (e) {
+      message(root, t('slots_failed'))
+      return
+    }
+    if (slots.length === 0) {
+      message(root, t('no_slots'))
+      // A fully booked day can be waited for
+      waitlistDate = date
+      $('waitlist').hidden = false
+      return
+    }
+    slots.forEach(s => {
+      const start = new Date(s.start), end = new Date(s.end)
+      const el = document.createElement('div'); el.className = 'slot'; el.textContent = timeLabel(start)
+      el.onclick = () => {
+        selectedSlot = { start: rfc3339(start), end: rfc3339(end) }
+        setActiveSlot(el)
+      }
+      root.appendChild(el)
+    })
+  }
+
+  function setActiveSlot(el) {
+    document.querySelectorAll('.slot').forEach(s => s.classList.remove('active'))
+    el.classList.add('active')
+    $('book').disabled = false
+  }
+
+  function buildWeekdays() {
+    const root = $('weekdays'); root.innerHTML = ''
+    // 2024-01-07 is a Sunday, the first column of the calendar
+    for (let i = 0; i < 7; i++) {
+      const el = document.createElement('div'); el.className = 'weekday'
+      el.textContent = new Date(2024, 0, 7 + i).toLocaleString(cfg.locale, { weekday: 'short' })
+      root.appendChild(el)
+    }
+  }
+
+  function buildCalendar(d) {
+    const y = d.getFullYear(), m = d.getMonth()
+    $('monthTitle').textContent = monthName(y, m)
+    const grid = $('calendar'); grid.innerHTML = ''
+    const first = new Date(y, m, 1), last = new Date(y, m + 1, 0)
+    for (let i = 0; i < first.getDay(); i++) {
+      const ph = document.createElement('div'); ph.className = 'day empty'; grid.appendChild(ph)
+    }
+    for (let day = 1; day <= last.getDate(); day++) {
+      const date = new Date(y, m, day)
+      const el = document.createElement('div'); el.className = 'day'; el.textContent = day
+      el.onclick = () => {
+        document.querySelectorAll('.day').forEach(x => x.classList.remove('active'))
+        el.classList.add('active')
+        loadSlotsForDay(date)
+      }
+      grid.appendChild(el)
+    }
+  }
+
+  $('prev').onclick = () => { current = new Date(current.getFullYear(), current.getMonth() - 1, 1); buildCalendar(current) }
+  $('next').onclick = () => { current = new Date(current.getFullYear(), current.getMonth() + 1, 1); buildCalendar(current) }
+  // solveChallenge finds a nonce so that SHA-256(challenge + ":" + nonce) starts with `difficulty` zero bits
+  async function solveChallenge() {
+    const res = await fetch(api + '/challenge')
+    const c = await res.json()
+    const enc = new TextEncoder()
+    for (let nonce = 0; ; nonce++) {
+      const hash = new Uint8Array(await crypto.subtle.digest('SHA-256', enc.encode(c.challenge + ':' + nonce)))
+      let bits = 0
+      for (const b of hash) {
+        if (b === 0) { bits += 8; continue }
+        bits += Math.clz32(b) - 24
+        break
+      }
+      if (bits >= c.difficulty) return { challenge: c.challenge, nonce: String(nonce) }
+    }
+  }
+
+  // The page link can pick the event type, e.g. /p/my-slug?event_type=intro-call
+  const eventType = new URLSearchParams(window.location.search).get('event_type') || ''
+  let questions = []
+
+  // loadQuestions renders the host's intake questions into the booking form
+  async function loadQuestions() {
+    try {
+      const res = await fetch(api + '/questions?event_type=' + encodeURIComponent(eventType))
+      const j = await res.json()
+      questions = (j && j.questions) || []
+    } catch (e) {
+      questions = []
+    }
+    const root = $('questions'); root.innerHTML = ''
+    questions.forEach(q => {
+      const wrap = document.createElement('div'); wrap.className = 'question'
+      const label = document.createElement('label'); label.textContent = q.label + (q.required ? ' *' : '')
+      wrap.appendChild(label)
+      const id = 'q-' + q.id
+      if (q.type === 'single_choice') {
+        const select = document.createElement('select'); select.id = id
+        select.appendChild(new Option('', ''))
+        q.options.forEach(o => select.appendChild(new Option(o, o)))
+        wrap.appendChild(select)
+      } else if (q.type === 'multi_choice') {
+        q.options.forEach(o => {
+          const row = document.createElement('label'); row.className = 'choice'
+          const box = document.createElement('input'); box.type = 'checkbox'; box.name = id; box.value = o
+          row.appendChild(box); row.appendChild(document.createTextNode(o))
+          wrap.appendChild(row)
+        })
+      } else {
+        const input = document.createElement(q.type === 'long_text' ? 'textarea' : 'input'); input.id = id
+        if (q.type === 'phone') input.type = 'tel'
+        wrap.appendChild(input)
+      }
+      root.appendChild(wrap)
+    })
+  }
+
+  let priced = false
+
+  // loadPrice shows the price of a paid event type and the payment methods the guest can pick
+  async function loadPrice() {
+    let price
+    try {
+      const res = await fetch(api + '/price?event_type=' + encodeURIComponent(eventType))
+      price = await res.json()
+    } catch (e) {
+      return
+    }
+    priced = !!(price && price.amount > 0)
+    $('payment').hidden = !priced
+    if (!priced) return
+    $('price').textContent = new Intl.NumberFormat(cfg.locale, { style: 'currency', currency: 'VND' }).format(price.amount)
+    const select = $('paymentMethod'); select.innerHTML = ''
+    select.appendChild(new Option(t('payment_method'), ''))
+    price.payment_methods.forEach(m => select.appendChild(new Option(m.name, m.id)))
+  }
+
+  function collectAnswers() {
+    const answers = {}
+    questions.forEach(q => {
+      const id = 'q-' + q.id
+      if (q.type === 'multi_choice') {
+        answers[q.id] = Array.from(document.querySelectorAll('input[name="' + id + '"]:checked')).map(el => el.value)
+      } else {
+        answers[q.id] = $(id).value
+      }
+    })
+    return answers
+  }
+
+  let verificationId = null
+
+  // confirmEmail sends the emailed code; the host gets the request once it matches
+  async function confirmEmail(id, code) {
+    const res = await fetch('/api/v1/public/booking/verifications/' + encodeURIComponent(id), { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify({ code: code }) })
+    const j = await res.json()
+    if (!res.ok) {
+      alert((j && j.message) || t('verify_failed'))
+      return
+    }
+    $('verify').hidden = true
+    alert((j && j.message) || t('booked'))
+  }
+
+  $('book').onclick = async () => {
+    if (!selectedSlot) return
+    if (priced && !$('paymentMethod').value) {
+      alert(t('payment_required'))
+      $('paymentMethod').focus()
+      return
+    }
+    const payload = { start_time: selectedSlot.start, end_time: selectedSlot.end, name: $('name').value, email: $('email').value, website: $('website').value, event_type: eventType, answers: collectAnswers() }
+    if (priced) payload.payment_method_id = Number($('paymentMethod').value)
+    $('book').disabled = true
+    try {
+      if (cfg.challenge) Object.assign(payload, await solveChallenge())
+      const res = await fetch(api + '/schedule', { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(payload) })
+      const j = await res.json()
+      if (j && j.status === 'verification_required') {
+        verificationId = j.verification_id
+        $('verify').hidden = false
+        $('code').focus()
+        return
+      }
+      alert((j && j.message) || (res.ok ? t('booked') : t('book_failed')))
+    } catch (e) {
+      alert(t('book_failed'))
+    } finally {
+      $('book').disabled = !selectedSlot
+    }
+  }
+  $('confirm').onclick = () => { if (verificationId) confirmEmail(verificationId, $('code').value.trim()) }
+
+  // joinWaitlist puts the guest of the form on the waitlist of the fully booked day
+  $('joinWaitlist').onclick = async () => {
+    if (!waitlistDate) return
+    if (!$('name').value.trim() || !$('email').value.trim()) {
+      alert(t('waitlist_contact'))
+      $('name').focus()
+      return
+    }
+    const date = waitlistDate.getFullYear() + '-' + pad(waitlistDate.getMonth() + 1) + '-' + pad(waitlistDate.getDate())
+    if (priced && !$('paymentMethod').value) {
+      alert(t('payment_required'))
+      $('paymentMethod').focus()
+      return
+    }
+    const payload = { name: $('name').value, email: $('email').value, website: $('website').value, date: date, event_type: eventType, timezone: Intl.DateTimeFormat().resolvedOptions().timeZone, answers: collectAnswers() }
+    if (priced) payload.payment_method_id = Number($('paymentMethod').value)
+    $('joinWaitlist').disabled = true
+    try {
+      if (cfg.challenge) Object.assign(payload, await solveChallenge())
+      const res = await fetch(api + '/waitlist', { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(payload) })
+      const j = await res.json()
+      if (res.ok) $('waitlist').hidden = true
+      alert(res.ok ? t('waitlist_joined') : ((j && j.message) || t('waitlist_failed')))
+    } catch (e) {
+      alert(t('waitlist_failed'))
+    } finally {
+      $('joinWaitlist').disabled = false
+    }
+  }
+
+  buildWeekdays()
+  buildCalendar(current)
+  loadQuestions()
+  loadPrice()
+
+  // The confirm link of the verification email opens the page with ?verification=&code=
+  const query = new URLSearchParams(window.location.search)
+  if (query.get('verification') && query.get('code')) confirmEmail(query.get('verification'), query.get('code'))
+})()
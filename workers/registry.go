@@ -0,0 +1,79 @@
+package workers
+
+import (
+	"context"
+	"go-api-starter/core/config"
+	"go-api-starter/core/logger"
+	"sync"
+
+	"github.com/hibiken/asynq"
+)
+
+// TaskHandler processes the payload of a registered task type
+type TaskHandler func(ctx context.Context, payload []byte) error
+
+type periodicTask struct {
+	cronspec string
+	taskType string
+	opts     []asynq.Option
+}
+
+var (
+	handlersMu    sync.RWMutex
+	handlers      = map[string]TaskHandler{}
+	periodicTasks []periodicTask
+)
+
+// RegisterHandler registers a handler for a task type.
+// Modules call this during Init so ProcessTask can dispatch their tasks
+// without the workers package importing them.
+func RegisterHandler(taskType string, handler TaskHandler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[taskType] = handler
+}
+
+func getHandler(taskType string) (TaskHandler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	handler, ok := handlers[taskType]
+	return handler, ok
+}
+
+// RegisterPeriodicTask schedules an empty-payload task on a cron spec (e.g. "0 * * * *").
+// Must be called before NewScheduler.
+func RegisterPeriodicTask(cronspec string, taskType string, opts ...asynq.Option) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	periodicTasks = append(periodicTasks, periodicTask{cronspec: cronspec, taskType: taskType, opts: opts})
+}
+
+// NewScheduler starts an asynq scheduler that enqueues all registered periodic tasks
+func NewScheduler() *asynq.Scheduler {
+	cfg := config.Get()
+	redisOpt := asynq.RedisClientOpt{
+		Addr:     cfg.Redis.Address,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	}
+
+	scheduler := asynq.NewScheduler(redisOpt, nil)
+
+	handlersMu.RLock()
+	for _, pt := range periodicTasks {
+		if _, err := scheduler.Register(pt.cronspec, asynq.NewTask(pt.taskType, nil), pt.opts...); err != nil {
+			logger.Error("NewScheduler: Failed to register periodic task", "error", err, "task_type", pt.taskType, "cronspec", pt.cronspec)
+		}
+	}
+	count := len(periodicTasks)
+	handlersMu.RUnlock()
+
+	go func() {
+		if err := scheduler.Run(); err != nil {
+			logger.Error("NewScheduler: Asynq scheduler failed to run", "error", err)
+		}
+	}()
+
+	logger.Info("NewScheduler: Asynq scheduler initialized successfully", "periodic_tasks", count)
+	return scheduler
+}
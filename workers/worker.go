@@ -79,6 +79,16 @@ func ProcessTask(ctx context.Context, task *asynq.Task) error {
 			logger.Error("ProcessTask:SendEmail failed", "err", err)
 			return fmt.Errorf("send email failed: %w", err)
 		}
+	default:
+		handler, ok := getHandler(task.Type())
+		if !ok {
+			logger.Warn("ProcessTask:No handler registered", "type", task.Type())
+			return nil
+		}
+		if err := handler(ctx, task.Payload()); err != nil {
+			logger.Error("ProcessTask:Handler failed", "type", task.Type(), "err", err)
+			return fmt.Errorf("%s failed: %w", task.Type(), err)
+		}
 	}
 
 	return nil